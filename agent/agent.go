@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/persister"
 	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 )
@@ -44,8 +45,18 @@ func NewAgent(config *config.Config) (*Agent, error) {
 type inputUnit struct {
 	dst    chan<- telegraf.Metric
 	inputs []*models.RunningInput
+
+	// pending holds the accumulators of service inputs that failed to start
+	// and are retried before gathering.
+	pending map[*models.RunningInput]telegraf.Accumulator
 }
 
+var (
+	// Bounds of the backoff between retries of failed startups.
+	startupRetryMinBackoff = time.Second
+	startupRetryMaxBackoff = 5 * time.Minute
+)
+
 //  ______     ┌───────────┐     ______
 // ()_____)──▶ │ Processor │──▶ ()_____)
 //             └───────────┘
@@ -107,6 +118,15 @@ func (a *Agent) Run(ctx context.Context) error {
 		return err
 	}
 
+	var states *persister.Persister
+	if a.Config.Agent.Statefile != "" {
+		log.Printf("D! [agent] Restoring plugin states from %q", a.Config.Agent.Statefile)
+		states, err = a.loadStates()
+		if err != nil {
+			return err
+		}
+	}
+
 	startTime := time.Now()
 
 	log.Printf("D! [agent] Connecting outputs")
@@ -179,10 +199,79 @@ func (a *Agent) Run(ctx context.Context) error {
 
 	wg.Wait()
 
+	if states != nil {
+		log.Printf("D! [agent] Storing plugin states to %q", a.Config.Agent.Statefile)
+		if err := states.Store(); err != nil {
+			log.Printf("E! [agent] Storing plugin states failed: %v", err)
+		}
+	}
+
 	log.Printf("D! [agent] Stopped Successfully")
 	return err
 }
 
+// loadStates registers the stateful plugins and restores their states from
+// the statefile.
+func (a *Agent) loadStates() (*persister.Persister, error) {
+	states := &persister.Persister{Filename: a.Config.Agent.Statefile}
+	// Identically configured plugins share the id of their settings, the
+	// repeated ones are told apart by their position among them
+	seen := make(map[string]int)
+	register := func(id string, plugin interface{}) error {
+		if p, ok := plugin.(unwrappable); ok {
+			plugin = p.Unwrap()
+		}
+		p, ok := plugin.(telegraf.StatefulPlugin)
+		if !ok {
+			return nil
+		}
+		n := seen[id]
+		seen[id]++
+		if n > 0 {
+			id = fmt.Sprintf("%s::%d", id, n)
+		}
+		return states.Register(id, p)
+	}
+
+	for _, input := range a.Config.Inputs {
+		if err := register(input.Config.ID, input.Input); err != nil {
+			return nil, fmt.Errorf("could not register input %s: %v", input.LogName(), err)
+		}
+	}
+	for _, processor := range a.Config.Processors {
+		if err := register(processor.Config.ID, processor.Processor); err != nil {
+			return nil, fmt.Errorf("could not register processor %s: %v", processor.LogName(), err)
+		}
+	}
+	for _, aggregator := range a.Config.Aggregators {
+		if err := register(aggregator.Config.ID, aggregator.Aggregator); err != nil {
+			return nil, fmt.Errorf("could not register aggregator %s: %v", aggregator.LogName(), err)
+		}
+	}
+	// The processors after the aggregators are a second instance of the
+	// processors with the same settings
+	for _, processor := range a.Config.AggProcessors {
+		if err := register(processor.Config.ID+"::aggregators", processor.Processor); err != nil {
+			return nil, fmt.Errorf("could not register processor %s: %v", processor.LogName(), err)
+		}
+	}
+	for _, output := range a.Config.Outputs {
+		if err := register(output.Config.ID, output.Output); err != nil {
+			return nil, fmt.Errorf("could not register output %s: %v", output.LogName(), err)
+		}
+	}
+
+	if err := states.Load(); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// unwrappable is implemented by processors wrapped into streaming processors.
+type unwrappable interface {
+	Unwrap() telegraf.Processor
+}
+
 // initPlugins runs the Init function on plugins.
 func (a *Agent) initPlugins() error {
 	for _, input := range a.Config.Inputs {
@@ -192,12 +281,8 @@ func (a *Agent) initPlugins() error {
 				input.LogName(), err)
 		}
 	}
-	for _, processor := range a.Config.Processors {
-		err := processor.Init()
-		if err != nil {
-			return fmt.Errorf("could not initialize processor %s: %v",
-				processor.Config.Name, err)
-		}
+	if err := initProcessors(a.Config.Processors, a.Config.Agent.PreserveMetricOrder); err != nil {
+		return err
 	}
 	for _, aggregator := range a.Config.Aggregators {
 		err := aggregator.Init()
@@ -206,13 +291,38 @@ func (a *Agent) initPlugins() error {
 				aggregator.Config.Name, err)
 		}
 	}
-	for _, processor := range a.Config.AggProcessors {
+	if err := initProcessors(a.Config.AggProcessors, a.Config.Agent.PreserveMetricOrder); err != nil {
+		return err
+	}
+	return a.initOutputs()
+}
+
+// initProcessors runs the Init function on the processors and makes them
+// preserve the order of the metrics if requested.
+func initProcessors(processors models.RunningProcessors, preserveOrder bool) error {
+	for _, processor := range processors {
 		err := processor.Init()
 		if err != nil {
 			return fmt.Errorf("could not initialize processor %s: %v",
 				processor.Config.Name, err)
 		}
+
+		if !preserveOrder {
+			continue
+		}
+		var plugin interface{} = processor.Processor
+		if p, ok := plugin.(unwrappable); ok {
+			plugin = p.Unwrap()
+		}
+		if p, ok := plugin.(telegraf.OrderPreserver); ok {
+			p.PreserveOrder()
+		}
 	}
+	return nil
+}
+
+// initOutputs runs the Init function on the outputs.
+func (a *Agent) initOutputs() error {
 	for _, output := range a.Config.Outputs {
 		err := output.Init()
 		if err != nil {
@@ -234,7 +344,7 @@ func (a *Agent) startInputs(
 	}
 
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
+		if _, ok := input.Input.(telegraf.ServiceInput); ok {
 			// Service input plugins are not normally subject to timestamp
 			// rounding except for when precision is set on the input plugin.
 			//
@@ -250,10 +360,22 @@ func (a *Agent) startInputs(
 			acc := NewAccumulator(input, dst)
 			acc.SetPrecision(getPrecision(precision, interval))
 
-			err := si.Start(acc)
+			err := input.Start(acc)
 			if err != nil {
-				stopServiceInputs(unit.inputs)
-				return nil, fmt.Errorf("starting input %s: %w", input.LogName(), err)
+				switch input.Config.StartupErrorBehavior {
+				case "ignore":
+					log.Printf("E! [agent] Starting input %s: %v; ignoring plugin", input.LogName(), err)
+					continue
+				case "retry":
+					log.Printf("E! [agent] Starting input %s: %v; retrying", input.LogName(), err)
+					if unit.pending == nil {
+						unit.pending = make(map[*models.RunningInput]telegraf.Accumulator)
+					}
+					unit.pending[input] = acc
+				default:
+					stopServiceInputs(unit.inputs)
+					return nil, fmt.Errorf("starting input %s: %w", input.LogName(), err)
+				}
 			}
 		}
 		unit.inputs = append(unit.inputs, input)
@@ -303,10 +425,18 @@ func (a *Agent) runInputs(
 		acc.SetPrecision(getPrecision(precision, interval))
 
 		wg.Add(1)
-		go func(input *models.RunningInput) {
+		go func(input *models.RunningInput, startAcc telegraf.Accumulator) {
 			defer wg.Done()
+			if startAcc != nil {
+				started := retryStartup(ctx, input, "Starting", func() error {
+					return input.Start(startAcc)
+				})
+				if !started {
+					return
+				}
+			}
 			a.gatherLoop(ctx, acc, input, ticker, interval)
-		}(input)
+		}(input, unit.pending[input])
 	}
 
 	wg.Wait()
@@ -332,7 +462,7 @@ func (a *Agent) testStartInputs(
 	}
 
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
+		if _, ok := input.Input.(telegraf.ServiceInput); ok {
 			// Service input plugins are not subject to timestamp rounding.
 			// This only applies to the accumulator passed to Start(), the
 			// Gather() accumulator does apply rounding according to the
@@ -340,7 +470,7 @@ func (a *Agent) testStartInputs(
 			acc := NewAccumulator(input, dst)
 			acc.SetPrecision(time.Nanosecond)
 
-			err := si.Start(acc)
+			err := input.Start(acc)
 			if err != nil {
 				log.Printf("E! [agent] Starting input %s: %v", input.LogName(), err)
 			}
@@ -416,12 +546,10 @@ func (a *Agent) testRunInputs(
 	log.Printf("D! [agent] Input channel closed")
 }
 
-// stopServiceInputs stops all service inputs.
+// stopServiceInputs stops all started service inputs.
 func stopServiceInputs(inputs []*models.RunningInput) {
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
-			si.Stop()
-		}
+		input.Stop()
 	}
 }
 
@@ -443,10 +571,31 @@ func (a *Agent) gatherLoop(
 ) {
 	defer panicRecover(input)
 
+	// The first gather probes the input unless startup errors are treated
+	// like any other gather error.
+	probed := input.Config.StartupErrorBehavior != "ignore" &&
+		input.Config.StartupErrorBehavior != "retry"
+
 	for {
 		select {
 		case <-ticker.Elapsed():
 			err := a.gatherOnce(acc, input, ticker, interval)
+			if err != nil && !probed {
+				if input.Config.StartupErrorBehavior == "ignore" {
+					log.Printf("E! [agent] Gathering input %s: %v; ignoring plugin", input.LogName(), err)
+					return
+				}
+
+				log.Printf("E! [agent] Gathering input %s: %v; retrying", input.LogName(), err)
+				gathered := retryStartup(ctx, input, "Gathering", func() error {
+					return a.gatherOnce(acc, input, ticker, interval)
+				})
+				if !gathered {
+					return
+				}
+				err = nil
+			}
+			probed = true
 			if err != nil {
 				acc.AddError(err)
 			}
@@ -456,6 +605,30 @@ func (a *Agent) gatherLoop(
 	}
 }
 
+// retryStartup retries a failed startup step of the input with exponential
+// backoff.  It returns false if the context is done before the step
+// succeeded.
+func retryStartup(ctx context.Context, input *models.RunningInput, step string, fn func() error) bool {
+	backoff := startupRetryMinBackoff
+	for {
+		if err := internal.SleepContext(ctx, backoff); err != nil {
+			return false
+		}
+
+		err := fn()
+		if err == nil {
+			log.Printf("I! [agent] %s input %s succeeded after retry", step, input.LogName())
+			return true
+		}
+
+		backoff *= 2
+		if backoff > startupRetryMaxBackoff {
+			backoff = startupRetryMaxBackoff
+		}
+		log.Printf("E! [agent] %s input %s: %v; retrying in %s", step, input.LogName(), err, backoff)
+	}
+}
+
 // gatherOnce runs the input's Gather function once, logging a warning each
 // interval it fails to complete before.
 func (a *Agent) gatherOnce(
@@ -741,6 +914,12 @@ func (a *Agent) runOutputs(
 			jitter = output.Config.FlushJitter
 		}
 
+		fullFlush := a.Config.Agent.MetricBatchFullFlush
+		// Overwrite agent metric_batch_full_flush if this plugin has its own.
+		if output.Config.MetricBatchFullFlush != nil {
+			fullFlush = *output.Config.MetricBatchFullFlush
+		}
+
 		wg.Add(1)
 		go func(output *models.RunningOutput) {
 			defer wg.Done()
@@ -748,7 +927,7 @@ func (a *Agent) runOutputs(
 			ticker := NewRollingTicker(interval, jitter)
 			defer ticker.Stop()
 
-			a.flushLoop(ctx, output, ticker)
+			a.flushLoop(ctx, output, ticker, fullFlush)
 		}(output)
 	}
 
@@ -771,11 +950,13 @@ func (a *Agent) runOutputs(
 }
 
 // flushLoop runs an output's flush function periodically until the context is
-// done.
+// done.  With fullFlush the output is also flushed whenever a full batch is
+// buffered.
 func (a *Agent) flushLoop(
 	ctx context.Context,
 	output *models.RunningOutput,
 	ticker *RollingTicker,
+	fullFlush bool,
 ) {
 	logError := func(err error) {
 		if err != nil {
@@ -788,6 +969,12 @@ func (a *Agent) flushLoop(
 	watchForFlushSignal(flushRequested)
 	defer stopListeningForFlushSignal(flushRequested)
 
+	// A nil channel is never ready
+	var batchReady <-chan time.Time
+	if fullFlush {
+		batchReady = output.BatchReady
+	}
+
 	for {
 		// Favor shutdown over other methods.
 		select {
@@ -806,7 +993,7 @@ func (a *Agent) flushLoop(
 		case <-flushRequested:
 			ticker.Reset()
 			logError(a.flushOnce(output, ticker, output.Write))
-		case <-output.BatchReady:
+		case <-batchReady:
 			ticker.Reset()
 			logError(a.flushOnce(output, ticker, output.WriteBatch))
 		}
@@ -1,10 +1,17 @@
 package agent
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
 	_ "github.com/influxdata/telegraf/plugins/inputs/all"
 	_ "github.com/influxdata/telegraf/plugins/outputs/all"
 	"github.com/stretchr/testify/assert"
@@ -166,3 +173,127 @@ func TestWindow(t *testing.T) {
 		})
 	}
 }
+
+type failingServiceInput struct {
+	failures int
+	started  int
+	stopped  int
+}
+
+func (i *failingServiceInput) SampleConfig() string                { return "" }
+func (i *failingServiceInput) Description() string                 { return "" }
+func (i *failingServiceInput) Gather(_ telegraf.Accumulator) error { return nil }
+func (i *failingServiceInput) Start(_ telegraf.Accumulator) error {
+	if i.failures > 0 {
+		i.failures--
+		return errors.New("start failed")
+	}
+	i.started++
+	return nil
+}
+func (i *failingServiceInput) Stop() { i.stopped++ }
+
+func TestStartInputsStartupErrorBehavior(t *testing.T) {
+	a, err := NewAgent(config.NewConfig())
+	require.NoError(t, err)
+
+	newInput := func(behavior string) (*failingServiceInput, *models.RunningInput) {
+		input := &failingServiceInput{failures: 2}
+		return input, models.NewRunningInput(input, &models.InputConfig{
+			Name:                 "failing",
+			StartupErrorBehavior: behavior,
+		})
+	}
+
+	dst := make(chan telegraf.Metric, 10)
+
+	_, ri := newInput("error")
+	_, err = a.startInputs(dst, []*models.RunningInput{ri})
+	require.Error(t, err)
+
+	input, ri := newInput("ignore")
+	unit, err := a.startInputs(dst, []*models.RunningInput{ri})
+	require.NoError(t, err)
+	require.Len(t, unit.inputs, 0)
+	stopServiceInputs([]*models.RunningInput{ri})
+	require.Equal(t, 0, input.stopped)
+
+	input, ri = newInput("retry")
+	unit, err = a.startInputs(dst, []*models.RunningInput{ri})
+	require.NoError(t, err)
+	require.Len(t, unit.inputs, 1)
+	require.Contains(t, unit.pending, ri)
+
+	startupRetryMinBackoff = time.Millisecond
+	defer func() { startupRetryMinBackoff = time.Second }()
+	started := retryStartup(context.Background(), ri, "Starting", func() error {
+		return ri.Start(unit.pending[ri])
+	})
+	require.True(t, started)
+	require.Equal(t, 1, input.started)
+
+	stopServiceInputs(unit.inputs)
+	require.Equal(t, 1, input.stopped)
+}
+
+func TestRetryStartupCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ri := models.NewRunningInput(&failingServiceInput{}, &models.InputConfig{Name: "failing"})
+	called := false
+	ok := retryStartup(ctx, ri, "Starting", func() error {
+		called = true
+		return nil
+	})
+	require.False(t, ok)
+	require.False(t, called)
+}
+
+type parallelProcessor struct {
+	ordered bool
+}
+
+func (p *parallelProcessor) SampleConfig() string                                { return "" }
+func (p *parallelProcessor) Description() string                                 { return "" }
+func (p *parallelProcessor) Start(_ telegraf.Accumulator) error                  { return nil }
+func (p *parallelProcessor) Add(_ telegraf.Metric, _ telegraf.Accumulator) error { return nil }
+func (p *parallelProcessor) Stop() error                                         { return nil }
+func (p *parallelProcessor) PreserveOrder()                                      { p.ordered = true }
+
+func TestInitProcessorsPreserveOrder(t *testing.T) {
+	processor := &parallelProcessor{}
+	processors := models.RunningProcessors{
+		models.NewRunningProcessor(processor, &models.ProcessorConfig{Name: "parallel"}),
+	}
+
+	require.NoError(t, initProcessors(processors, false))
+	require.False(t, processor.ordered)
+
+	require.NoError(t, initProcessors(processors, true))
+	require.True(t, processor.ordered)
+}
+
+func TestLoadStatesIdenticalPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agent")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[inputs.tail]]
+  files = ["/var/log/syslog"]
+
+[[inputs.tail]]
+  files = ["/var/log/syslog"]
+`)))
+	c.Agent.Statefile = filepath.Join(dir, "telegraf.state")
+	require.Len(t, c.Inputs, 2)
+	require.Equal(t, c.Inputs[0].Config.ID, c.Inputs[1].Config.ID)
+
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+	states, err := a.loadStates()
+	require.NoError(t, err)
+	require.NotNil(t, states)
+}
@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// BenchConfig describes the synthetic metrics sent by Bench.
+type BenchConfig struct {
+	// Rate of the metrics per second, 0 sends them as fast as possible.
+	Rate float64
+	// Duration of the benchmark.
+	Duration time.Duration
+	// Series is the number of distinct series, each metric belongs to the
+	// next series in turn.
+	Series int
+	// Tags and Fields are the number of tags, besides the series tag, and
+	// fields of each metric.
+	Tags   int
+	Fields int
+}
+
+// BenchResult holds the measurements of a benchmark.
+type BenchResult struct {
+	// Elapsed is the time the metrics were generated for.
+	Elapsed time.Duration
+	// Generated is the number of metrics sent to the processors.
+	Generated int
+	// Mallocs and AllocBytes are the number of heap allocations and the
+	// allocated bytes during the benchmark.
+	Mallocs    uint64
+	AllocBytes uint64
+	// Latency is the average and maximum time from the generation of the
+	// metrics until they reach the outputs, Arrived is their number.
+	Arrived    int
+	Latency    time.Duration
+	MaxLatency time.Duration
+
+	Processors []BenchStage
+	Outputs    []BenchStage
+}
+
+// BenchStage holds the measurements of a single plugin.  For processors
+// Elapsed is the time spent in Add for Count metrics, for outputs the time
+// spent writing Count metrics.
+type BenchStage struct {
+	Name    string
+	Count   int64
+	Elapsed time.Duration
+}
+
+// Bench sends synthetic metrics through the processors to the outputs,
+// skipping inputs and aggregators, and measures the throughput, the
+// allocations and the time spent in each stage.
+func (a *Agent) Bench(ctx context.Context, cfg BenchConfig) (*BenchResult, error) {
+	if cfg.Rate < 0 || cfg.Duration <= 0 || cfg.Series < 1 || cfg.Tags < 0 || cfg.Fields < 1 {
+		return nil, fmt.Errorf("invalid benchmark %+v", cfg)
+	}
+
+	log.Printf("D! [agent] Initializing plugins")
+	if err := initProcessors(a.Config.Processors, a.Config.Agent.PreserveMetricOrder); err != nil {
+		return nil, err
+	}
+	if err := a.initOutputs(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("D! [agent] Connecting outputs")
+	outputC, ou, err := a.startOutputs(ctx, a.Config.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metrics leaving the processors pass a probe measuring their latency
+	// on the way to the outputs
+	probeC := make(chan telegraf.Metric, 100)
+	next := chan<- telegraf.Metric(probeC)
+	var pu []*processorUnit
+	if len(a.Config.Processors) != 0 {
+		next, pu, err = a.startProcessors(probeC, a.Config.Processors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BenchResult{}
+	stages := make([]BenchStage, len(pu))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.runOutputs(ou)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		probeLatency(probeC, outputC, result)
+	}()
+
+	if pu != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			benchProcessors(pu, stages)
+		}()
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	result.Generated = generateMetrics(ctx, cfg, next)
+	result.Elapsed = time.Since(start)
+	close(next)
+	wg.Wait()
+	runtime.ReadMemStats(&after)
+
+	result.Mallocs = after.Mallocs - before.Mallocs
+	result.AllocBytes = after.TotalAlloc - before.TotalAlloc
+
+	// The processors were started from last to first
+	for i := len(stages) - 1; i >= 0; i-- {
+		result.Processors = append(result.Processors, stages[i])
+	}
+	for _, output := range a.Config.Outputs {
+		written := output.MetricsWritten()
+		result.Outputs = append(result.Outputs, BenchStage{
+			Name:  output.LogName(),
+			Count: written,
+			// WriteTime is the average time of the writes
+			Elapsed: time.Duration(output.WriteTime.Get()),
+		})
+	}
+	return result, nil
+}
+
+// generateMetrics sends metrics of the configured shape to dst at the
+// configured rate until the duration elapsed or the context is done.  It
+// returns the number of metrics sent.
+func generateMetrics(ctx context.Context, cfg BenchConfig, dst chan<- telegraf.Metric) int {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	tags := make([]map[string]string, cfg.Series)
+	for i := range tags {
+		tags[i] = map[string]string{"series": strconv.Itoa(i)}
+		for j := 0; j < cfg.Tags; j++ {
+			tags[i]["tag"+strconv.Itoa(j)] = "value" + strconv.Itoa(i)
+		}
+	}
+	fieldNames := make([]string, cfg.Fields)
+	for i := range fieldNames {
+		fieldNames[i] = "field" + strconv.Itoa(i)
+	}
+
+	start := time.Now()
+	var count int
+	for {
+		if cfg.Rate > 0 {
+			// Wait until the next metric is due, sending metrics in bursts
+			// instead of sleeping for every single metric
+			due := start.Add(time.Duration(float64(count) / cfg.Rate * float64(time.Second)))
+			if wait := time.Until(due); wait > time.Millisecond {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return count
+				}
+			}
+		}
+
+		fields := make(map[string]interface{}, len(fieldNames))
+		for i, name := range fieldNames {
+			fields[name] = float64(count + i)
+		}
+		m := metric.New("bench", tags[count%len(tags)], fields, time.Now())
+
+		select {
+		case dst <- m:
+			count++
+		case <-ctx.Done():
+			return count
+		}
+	}
+}
+
+// probeLatency forwards the metrics from src to dst, recording the time
+// since the metrics were generated.  The latency is wrong for processors
+// changing the timestamp of the metrics.
+func probeLatency(src <-chan telegraf.Metric, dst chan<- telegraf.Metric, result *BenchResult) {
+	var total time.Duration
+	for m := range src {
+		latency := time.Since(m.Time())
+		total += latency
+		if latency > result.MaxLatency {
+			result.MaxLatency = latency
+		}
+		result.Arrived++
+		dst <- m
+	}
+	close(dst)
+
+	if result.Arrived > 0 {
+		result.Latency = total / time.Duration(result.Arrived)
+	}
+}
+
+// benchProcessors is a variation of runProcessors measuring the time spent
+// in each processor.
+func benchProcessors(units []*processorUnit, stages []BenchStage) {
+	var wg sync.WaitGroup
+	for i, unit := range units {
+		wg.Add(1)
+		go func(unit *processorUnit, stage *BenchStage) {
+			defer wg.Done()
+
+			stage.Name = unit.processor.LogName()
+			acc := NewAccumulator(unit.processor, unit.dst)
+			for m := range unit.src {
+				start := time.Now()
+				if err := unit.processor.Add(m, acc); err != nil {
+					acc.AddError(err)
+					m.Drop()
+				}
+				stage.Elapsed += time.Since(start)
+				stage.Count++
+			}
+			unit.processor.Stop()
+			close(unit.dst)
+		}(unit, &stages[i])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBench(t *testing.T) {
+	output := &recordingOutput{}
+
+	c := config.NewConfig()
+	c.Outputs = append(c.Outputs, models.NewRunningOutput(output, &models.OutputConfig{Name: "bench_recording"}, 1000, 10000))
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+
+	cfg := BenchConfig{Rate: 1000, Duration: 200 * time.Millisecond, Series: 10, Tags: 1, Fields: 2}
+	result, err := a.Bench(context.Background(), cfg)
+	require.NoError(t, err)
+
+	require.InDelta(t, 200, result.Generated, 50)
+	require.Equal(t, result.Generated, result.Arrived)
+	require.Len(t, output.metrics, result.Generated)
+	require.Len(t, result.Outputs, 1)
+	require.Equal(t, int64(result.Generated), result.Outputs[0].Count)
+
+	m := output.metrics[11]
+	require.Equal(t, "bench", m.Name())
+	require.Equal(t, map[string]string{"series": "1", "tag0": "value1"}, m.Tags())
+	require.Len(t, m.FieldList(), 2)
+}
+
+func TestBenchInvalid(t *testing.T) {
+	a, err := NewAgent(config.NewConfig())
+	require.NoError(t, err)
+	_, err = a.Bench(context.Background(), BenchConfig{Duration: time.Second})
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/parsers/influx"
+)
+
+// Replay reads metrics in line protocol from the reader and writes them
+// through the processors to the outputs, skipping inputs and aggregators.
+//
+// The delay between two metrics is the difference of their timestamps
+// divided by speed; a speed of 1 replays the metrics at their original pace.
+// If speed is zero the metrics are replayed as fast as possible.
+func (a *Agent) Replay(ctx context.Context, r io.Reader, speed float64) error {
+	if speed < 0 {
+		return fmt.Errorf("invalid replay speed %v", speed)
+	}
+
+	log.Printf("D! [agent] Initializing plugins")
+	if err := initProcessors(a.Config.Processors, a.Config.Agent.PreserveMetricOrder); err != nil {
+		return err
+	}
+	if err := a.initOutputs(); err != nil {
+		return err
+	}
+
+	log.Printf("D! [agent] Connecting outputs")
+	next, ou, err := a.startOutputs(ctx, a.Config.Outputs)
+	if err != nil {
+		return err
+	}
+
+	var pu []*processorUnit
+	if len(a.Config.Processors) != 0 {
+		next, pu, err = a.startProcessors(next, a.Config.Processors)
+		if err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.runOutputs(ou)
+	}()
+
+	if pu != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.runProcessors(pu)
+		}()
+	}
+
+	count, err := replayMetrics(ctx, r, speed, next)
+	close(next)
+	wg.Wait()
+
+	log.Printf("I! [agent] Replayed %d metrics", count)
+	if err != nil {
+		return err
+	}
+
+	unsent := 0
+	for _, output := range a.Config.Outputs {
+		unsent += output.BufferLength()
+	}
+	if unsent != 0 {
+		return fmt.Errorf("output plugins unable to send %d metrics", unsent)
+	}
+	return nil
+}
+
+// replayMetrics parses the metrics from the reader and sends them to dst,
+// keeping the pace given by the speed.  It returns the number of metrics
+// sent.
+func replayMetrics(
+	ctx context.Context,
+	r io.Reader,
+	speed float64,
+	dst chan<- telegraf.Metric,
+) (int, error) {
+	parser := influx.NewStreamParser(r)
+
+	var count int
+	var last time.Time
+	for {
+		m, err := parser.Next()
+		if err == influx.EOF {
+			return count, nil
+		}
+		if err != nil {
+			if parseErr, ok := err.(*influx.ParseError); ok {
+				log.Printf("E! [agent] Skipping metric: %v", parseErr)
+				continue
+			}
+			return count, err
+		}
+
+		// Metrics older than the newest one replayed so far are sent
+		// without delay.
+		if speed > 0 && m.Time().After(last) {
+			if !last.IsZero() {
+				delay := time.Duration(float64(m.Time().Sub(last)) / speed)
+				if err := internal.SleepContext(ctx, delay); err != nil {
+					return count, err
+				}
+			}
+			last = m.Time()
+		}
+
+		select {
+		case dst <- m:
+			count++
+		case <-ctx.Done():
+			return count, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const replayData = `cpu,host=a value=1 1600000000000000000
+not line protocol
+cpu,host=b value=2 1600000000100000000
+cpu,host=c value=3 1600000000050000000
+`
+
+type recordingOutput struct {
+	sync.Mutex
+	metrics []telegraf.Metric
+}
+
+func (o *recordingOutput) SampleConfig() string { return "" }
+func (o *recordingOutput) Description() string  { return "" }
+func (o *recordingOutput) Connect() error       { return nil }
+func (o *recordingOutput) Close() error         { return nil }
+func (o *recordingOutput) Write(metrics []telegraf.Metric) error {
+	o.Lock()
+	defer o.Unlock()
+	o.metrics = append(o.metrics, metrics...)
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	output := &recordingOutput{}
+
+	c := config.NewConfig()
+	c.Outputs = append(c.Outputs, models.NewRunningOutput(output, &models.OutputConfig{Name: "recording"}, 1000, 10000))
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Replay(context.Background(), strings.NewReader(replayData), 0))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 1600000000000000000)),
+		testutil.MustMetric("cpu", map[string]string{"host": "b"}, map[string]interface{}{"value": 2.0}, time.Unix(0, 1600000000100000000)),
+		testutil.MustMetric("cpu", map[string]string{"host": "c"}, map[string]interface{}{"value": 3.0}, time.Unix(0, 1600000000050000000)),
+	}
+	testutil.RequireMetricsEqual(t, expected, output.metrics)
+}
+
+func TestReplayInvalidSpeed(t *testing.T) {
+	a, err := NewAgent(config.NewConfig())
+	require.NoError(t, err)
+	require.Error(t, a.Replay(context.Background(), strings.NewReader(replayData), -1))
+}
+
+func TestReplayMetricsSpeed(t *testing.T) {
+	dst := make(chan telegraf.Metric, 10)
+
+	// 100ms between the first and the newest metric at a speed of 2
+	start := time.Now()
+	count, err := replayMetrics(context.Background(), strings.NewReader(replayData), 2, dst)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
+
+func TestReplayMetricsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dst := make(chan telegraf.Metric)
+	count, err := replayMetrics(ctx, strings.NewReader(replayData), 1, dst)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 0, count)
+}
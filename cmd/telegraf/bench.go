@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/influxdata/telegraf/agent"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/outputs/discard"
+)
+
+// runBench implements the bench command:
+//
+//	telegraf [--config <file>] bench [--rate <n>] [--duration <d>]
+//	    [--series <n>] [--tags <n>] [--fields <n>] [--null]
+//
+// Synthetic metrics are written through the configured processors to the
+// outputs, or to no output at all with --null, and the throughput, the
+// allocations and the time spent in each plugin are reported.
+func runBench(args []string, outputFilters []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	rate := flags.Float64("rate", 0, "metrics per second, 0 sends them as fast as possible")
+	duration := flags.Duration("duration", 10*time.Second, "duration of the benchmark")
+	series := flags.Int("series", 1000, "number of distinct series")
+	tags := flags.Int("tags", 2, "number of tags of each metric besides the series tag")
+	fields := flags.Int("fields", 4, "number of fields of each metric")
+	null := flags.Bool("null", false, "discard the metrics instead of writing them to the outputs")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	c, err := loadConfig(nil, outputFilters)
+	if err != nil {
+		return err
+	}
+	if *null || len(c.Outputs) == 0 {
+		c.Outputs = []*models.RunningOutput{
+			models.NewRunningOutput(&discard.Discard{}, &models.OutputConfig{Name: "discard"},
+				c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit),
+		}
+	}
+
+	ag, err := agent.NewAgent(c)
+	if err != nil {
+		return err
+	}
+	setupLogging(c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	cfg := agent.BenchConfig{
+		Rate:     *rate,
+		Duration: *duration,
+		Series:   *series,
+		Tags:     *tags,
+		Fields:   *fields,
+	}
+	log.Printf("I! Benchmarking processors: %s", strings.Join(c.ProcessorNames(), " "))
+	log.Printf("I! Benchmarking outputs: %s", strings.Join(c.OutputNames(), " "))
+	result, err := ag.Bench(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	printBenchResult(cfg, result)
+	return nil
+}
+
+func printBenchResult(cfg agent.BenchConfig, r *agent.BenchResult) {
+	perSecond := func(n int64) float64 {
+		return float64(n) / r.Elapsed.Seconds()
+	}
+
+	fmt.Printf("Metrics with %d tags and %d fields in %d series for %s\n",
+		cfg.Tags+1, cfg.Fields, cfg.Series, r.Elapsed.Round(time.Millisecond))
+	fmt.Printf("  generated:   %d metrics, %.1f/s\n", r.Generated, perSecond(int64(r.Generated)))
+	if r.Generated > 0 {
+		fmt.Printf("  allocations: %.1f allocs, %.0f bytes per metric\n",
+			float64(r.Mallocs)/float64(r.Generated), float64(r.AllocBytes)/float64(r.Generated))
+	}
+	fmt.Printf("  latency:     %s average, %s maximum until %d metrics reached the outputs\n",
+		r.Latency, r.MaxLatency, r.Arrived)
+
+	for _, p := range r.Processors {
+		var avg time.Duration
+		if p.Count > 0 {
+			avg = p.Elapsed / time.Duration(p.Count)
+		}
+		fmt.Printf("  %s: %d metrics, %s per metric\n", p.Name, p.Count, avg)
+	}
+	for _, o := range r.Outputs {
+		fmt.Printf("  %s: %d metrics written, %.1f/s, %s per write\n",
+			o.Name, o.Count, perSecond(o.Count), o.Elapsed)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+// pluginCategory describes the registered plugins of one kind.
+type pluginCategory struct {
+	name         string
+	names        func() []string
+	deprecations map[string]telegraf.DeprecationInfo
+	printConfig  func(name string) error
+}
+
+var pluginCategories = []pluginCategory{
+	{
+		name: "inputs",
+		names: func() []string {
+			names := make([]string, 0, len(inputs.Inputs))
+			for name := range inputs.Inputs {
+				names = append(names, name)
+			}
+			return names
+		},
+		deprecations: inputs.Deprecations,
+		printConfig:  config.PrintInputConfig,
+	},
+	{
+		name: "outputs",
+		names: func() []string {
+			names := make([]string, 0, len(outputs.Outputs))
+			for name := range outputs.Outputs {
+				names = append(names, name)
+			}
+			return names
+		},
+		deprecations: outputs.Deprecations,
+		printConfig:  config.PrintOutputConfig,
+	},
+	{
+		name: "processors",
+		names: func() []string {
+			names := make([]string, 0, len(processors.Processors))
+			for name := range processors.Processors {
+				names = append(names, name)
+			}
+			return names
+		},
+		deprecations: processors.Deprecations,
+		printConfig:  config.PrintProcessorConfig,
+	},
+	{
+		name: "aggregators",
+		names: func() []string {
+			names := make([]string, 0, len(aggregators.Aggregators))
+			for name := range aggregators.Aggregators {
+				names = append(names, name)
+			}
+			return names
+		},
+		deprecations: aggregators.Deprecations,
+		printConfig:  config.PrintAggregatorConfig,
+	},
+}
+
+func findPluginCategory(name string) (pluginCategory, error) {
+	names := make([]string, 0, len(pluginCategories))
+	for _, category := range pluginCategories {
+		if category.name == name {
+			return category, nil
+		}
+		names = append(names, category.name)
+	}
+	return pluginCategory{}, fmt.Errorf("unknown plugin category %q, must be one of: %s",
+		name, strings.Join(names, ", "))
+}
+
+// runPlugins implements the plugins command:
+//
+//	telegraf plugins                    list the plugins of all categories
+//	telegraf plugins <category>         list the plugins of a category
+//	telegraf plugins <category> <name>  print the sample config of a plugin
+//
+// Plugins are listed one per line; deprecated plugins are followed by a tab
+// and their deprecation notice.
+func runPlugins(args []string) error {
+	switch len(args) {
+	case 0:
+		for i, category := range pluginCategories {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s:\n", category.name)
+			printPluginList(category, "  ")
+		}
+		return nil
+	case 1:
+		category, err := findPluginCategory(args[0])
+		if err != nil {
+			return err
+		}
+		printPluginList(category, "")
+		return nil
+	case 2:
+		category, err := findPluginCategory(args[0])
+		if err != nil {
+			return err
+		}
+		if info, ok := category.deprecations[args[1]]; ok {
+			fmt.Printf("# %s\n", deprecationNotice(info))
+		}
+		return category.printConfig(args[1])
+	default:
+		return fmt.Errorf("too many arguments, usage: telegraf plugins [<category> [<name>]]")
+	}
+}
+
+func printPluginList(category pluginCategory, indent string) {
+	names := category.names()
+	sort.Strings(names)
+	for _, name := range names {
+		if info, ok := category.deprecations[name]; ok {
+			fmt.Printf("%s%s\t%s\n", indent, name, deprecationNotice(info))
+			continue
+		}
+		fmt.Printf("%s%s\n", indent, name)
+	}
+}
+
+func deprecationNotice(info telegraf.DeprecationInfo) string {
+	notice := "DEPRECATED since " + info.Since
+	if info.RemovalIn != "" {
+		notice += ", removal in " + info.RemovalIn
+	}
+	return notice + ": " + info.Notice
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/influxdata/telegraf/agent"
+)
+
+// runReplay implements the replay command:
+//
+//	telegraf [--config <file>] replay [--speed <factor>] <file>
+//
+// The metrics are read in line protocol from the file, or stdin for "-", and
+// written through the configured processors to the outputs.
+func runReplay(args []string, outputFilters []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := flags.Float64("speed", 1,
+		"factor to speed up the original pace of the metrics, 0 replays as fast as possible")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: telegraf replay [--speed <factor>] <file>")
+	}
+
+	c, err := loadConfig(nil, outputFilters)
+	if err != nil {
+		return err
+	}
+	if len(c.Outputs) == 0 {
+		return errors.New("Error: no outputs found, did you provide a valid config file?")
+	}
+
+	ag, err := agent.NewAgent(c)
+	if err != nil {
+		return err
+	}
+	setupLogging(c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var r io.Reader = os.Stdin
+	if filename := flags.Arg(0); filename != "-" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("opening metrics file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	log.Printf("I! Replaying metrics to outputs: %s", strings.Join(c.OutputNames(), " "))
+	return ag.Replay(ctx, r, *speed)
+}
@@ -55,6 +55,8 @@ var fConfigs sliceFlags
 var fConfigDirs sliceFlags
 
 var fVersion = flag.Bool("version", false, "display the version and exit")
+var fStrictDeprecations = flag.Bool("strict-deprecations", false,
+	"fail if the config uses deprecated plugins or options")
 var fSampleConfig = flag.Bool("sample-config", false,
 	"print out full sample configuration")
 var fPidfile = flag.String("pidfile", "", "file to write our pid to")
@@ -143,29 +145,9 @@ func runAgent(ctx context.Context,
 	log.Printf("I! Starting Telegraf %s", version)
 
 	// If no other options are specified, load the config file and run.
-	c := config.NewConfig()
-	c.OutputFilters = outputFilters
-	c.InputFilters = inputFilters
-	var err error
-	// providing no "config" flag should load default config
-	if len(fConfigs) == 0 {
-		err = c.LoadConfig("")
-		if err != nil {
-			return err
-		}
-	}
-	for _, fConfig := range fConfigs {
-		err = c.LoadConfig(fConfig)
-		if err != nil {
-			return err
-		}
-	}
-
-	for _, fConfigDirectory := range fConfigDirs {
-		err = c.LoadDirectory(fConfigDirectory)
-		if err != nil {
-			return err
-		}
+	c, err := loadConfig(inputFilters, outputFilters)
+	if err != nil {
+		return err
 	}
 
 	if !*fTest && len(c.Outputs) == 0 {
@@ -188,20 +170,7 @@ func runAgent(ctx context.Context,
 		return err
 	}
 
-	// Setup logging as configured.
-	telegraf.Debug = ag.Config.Agent.Debug || *fDebug
-	logConfig := logger.LogConfig{
-		Debug:               telegraf.Debug,
-		Quiet:               ag.Config.Agent.Quiet || *fQuiet,
-		LogTarget:           ag.Config.Agent.LogTarget,
-		Logfile:             ag.Config.Agent.Logfile,
-		RotationInterval:    ag.Config.Agent.LogfileRotationInterval,
-		RotationMaxSize:     ag.Config.Agent.LogfileRotationMaxSize,
-		RotationMaxArchives: ag.Config.Agent.LogfileRotationMaxArchives,
-		LogWithTimezone:     ag.Config.Agent.LogWithTimezone,
-	}
-
-	logger.SetupLogging(logConfig)
+	setupLogging(c)
 
 	if *fRunOnce {
 		wait := time.Duration(*fTestWait) * time.Second
@@ -240,6 +209,50 @@ func runAgent(ctx context.Context,
 	return ag.Run(ctx)
 }
 
+// loadConfig loads the configuration files given on the command line.
+func loadConfig(inputFilters []string, outputFilters []string) (*config.Config, error) {
+	c := config.NewConfig()
+	c.OutputFilters = outputFilters
+	c.InputFilters = inputFilters
+	c.StrictDeprecations = *fStrictDeprecations
+
+	// providing no "config" flag should load default config
+	if len(fConfigs) == 0 {
+		if err := c.LoadConfig(""); err != nil {
+			return nil, err
+		}
+	}
+	for _, fConfig := range fConfigs {
+		if err := c.LoadConfig(fConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fConfigDirectory := range fConfigDirs {
+		if err := c.LoadDirectory(fConfigDirectory); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// setupLogging sets up logging as configured.
+func setupLogging(c *config.Config) {
+	telegraf.Debug = c.Agent.Debug || *fDebug
+	logConfig := logger.LogConfig{
+		Debug:               telegraf.Debug,
+		Quiet:               c.Agent.Quiet || *fQuiet,
+		LogTarget:           c.Agent.LogTarget,
+		Logfile:             c.Agent.Logfile,
+		RotationInterval:    c.Agent.LogfileRotationInterval,
+		RotationMaxSize:     c.Agent.LogfileRotationMaxSize,
+		RotationMaxArchives: c.Agent.LogfileRotationMaxArchives,
+		LogWithTimezone:     c.Agent.LogWithTimezone,
+	}
+
+	logger.SetupLogging(logConfig)
+}
+
 func usageExit(rc int) {
 	fmt.Println(internal.Usage)
 	os.Exit(rc)
@@ -336,6 +349,21 @@ func main() {
 				processorFilters,
 			)
 			return
+		case "plugins":
+			if err := runPlugins(args[1:]); err != nil {
+				log.Fatal("E! " + err.Error())
+			}
+			return
+		case "bench":
+			if err := runBench(args[1:], outputFilters); err != nil {
+				log.Fatal("E! " + err.Error())
+			}
+			return
+		case "replay":
+			if err := runReplay(args[1:], outputFilters); err != nil {
+				log.Fatal("E! " + err.Error())
+			}
+			return
 		}
 	}
 
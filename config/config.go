@@ -20,11 +20,14 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/internal/host"
+	"github.com/influxdata/telegraf/internal/resolver"
 	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/parsers/binary"
 	"github.com/influxdata/telegraf/plugins/parsers/json_v2"
 	"github.com/influxdata/telegraf/plugins/processors"
 	"github.com/influxdata/telegraf/plugins/serializers"
@@ -77,6 +80,12 @@ type Config struct {
 	// Processors have a slice wrapper type because they need to be sorted
 	Processors    models.RunningProcessors
 	AggProcessors models.RunningProcessors
+
+	// Deprecations lists the deprecated plugins and options in use.
+	Deprecations []Deprecation
+	// StrictDeprecations turns the use of deprecated plugins and options
+	// into an error.
+	StrictDeprecations bool
 }
 
 // NewConfig creates a new struct to hold the Telegraf config.
@@ -91,6 +100,7 @@ func NewConfig() *Config {
 			Interval:                   Duration(10 * time.Second),
 			RoundInterval:              true,
 			FlushInterval:              Duration(10 * time.Second),
+			MetricBatchFullFlush:       true,
 			LogTarget:                  "file",
 			LogfileRotationMaxArchives: 5,
 		},
@@ -157,15 +167,23 @@ type AgentConfig struct {
 	// not be less than 2 times MetricBatchSize.
 	MetricBufferLimit int
 
+	// MetricBatchFullFlush flushes an output as soon as metric_batch_size
+	// metrics are buffered instead of waiting for the next flush interval.
+	MetricBatchFullFlush bool `toml:"metric_batch_full_flush"`
+
+	// OutputPrecision truncates the timestamps of the metrics written by the
+	// outputs, e.g. "1s" drops the sub-second part.  Disabled when zero.
+	OutputPrecision Duration `toml:"output_precision"`
+
 	// FlushBufferWhenFull tells Telegraf to flush the metric buffer whenever
 	// it fills up, regardless of FlushInterval. Setting this option to true
 	// does _not_ deactivate FlushInterval.
-	FlushBufferWhenFull bool // deprecated in 0.13; has no effect
+	FlushBufferWhenFull bool `deprecated:"0.13.0;2.0.0;option is ignored"`
 
 	// TODO(cam): Remove UTC and parameter, they are no longer
 	// valid for the agent config. Leaving them here for now for backwards-
 	// compatibility
-	UTC bool `toml:"utc"` // deprecated in 1.0.0; has no effect
+	UTC bool `toml:"utc" deprecated:"1.0.0;2.0.0;option is ignored"`
 
 	// Debug is the option for running in debug mode
 	Debug bool `toml:"debug"`
@@ -199,6 +217,27 @@ type AgentConfig struct {
 
 	Hostname     string
 	OmitHostname bool
+
+	// Paths of the host filesystems when running in a container with the
+	// host filesystem mounted, empty uses the HOST_PROC, HOST_SYS, HOST_ETC
+	// and HOST_MOUNT_PREFIX environment variables.
+	HostProc        string `toml:"host_proc"`
+	HostSys         string `toml:"host_sys"`
+	HostEtc         string `toml:"host_etc"`
+	HostMountPrefix string `toml:"host_mountprefix"`
+
+	// Caching of the addresses of the hosts outputs connect to, 0 resolves
+	// the hosts on every connection.
+	DNSCacheTTL        Duration `toml:"dns_cache_ttl"`
+	DNSRefreshInterval Duration `toml:"dns_refresh_interval"`
+
+	// PreserveMetricOrder makes processors which reorder metrics, like those
+	// doing parallel lookups, keep the metrics in the order of the inputs.
+	PreserveMetricOrder bool `toml:"preserve_metric_order"`
+
+	// Statefile is the file the states of the stateful plugins are stored in
+	// on shutdown and restored from on startup, empty disables persisting.
+	Statefile string `toml:"statefile"`
 }
 
 // InputNames returns a list of strings of the configured inputs.
@@ -310,6 +349,10 @@ var agentConfig = `
   ## This controls the size of writes that Telegraf sends to output plugins.
   metric_batch_size = 1000
 
+  ## Flush outputs as soon as metric_batch_size metrics are buffered instead
+  ## of waiting for the next flush_interval.
+  # metric_batch_full_flush = true
+
   ## Maximum number of unwritten metrics per output.  Increasing this value
   ## allows for longer periods of output downtime without dropping metrics at the
   ## cost of higher maximum memory usage.
@@ -338,6 +381,11 @@ var agentConfig = `
   ## Valid time units are "ns", "us" (or "µs"), "ms", "s".
   precision = ""
 
+  ## Truncate the timestamps of the metrics written by the outputs to the
+  ## given precision, e.g. "1s" to drop the sub-second part.  Can be overridden
+  ## with the output_precision option of each output.
+  # output_precision = "0s"
+
   ## Log at debug level.
   # debug = false
   ## Log only error level messages.
@@ -373,6 +421,34 @@ var agentConfig = `
   hostname = ""
   ## If set to true, do no set the "host" tag in the telegraf agent.
   omit_hostname = false
+
+  ## Paths of the host filesystems read by system inputs when running in a
+  ## container with the host filesystem mounted, e.g. at /hostfs.  If empty
+  ## the HOST_PROC, HOST_SYS, HOST_ETC and HOST_MOUNT_PREFIX environment
+  ## variables are used.
+  # host_proc = "/hostfs/proc"
+  # host_sys = "/hostfs/sys"
+  # host_etc = "/hostfs/etc"
+  # host_mountprefix = "/hostfs"
+
+  ## Cache the addresses of the hosts outputs and the prometheus input connect
+  ## to for dns_cache_ttl, 0 resolves the hosts on every connection.  The
+  ## cached hosts are re-resolved in the background every
+  ## dns_refresh_interval if set.
+  # dns_cache_ttl = "0s"
+  # dns_refresh_interval = "0s"
+
+  ## Keep the metrics of each input in the order they were gathered through
+  ## processors which otherwise emit metrics as soon as they are done, e.g.
+  ## the parallel lookups of the reverse_dns, ifname and aws_ec2 processors.
+  ## Needed by outputs writing to databases rejecting out-of-order points.
+  # preserve_metric_order = false
+
+  ## File storing the state of stateful plugins, like the file offsets of the
+  ## tail input, when Telegraf stops and restoring it on startup.  Plugins are
+  ## identified by their settings, so changing the settings of a plugin
+  ## discards its state.
+  # statefile = ""
 `
 
 var outputHeader = `
@@ -668,6 +744,26 @@ func PrintOutputConfig(name string) error {
 	return nil
 }
 
+// PrintProcessorConfig prints the config usage of a single processor.
+func PrintProcessorConfig(name string) error {
+	if creator, ok := processors.Processors[name]; ok {
+		printConfig(name, creator(), "processors", false)
+	} else {
+		return fmt.Errorf("Processor %s not found", name)
+	}
+	return nil
+}
+
+// PrintAggregatorConfig prints the config usage of a single aggregator.
+func PrintAggregatorConfig(name string) error {
+	if creator, ok := aggregators.Aggregators[name]; ok {
+		printConfig(name, creator(), "aggregators", false)
+	} else {
+		return fmt.Errorf("Aggregator %s not found", name)
+	}
+	return nil
+}
+
 // LoadDirectory loads all toml config files found in the specified path, recursively.
 func (c *Config) LoadDirectory(path string) error {
 	walkfn := func(thispath string, info os.FileInfo, _ error) error {
@@ -783,6 +879,14 @@ func (c *Config) LoadConfigData(data []byte) error {
 		if err = c.toml.UnmarshalTable(subTable, c.Agent); err != nil {
 			return fmt.Errorf("error parsing [agent]: %w", err)
 		}
+		if err = c.checkOptionDeprecations("agent", subTable, c.Agent); err != nil {
+			return err
+		}
+		// The plugins created below may resolve host paths on creation
+		if err = host.Configure(c.Agent.HostProc, c.Agent.HostSys, c.Agent.HostEtc, c.Agent.HostMountPrefix); err != nil {
+			return fmt.Errorf("error setting host paths: %w", err)
+		}
+		resolver.Configure(time.Duration(c.Agent.DNSCacheTTL), time.Duration(c.Agent.DNSRefreshInterval))
 	}
 
 	if !c.Agent.OmitHostname {
@@ -1017,6 +1121,13 @@ func (c *Config) addAggregator(name string, table *ast.Table) error {
 		return err
 	}
 
+	if err := c.checkPluginDeprecation("aggregators", name, aggregators.Deprecations); err != nil {
+		return err
+	}
+	if err := c.checkOptionDeprecations("aggregators."+name, table, aggregator); err != nil {
+		return err
+	}
+
 	c.Aggregators = append(c.Aggregators, models.NewRunningAggregator(aggregator, conf))
 	return nil
 }
@@ -1045,7 +1156,14 @@ func (c *Config) addProcessor(name string, table *ast.Table) error {
 	}
 	c.AggProcessors = append(c.AggProcessors, rf)
 
-	return nil
+	if err := c.checkPluginDeprecation("processors", name, processors.Deprecations); err != nil {
+		return err
+	}
+	var processor interface{} = rf.Processor
+	if p, ok := processor.(unwrappable); ok {
+		processor = p.Unwrap()
+	}
+	return c.checkOptionDeprecations("processors."+name, table, processor)
 }
 
 func (c *Config) newRunningProcessor(
@@ -1099,6 +1217,17 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 		return err
 	}
 
+	if err := c.checkPluginDeprecation("outputs", name, outputs.Deprecations); err != nil {
+		return err
+	}
+	if err := c.checkOptionDeprecations("outputs."+name, table, output); err != nil {
+		return err
+	}
+
+	if outputConfig.Precision == 0 {
+		outputConfig.Precision = time.Duration(c.Agent.OutputPrecision)
+	}
+
 	ro := models.NewRunningOutput(output, outputConfig, c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
 	c.Outputs = append(c.Outputs, ro)
 	return nil
@@ -1148,6 +1277,13 @@ func (c *Config) addInput(name string, table *ast.Table) error {
 		return err
 	}
 
+	if err := c.checkPluginDeprecation("inputs", name, inputs.Deprecations); err != nil {
+		return err
+	}
+	if err := c.checkOptionDeprecations("inputs."+name, table, input); err != nil {
+		return err
+	}
+
 	rp := models.NewRunningInput(input, pluginConfig)
 	rp.SetDefaultTags(c.Tags)
 	c.Inputs = append(c.Inputs, rp)
@@ -1169,11 +1305,19 @@ func (c *Config) buildAggregator(name string, tbl *ast.Table) (*models.Aggregato
 	c.getFieldDuration(tbl, "delay", &conf.Delay)
 	c.getFieldDuration(tbl, "grace", &conf.Grace)
 	c.getFieldBool(tbl, "drop_original", &conf.DropOriginal)
+	c.getFieldStringSlice(tbl, "drop_original_namepass", &conf.DropOriginalNamePass)
+	c.getFieldStringSlice(tbl, "drop_original_namedrop", &conf.DropOriginalNameDrop)
 	c.getFieldString(tbl, "name_prefix", &conf.MeasurementPrefix)
 	c.getFieldString(tbl, "name_suffix", &conf.MeasurementSuffix)
 	c.getFieldString(tbl, "name_override", &conf.NameOverride)
 	c.getFieldString(tbl, "alias", &conf.Alias)
 
+	var err error
+	conf.ID, err = pluginID("aggregators."+name, tbl)
+	if err != nil {
+		return nil, err
+	}
+
 	conf.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
 		if subtbl, ok := node.(*ast.Table); ok {
@@ -1187,7 +1331,6 @@ func (c *Config) buildAggregator(name string, tbl *ast.Table) (*models.Aggregato
 		return nil, c.firstErr()
 	}
 
-	var err error
 	conf.Filter, err = c.buildFilter(tbl)
 	if err != nil {
 		return conf, err
@@ -1209,6 +1352,11 @@ func (c *Config) buildProcessor(name string, tbl *ast.Table) (*models.ProcessorC
 	}
 
 	var err error
+	conf.ID, err = pluginID("processors."+name, tbl)
+	if err != nil {
+		return nil, err
+	}
+
 	conf.Filter, err = c.buildFilter(tbl)
 	if err != nil {
 		return conf, err
@@ -1261,6 +1409,15 @@ func (c *Config) buildInput(name string, tbl *ast.Table) (*models.InputConfig, e
 	c.getFieldString(tbl, "name_suffix", &cp.MeasurementSuffix)
 	c.getFieldString(tbl, "name_override", &cp.NameOverride)
 	c.getFieldString(tbl, "alias", &cp.Alias)
+	c.getFieldString(tbl, "startup_error_behavior", &cp.StartupErrorBehavior)
+	c.getFieldInt(tbl, "max_series", &cp.MaxSeries)
+	c.getFieldString(tbl, "series_limit_policy", &cp.SeriesLimitPolicy)
+
+	var err error
+	cp.ID, err = pluginID("inputs."+name, tbl)
+	if err != nil {
+		return nil, err
+	}
 
 	cp.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
@@ -1275,7 +1432,18 @@ func (c *Config) buildInput(name string, tbl *ast.Table) (*models.InputConfig, e
 		return nil, c.firstErr()
 	}
 
-	var err error
+	switch cp.StartupErrorBehavior {
+	case "", "error", "ignore", "retry":
+	default:
+		return nil, fmt.Errorf("invalid startup_error_behavior %q for input %s", cp.StartupErrorBehavior, name)
+	}
+
+	switch cp.SeriesLimitPolicy {
+	case "", "drop", "evict":
+	default:
+		return nil, fmt.Errorf("invalid series_limit_policy %q for input %s", cp.SeriesLimitPolicy, name)
+	}
+
 	cp.Filter, err = c.buildFilter(tbl)
 	if err != nil {
 		return cp, err
@@ -1367,11 +1535,57 @@ func (c *Config) getParserConfig(name string, tbl *ast.Table) (*parsers.Config,
 	c.getFieldInt(tbl, "csv_skip_columns", &pc.CSVSkipColumns)
 	c.getFieldBool(tbl, "csv_trim_space", &pc.CSVTrimSpace)
 	c.getFieldStringSlice(tbl, "csv_skip_values", &pc.CSVSkipValues)
+	c.getFieldInt(tbl, "csv_metadata_rows", &pc.CSVMetadataRows)
+	c.getFieldStringSlice(tbl, "csv_metadata_separators", &pc.CSVMetadataSeparators)
+	c.getFieldString(tbl, "csv_metadata_trim_set", &pc.CSVMetadataTrimSet)
+	c.getFieldString(tbl, "csv_reset_mode", &pc.CSVResetMode)
 
 	c.getFieldStringSlice(tbl, "form_urlencoded_tag_keys", &pc.FormUrlencodedTagKeys)
 
 	c.getFieldString(tbl, "value_field_name", &pc.ValueFieldName)
 
+	//for avro parser
+	c.getFieldString(tbl, "avro_schema_registry", &pc.AvroSchemaRegistry)
+	c.getFieldString(tbl, "avro_schema", &pc.AvroSchema)
+	c.getFieldString(tbl, "avro_measurement_field", &pc.AvroMeasurementField)
+	c.getFieldStringSlice(tbl, "avro_tags", &pc.AvroTags)
+	c.getFieldStringSlice(tbl, "avro_fields", &pc.AvroFields)
+	c.getFieldString(tbl, "avro_timestamp", &pc.AvroTimestamp)
+	c.getFieldString(tbl, "avro_timestamp_format", &pc.AvroTimestampFormat)
+	c.getFieldString(tbl, "avro_field_separator", &pc.AvroFieldSeparator)
+
+	//for binary parser
+	if pc.DataFormat == "binary" {
+		c.getFieldString(tbl, "binary_endianness", &pc.BinaryEndianness)
+
+		if node, ok := tbl.Fields["binary"]; ok {
+			if subtbls, ok := node.([]*ast.Table); ok {
+				pc.BinaryConfig = make([]parsers.BinaryConfig, len(subtbls))
+				for i, subtbl := range subtbls {
+					subcfg := pc.BinaryConfig[i]
+					c.getFieldString(subtbl, "metric_name", &subcfg.MetricName)
+
+					if node, ok := subtbl.Fields["entries"]; ok {
+						if entrytbls, ok := node.([]*ast.Table); ok {
+							for _, entrytbl := range entrytbls {
+								var e binary.Entry
+								c.getFieldString(entrytbl, "name", &e.Name)
+								c.getFieldString(entrytbl, "type", &e.Type)
+								c.getFieldInt(entrytbl, "offset", &e.Offset)
+								c.getFieldInt(entrytbl, "length", &e.Length)
+								c.getFieldString(entrytbl, "endianness", &e.Endianness)
+								c.getFieldString(entrytbl, "assignment", &e.Assignment)
+								c.getFieldString(entrytbl, "format", &e.Format)
+								subcfg.Entries = append(subcfg.Entries, e)
+							}
+						}
+					}
+					pc.BinaryConfig[i] = subcfg
+				}
+			}
+		}
+	}
+
 	//for XPath parser family
 	if choice.Contains(pc.DataFormat, []string{"xml", "xpath_json", "xpath_msgpack", "xpath_protobuf"}) {
 		c.getFieldString(tbl, "xpath_protobuf_file", &pc.XPathProtobufFile)
@@ -1504,6 +1718,12 @@ func (c *Config) buildSerializer(tbl *ast.Table) (serializers.Serializer, error)
 	c.getFieldString(tbl, "graphite_separator", &sc.GraphiteSeparator)
 
 	c.getFieldDuration(tbl, "json_timestamp_units", &sc.TimestampUnits)
+	c.getFieldString(tbl, "json_timestamp_format", &sc.JSONTimestampFormat)
+	c.getFieldString(tbl, "json_batch_format", &sc.JSONBatchFormat)
+	c.getFieldString(tbl, "json_name_path", &sc.JSONNamePath)
+	c.getFieldString(tbl, "json_tags_path", &sc.JSONTagsPath)
+	c.getFieldString(tbl, "json_fields_path", &sc.JSONFieldsPath)
+	c.getFieldString(tbl, "json_timestamp_path", &sc.JSONTimestampPath)
 
 	c.getFieldBool(tbl, "splunkmetric_hec_routing", &sc.HecRouting)
 	c.getFieldBool(tbl, "splunkmetric_multimetric", &sc.SplunkmetricMultiMetric)
@@ -1531,8 +1751,13 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 	if err != nil {
 		return nil, err
 	}
+	id, err := pluginID("outputs."+name, tbl)
+	if err != nil {
+		return nil, err
+	}
 	oc := &models.OutputConfig{
 		Name:   name,
+		ID:     id,
 		Filter: filter,
 	}
 
@@ -1540,9 +1765,15 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 
 	c.getFieldDuration(tbl, "flush_interval", &oc.FlushInterval)
 	c.getFieldDuration(tbl, "flush_jitter", &oc.FlushJitter)
+	c.getFieldDuration(tbl, "output_precision", &oc.Precision)
 
 	c.getFieldInt(tbl, "metric_buffer_limit", &oc.MetricBufferLimit)
 	c.getFieldInt(tbl, "metric_batch_size", &oc.MetricBatchSize)
+	if _, ok := tbl.Fields["metric_batch_full_flush"]; ok {
+		var fullFlush bool
+		c.getFieldBool(tbl, "metric_batch_full_flush", &fullFlush)
+		oc.MetricBatchFullFlush = &fullFlush
+	}
 	c.getFieldString(tbl, "alias", &oc.Alias)
 	c.getFieldString(tbl, "name_override", &oc.NameOverride)
 	c.getFieldString(tbl, "name_suffix", &oc.NameSuffix)
@@ -1557,12 +1788,16 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 
 func (c *Config) missingTomlField(_ reflect.Type, key string) error {
 	switch key {
-	case "alias", "carbon2_format", "carbon2_sanitize_replace_char", "collectd_auth_file",
+	case "alias", "avro_field_separator", "avro_fields", "avro_measurement_field", "avro_schema",
+		"avro_schema_registry", "avro_tags", "avro_timestamp", "avro_timestamp_format", "binary",
+		"binary_endianness", "carbon2_format", "carbon2_sanitize_replace_char", "collectd_auth_file",
 		"collectd_parse_multivalue", "collectd_security_level", "collectd_typesdb", "collection_jitter",
 		"csv_column_names", "csv_column_types", "csv_comment", "csv_delimiter", "csv_header_row_count",
 		"csv_measurement_column", "csv_skip_columns", "csv_skip_rows", "csv_tag_columns",
 		"csv_timestamp_column", "csv_timestamp_format", "csv_timezone", "csv_trim_space", "csv_skip_values",
-		"data_format", "data_type", "delay", "drop", "drop_original", "dropwizard_metric_registry_path",
+		"csv_metadata_rows", "csv_metadata_separators", "csv_metadata_trim_set", "csv_reset_mode",
+		"data_format", "data_type", "delay", "drop", "drop_original", "drop_original_namedrop",
+		"drop_original_namepass", "dropwizard_metric_registry_path",
 		"dropwizard_tag_paths", "dropwizard_tags_path", "dropwizard_time_format", "dropwizard_time_path",
 		"fielddrop", "fieldpass", "flush_interval", "flush_jitter", "form_urlencoded_tag_keys",
 		"grace", "graphite_separator", "graphite_tag_sanitize_mode", "graphite_tag_support",
@@ -1570,10 +1805,13 @@ func (c *Config) missingTomlField(_ reflect.Type, key string) error {
 		"grok_timezone", "grok_unique_timestamp", "influx_max_line_bytes", "influx_sort_fields",
 		"influx_uint_support", "interval", "json_name_key", "json_query", "json_strict",
 		"json_string_fields", "json_time_format", "json_time_key", "json_timestamp_units", "json_timezone", "json_v2",
-		"metric_batch_size", "metric_buffer_limit", "name_override", "name_prefix",
-		"name_suffix", "namedrop", "namepass", "order", "pass", "period", "precision",
+		"json_batch_format", "json_fields_path", "json_name_path", "json_tags_path", "json_timestamp_format",
+		"json_timestamp_path", "max_series",
+		"metric_batch_full_flush", "metric_batch_size", "metric_buffer_limit", "name_override", "name_prefix",
+		"name_suffix", "namedrop", "namepass", "order", "output_precision", "pass", "period", "precision",
 		"prefix", "prometheus_export_timestamp", "prometheus_sort_metrics", "prometheus_string_as_label",
-		"separator", "splunkmetric_hec_routing", "splunkmetric_multimetric", "tag_keys",
+		"separator", "series_limit_policy", "splunkmetric_hec_routing", "splunkmetric_multimetric",
+		"startup_error_behavior", "tag_keys",
 		"tagdrop", "tagexclude", "taginclude", "tagpass", "tags", "template", "templates",
 		"value_field_name", "wavefront_source_override", "wavefront_use_strict",
 		"xml", "xpath", "xpath_json", "xpath_msgpack", "xpath_protobuf", "xpath_print_document",
@@ -54,9 +54,10 @@ func TestConfig_LoadSingleInputWithEnvVars(t *testing.T) {
 	}
 	inputConfig.Tags = make(map[string]string)
 
-	// Ignore Log and Parser
+	// Ignore Log, Parser and ID
 	c.Inputs[0].Input.(*MockupInputPlugin).Log = nil
 	c.Inputs[0].Input.(*MockupInputPlugin).parser = nil
+	c.Inputs[0].Config.ID = ""
 	require.Equal(t, input, c.Inputs[0].Input, "Testdata did not produce a correct mockup struct.")
 	require.Equal(t, inputConfig, c.Inputs[0].Config, "Testdata did not produce correct input metadata.")
 }
@@ -94,9 +95,10 @@ func TestConfig_LoadSingleInput(t *testing.T) {
 	}
 	inputConfig.Tags = make(map[string]string)
 
-	// Ignore Log and Parser
+	// Ignore Log, Parser and ID
 	c.Inputs[0].Input.(*MockupInputPlugin).Log = nil
 	c.Inputs[0].Input.(*MockupInputPlugin).parser = nil
+	c.Inputs[0].Config.ID = ""
 	require.Equal(t, input, c.Inputs[0].Input, "Testdata did not produce a correct memcached struct.")
 	require.Equal(t, inputConfig, c.Inputs[0].Config, "Testdata did not produce correct memcached metadata.")
 }
@@ -202,6 +204,10 @@ func TestConfig_LoadDirectory(t *testing.T) {
 			input.parser = nil
 		}
 
+		// Check the id and ignore it for comparison
+		require.NotEmpty(t, plugin.Config.ID)
+		plugin.Config.ID = ""
+
 		require.Equalf(t, expectedPlugins[i], plugin.Input, "Plugin %d: incorrect struct produced", i)
 		require.Equalf(t, expectedConfigs[i], plugin.Config, "Plugin %d: incorrect config produced", i)
 	}
@@ -288,6 +294,47 @@ func TestConfig_AzureMonitorNamespacePrefix(t *testing.T) {
 	}
 }
 
+func TestConfig_MetricBatchFullFlush(t *testing.T) {
+	c := NewConfig()
+	require.True(t, c.Agent.MetricBatchFullFlush)
+
+	require.NoError(t, c.LoadConfig("./testdata/batch_full_flush.toml"))
+	require.False(t, c.Agent.MetricBatchFullFlush)
+	require.Len(t, c.Outputs, 2)
+	require.Nil(t, c.Outputs[0].Config.MetricBatchFullFlush)
+	require.NotNil(t, c.Outputs[1].Config.MetricBatchFullFlush)
+	require.True(t, *c.Outputs[1].Config.MetricBatchFullFlush)
+}
+
+func TestConfig_OutputPrecision(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfig("./testdata/output_precision.toml"))
+	require.Len(t, c.Outputs, 2)
+	require.Equal(t, time.Second, c.Outputs[0].Config.Precision)
+	require.Equal(t, time.Millisecond, c.Outputs[1].Config.Precision)
+}
+
+func TestConfig_OutputLegacyPrecision(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.influxdb]]
+  precision = "s"
+
+[[outputs.amqp]]
+  precision = "s"
+  output_precision = "1ms"
+`)))
+	require.Len(t, c.Outputs, 2)
+
+	// The precision of these outputs is a string and left to the plugins
+	precisions := make(map[string]time.Duration)
+	for _, o := range c.Outputs {
+		require.Equal(t, "s", o.Output.(*MockupPrecisionOutputPlugin).Precision)
+		precisions[o.Config.Name] = o.Config.Precision
+	}
+	require.Equal(t, map[string]time.Duration{"influxdb": 0, "amqp": time.Millisecond}, precisions)
+}
+
 func TestConfig_URLRetries3Fails(t *testing.T) {
 	httpLoadConfigRetryInterval = 0 * time.Second
 	responseCounter := 0
@@ -376,6 +423,28 @@ func (m *MockupInputPlugin) Gather(acc telegraf.Accumulator) error { return nil
 func (m *MockupInputPlugin) SetParser(parser parsers.Parser)       { m.parser = parser }
 
 /*** Mockup OUTPUT plugin for testing to avoid cyclic dependencies ***/
+// MockupPrecisionOutputPlugin mimics outputs with a deprecated precision
+// setting of their own
+type MockupPrecisionOutputPlugin struct {
+	Precision string `toml:"precision"`
+}
+
+func (m *MockupPrecisionOutputPlugin) Connect() error {
+	return nil
+}
+func (m *MockupPrecisionOutputPlugin) Close() error {
+	return nil
+}
+func (m *MockupPrecisionOutputPlugin) Description() string {
+	return "Mockup test output plugin with a precision setting"
+}
+func (m *MockupPrecisionOutputPlugin) SampleConfig() string {
+	return "Mockup test output plugin with a precision setting"
+}
+func (m *MockupPrecisionOutputPlugin) Write(_ []telegraf.Metric) error {
+	return nil
+}
+
 type MockupOuputPlugin struct {
 	URL             string            `toml:"url"`
 	Headers         map[string]string `toml:"headers"`
@@ -402,4 +471,83 @@ func init() {
 	// Register the mockup output plugin for the required names
 	outputs.Add("azure_monitor", func() telegraf.Output { return &MockupOuputPlugin{NamespacePrefix: "Telegraf/"} })
 	outputs.Add("http", func() telegraf.Output { return &MockupOuputPlugin{} })
+	outputs.Add("influxdb", func() telegraf.Output { return &MockupPrecisionOutputPlugin{} })
+	outputs.Add("amqp", func() telegraf.Output { return &MockupPrecisionOutputPlugin{} })
+}
+
+func TestConfig_StartupErrorBehavior(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  startup_error_behavior = "retry"
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Inputs, 1)
+	require.Equal(t, "retry", c.Inputs[0].Config.StartupErrorBehavior)
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  startup_error_behavior = "panic"
+`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid startup_error_behavior "panic"`)
+}
+
+func TestConfig_SeriesLimit(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  max_series = 1000
+
+[[inputs.memcached]]
+  max_series = 500
+  series_limit_policy = "evict"
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Inputs, 2)
+	require.Equal(t, 1000, c.Inputs[0].Config.MaxSeries)
+	require.Equal(t, "", c.Inputs[0].Config.SeriesLimitPolicy)
+	require.Equal(t, 500, c.Inputs[1].Config.MaxSeries)
+	require.Equal(t, "evict", c.Inputs[1].Config.SeriesLimitPolicy)
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  series_limit_policy = "oldest"
+`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid series_limit_policy "oldest"`)
+}
+
+func TestConfig_PluginID(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  servers = ["localhost"]
+  interval = "5s"
+  [inputs.memcached.tags]
+    a = "1"
+    b = "2"
+
+[[inputs.memcached]]
+  interval = "5s"
+  servers = ["localhost"]
+  [inputs.memcached.tags]
+    b = "2"
+    a = "1"
+
+[[inputs.memcached]]
+  servers = ["localhost:11211"]
+  [inputs.memcached.tags]
+    a = "1"
+    b = "2"
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Inputs, 3)
+
+	// The order of the settings does not change the id
+	require.True(t, strings.HasPrefix(c.Inputs[0].Config.ID, "inputs.memcached::"))
+	require.Equal(t, c.Inputs[0].Config.ID, c.Inputs[1].Config.ID)
+	require.NotEqual(t, c.Inputs[0].Config.ID, c.Inputs[2].Config.ID)
 }
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/toml/ast"
+)
+
+// Deprecation describes the use of a deprecated plugin or option found while
+// loading the configuration.
+type Deprecation struct {
+	// Plugin is the name of the plugin including its category, e.g.
+	// "inputs.jolokia", or "agent" for the agent settings.
+	Plugin string
+	// Option is the name of the deprecated option or empty if the plugin
+	// itself is deprecated.
+	Option string
+
+	telegraf.DeprecationInfo
+}
+
+func (d Deprecation) String() string {
+	var msg string
+	if d.Option == "" {
+		msg = fmt.Sprintf("Plugin %q deprecated since version %s", d.Plugin, d.Since)
+	} else {
+		msg = fmt.Sprintf("Option %q of plugin %q deprecated since version %s", d.Option, d.Plugin, d.Since)
+	}
+	if d.RemovalIn != "" {
+		msg += fmt.Sprintf(" and will be removed in %s", d.RemovalIn)
+	}
+	if d.Notice != "" {
+		msg += ": " + d.Notice
+	}
+	return msg
+}
+
+// Removed returns true if the version of telegraf is at or past the version
+// the plugin or option is removed in.
+func (d Deprecation) Removed() bool {
+	return d.removed(internal.Version())
+}
+
+func (d Deprecation) removed(version string) bool {
+	if d.RemovalIn == "" {
+		return false
+	}
+	current, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	removal, ok := parseVersion(d.RemovalIn)
+	if !ok {
+		return false
+	}
+	for i := range removal {
+		if current[i] != removal[i] {
+			return current[i] > removal[i]
+		}
+	}
+	return true
+}
+
+// parseVersion parses the major, minor and patch number of a version like
+// "1.19.0" or "1.20.0-rc1".  Missing numbers are zero.
+func parseVersion(v string) ([3]int, bool) {
+	var version [3]int
+
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) > len(version) {
+		return version, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// checkPluginDeprecation records the use of a plugin listed in the
+// deprecations of its category.
+func (c *Config) checkPluginDeprecation(category, name string, deprecations map[string]telegraf.DeprecationInfo) error {
+	info, ok := deprecations[name]
+	if !ok {
+		return nil
+	}
+	return c.addDeprecation(Deprecation{
+		Plugin:          category + "." + name,
+		DeprecationInfo: info,
+	})
+}
+
+// checkOptionDeprecations records the deprecated options set in the table.
+// Options are deprecated with a struct tag of the form
+//
+//	deprecated:"<since>;<removal in>;<notice>"
+//
+// where the removal version and the notice may be empty.
+func (c *Config) checkOptionDeprecations(plugin string, table *ast.Table, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return c.checkStructDeprecations(plugin, table, rv.Type())
+}
+
+func (c *Config) checkStructDeprecations(plugin string, table *ast.Table, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := c.checkStructDeprecations(plugin, table, ft); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("deprecated")
+		if !ok {
+			continue
+		}
+
+		key, ok := tableKey(table, optionKey(field))
+		if !ok {
+			continue
+		}
+
+		parts := strings.SplitN(tag, ";", 3)
+		for len(parts) < 3 {
+			parts = append(parts, "")
+		}
+		err := c.addDeprecation(Deprecation{
+			Plugin: plugin,
+			Option: key,
+			DeprecationInfo: telegraf.DeprecationInfo{
+				Since:     parts[0],
+				RemovalIn: parts[1],
+				Notice:    parts[2],
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optionKey returns the name of the option as used in the configuration.
+func optionKey(field reflect.StructField) string {
+	if tag := field.Tag.Get("toml"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	return field.Name
+}
+
+// tableKey returns the key as written in the table if it is set, matching
+// the names the same way the toml library does.
+func tableKey(table *ast.Table, key string) (string, bool) {
+	normKey := normalizeKey(key)
+	for k := range table.Fields {
+		if normalizeKey(k) == normKey {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.Replace(key, "_", "", -1))
+}
+
+// addDeprecation logs the deprecation and returns an error if deprecations
+// are treated as errors or the plugin or option was already removed.
+func (c *Config) addDeprecation(d Deprecation) error {
+	c.Deprecations = append(c.Deprecations, d)
+
+	if c.StrictDeprecations || d.Removed() {
+		return fmt.Errorf("DeprecationError: %s", d)
+	}
+	log.Printf("W! DeprecationWarning: %s", d)
+	return nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation_Plugin(t *testing.T) {
+	deprecations := inputs.Deprecations
+	t.Cleanup(func() { inputs.Deprecations = deprecations })
+	inputs.Deprecations = map[string]telegraf.DeprecationInfo{
+		"deprecated_mockup": {
+			Since:     "1.1.0",
+			RemovalIn: "99.0.0",
+			Notice:    "use 'inputs.memcached' instead",
+		},
+	}
+
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.deprecated_mockup]]
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Inputs, 1)
+	require.Equal(t, []Deprecation{
+		{
+			Plugin: "inputs.deprecated_mockup",
+			DeprecationInfo: telegraf.DeprecationInfo{
+				Since:     "1.1.0",
+				RemovalIn: "99.0.0",
+				Notice:    "use 'inputs.memcached' instead",
+			},
+		},
+	}, c.Deprecations)
+	require.Equal(t,
+		`Plugin "inputs.deprecated_mockup" deprecated since version 1.1.0 and will be removed in 99.0.0: use 'inputs.memcached' instead`,
+		c.Deprecations[0].String())
+}
+
+func TestDeprecation_Options(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  utc = true
+
+[[inputs.memcached]]
+  servers = ["localhost"]
+
+[[inputs.deprecated_option_mockup]]
+  old_address = "localhost"
+  urls = ["http://localhost"]
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Deprecation{
+		{
+			Plugin: "agent",
+			Option: "utc",
+			DeprecationInfo: telegraf.DeprecationInfo{
+				Since:     "1.0.0",
+				RemovalIn: "2.0.0",
+				Notice:    "option is ignored",
+			},
+		},
+		{
+			Plugin: "inputs.deprecated_option_mockup",
+			Option: "old_address",
+			DeprecationInfo: telegraf.DeprecationInfo{
+				Since:  "1.12.0",
+				Notice: "use 'urls' instead",
+			},
+		},
+	}, c.Deprecations)
+	require.Equal(t,
+		`Option "old_address" of plugin "inputs.deprecated_option_mockup" deprecated since version 1.12.0: use 'urls' instead`,
+		c.Deprecations[1].String())
+}
+
+func TestDeprecation_UnsetOptionIgnored(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.deprecated_option_mockup]]
+  urls = ["http://localhost"]
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Deprecations, 0)
+}
+
+func TestDeprecation_Strict(t *testing.T) {
+	c := NewConfig()
+	c.StrictDeprecations = true
+	err := c.LoadConfigData([]byte(`
+[[inputs.deprecated_option_mockup]]
+  old_address = "localhost"
+`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `DeprecationError: Option "old_address"`)
+}
+
+func TestDeprecation_Removed(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		removalIn string
+		expected  bool
+	}{
+		{name: "no removal", version: "1.19.0", expected: false},
+		{name: "before", version: "1.19.0", removalIn: "2.0.0", expected: false},
+		{name: "at", version: "2.0.0", removalIn: "2.0.0", expected: true},
+		{name: "after", version: "2.1.3", removalIn: "2.0.0", expected: true},
+		{name: "short", version: "2.0", removalIn: "2.0.0", expected: true},
+		{name: "prerelease", version: "2.0.0-rc1", removalIn: "2.0.0", expected: true},
+		{name: "unknown version", version: "unknown", removalIn: "2.0.0", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Deprecation{DeprecationInfo: telegraf.DeprecationInfo{RemovalIn: tt.removalIn}}
+			require.Equal(t, tt.expected, d.removed(tt.version))
+		})
+	}
+}
+
+/*** Mockup plugin with a deprecated option ***/
+type MockupDeprecatedOptionPlugin struct {
+	OldAddress string   `toml:"old_address" deprecated:"1.12.0;;use 'urls' instead"`
+	URLs       []string `toml:"urls"`
+}
+
+func (m *MockupDeprecatedOptionPlugin) SampleConfig() string {
+	return "Mockup deprecated option plugin"
+}
+func (m *MockupDeprecatedOptionPlugin) Description() string                   { return "Mockup deprecated option plugin" }
+func (m *MockupDeprecatedOptionPlugin) Gather(acc telegraf.Accumulator) error { return nil }
+
+func init() {
+	inputs.Add("deprecated_mockup", func() telegraf.Input { return &MockupInputPlugin{} })
+	inputs.Add("deprecated_option_mockup", func() telegraf.Input { return &MockupDeprecatedOptionPlugin{} })
+}
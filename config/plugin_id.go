@@ -0,0 +1,54 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/toml/ast"
+)
+
+// pluginID returns an id identifying the plugin across restarts by its type,
+// name and settings.  Changing any setting of the plugin changes its id.
+func pluginID(prefix string, table *ast.Table) (string, error) {
+	var b strings.Builder
+	if err := writeTable(&b, table); err != nil {
+		return "", fmt.Errorf("computing id of %s failed: %w", prefix, err)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return prefix + "::" + hex.EncodeToString(sum[:]), nil
+}
+
+// writeTable writes the settings of the table sorted by their key, so the
+// order of the settings in the file does not matter.
+func writeTable(b *strings.Builder, table *ast.Table) error {
+	keys := make([]string, 0, len(table.Fields))
+	for key := range table.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch field := table.Fields[key].(type) {
+		case *ast.KeyValue:
+			fmt.Fprintf(b, "%s=%s\n", key, field.Value.Source())
+		case *ast.Table:
+			fmt.Fprintf(b, "[%s]\n", key)
+			if err := writeTable(b, field); err != nil {
+				return err
+			}
+		case []*ast.Table:
+			for _, t := range field {
+				fmt.Fprintf(b, "[[%s]]\n", key)
+				if err := writeTable(b, t); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unexpected type %T of setting %q", field, key)
+		}
+	}
+	return nil
+}
@@ -4,18 +4,31 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"errors"
 	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 )
 
 // NewStreamContentDecoder returns a reader that will decode the stream
-// according to the encoding type.
-func NewStreamContentDecoder(encoding string, r io.Reader) (io.Reader, error) {
+// according to the encoding type.  Closing the reader releases the resources
+// of the decoder, it does not close r.
+func NewStreamContentDecoder(encoding string, r io.Reader) (io.ReadCloser, error) {
 	switch encoding {
 	case "gzip":
 		return NewGzipReader(r)
+	case "zlib":
+		return NewZlibReader(r)
+	case "snappy":
+		// streams use the framing format of snappy
+		return ioutil.NopCloser(snappy.NewReader(r)), nil
+	case "zstd":
+		return NewZstdReader(r)
 	case "identity", "":
-		return r, nil
+		return ioutil.NopCloser(r), nil
 	default:
 		return nil, errors.New("invalid value for content_encoding")
 	}
@@ -28,7 +41,7 @@ type GzipReader struct {
 	endOfStream bool
 }
 
-func NewGzipReader(r io.Reader) (io.Reader, error) {
+func NewGzipReader(r io.Reader) (io.ReadCloser, error) {
 	// We need a read that implements ByteReader in order to line up the next
 	// stream.
 	br := bufio.NewReader(r)
@@ -67,11 +80,98 @@ func (r *GzipReader) Read(b []byte) (int, error) {
 	return n, err
 }
 
+func (r *GzipReader) Close() error {
+	return r.z.Close()
+}
+
+// ZlibReader reads a sequence of zlib streams, similar to GzipReader.
+type ZlibReader struct {
+	r           *bufio.Reader
+	z           io.ReadCloser
+	endOfStream bool
+}
+
+func NewZlibReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+
+	z, err := zlib.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZlibReader{r: br, z: z}, nil
+}
+
+func (r *ZlibReader) Read(b []byte) (int, error) {
+	if r.endOfStream {
+		// The end of the data is only reached if there is no further
+		// stream header.
+		if _, err := r.r.Peek(1); err != nil {
+			return 0, err
+		}
+		err := r.z.(zlib.Resetter).Reset(r.r, nil)
+		if err != nil {
+			return 0, err
+		}
+		r.endOfStream = false
+	}
+
+	n, err := r.z.Read(b)
+	if err == io.EOF {
+		r.endOfStream = true
+		return n, nil
+	}
+	return n, err
+}
+
+func (r *ZlibReader) Close() error {
+	return r.z.Close()
+}
+
+// ZstdReader reads a sequence of zstd frames.  The decoder runs goroutines
+// until the reader is closed.
+type ZstdReader struct {
+	z *zstd.Decoder
+}
+
+func NewZstdReader(r io.Reader) (io.ReadCloser, error) {
+	z, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &ZstdReader{z: z}, nil
+}
+
+func (r *ZstdReader) Read(b []byte) (int, error) {
+	return r.z.Read(b)
+}
+
+func (r *ZstdReader) Close() error {
+	r.z.Close()
+	return nil
+}
+
+// NewStreamContentEncoder returns a ContentEncoder for the encoding type
+// whose encoded buffers can be concatenated and read by the decoder of
+// NewStreamContentDecoder.
+func NewStreamContentEncoder(encoding string) (ContentEncoder, error) {
+	if encoding == "snappy" {
+		return NewSnappyStreamEncoder(), nil
+	}
+	return NewContentEncoder(encoding)
+}
+
 // NewContentEncoder returns a ContentEncoder for the encoding type.
 func NewContentEncoder(encoding string) (ContentEncoder, error) {
 	switch encoding {
 	case "gzip":
 		return NewGzipEncoder()
+	case "zlib":
+		return NewZlibEncoder()
+	case "snappy":
+		return NewSnappyEncoder(), nil
+	case "zstd":
+		return NewZstdEncoder()
 	case "identity", "":
 		return NewIdentityEncoder(), nil
 	default:
@@ -84,6 +184,12 @@ func NewContentDecoder(encoding string) (ContentDecoder, error) {
 	switch encoding {
 	case "gzip":
 		return NewGzipDecoder()
+	case "zlib":
+		return NewZlibDecoder(), nil
+	case "snappy":
+		return NewSnappyDecoder(), nil
+	case "zstd":
+		return NewZstdDecoder()
 	case "identity", "":
 		return NewIdentityDecoder(), nil
 	default:
@@ -125,6 +231,92 @@ func (e *GzipEncoder) Encode(data []byte) ([]byte, error) {
 	return e.buf.Bytes(), nil
 }
 
+// ZlibEncoder compresses the buffer using zlib at the default level.
+type ZlibEncoder struct {
+	writer *zlib.Writer
+	buf    *bytes.Buffer
+}
+
+func NewZlibEncoder() (*ZlibEncoder, error) {
+	var buf bytes.Buffer
+	return &ZlibEncoder{
+		writer: zlib.NewWriter(&buf),
+		buf:    &buf,
+	}, nil
+}
+
+func (e *ZlibEncoder) Encode(data []byte) ([]byte, error) {
+	e.buf.Reset()
+	e.writer.Reset(e.buf)
+
+	_, err := e.writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = e.writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// SnappyEncoder compresses the buffer using the snappy block format.
+type SnappyEncoder struct{}
+
+func NewSnappyEncoder() *SnappyEncoder {
+	return &SnappyEncoder{}
+}
+
+func (*SnappyEncoder) Encode(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+// SnappyStreamEncoder compresses the buffer using the snappy framing format.
+type SnappyStreamEncoder struct {
+	writer *snappy.Writer
+	buf    *bytes.Buffer
+}
+
+func NewSnappyStreamEncoder() *SnappyStreamEncoder {
+	var buf bytes.Buffer
+	return &SnappyStreamEncoder{
+		writer: snappy.NewBufferedWriter(&buf),
+		buf:    &buf,
+	}
+}
+
+func (e *SnappyStreamEncoder) Encode(data []byte) ([]byte, error) {
+	e.buf.Reset()
+	e.writer.Reset(e.buf)
+
+	_, err := e.writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = e.writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// ZstdEncoder compresses the buffer using zstd at the default level.
+type ZstdEncoder struct {
+	encoder *zstd.Encoder
+}
+
+func NewZstdEncoder() (*ZstdEncoder, error) {
+	e, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ZstdEncoder{encoder: e}, nil
+}
+
+func (e *ZstdEncoder) Encode(data []byte) ([]byte, error) {
+	return e.encoder.EncodeAll(data, nil), nil
+}
+
 // IdentityEncoder is a null encoder that applies no transformation.
 type IdentityEncoder struct{}
 
@@ -169,6 +361,50 @@ func (d *GzipDecoder) Decode(data []byte) ([]byte, error) {
 	return d.buf.Bytes(), nil
 }
 
+// ZlibDecoder decompresses buffers with zlib compression.
+type ZlibDecoder struct{}
+
+func NewZlibDecoder() *ZlibDecoder {
+	return &ZlibDecoder{}
+}
+
+func (*ZlibDecoder) Decode(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// SnappyDecoder decompresses buffers in the snappy block format.
+type SnappyDecoder struct{}
+
+func NewSnappyDecoder() *SnappyDecoder {
+	return &SnappyDecoder{}
+}
+
+func (*SnappyDecoder) Decode(data []byte) ([]byte, error) {
+	return snappy.Decode(nil, data)
+}
+
+// ZstdDecoder decompresses buffers with zstd compression.
+type ZstdDecoder struct {
+	decoder *zstd.Decoder
+}
+
+func NewZstdDecoder() (*ZstdDecoder, error) {
+	d, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ZstdDecoder{decoder: d}, nil
+}
+
+func (d *ZstdDecoder) Decode(data []byte) ([]byte, error) {
+	return d.decoder.DecodeAll(data, nil)
+}
+
 // IdentityDecoder is a null decoder that returns the input.
 type IdentityDecoder struct{}
 
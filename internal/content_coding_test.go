@@ -2,8 +2,11 @@ package internal
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -92,3 +95,80 @@ func TestStreamGzipDecode(t *testing.T) {
 
 	require.Equal(t, []byte("howdy"), b[:n])
 }
+
+func TestEncodeDecode(t *testing.T) {
+	for _, encoding := range []string{"gzip", "zlib", "snappy", "zstd", "identity"} {
+		t.Run(encoding, func(t *testing.T) {
+			enc, err := NewContentEncoder(encoding)
+			require.NoError(t, err)
+			dec, err := NewContentDecoder(encoding)
+			require.NoError(t, err)
+
+			for _, payload := range []string{"howdy", "doody"} {
+				encoded, err := enc.Encode([]byte(payload))
+				require.NoError(t, err)
+
+				actual, err := dec.Decode(encoded)
+				require.NoError(t, err)
+				require.Equal(t, payload, string(actual))
+			}
+		})
+	}
+}
+
+func TestStreamEncodeDecode(t *testing.T) {
+	for _, encoding := range []string{"gzip", "zlib", "snappy", "zstd", "identity"} {
+		t.Run(encoding, func(t *testing.T) {
+			enc, err := NewStreamContentEncoder(encoding)
+			require.NoError(t, err)
+
+			var stream bytes.Buffer
+			for _, payload := range []string{"howdy\n", "doody\n"} {
+				encoded, err := enc.Encode([]byte(payload))
+				require.NoError(t, err)
+				stream.Write(encoded)
+			}
+
+			dec, err := NewStreamContentDecoder(encoding, &stream)
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(dec)
+			require.NoError(t, err)
+			require.Equal(t, "howdy\ndoody\n", string(data))
+		})
+	}
+}
+
+func TestZstdReaderClose(t *testing.T) {
+	enc, err := NewStreamContentEncoder("zstd")
+	require.NoError(t, err)
+	encoded, err := enc.Encode(bytes.Repeat([]byte("howdy\n"), 100000))
+	require.NoError(t, err)
+
+	before := runtime.NumGoroutine()
+
+	// The stream is abandoned after reading some of the data
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write(encoded[:len(encoded)/2])
+	}()
+	dec, err := NewStreamContentDecoder("zstd", pr)
+	require.NoError(t, err)
+	_, err = dec.Read(make([]byte, 6))
+	require.NoError(t, err)
+
+	require.NoError(t, pr.Close())
+	require.NoError(t, dec.Close())
+	// The goroutines of the decoder are stopped by closing it
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
+
+func TestInvalidEncoding(t *testing.T) {
+	_, err := NewContentEncoder("brotli")
+	require.Error(t, err)
+	_, err = NewContentDecoder("brotli")
+	require.Error(t, err)
+}
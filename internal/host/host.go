@@ -0,0 +1,68 @@
+// Package host resolves the paths of the host filesystems read by system
+// inputs.  When the agent runs in a container with the host filesystem
+// mounted, e.g. at /hostfs, the paths are set with the host_proc, host_sys,
+// host_etc and host_mountprefix agent options or the HOST_PROC, HOST_SYS,
+// HOST_ETC and HOST_MOUNT_PREFIX environment variables.
+package host
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Environment variables holding the paths, they are also read by gopsutil.
+const (
+	EnvProc        = "HOST_PROC"
+	EnvSys         = "HOST_SYS"
+	EnvEtc         = "HOST_ETC"
+	EnvMountPrefix = "HOST_MOUNT_PREFIX"
+)
+
+// Configure sets the paths of the host filesystems, empty paths keep the
+// ones of the environment.  The paths are stored in the environment, so
+// libraries reading the host filesystems use them as well.
+func Configure(proc, sys, etc, mountPrefix string) error {
+	for env, path := range map[string]string{
+		EnvProc:        proc,
+		EnvSys:         sys,
+		EnvEtc:         etc,
+		EnvMountPrefix: mountPrefix,
+	} {
+		if path == "" {
+			continue
+		}
+		if err := os.Setenv(env, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Proc returns the path of the proc filesystem of the host joined with elem.
+func Proc(elem ...string) string {
+	return join(EnvProc, "/proc", elem)
+}
+
+// Sys returns the path of the sys filesystem of the host joined with elem.
+func Sys(elem ...string) string {
+	return join(EnvSys, "/sys", elem)
+}
+
+// Etc returns the path of the etc directory of the host joined with elem.
+func Etc(elem ...string) string {
+	return join(EnvEtc, "/etc", elem)
+}
+
+// MountPrefix returns the prefix of the mount points of the host, empty if
+// the agent does not run in a container.
+func MountPrefix() string {
+	return os.Getenv(EnvMountPrefix)
+}
+
+func join(env, defaultPath string, elem []string) string {
+	root := os.Getenv(env)
+	if root == "" {
+		root = defaultPath
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
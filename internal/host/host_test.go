@@ -0,0 +1,33 @@
+package host
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaths(t *testing.T) {
+	for _, env := range []string{EnvProc, EnvSys, EnvEtc, EnvMountPrefix} {
+		value, ok := os.LookupEnv(env)
+		require.NoError(t, os.Unsetenv(env))
+		if ok {
+			defer os.Setenv(env, value)
+		} else {
+			defer os.Unsetenv(env)
+		}
+	}
+
+	require.Equal(t, "/proc", Proc())
+	require.Equal(t, "/proc/net/snmp", Proc("net", "snmp"))
+	require.Equal(t, "/sys/class/net", Sys("class/net"))
+	require.Equal(t, "/etc/os-release", Etc("os-release"))
+	require.Equal(t, "", MountPrefix())
+
+	require.NoError(t, Configure("/hostfs/proc", "", "/hostfs/etc", "/hostfs"))
+	require.Equal(t, "/hostfs/proc/net/snmp", Proc("net", "snmp"))
+	require.Equal(t, "/sys/class/net", Sys("class/net"))
+	require.Equal(t, "/hostfs/etc/os-release", Etc("os-release"))
+	require.Equal(t, "/hostfs", MountPrefix())
+	require.Equal(t, "/hostfs/proc", os.Getenv(EnvProc))
+}
@@ -0,0 +1,241 @@
+// Package k8s shares Kubernetes clients and informers between plugins, so
+// plugins talking to the same API server reuse their connections and watches
+// instead of each opening their own.
+package k8s
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	mu        sync.Mutex
+	clients   = make(map[string]*kubernetes.Clientset)
+	factories = make(map[string]*factory)
+)
+
+// LoadConfig returns the in-cluster config, falling back to the kubeconfig
+// file at the given path or ~/.kube/config if the path is empty.  The file
+// is read as a plain rest config, extensions and auth providers are not
+// supported.
+func LoadConfig(kubeconfigPath string) (*rest.Config, error) {
+	if config, err := rest.InClusterConfig(); err == nil {
+		return config, nil
+	}
+
+	if kubeconfigPath == "" {
+		u, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user - %v", err)
+		}
+		kubeconfigPath = filepath.Join(u.HomeDir, ".kube/config")
+	}
+
+	data, err := ioutil.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading '%s': %v", kubeconfigPath, err)
+	}
+
+	var config rest.Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// configKey identifies the API server, credentials and proxy of a config.
+func configKey(config *rest.Config) string {
+	tls := config.TLSClientConfig
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%t|%s|%s",
+		config.Host, config.APIPath, config.BearerToken, config.BearerTokenFile,
+		config.Username, config.Password, tls.ServerName, tls.CAFile, tls.CertFile,
+		tls.Insecure, config.Timeout, proxyKey(config))
+}
+
+// proxyKey returns the proxy the config uses for requests to the API server,
+// as the proxy function itself cannot be compared.
+func proxyKey(config *rest.Config) string {
+	if config.Proxy == nil {
+		return ""
+	}
+	host := config.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	req, err := http.NewRequest("GET", host, nil)
+	if err != nil {
+		return ""
+	}
+	u, err := config.Proxy(req)
+	if err != nil || u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+// Client returns the client shared by all plugins using the same API server
+// and credentials, creating it on first use.
+func Client(config *rest.Config) (*kubernetes.Clientset, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return client(config)
+}
+
+func client(config *rest.Config) (*kubernetes.Clientset, error) {
+	key := configKey(config)
+	if c, ok := clients[key]; ok {
+		return c, nil
+	}
+
+	c, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	clients[key] = c
+	return c, nil
+}
+
+// InformerOptions restrict the objects watched by shared informers.
+type InformerOptions struct {
+	// Namespace to watch, empty watches all namespaces.
+	Namespace string
+	// LabelSelector and FieldSelector are applied on the server side.
+	LabelSelector string
+	FieldSelector string
+	// Resync is the interval of resyncing the informers, 0 disables it.
+	Resync time.Duration
+}
+
+type factory struct {
+	informers.SharedInformerFactory
+	refs int
+	stop chan struct{}
+}
+
+// Informers is a reference to an informer factory shared by all plugins
+// using the same client and options.  The informers keep running until all
+// references are released.
+type Informers struct {
+	informers.SharedInformerFactory
+
+	key      string
+	factory  *factory
+	released bool
+
+	// handlersMu is held while delivering events to the handlers of the
+	// reference, so no handler runs anymore once Release returns.
+	handlersMu sync.RWMutex
+	detached   bool
+}
+
+// AcquireInformers returns a reference to the shared informer factory for the
+// config and options.  Release must be called when the informers are no
+// longer used.
+func AcquireInformers(config *rest.Config, options InformerOptions) (*Informers, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := fmt.Sprintf("%s|%s|%s|%s|%s", configKey(config), options.Namespace,
+		options.LabelSelector, options.FieldSelector, options.Resync)
+	f, ok := factories[key]
+	if !ok {
+		c, err := client(config)
+		if err != nil {
+			return nil, err
+		}
+		f = &factory{
+			SharedInformerFactory: informers.NewSharedInformerFactoryWithOptions(c, options.Resync,
+				informers.WithNamespace(options.Namespace),
+				informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+					o.LabelSelector = options.LabelSelector
+					o.FieldSelector = options.FieldSelector
+				}),
+			),
+			stop: make(chan struct{}),
+		}
+		factories[key] = f
+	}
+	f.refs++
+
+	return &Informers{SharedInformerFactory: f.SharedInformerFactory, key: key, factory: f}, nil
+}
+
+// Start starts the informers requested so far.  Informers already started by
+// other plugins are not started again.
+func (i *Informers) Start() {
+	i.SharedInformerFactory.Start(i.factory.stop)
+}
+
+// AddEventHandler adds the handler to the shared informer.  The informer may
+// keep running for other plugins after the reference is released and handlers
+// can not be removed from it, so the events are no longer passed to the
+// handler after Release instead.
+func (i *Informers) AddEventHandler(informer cache.SharedInformer, handler cache.ResourceEventHandler) {
+	informer.AddEventHandler(&releasableHandler{informers: i, handler: handler})
+}
+
+// Release drops the reference, detaches its event handlers and stops the
+// informers once no plugin uses them anymore.
+func (i *Informers) Release() {
+	i.handlersMu.Lock()
+	i.detached = true
+	i.handlersMu.Unlock()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if i.released {
+		return
+	}
+	i.released = true
+
+	i.factory.refs--
+	if i.factory.refs == 0 {
+		close(i.factory.stop)
+		delete(factories, i.key)
+	}
+}
+
+// releasableHandler passes the events to the handler until the reference it
+// was added through is released.
+type releasableHandler struct {
+	informers *Informers
+	handler   cache.ResourceEventHandler
+}
+
+func (h *releasableHandler) OnAdd(obj interface{}) {
+	h.informers.handlersMu.RLock()
+	defer h.informers.handlersMu.RUnlock()
+	if !h.informers.detached {
+		h.handler.OnAdd(obj)
+	}
+}
+
+func (h *releasableHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.informers.handlersMu.RLock()
+	defer h.informers.handlersMu.RUnlock()
+	if !h.informers.detached {
+		h.handler.OnUpdate(oldObj, newObj)
+	}
+}
+
+func (h *releasableHandler) OnDelete(obj interface{}) {
+	h.informers.handlersMu.RLock()
+	defer h.informers.handlersMu.RUnlock()
+	if !h.informers.detached {
+		h.handler.OnDelete(obj)
+	}
+}
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestClientShared(t *testing.T) {
+	c1, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", BearerToken: "abc"})
+	require.NoError(t, err)
+	c2, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", BearerToken: "abc"})
+	require.NoError(t, err)
+	require.Same(t, c1, c2)
+
+	// Other credentials get their own client
+	c3, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", BearerToken: "def"})
+	require.NoError(t, err)
+	require.NotSame(t, c1, c3)
+}
+
+func TestInformersShared(t *testing.T) {
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+	options := InformerOptions{Namespace: "default", LabelSelector: "app=nginx"}
+
+	i1, err := AcquireInformers(config, options)
+	require.NoError(t, err)
+	i2, err := AcquireInformers(config, options)
+	require.NoError(t, err)
+	require.Equal(t, i1.SharedInformerFactory, i2.SharedInformerFactory)
+
+	other, err := AcquireInformers(config, InformerOptions{Namespace: "kube-system"})
+	require.NoError(t, err)
+	require.NotEqual(t, i1.SharedInformerFactory, other.SharedInformerFactory)
+	other.Release()
+
+	// The informers are kept until the last reference is released
+	i1.Release()
+	i1.Release()
+	require.Len(t, factories, 1)
+	i2.Release()
+	require.Empty(t, factories)
+}
+
+// stubInformer records the handler added to it.
+type stubInformer struct {
+	cache.SharedInformer
+	handler cache.ResourceEventHandler
+}
+
+func (s *stubInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	s.handler = handler
+}
+
+func TestInformersReleaseDetachesHandlers(t *testing.T) {
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+	i1, err := AcquireInformers(config, InformerOptions{})
+	require.NoError(t, err)
+	i2, err := AcquireInformers(config, InformerOptions{})
+	require.NoError(t, err)
+	defer i2.Release()
+
+	var added []interface{}
+	informer := &stubInformer{}
+	i1.AddEventHandler(informer, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { added = append(added, obj) },
+	})
+
+	informer.handler.OnAdd("a")
+	i1.Release()
+	// The informer keeps running for the other reference
+	informer.handler.OnAdd("b")
+	require.Equal(t, []interface{}{"a"}, added)
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k8s")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	require.NoError(t, ioutil.WriteFile(path, []byte("host: https://127.0.0.1:6443\nbearerToken: abc\n"), 0600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "https://127.0.0.1:6443", config.Host)
+	require.Equal(t, "abc", config.BearerToken)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestClientSharedPerProxy(t *testing.T) {
+	proxyURL := func(proxy string) func(*http.Request) (*url.URL, error) {
+		u, err := url.Parse(proxy)
+		require.NoError(t, err)
+		return http.ProxyURL(u)
+	}
+
+	c1, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", Proxy: proxyURL("socks5://dmz:1080")})
+	require.NoError(t, err)
+	c2, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", Proxy: proxyURL("socks5://dmz:1080")})
+	require.NoError(t, err)
+	require.Same(t, c1, c2)
+
+	c3, err := Client(&rest.Config{Host: "https://127.0.0.1:6443", Proxy: proxyURL("http://proxy:3128")})
+	require.NoError(t, err)
+	require.NotSame(t, c1, c3)
+
+	c4, err := Client(&rest.Config{Host: "https://127.0.0.1:6443"})
+	require.NoError(t, err)
+	require.NotSame(t, c1, c4)
+}
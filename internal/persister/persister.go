@@ -0,0 +1,105 @@
+// Package persister stores the state of stateful plugins in a file, so they
+// can resume where they stopped after Telegraf is restarted.
+package persister
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Persister loads and stores the states of the registered plugins as JSON
+// object keyed by the id of the plugins.
+type Persister struct {
+	Filename string
+
+	plugins map[string]telegraf.StatefulPlugin
+}
+
+// Register adds the plugin with the id identifying it across restarts.
+func (p *Persister) Register(id string, plugin telegraf.StatefulPlugin) error {
+	if p.plugins == nil {
+		p.plugins = make(map[string]telegraf.StatefulPlugin)
+	}
+	if _, ok := p.plugins[id]; ok {
+		return fmt.Errorf("plugin with id %q already registered", id)
+	}
+	p.plugins[id] = plugin
+	return nil
+}
+
+// Load passes the stored states to the registered plugins.  The states are
+// decoded into the type returned by GetState of each plugin.  A missing file
+// is not an error, states of plugins no longer configured are ignored.
+func (p *Persister) Load() error {
+	data, err := ioutil.ReadFile(p.Filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading states from %q failed: %w", p.Filename, err)
+	}
+
+	var states map[string]json.RawMessage
+	if err := json.Unmarshal(data, &states); err != nil {
+		return fmt.Errorf("decoding states from %q failed: %w", p.Filename, err)
+	}
+
+	for id, raw := range states {
+		plugin, ok := p.plugins[id]
+		if !ok {
+			continue
+		}
+		current := plugin.GetState()
+		if current == nil {
+			continue
+		}
+
+		state := reflect.New(reflect.TypeOf(current))
+		if err := json.Unmarshal(raw, state.Interface()); err != nil {
+			return fmt.Errorf("decoding state of plugin %q failed: %w", id, err)
+		}
+		if err := plugin.SetState(state.Elem().Interface()); err != nil {
+			return fmt.Errorf("restoring state of plugin %q failed: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Store writes the states of the registered plugins, replacing the file
+// atomically.
+func (p *Persister) Store() error {
+	states := make(map[string]interface{}, len(p.plugins))
+	for id, plugin := range p.plugins {
+		if state := plugin.GetState(); state != nil {
+			states[id] = state
+		}
+	}
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("encoding states failed: %w", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p.Filename), filepath.Base(p.Filename)+".*")
+	if err != nil {
+		return fmt.Errorf("writing states to %q failed: %w", p.Filename, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing states to %q failed: %w", p.Filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing states to %q failed: %w", p.Filename, err)
+	}
+	if err := os.Rename(tmp.Name(), p.Filename); err != nil {
+		return fmt.Errorf("writing states to %q failed: %w", p.Filename, err)
+	}
+	return nil
+}
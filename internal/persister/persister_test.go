@@ -0,0 +1,64 @@
+package persister
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type offsets map[string]int64
+
+type statefulPlugin struct {
+	offsets offsets
+}
+
+func (p *statefulPlugin) GetState() interface{} {
+	return p.offsets
+}
+
+func (p *statefulPlugin) SetState(state interface{}) error {
+	p.offsets = state.(offsets)
+	return nil
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persister")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "telegraf.state")
+
+	// Loading without a statefile keeps the plugins as they are
+	plugin := &statefulPlugin{offsets: offsets{}}
+	p := &Persister{Filename: filename}
+	require.NoError(t, p.Register("inputs.tail::abc", plugin))
+	require.Error(t, p.Register("inputs.tail::abc", plugin))
+	require.NoError(t, p.Load())
+	require.Empty(t, plugin.offsets)
+
+	plugin.offsets["/var/log/syslog"] = 42
+	require.NoError(t, p.Store())
+
+	restored := &statefulPlugin{offsets: offsets{}}
+	other := &statefulPlugin{offsets: offsets{}}
+	p = &Persister{Filename: filename}
+	require.NoError(t, p.Register("inputs.tail::abc", restored))
+	require.NoError(t, p.Register("inputs.tail::def", other))
+	require.NoError(t, p.Load())
+	require.Equal(t, offsets{"/var/log/syslog": 42}, restored.offsets)
+	require.Empty(t, other.offsets)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persister")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "telegraf.state")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`{"inputs.tail::abc": "offsets"}`), 0640))
+
+	p := &Persister{Filename: filename}
+	require.NoError(t, p.Register("inputs.tail::abc", &statefulPlugin{offsets: offsets{}}))
+	require.Error(t, p.Load())
+}
@@ -0,0 +1,258 @@
+// Package resolver caches the addresses of the hosts plugins connect to.
+// Outputs connecting on every flush would otherwise query the DNS servers
+// each time, while caching the addresses forever would miss DNS failovers.
+// Caching is enabled with the dns_cache_ttl agent option, and
+// dns_refresh_interval additionally re-resolves the cached hosts in the
+// background so lookups never wait for the DNS servers.
+package resolver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Resolver looks up the addresses of hosts, caching them for the TTL.
+type Resolver struct {
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
+	mu     sync.Mutex
+	ttl    time.Duration
+	cache  map[string]*entry
+	cancel context.CancelFunc
+}
+
+type entry struct {
+	addrs   []string
+	expires time.Time
+	used    time.Time
+}
+
+var defaultResolver = New()
+
+// New returns a resolver without caching.
+func New() *Resolver {
+	return &Resolver{
+		lookupHost: net.DefaultResolver.LookupHost,
+		cache:      make(map[string]*entry),
+	}
+}
+
+// Default returns the resolver configured by the agent.
+func Default() *Resolver {
+	return defaultResolver
+}
+
+// Configure sets the caching of the default resolver.
+func Configure(ttl, refreshInterval time.Duration) {
+	defaultResolver.Configure(ttl, refreshInterval)
+}
+
+// Configure caches the addresses for the ttl, 0 disables caching.  With a
+// refresh interval the hosts looked up within the ttl are re-resolved in the
+// background.
+func (r *Resolver) Configure(ttl, refreshInterval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.ttl = ttl
+	r.cache = make(map[string]*entry)
+
+	if ttl > 0 && refreshInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		go r.refreshLoop(ctx, refreshInterval)
+	}
+}
+
+// Enabled returns true if the addresses are cached.
+func (r *Resolver) Enabled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ttl > 0
+}
+
+// LookupHost returns the addresses of the host.  When the DNS servers fail,
+// the addresses of the previous lookup are used until the next attempt after
+// the ttl.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	// The entry is updated by the refresh loop, so its fields are copied
+	// while holding the lock
+	now := time.Now()
+	r.mu.Lock()
+	ttl := r.ttl
+	var cached []string
+	var expires time.Time
+	e, ok := r.cache[host]
+	if ok {
+		e.used = now
+		cached = e.addrs
+		expires = e.expires
+	}
+	r.mu.Unlock()
+
+	if ttl <= 0 {
+		return r.lookupHost(ctx, host)
+	}
+	if ok && now.Before(expires) {
+		return append([]string(nil), cached...), nil
+	}
+
+	addrs, err := r.lookupHost(ctx, host)
+	if err != nil {
+		if !ok {
+			return nil, err
+		}
+		addrs = cached
+	}
+	r.store(host, addrs, now)
+	return append([]string(nil), addrs...), nil
+}
+
+func (r *Resolver) store(host string, addrs []string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[host] = &entry{addrs: addrs, expires: now.Add(r.ttl), used: now}
+}
+
+// refreshLoop re-resolves the cached hosts every interval, forgetting the
+// hosts not looked up within the ttl.
+func (r *Resolver) refreshLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		var hosts []string
+		r.mu.Lock()
+		for host, e := range r.cache {
+			if now.Sub(e.used) > r.ttl {
+				delete(r.cache, host)
+				continue
+			}
+			hosts = append(hosts, host)
+		}
+		r.mu.Unlock()
+
+		for _, host := range hosts {
+			addrs, err := r.lookupHost(ctx, host)
+			if err != nil {
+				continue
+			}
+			r.mu.Lock()
+			if e, ok := r.cache[host]; ok {
+				e.addrs = addrs
+				e.expires = now.Add(r.ttl)
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Dialer connects to the addresses of the resolver, trying all addresses of
+// a host in order.  Without caching it dials like the embedded net.Dialer.
+type Dialer struct {
+	net.Dialer
+	Resolver *Resolver
+}
+
+// NewDialer returns a dialer using the default resolver.
+func NewDialer(d net.Dialer) *Dialer {
+	return &Dialer{Dialer: d, Resolver: Default()}
+}
+
+// Dial connects to the address on the named network.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network using the
+// context.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return d.Dialer.DialContext(ctx, network, address)
+	}
+	if d.Resolver == nil || !d.Resolver.Enabled() {
+		return d.Dialer.DialContext(ctx, network, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := d.Resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %q", host)
+	}
+
+	var firstErr error
+	for _, addr := range addrs {
+		conn, err := d.Dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// DialTLS connects to the address like tls.DialWithDialer.
+func (d *Dialer) DialTLS(network, address string, config *tls.Config) (net.Conn, error) {
+	conn, err := d.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	if d.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(d.Timeout)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if d.Timeout > 0 {
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return tlsConn, nil
+}
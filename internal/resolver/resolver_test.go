@@ -0,0 +1,155 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDNS struct {
+	sync.Mutex
+	addrs   map[string][]string
+	lookups int
+	fail    bool
+}
+
+func (f *fakeDNS) lookupHost(_ context.Context, host string) ([]string, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.lookups++
+	if f.fail {
+		return nil, errors.New("server misbehaving")
+	}
+	addrs, ok := f.addrs[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func (f *fakeDNS) set(host string, addrs ...string) {
+	f.Lock()
+	defer f.Unlock()
+	f.addrs[host] = addrs
+}
+
+func (f *fakeDNS) count() int {
+	f.Lock()
+	defer f.Unlock()
+	return f.lookups
+}
+
+func newTestResolver() (*Resolver, *fakeDNS) {
+	dns := &fakeDNS{addrs: map[string][]string{"example.org": {"192.0.2.1"}}}
+	r := New()
+	r.lookupHost = dns.lookupHost
+	return r, dns
+}
+
+func TestLookupHostWithoutCache(t *testing.T) {
+	r, dns := newTestResolver()
+	for i := 0; i < 3; i++ {
+		addrs, err := r.LookupHost(context.Background(), "example.org")
+		require.NoError(t, err)
+		require.Equal(t, []string{"192.0.2.1"}, addrs)
+	}
+	require.Equal(t, 3, dns.count())
+
+	addrs, err := r.LookupHost(context.Background(), "198.51.100.1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"198.51.100.1"}, addrs)
+	require.Equal(t, 3, dns.count())
+}
+
+func TestLookupHostCache(t *testing.T) {
+	r, dns := newTestResolver()
+	r.Configure(50*time.Millisecond, 0)
+
+	for i := 0; i < 3; i++ {
+		addrs, err := r.LookupHost(context.Background(), "example.org")
+		require.NoError(t, err)
+		require.Equal(t, []string{"192.0.2.1"}, addrs)
+	}
+	require.Equal(t, 1, dns.count())
+
+	// The changed record is used once the addresses expired
+	dns.set("example.org", "192.0.2.2")
+	time.Sleep(60 * time.Millisecond)
+	addrs, err := r.LookupHost(context.Background(), "example.org")
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.2"}, addrs)
+	require.Equal(t, 2, dns.count())
+
+	_, err = r.LookupHost(context.Background(), "unknown.example.org")
+	require.Error(t, err)
+}
+
+func TestLookupHostStaleOnError(t *testing.T) {
+	r, dns := newTestResolver()
+	r.Configure(10*time.Millisecond, 0)
+
+	_, err := r.LookupHost(context.Background(), "example.org")
+	require.NoError(t, err)
+
+	dns.Lock()
+	dns.fail = true
+	dns.Unlock()
+	time.Sleep(20 * time.Millisecond)
+
+	addrs, err := r.LookupHost(context.Background(), "example.org")
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.1"}, addrs)
+	require.Equal(t, 2, dns.count())
+}
+
+func TestRefresh(t *testing.T) {
+	r, dns := newTestResolver()
+	r.Configure(time.Minute, 10*time.Millisecond)
+	defer r.Configure(0, 0)
+
+	_, err := r.LookupHost(context.Background(), "example.org")
+	require.NoError(t, err)
+
+	dns.set("example.org", "192.0.2.2")
+	require.Eventually(t, func() bool {
+		addrs, err := r.LookupHost(context.Background(), "example.org")
+		return err == nil && addrs[0] == "192.0.2.2"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	// The unreachable first address is skipped
+	r, dns := newTestResolver()
+	dns.set("metrics.example.org", "127.0.0.2", "127.0.0.1")
+	r.Configure(time.Minute, 0)
+
+	d := &Dialer{Dialer: net.Dialer{Timeout: time.Second}, Resolver: r}
+	conn, err := d.Dial("tcp", net.JoinHostPort("metrics.example.org", port))
+	if err != nil {
+		// 127.0.0.2 is not a loopback address on all platforms
+		t.Skipf("dial failed: %v", err)
+	}
+	require.Equal(t, listener.Addr().String(), conn.RemoteAddr().String())
+	require.NoError(t, conn.Close())
+	require.Equal(t, 1, dns.count())
+}
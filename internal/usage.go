@@ -10,7 +10,20 @@ Usage:
 
 The commands & flags are:
 
+  bench [--rate <n>] [--duration <d>] [--series <n>] [--tags <n>]
+        [--fields <n>] [--null]
+                      send synthetic metrics through the processors to the
+                      outputs, or discard them with --null, and report the
+                      throughput, allocations and time spent in each plugin
   config              print out full sample configuration to stdout
+  plugins [<category> [<name>]]
+                      list the available plugins, optionally only of one
+                      category (inputs, outputs, processors or aggregators),
+                      or print the sample configuration of a single plugin
+  replay [--speed <factor>] <file>
+                      send the metrics in line protocol from the file, or
+                      stdin for '-', through the processors to the outputs;
+                      a speed of 0 replays them as fast as possible
   version             print the version to stdout
 
   --aggregator-filter <filter>   filter the aggregators to enable, separator is :
@@ -32,6 +45,7 @@ The commands & flags are:
                                  Valid values are 'agent', 'global_tags', 'outputs',
                                  'processors', 'aggregators' and 'inputs'
   --sample-config                print out full sample configuration
+  --strict-deprecations          fail if the config uses deprecated plugins or options
   --once                         enable once mode: gather metrics once, write them, and exit
   --test                         enable test mode: gather metrics once and print them
   --test-wait                    wait up to this many seconds for service
@@ -41,6 +55,12 @@ The commands & flags are:
 
 Examples:
 
+  # list the available input plugins, including deprecation notices:
+  telegraf plugins inputs
+
+  # print the sample configuration of the cpu input:
+  telegraf plugins inputs cpu
+
   # generate a telegraf config file:
   telegraf config > telegraf.conf
 
@@ -50,6 +70,12 @@ Examples:
   # run a single telegraf collection, outputting metrics to stdout
   telegraf --config telegraf.conf --test
 
+  # measure the throughput of the processors of a config at 50000 metrics/s
+  telegraf --config telegraf.conf bench --rate 50000 --null
+
+  # replay captured metrics to the outputs at twice the original pace
+  telegraf --config telegraf.conf replay --speed 2 metrics.out
+
   # run telegraf with all plugins defined in config file
   telegraf --config telegraf.conf
 
@@ -0,0 +1,173 @@
+package metric
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Bucket is a histogram bucket with the cumulative count of the observations
+// less than or equal to the upper bound.
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// Quantile is the value of a summary at the quantile.
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// HistogramValue is the value of a histogram metric.
+type HistogramValue struct {
+	Buckets []Bucket
+	Count   uint64
+	Sum     float64
+}
+
+// SummaryValue is the value of a summary metric.
+type SummaryValue struct {
+	Quantiles []Quantile
+	Count     uint64
+	Sum       float64
+}
+
+// NewHistogram creates a histogram metric with the layout of AddHistogram:
+// a field per bucket keyed by its upper bound and the count and sum fields.
+func NewHistogram(name string, tags map[string]string, value HistogramValue, tm time.Time) telegraf.Metric {
+	fields := make(map[string]interface{}, len(value.Buckets)+2)
+	for _, b := range value.Buckets {
+		fields[formatBound(b.UpperBound)] = float64(b.Count)
+	}
+	fields["count"] = float64(value.Count)
+	fields["sum"] = value.Sum
+	return New(name, tags, fields, tm, telegraf.Histogram)
+}
+
+// NewSummary creates a summary metric with the layout of AddSummary: a field
+// per quantile and the count and sum fields.
+func NewSummary(name string, tags map[string]string, value SummaryValue, tm time.Time) telegraf.Metric {
+	fields := make(map[string]interface{}, len(value.Quantiles)+2)
+	for _, q := range value.Quantiles {
+		fields[formatBound(q.Quantile)] = q.Value
+	}
+	fields["count"] = float64(value.Count)
+	fields["sum"] = value.Sum
+	return New(name, tags, fields, tm, telegraf.Summary)
+}
+
+// GetHistogram returns the value of a histogram metric with the layout of
+// AddHistogram.  It returns false for other metrics, including histograms
+// split into a metric per bucket with an "le" tag.
+func GetHistogram(m telegraf.Metric) (HistogramValue, bool) {
+	var value HistogramValue
+	if m.Type() != telegraf.Histogram {
+		return value, false
+	}
+
+	count, sum, ok := countAndSum(m)
+	if !ok {
+		return value, false
+	}
+	value.Count = uint64(count)
+	value.Sum = sum
+
+	for _, field := range m.FieldList() {
+		bound, ok := parseBound(field.Key)
+		if !ok {
+			continue
+		}
+		v, ok := toFloat(field.Value)
+		if !ok {
+			continue
+		}
+		value.Buckets = append(value.Buckets, Bucket{UpperBound: bound, Count: uint64(v)})
+	}
+	sort.Slice(value.Buckets, func(i, j int) bool {
+		return value.Buckets[i].UpperBound < value.Buckets[j].UpperBound
+	})
+	return value, true
+}
+
+// GetSummary returns the value of a summary metric with the layout of
+// AddSummary.  It returns false for other metrics, including summaries split
+// into a metric per quantile with a "quantile" tag.
+func GetSummary(m telegraf.Metric) (SummaryValue, bool) {
+	var value SummaryValue
+	if m.Type() != telegraf.Summary {
+		return value, false
+	}
+
+	count, sum, ok := countAndSum(m)
+	if !ok {
+		return value, false
+	}
+	value.Count = uint64(count)
+	value.Sum = sum
+
+	for _, field := range m.FieldList() {
+		quantile, ok := parseBound(field.Key)
+		if !ok {
+			continue
+		}
+		v, ok := toFloat(field.Value)
+		if !ok {
+			continue
+		}
+		value.Quantiles = append(value.Quantiles, Quantile{Quantile: quantile, Value: v})
+	}
+	sort.Slice(value.Quantiles, func(i, j int) bool {
+		return value.Quantiles[i].Quantile < value.Quantiles[j].Quantile
+	})
+	return value, true
+}
+
+func countAndSum(m telegraf.Metric) (float64, float64, bool) {
+	countField, ok := m.GetField("count")
+	if !ok {
+		return 0, 0, false
+	}
+	sumField, ok := m.GetField("sum")
+	if !ok {
+		return 0, 0, false
+	}
+	count, ok := toFloat(countField)
+	if !ok || count < 0 {
+		return 0, 0, false
+	}
+	sum, ok := toFloat(sumField)
+	if !ok {
+		return 0, 0, false
+	}
+	return count, sum, true
+}
+
+// parseBound parses field keys of bucket bounds and quantiles, other fields
+// such as exemplars are skipped.
+func parseBound(key string) (float64, bool) {
+	bound, err := strconv.ParseFloat(key, 64)
+	if err != nil || math.IsNaN(bound) {
+		return 0, false
+	}
+	return bound, true
+}
+
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
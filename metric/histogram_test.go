@@ -0,0 +1,89 @@
+package metric
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramRoundTrip(t *testing.T) {
+	value := HistogramValue{
+		Buckets: []Bucket{
+			{UpperBound: 0.1, Count: 10},
+			{UpperBound: 0.5, Count: 25},
+			{UpperBound: math.Inf(1), Count: 30},
+		},
+		Count: 30,
+		Sum:   7.5,
+	}
+	m := NewHistogram("http_request_duration_seconds", map[string]string{"method": "get"}, value, time.Unix(0, 0))
+	require.Equal(t, telegraf.Histogram, m.Type())
+	require.Equal(t, map[string]interface{}{
+		"0.1":   10.0,
+		"0.5":   25.0,
+		"+Inf":  30.0,
+		"count": 30.0,
+		"sum":   7.5,
+	}, m.Fields())
+
+	actual, ok := GetHistogram(m)
+	require.True(t, ok)
+	require.Equal(t, value, actual)
+
+	_, ok = GetSummary(m)
+	require.False(t, ok)
+}
+
+func TestSummaryRoundTrip(t *testing.T) {
+	value := SummaryValue{
+		Quantiles: []Quantile{
+			{Quantile: 0.5, Value: 0.2},
+			{Quantile: 0.99, Value: 1.3},
+		},
+		Count: 12,
+		Sum:   4,
+	}
+	m := NewSummary("rpc_duration_seconds", nil, value, time.Unix(0, 0))
+	require.Equal(t, telegraf.Summary, m.Type())
+
+	actual, ok := GetSummary(m)
+	require.True(t, ok)
+	require.Equal(t, value, actual)
+}
+
+func TestGetHistogramSplitLayout(t *testing.T) {
+	// Histograms split into a metric per bucket, e.g. of metric_version 2 of
+	// the prometheus input, are not decoded
+	m := New("prometheus",
+		map[string]string{"le": "0.5"},
+		map[string]interface{}{"http_request_duration_seconds_bucket": 25.0},
+		time.Unix(0, 0),
+		telegraf.Histogram,
+	)
+	_, ok := GetHistogram(m)
+	require.False(t, ok)
+}
+
+func TestGetHistogramSkipsOtherFields(t *testing.T) {
+	m := New("http_request_duration_seconds",
+		nil,
+		map[string]interface{}{
+			"0.5":                25.0,
+			"0.5_exemplar_value": 0.42,
+			"count":              int64(30),
+			"sum":                7.5,
+		},
+		time.Unix(0, 0),
+		telegraf.Histogram,
+	)
+	value, ok := GetHistogram(m)
+	require.True(t, ok)
+	require.Equal(t, HistogramValue{
+		Buckets: []Bucket{{UpperBound: 0.5, Count: 25}},
+		Count:   30,
+		Sum:     7.5,
+	}, value)
+}
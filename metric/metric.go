@@ -2,7 +2,6 @@ package metric
 
 import (
 	"fmt"
-	"hash/fnv"
 	"sort"
 	"time"
 
@@ -41,22 +40,23 @@ func New(
 	}
 
 	if len(tags) > 0 {
-		m.tags = make([]*telegraf.Tag, 0, len(tags))
+		m.tags = newTagList(len(tags))
 		for k, v := range tags {
-			m.tags = append(m.tags,
-				&telegraf.Tag{Key: k, Value: v})
+			m.tags = appendTag(m.tags, k, v)
 		}
-		sort.Slice(m.tags, func(i, j int) bool { return m.tags[i].Key < m.tags[j].Key })
+		sort.Sort(tagsByKey(m.tags))
 	}
 
 	if len(fields) > 0 {
-		m.fields = make([]*telegraf.Field, 0, len(fields))
+		m.fields = newFieldList(len(fields))
 		for k, v := range fields {
 			v := convertField(v)
 			if v == nil {
 				continue
 			}
-			m.AddField(k, v)
+			// keys of the map are unique, there is no need to check for
+			// existing fields
+			m.fields = appendField(m.fields, k, v)
 		}
 	}
 
@@ -66,22 +66,83 @@ func New(
 // FromMetric returns a deep copy of the metric with any tracking information
 // removed.
 func FromMetric(other telegraf.Metric) telegraf.Metric {
-	m := &metric{
+	return &metric{
 		name:   other.Name(),
-		tags:   make([]*telegraf.Tag, len(other.TagList())),
-		fields: make([]*telegraf.Field, len(other.FieldList())),
+		tags:   copyTagList(other.TagList()),
+		fields: copyFieldList(other.FieldList()),
 		tm:     other.Time(),
 		tp:     other.Type(),
 	}
+}
+
+type tagsByKey []*telegraf.Tag
+
+func (t tagsByKey) Len() int           { return len(t) }
+func (t tagsByKey) Less(i, j int) bool { return t[i].Key < t[j].Key }
+func (t tagsByKey) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
 
-	for i, tag := range other.TagList() {
-		m.tags[i] = &telegraf.Tag{Key: tag.Key, Value: tag.Value}
+// newTagList returns an empty tag list with room for n tags.  The tags
+// appended with appendTag share a single allocation.
+func newTagList(n int) []*telegraf.Tag {
+	list := make([]*telegraf.Tag, 0, n)
+	store := make([]telegraf.Tag, n)
+	for i := range store {
+		list = append(list, &store[i])
 	}
+	return list[:0]
+}
 
-	for i, field := range other.FieldList() {
-		m.fields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
+// appendTag adds a tag using the preallocated storage of the list if any is
+// left.
+func appendTag(list []*telegraf.Tag, key, value string) []*telegraf.Tag {
+	if len(list) < cap(list) && list[:cap(list)][len(list)] != nil {
+		list = list[:len(list)+1]
+		tag := list[len(list)-1]
+		tag.Key = key
+		tag.Value = value
+		return list
 	}
-	return m
+	return append(list, &telegraf.Tag{Key: key, Value: value})
+}
+
+func copyTagList(other []*telegraf.Tag) []*telegraf.Tag {
+	list := newTagList(len(other))
+	for _, tag := range other {
+		list = appendTag(list, tag.Key, tag.Value)
+	}
+	return list
+}
+
+// newFieldList returns an empty field list with room for n fields.  The
+// fields appended with appendField share a single allocation.
+func newFieldList(n int) []*telegraf.Field {
+	list := make([]*telegraf.Field, 0, n)
+	store := make([]telegraf.Field, n)
+	for i := range store {
+		list = append(list, &store[i])
+	}
+	return list[:0]
+}
+
+// appendField adds a field using the preallocated storage of the list if
+// any is left.
+func appendField(list []*telegraf.Field, key string, value interface{}) []*telegraf.Field {
+	if len(list) < cap(list) && list[:cap(list)][len(list)] != nil {
+		list = list[:len(list)+1]
+		field := list[len(list)-1]
+		field.Key = key
+		field.Value = value
+		return list
+	}
+	return append(list, &telegraf.Field{Key: key, Value: value})
+}
+
+func copyFieldList(other []*telegraf.Field) []*telegraf.Field {
+	list := newFieldList(len(other))
+	for _, field := range other {
+		list = appendField(list, field.Key, field.Value)
+	}
+	return list
 }
 
 func (m *metric) String() string {
@@ -230,35 +291,41 @@ func (m *metric) SetTime(t time.Time) {
 }
 
 func (m *metric) Copy() telegraf.Metric {
-	m2 := &metric{
+	return &metric{
 		name:   m.name,
-		tags:   make([]*telegraf.Tag, len(m.tags)),
-		fields: make([]*telegraf.Field, len(m.fields)),
+		tags:   copyTagList(m.tags),
+		fields: copyFieldList(m.fields),
 		tm:     m.tm,
 		tp:     m.tp,
 	}
+}
 
-	for i, tag := range m.tags {
-		m2.tags[i] = &telegraf.Tag{Key: tag.Key, Value: tag.Value}
-	}
+// FNV-1a parameters, see hash/fnv
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
 
-	for i, field := range m.fields {
-		m2.fields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
+// HashID returns the FNV-1a hash of the name and the tags, each followed by
+// a newline.  The hash is computed inline to avoid allocations.
+func (m *metric) HashID() uint64 {
+	h := uint64(fnvOffset64)
+	h = fnvAdd(h, m.name)
+	for _, tag := range m.tags {
+		h = fnvAdd(h, tag.Key)
+		h = fnvAdd(h, tag.Value)
 	}
-	return m2
+	return h
 }
 
-func (m *metric) HashID() uint64 {
-	h := fnv.New64a()
-	h.Write([]byte(m.name))
-	h.Write([]byte("\n"))
-	for _, tag := range m.tags {
-		h.Write([]byte(tag.Key))
-		h.Write([]byte("\n"))
-		h.Write([]byte(tag.Value))
-		h.Write([]byte("\n"))
+func fnvAdd(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
 	}
-	return h.Sum64()
+	h ^= '\n'
+	h *= fnvPrime64
+	return h
 }
 
 func (m *metric) Accept() {
@@ -273,20 +340,13 @@ func (m *metric) Drop() {
 // Convert field to a supported type or nil if unconvertible
 func convertField(v interface{}) interface{} {
 	switch v := v.(type) {
-	case float64:
-		return v
-	case int64:
-		return v
-	case string:
-		return v
-	case bool:
+	case float64, int64, string, bool, uint64:
+		// return the value as is to avoid allocating a new interface
 		return v
 	case int:
 		return int64(v)
 	case uint:
 		return uint64(v)
-	case uint64:
-		return v
 	case []byte:
 		return string(v)
 	case int32:
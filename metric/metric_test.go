@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"hash/fnv"
 	"testing"
 	"time"
 
@@ -326,3 +327,100 @@ func TestValueType(t *testing.T) {
 
 	assert.Equal(t, telegraf.Gauge, m.Type())
 }
+
+func TestHashID_FNV(t *testing.T) {
+	m := baseMetric()
+	m.AddTag("host", "localhost")
+
+	h := fnv.New64a()
+	h.Write([]byte(m.Name() + "\n"))
+	for _, tag := range m.TagList() {
+		h.Write([]byte(tag.Key + "\n" + tag.Value + "\n"))
+	}
+	require.Equal(t, h.Sum64(), m.HashID())
+}
+
+func TestCopyIsIndependent(t *testing.T) {
+	m := New(
+		"cpu",
+		map[string]string{
+			"host": "localhost",
+			"cpu":  "cpu0",
+		},
+		map[string]interface{}{
+			"value": float64(42),
+			"idle":  float64(1),
+		},
+		time.Now(),
+	)
+	m2 := m.Copy()
+
+	m2.TagList()[0].Value = "cpu1"
+	m2.FieldList()[0].Value = float64(0)
+	m2.AddTag("dc", "us-east-1")
+	m2.AddField("user", float64(2))
+	m2.RemoveTag("host")
+
+	require.Equal(t, map[string]string{"host": "localhost", "cpu": "cpu0"}, m.Tags())
+	require.Equal(t, map[string]interface{}{"value": float64(42), "idle": float64(1)}, m.Fields())
+	require.Equal(t, map[string]string{"dc": "us-east-1", "cpu": "cpu1"}, m2.Tags())
+	require.Len(t, m2.FieldList(), 3)
+}
+
+func TestNewSkipsUnsupportedFields(t *testing.T) {
+	m := New(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{
+			"value":   float64(42),
+			"invalid": struct{}{},
+		},
+		time.Now(),
+	)
+	require.Equal(t, map[string]interface{}{"value": float64(42)}, m.Fields())
+
+	m.AddField("idle", float64(1))
+	m.AddField("user", float64(2))
+	require.Equal(t, map[string]interface{}{"value": float64(42), "idle": float64(1), "user": float64(2)}, m.Fields())
+}
+
+func BenchmarkNew(b *testing.B) {
+	tags := map[string]string{
+		"host": "localhost",
+		"cpu":  "cpu0",
+		"dc":   "us-east-1",
+	}
+	fields := map[string]interface{}{
+		"usage_idle":   float64(91.5),
+		"usage_user":   float64(5.5),
+		"usage_system": float64(3),
+	}
+	now := time.Now()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		New("cpu", tags, fields, now)
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	m := baseMetric()
+	m.AddTag("host", "localhost")
+	m.AddTag("dc", "us-east-1")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Copy()
+	}
+}
+
+func BenchmarkHashID(b *testing.B) {
+	m := baseMetric()
+	m.AddTag("host", "localhost")
+	m.AddTag("dc", "us-east-1")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.HashID()
+	}
+}
@@ -193,7 +193,49 @@ func (b *Buffer) Reject(batch []telegraf.Metric) {
 	b.Lock()
 	defer b.Unlock()
 
+	b.reject(batch)
+}
+
+// Partial completes the batch, acquired from Batch(), after a partial write.
+// The metrics at the accept indices are marked as successfully written, the
+// metrics at the reject indices are dropped and all remaining metrics are
+// returned to the buffer as unsent.  The number of returned metrics is
+// reported.
+func (b *Buffer) Partial(batch []telegraf.Metric, accept, reject []int) int {
+	b.Lock()
+	defer b.Unlock()
+
+	done := make([]bool, len(batch))
+	for _, i := range accept {
+		if i < 0 || i >= len(batch) || done[i] {
+			continue
+		}
+		b.metricWritten(batch[i])
+		done[i] = true
+	}
+	for _, i := range reject {
+		if i < 0 || i >= len(batch) || done[i] {
+			continue
+		}
+		b.metricDropped(batch[i])
+		done[i] = true
+	}
+
+	keep := make([]telegraf.Metric, 0, len(batch))
+	for i, m := range batch {
+		if !done[i] {
+			keep = append(keep, m)
+		}
+	}
+
+	b.reject(keep)
+	return len(keep)
+}
+
+func (b *Buffer) reject(batch []telegraf.Metric) {
 	if len(batch) == 0 {
+		b.resetBatch()
+		b.BufferSize.Set(int64(b.length()))
 		return
 	}
 
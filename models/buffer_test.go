@@ -723,3 +723,36 @@ func TestBuffer_RejectEmptyBatch(t *testing.T) {
 		require.NotNil(t, m)
 	}
 }
+
+func TestBuffer_PartialReturnsUnhandled(t *testing.T) {
+	b := setup(NewBuffer("test", "", 5))
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3), MetricTime(4))
+	batch := b.Batch(3)
+	b.Add(MetricTime(5))
+
+	retry := b.Partial(batch, []int{0}, []int{2})
+	require.Equal(t, 1, retry)
+	require.Equal(t, int64(1), b.MetricsWritten.Get())
+	require.Equal(t, int64(1), b.MetricsDropped.Get())
+
+	batch = b.Batch(5)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(2),
+			MetricTime(4),
+			MetricTime(5),
+		}, batch)
+}
+
+func TestBuffer_PartialAllHandled(t *testing.T) {
+	m := Metric()
+	b := setup(NewBuffer("test", "", 5))
+	b.Add(m, m, m)
+	batch := b.Batch(2)
+
+	retry := b.Partial(batch, []int{1}, []int{0, 1, 5})
+	require.Equal(t, 0, retry)
+	require.Equal(t, int64(1), b.MetricsWritten.Get())
+	require.Equal(t, int64(1), b.MetricsDropped.Get())
+	require.Equal(t, 1, b.Len())
+}
@@ -1,10 +1,12 @@
 package models
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/selfstat"
 )
@@ -17,6 +19,8 @@ type RunningAggregator struct {
 	periodEnd   time.Time
 	log         telegraf.Logger
 
+	dropOriginalFilter filter.Filter
+
 	MetricsPushed   selfstat.Stat
 	MetricsFiltered selfstat.Stat
 	MetricsDropped  selfstat.Stat
@@ -68,11 +72,17 @@ func NewRunningAggregator(aggregator telegraf.Aggregator, config *AggregatorConf
 type AggregatorConfig struct {
 	Name         string
 	Alias        string
+	ID           string
 	DropOriginal bool
 	Period       time.Duration
 	Delay        time.Duration
 	Grace        time.Duration
 
+	// DropOriginalNamePass and DropOriginalNameDrop restrict drop_original to
+	// the metrics with matching names, the others are passed on as well.
+	DropOriginalNamePass []string
+	DropOriginalNameDrop []string
+
 	NameOverride      string
 	MeasurementPrefix string
 	MeasurementSuffix string
@@ -85,6 +95,14 @@ func (r *RunningAggregator) LogName() string {
 }
 
 func (r *RunningAggregator) Init() error {
+	if len(r.Config.DropOriginalNamePass) > 0 || len(r.Config.DropOriginalNameDrop) > 0 {
+		var err error
+		r.dropOriginalFilter, err = filter.NewIncludeExcludeFilter(r.Config.DropOriginalNamePass, r.Config.DropOriginalNameDrop)
+		if err != nil {
+			return fmt.Errorf("error compiling drop_original filters for %s: %w", r.LogName(), err)
+		}
+	}
+
 	if p, ok := r.Aggregator.(telegraf.Initializer); ok {
 		err := p.Init()
 		if err != nil {
@@ -138,7 +156,7 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 	r.Config.Filter.Modify(m)
 	if len(m.FieldList()) == 0 {
 		r.MetricsFiltered.Incr(1)
-		return r.Config.DropOriginal
+		return r.dropOriginal(m)
 	}
 
 	r.Lock()
@@ -148,11 +166,22 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 		r.log.Debugf("Metric is outside aggregation window; discarding. %s: m: %s e: %s g: %s",
 			m.Time(), r.periodStart, r.periodEnd, r.Config.Grace)
 		r.MetricsDropped.Incr(1)
-		return r.Config.DropOriginal
+		return r.dropOriginal(m)
 	}
 
 	r.Aggregator.Add(m)
-	return r.Config.DropOriginal
+	return r.dropOriginal(m)
+}
+
+// dropOriginal returns true if the original of the metric should be dropped.
+func (r *RunningAggregator) dropOriginal(m telegraf.Metric) bool {
+	if !r.Config.DropOriginal {
+		return false
+	}
+	if r.dropOriginalFilter != nil {
+		return r.dropOriginalFilter.Match(m.Name())
+	}
+	return true
 }
 
 func (r *RunningAggregator) Push(acc telegraf.Accumulator) {
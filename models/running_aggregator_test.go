@@ -212,6 +212,38 @@ func TestAddDropOriginal(t *testing.T) {
 	require.False(t, ra.Add(m2))
 }
 
+func TestAddDropOriginalNameFilter(t *testing.T) {
+	ra := NewRunningAggregator(&TestAggregator{}, &AggregatorConfig{
+		Name: "TestRunningAggregator",
+		Filter: Filter{
+			NamePass: []string{"*"},
+		},
+		DropOriginal:         true,
+		DropOriginalNamePass: []string{"cpu*"},
+		DropOriginalNameDrop: []string{"cpu_raw"},
+	})
+	require.NoError(t, ra.Config.Filter.Compile())
+	require.NoError(t, ra.Init())
+
+	now := time.Now()
+	ra.UpdateWindow(now, now.Add(ra.Config.Period))
+
+	newMetric := func(name string) telegraf.Metric {
+		return testutil.MustMetric(name,
+			map[string]string{},
+			map[string]interface{}{
+				"value": int64(101),
+			},
+			now,
+			telegraf.Untyped)
+	}
+
+	require.True(t, ra.Add(newMetric("cpu")))
+	// these metrics are aggregated, but the originals are passed on as well
+	require.False(t, ra.Add(newMetric("cpu_raw")))
+	require.False(t, ra.Add(newMetric("mem")))
+}
+
 func TestAddDoesNotModifyMetric(t *testing.T) {
 	ra := NewRunningAggregator(&TestAggregator{}, &AggregatorConfig{
 		Name: "TestRunningAggregator",
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -18,9 +19,16 @@ type RunningInput struct {
 
 	log         telegraf.Logger
 	defaultTags map[string]string
+	started     bool
 
 	MetricsGathered selfstat.Stat
 	GatherTime      selfstat.Stat
+
+	// Only set with a series limit
+	limiter        *seriesLimiter
+	limitReached   sync.Once
+	MetricsDropped selfstat.Stat
+	SeriesEvicted  selfstat.Stat
 }
 
 func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
@@ -37,7 +45,7 @@ func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
 	})
 	SetLoggerOnPlugin(input, logger)
 
-	return &RunningInput{
+	ri := &RunningInput{
 		Input:  input,
 		Config: config,
 		MetricsGathered: selfstat.Register(
@@ -52,12 +60,19 @@ func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
 		),
 		log: logger,
 	}
+	if config.MaxSeries > 0 {
+		ri.limiter = newSeriesLimiter(config.MaxSeries, config.SeriesLimitPolicy)
+		ri.MetricsDropped = selfstat.Register("gather", "metrics_dropped", tags)
+		ri.SeriesEvicted = selfstat.Register("gather", "series_evicted", tags)
+	}
+	return ri
 }
 
 // InputConfig is the common config for all inputs.
 type InputConfig struct {
 	Name             string
 	Alias            string
+	ID               string
 	Interval         time.Duration
 	CollectionJitter time.Duration
 	Precision        time.Duration
@@ -67,6 +82,18 @@ type InputConfig struct {
 	MeasurementSuffix string
 	Tags              map[string]string
 	Filter            Filter
+
+	// StartupErrorBehavior selects how errors on Start of service inputs and
+	// on the first Gather are handled, one of "error", "ignore" or "retry".
+	StartupErrorBehavior string
+
+	// MaxSeries limits the number of series of the input, unlimited when
+	// zero.  SeriesLimitPolicy selects whether metrics of new series are
+	// dropped once the limit is reached, "drop" or empty, or the least
+	// recently seen series is evicted for them, "evict".  Series seen during
+	// the current interval are never evicted.
+	MaxSeries         int
+	SeriesLimitPolicy string
 }
 
 func (r *RunningInput) metricFiltered(metric telegraf.Metric) {
@@ -87,6 +114,29 @@ func (r *RunningInput) Init() error {
 	return nil
 }
 
+// Start starts the input if it is a service input.
+func (r *RunningInput) Start(acc telegraf.Accumulator) error {
+	si, ok := r.Input.(telegraf.ServiceInput)
+	if !ok {
+		return nil
+	}
+	if err := si.Start(acc); err != nil {
+		return err
+	}
+	r.started = true
+	return nil
+}
+
+// Stop stops the input if it is a service input that was started.
+func (r *RunningInput) Stop() {
+	si, ok := r.Input.(telegraf.ServiceInput)
+	if !ok || !r.started {
+		return
+	}
+	si.Stop()
+	r.started = false
+}
+
 func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 	if ok := r.Config.Filter.Select(metric); !ok {
 		r.metricFiltered(metric)
@@ -107,12 +157,42 @@ func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 		return nil
 	}
 
+	if r.limiter != nil && !r.admitSeries(m) {
+		r.metricFiltered(m)
+		return nil
+	}
+
 	r.MetricsGathered.Incr(1)
 	GlobalMetricsGathered.Incr(1)
 	return m
 }
 
+// admitSeries applies the series limit to the metric.
+func (r *RunningInput) admitSeries(metric telegraf.Metric) bool {
+	accepted, evicted := r.limiter.admit(metric.HashID())
+	if evicted {
+		r.SeriesEvicted.Incr(1)
+	}
+	if !accepted {
+		r.MetricsDropped.Incr(1)
+	}
+	if evicted || !accepted {
+		r.limitReached.Do(func() {
+			if r.limiter.evict {
+				r.log.Warnf("Reached the limit of %d series, evicting series not seen during the interval", r.Config.MaxSeries)
+			} else {
+				r.log.Warnf("Reached the limit of %d series, dropping metrics of new series", r.Config.MaxSeries)
+			}
+		})
+	}
+	return accepted
+}
+
 func (r *RunningInput) Gather(acc telegraf.Accumulator) error {
+	if r.limiter != nil {
+		r.limiter.nextRound()
+	}
+
 	start := time.Now()
 	err := r.Input.Gather(acc)
 	elapsed := time.Since(start)
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -273,6 +274,106 @@ func TestMetricErrorCounters(t *testing.T) {
 	require.GreaterOrEqual(t, int64(1), GlobalGatherErrors.Get())
 }
 
+func TestMakeMetricSeriesLimitDrop(t *testing.T) {
+	ri := NewRunningInput(&testInput{}, &InputConfig{
+		Name:              "TestMakeMetricSeriesLimitDrop",
+		MaxSeries:         2,
+		SeriesLimitPolicy: "drop",
+	})
+
+	now := time.Now()
+	series := func(pod string) telegraf.Metric {
+		return testutil.MustMetric("kube", map[string]string{"pod": pod}, map[string]interface{}{"value": 1}, now)
+	}
+
+	require.NotNil(t, ri.MakeMetric(series("a")))
+	require.NotNil(t, ri.MakeMetric(series("b")))
+	require.Nil(t, ri.MakeMetric(series("c")))
+	require.NotNil(t, ri.MakeMetric(series("a")))
+	require.Nil(t, ri.MakeMetric(series("d")))
+
+	require.Equal(t, int64(3), ri.MetricsGathered.Get())
+	require.Equal(t, int64(2), ri.MetricsDropped.Get())
+	require.Equal(t, int64(0), ri.SeriesEvicted.Get())
+}
+
+func TestMakeMetricSeriesLimitEvict(t *testing.T) {
+	ri := NewRunningInput(&testInput{}, &InputConfig{
+		Name:              "TestMakeMetricSeriesLimitEvict",
+		MaxSeries:         2,
+		SeriesLimitPolicy: "evict",
+	})
+
+	now := time.Now()
+	series := func(pod string) telegraf.Metric {
+		return testutil.MustMetric("kube", map[string]string{"pod": pod}, map[string]interface{}{"value": 1}, now)
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, ri.Gather(&acc))
+	require.NotNil(t, ri.MakeMetric(series("a")))
+	require.NotNil(t, ri.MakeMetric(series("b")))
+	// Both series were seen during the interval
+	require.Nil(t, ri.MakeMetric(series("c")))
+	require.Equal(t, int64(0), ri.SeriesEvicted.Get())
+
+	require.NoError(t, ri.Gather(&acc))
+	require.NotNil(t, ri.MakeMetric(series("a")))
+	// Evicts b, the least recently seen series
+	require.NotNil(t, ri.MakeMetric(series("c")))
+	require.Equal(t, int64(1), ri.SeriesEvicted.Get())
+	require.Nil(t, ri.MakeMetric(series("b")))
+
+	require.NoError(t, ri.Gather(&acc))
+	// Evicts a
+	require.NotNil(t, ri.MakeMetric(series("b")))
+	require.Equal(t, int64(2), ri.SeriesEvicted.Get())
+	require.NotNil(t, ri.MakeMetric(series("c")))
+	require.Equal(t, int64(2), ri.SeriesEvicted.Get())
+
+	require.Equal(t, int64(6), ri.MetricsGathered.Get())
+	require.Equal(t, int64(2), ri.MetricsDropped.Get())
+}
+
+func TestMakeMetricSeriesLimitEvictBoundsEmittedSeries(t *testing.T) {
+	ri := NewRunningInput(&testInput{}, &InputConfig{
+		Name:              "TestMakeMetricSeriesLimitEvictBoundsEmittedSeries",
+		MaxSeries:         10,
+		SeriesLimitPolicy: "evict",
+	})
+
+	// A runaway input adds a unique tag to every metric
+	var acc testutil.Accumulator
+	for interval := 0; interval < 5; interval++ {
+		require.NoError(t, ri.Gather(&acc))
+
+		emitted := make(map[uint64]bool)
+		for i := 0; i < 100; i++ {
+			pod := strconv.Itoa(interval*100 + i)
+			m := testutil.MustMetric("kube", map[string]string{"pod": pod}, map[string]interface{}{"value": 1}, time.Now())
+			if m := ri.MakeMetric(m); m != nil {
+				emitted[m.HashID()] = true
+			}
+		}
+		require.Len(t, emitted, 10)
+	}
+	require.Equal(t, int64(40), ri.SeriesEvicted.Get())
+	require.Equal(t, int64(450), ri.MetricsDropped.Get())
+}
+
+func TestMakeMetricSeriesUnlimited(t *testing.T) {
+	ri := NewRunningInput(&testInput{}, &InputConfig{
+		Name: "TestMakeMetricSeriesUnlimited",
+	})
+	require.Nil(t, ri.MetricsDropped)
+	require.Nil(t, ri.SeriesEvicted)
+
+	for i := 0; i < 100; i++ {
+		m := testutil.MustMetric("kube", map[string]string{"pod": strconv.Itoa(i)}, map[string]interface{}{"value": 1}, time.Now())
+		require.NotNil(t, ri.MakeMetric(m))
+	}
+}
+
 type testInput struct{}
 
 func (t *testInput) Description() string                 { return "" }
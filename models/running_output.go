@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +22,7 @@ const (
 type OutputConfig struct {
 	Name   string
 	Alias  string
+	ID     string
 	Filter Filter
 
 	FlushInterval     time.Duration
@@ -28,6 +30,14 @@ type OutputConfig struct {
 	MetricBufferLimit int
 	MetricBatchSize   int
 
+	// MetricBatchFullFlush overrides the agent metric_batch_full_flush when
+	// set.
+	MetricBatchFullFlush *bool
+
+	// Precision truncates the metric timestamps before they are buffered,
+	// disabled when zero.
+	Precision time.Duration
+
 	NameOverride string
 	NamePrefix   string
 	NameSuffix   string
@@ -162,6 +172,10 @@ func (r *RunningOutput) AddMetric(metric telegraf.Metric) {
 		metric.AddSuffix(r.Config.NameSuffix)
 	}
 
+	if r.Config.Precision > 0 {
+		metric.SetTime(metric.Time().Truncate(r.Config.Precision))
+	}
+
 	dropped := r.buffer.Add(metric)
 	atomic.AddInt64(&r.droppedMetrics, int64(dropped))
 
@@ -198,12 +212,9 @@ func (r *RunningOutput) Write() error {
 			break
 		}
 
-		err := r.write(batch)
-		if err != nil {
-			r.buffer.Reject(batch)
+		if err := r.completeBatch(batch, r.write(batch)); err != nil {
 			return err
 		}
-		r.buffer.Accept(batch)
 	}
 	return nil
 }
@@ -216,12 +227,31 @@ func (r *RunningOutput) WriteBatch() error {
 	}
 
 	err := r.write(batch)
-	if err != nil {
+	return r.completeBatch(batch, err)
+}
+
+// completeBatch updates the buffer with the result of writing the batch.  On
+// a partial write only the metrics not handled by the output are returned to
+// the buffer and no error is reported if nothing is left to retry.
+func (r *RunningOutput) completeBatch(batch []telegraf.Metric, err error) error {
+	if err == nil {
+		r.buffer.Accept(batch)
+		return nil
+	}
+
+	var partial *telegraf.PartialWriteError
+	if !errors.As(err, &partial) {
 		r.buffer.Reject(batch)
 		return err
 	}
-	r.buffer.Accept(batch)
 
+	retry := r.buffer.Partial(batch, partial.MetricsAccept, partial.MetricsReject)
+	if retry > 0 {
+		return err
+	}
+	if len(partial.MetricsReject) > 0 {
+		r.log.Errorf("Dropped %d metrics rejected by the output: %v", len(partial.MetricsReject), err)
+	}
 	return nil
 }
 
@@ -263,3 +293,9 @@ func (r *RunningOutput) Log() telegraf.Logger {
 func (r *RunningOutput) BufferLength() int {
 	return r.buffer.Len()
 }
+
+// MetricsWritten returns the number of metrics successfully written by the
+// output.
+func (r *RunningOutput) MetricsWritten() int64 {
+	return r.buffer.MetricsWritten.Get()
+}
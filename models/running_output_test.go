@@ -228,6 +228,23 @@ func TestRunningOutput_NameOverride(t *testing.T) {
 	assert.Equal(t, "new_metric_name", m.Metrics()[0].Name())
 }
 
+func TestRunningOutput_Precision(t *testing.T) {
+	conf := &OutputConfig{
+		Precision: time.Millisecond,
+	}
+
+	m := &mockOutput{}
+	ro := NewRunningOutput(m, conf, 1000, 10000)
+
+	ro.AddMetric(testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 42},
+		time.Unix(1, 123456789)))
+
+	err := ro.Write()
+	assert.NoError(t, err)
+	assert.Len(t, m.Metrics(), 1)
+	assert.Equal(t, time.Unix(1, 123000000), m.Metrics()[0].Time())
+}
+
 // Test that measurement name prefix is added correctly
 func TestRunningOutput_NamePrefix(t *testing.T) {
 	conf := &OutputConfig{
@@ -460,6 +477,32 @@ func TestRunningOutputWriteFailOrder3(t *testing.T) {
 	assert.Equal(t, expected, m.Metrics())
 }
 
+func TestRunningOutputPartialWrite(t *testing.T) {
+	conf := &OutputConfig{
+		Filter: Filter{},
+	}
+
+	m := &partialOutput{accept: []int{0, 2}, reject: []int{1}}
+	ro := NewRunningOutput(m, conf, 5, 10)
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+
+	// metrics neither accepted nor rejected are retried
+	err := ro.Write()
+	require.Error(t, err)
+	require.Equal(t, 2, ro.BufferLength())
+	require.Equal(t, []telegraf.Metric{first5[0], first5[2]}, m.metrics)
+
+	// nothing left to retry
+	m.accept = []int{1}
+	m.reject = []int{0}
+	err = ro.Write()
+	require.NoError(t, err)
+	require.Equal(t, 0, ro.BufferLength())
+	require.Equal(t, []telegraf.Metric{first5[0], first5[2], first5[4]}, m.metrics)
+}
+
 func TestInternalMetrics(t *testing.T) {
 	_ = NewRunningOutput(
 		&mockOutput{},
@@ -576,3 +619,29 @@ func (m *perfOutput) Write(_ []telegraf.Metric) error {
 	}
 	return nil
 }
+
+// partialOutput writes the metrics at the accept indices of each batch and
+// reports a partial write if not all metrics were accepted.
+type partialOutput struct {
+	mockOutput
+
+	accept []int
+	reject []int
+}
+
+func (m *partialOutput) Write(metrics []telegraf.Metric) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, i := range m.accept {
+		m.metrics = append(m.metrics, metrics[i])
+	}
+	if len(m.accept) == len(metrics) {
+		return nil
+	}
+	return &telegraf.PartialWriteError{
+		Err:           fmt.Errorf("partial write"),
+		MetricsAccept: m.accept,
+		MetricsReject: m.reject,
+	}
+}
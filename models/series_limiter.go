@@ -0,0 +1,73 @@
+package models
+
+import (
+	"container/list"
+	"sync"
+)
+
+// seriesLimiter tracks the series emitted by an input, limiting their number.
+// Once the limit is reached metrics of new series are either dropped or the
+// least recently seen series is evicted to make room.  Only series not seen
+// during the current interval are evicted, so no more series than the limit
+// are emitted per interval with either policy.
+type seriesLimiter struct {
+	limit int
+	evict bool
+
+	sync.Mutex
+	round  uint64
+	lru    *list.List // tracked series, most recently seen first
+	series map[uint64]*list.Element
+}
+
+type trackedSeries struct {
+	id uint64
+	// round is the interval the series was last seen in
+	round uint64
+}
+
+func newSeriesLimiter(limit int, policy string) *seriesLimiter {
+	return &seriesLimiter{
+		limit:  limit,
+		evict:  policy == "evict",
+		lru:    list.New(),
+		series: make(map[uint64]*list.Element),
+	}
+}
+
+// nextRound starts a new interval.
+func (l *seriesLimiter) nextRound() {
+	l.Lock()
+	defer l.Unlock()
+	l.round++
+}
+
+// admit reports whether a metric of the series is accepted and whether
+// another series was evicted for it.
+func (l *seriesLimiter) admit(id uint64) (accepted bool, evicted bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	if e, ok := l.series[id]; ok {
+		e.Value.(*trackedSeries).round = l.round
+		l.lru.MoveToFront(e)
+		return true, false
+	}
+
+	if len(l.series) >= l.limit {
+		if !l.evict {
+			return false, false
+		}
+		// All series were seen during the current interval, evicting one
+		// would exceed the limit of series emitted
+		oldest := l.lru.Back()
+		if oldest.Value.(*trackedSeries).round == l.round {
+			return false, false
+		}
+		delete(l.series, oldest.Value.(*trackedSeries).id)
+		l.lru.Remove(oldest)
+		evicted = true
+	}
+	l.series[id] = l.lru.PushFront(&trackedSeries{id: id, round: l.round})
+	return true, evicted
+}
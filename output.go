@@ -27,3 +27,25 @@ type AggregatingOutput interface {
 	// Reset signals the the aggregator period is completed.
 	Reset()
 }
+
+// PartialWriteError may be returned by an Output's Write function if only a
+// part of the batch was handled.  The metrics are referenced by their index in
+// the batch passed to Write.  Accepted metrics are considered written,
+// rejected metrics can never be written and are dropped, all other metrics of
+// the batch are kept and retried with the next write.
+type PartialWriteError struct {
+	// Err is the error causing the write to be incomplete.
+	Err error
+	// MetricsAccept contains the indices of the metrics written successfully.
+	MetricsAccept []int
+	// MetricsReject contains the indices of the metrics to drop.
+	MetricsReject []int
+}
+
+func (e *PartialWriteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialWriteError) Unwrap() error {
+	return e.Err
+}
@@ -11,6 +11,29 @@ type Initializer interface {
 	Init() error
 }
 
+// StatefulPlugin is an interface that all plugin types can optionally
+// implement to keep their state across restarts of Telegraf when the agent
+// has a statefile configured.
+type StatefulPlugin interface {
+	// GetState returns the state of the plugin, it must be serializable to
+	// JSON.  It is called after the plugin is stopped.
+	GetState() interface{}
+
+	// SetState restores the state stored by a previous run, of the same type
+	// as returned by GetState.  It is called after Init and before the
+	// plugin is started.
+	SetState(state interface{}) error
+}
+
+// OrderPreserver is an interface processors emitting metrics asynchronously,
+// e.g. after lookups done in parallel, can implement to keep metrics in the
+// order they were added when the agent is configured to preserve the order.
+type OrderPreserver interface {
+	// PreserveOrder makes the processor emit the metrics in the order they
+	// were added.  It is called after Init and before Start.
+	PreserveOrder()
+}
+
 // PluginDescriber contains the functions all plugins must implement to describe
 // themselves to Telegraf. Note that all plugins may define a logger that is
 // not part of the interface, but will receive an injected logger if it's set.
@@ -42,3 +65,13 @@ type Logger interface {
 	// Info logs an information message, patterned after log.Print.
 	Info(args ...interface{})
 }
+
+// DeprecationInfo contains information about a deprecated plugin or option.
+type DeprecationInfo struct {
+	// Since is the version the plugin or option was deprecated in.
+	Since string
+	// RemovalIn is the version the plugin or option will be removed in.
+	RemovalIn string
+	// Notice is a hint on what to use instead.
+	Notice string
+}
@@ -1,13 +1,12 @@
+// Package all registers the aggregator plugins.  Each plugin is imported by
+// its own generated file, so a binary with a subset of plugins can be built
+// using the "custom" build tag together with "aggregators" to include all aggregators
+// or "aggregators.<name>" to include single ones, e.g.
+//
+//	go build -tags custom,aggregators.minmax ./cmd/telegraf
+//
+// The name of a plugin is its directory below plugins/aggregators, with slashes
+// replaced by underscores.  Run "go generate" after adding a plugin.
 package all
 
-import (
-	//Blank imports for plugins to register themselves
-	_ "github.com/influxdata/telegraf/plugins/aggregators/basicstats"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/derivative"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/final"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/histogram"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/merge"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/minmax"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/quantile"
-	_ "github.com/influxdata/telegraf/plugins/aggregators/valuecounter"
-)
+//go:generate go run ../../../scripts/generate_plugins.go aggregators
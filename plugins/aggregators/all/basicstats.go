@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || aggregators || aggregators.basicstats
+// +build !custom aggregators aggregators.basicstats
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/basicstats" // register plugin
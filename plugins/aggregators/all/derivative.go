@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || aggregators || aggregators.derivative
+// +build !custom aggregators aggregators.derivative
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/derivative" // register plugin
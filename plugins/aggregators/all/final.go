@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || aggregators || aggregators.final
+// +build !custom aggregators aggregators.final
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/final" // register plugin
@@ -0,0 +1,6 @@
+package aggregators
+
+import "github.com/influxdata/telegraf"
+
+// Deprecations lists the deprecated aggregators.
+var Deprecations = map[string]telegraf.DeprecationInfo{}
@@ -18,6 +18,9 @@ type HTTPClientConfig struct {
 	Timeout         config.Duration `toml:"timeout"`
 	IdleConnTimeout config.Duration `toml:"idle_conn_timeout"`
 
+	// Log the metadata of the requests for troubleshooting
+	LogRequests bool `toml:"log_requests"`
+
 	proxy.HTTPProxy
 	tls.ClientConfig
 	oauthConfig.OAuth2Config
@@ -50,6 +53,9 @@ func (h *HTTPClientConfig) CreateClient(ctx context.Context, log telegraf.Logger
 		Transport: transport,
 		Timeout:   time.Duration(timeout),
 	}
+	if h.LogRequests && log != nil {
+		client.Transport = newLoggingTransport(transport, log)
+	}
 
 	client = h.OAuth2Config.CreateOauth2Client(ctx, client)
 
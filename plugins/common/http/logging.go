@@ -0,0 +1,128 @@
+package httpconfig
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// loggingTransport logs the metadata of every request going through it.
+// Credentials and query parameter values are redacted from the logged URLs,
+// headers and bodies are never logged.
+type loggingTransport struct {
+	next http.RoundTripper
+	log  telegraf.Logger
+
+	sync.Mutex
+	// failures counts the consecutive failed requests per method and URL,
+	// identifying retries of the plugin
+	failures map[string]int
+}
+
+func newLoggingTransport(next http.RoundTripper, log telegraf.Logger) *loggingTransport {
+	return &loggingTransport{
+		next:     next,
+		log:      log,
+		failures: make(map[string]int),
+	}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := req.Method + " " + redactURL(req.URL)
+
+	t.Lock()
+	attempt := t.failures[target] + 1
+	t.Unlock()
+
+	sent := &countingReader{}
+	if req.Body != nil {
+		req = req.Clone(req.Context())
+		sent.ReadCloser = req.Body
+		req.Body = sent
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.finished(target, false)
+		t.log.Infof("%s (attempt %d) failed after %s, sent %d bytes: %v", target, attempt, elapsed, sent.count(), err)
+		return resp, err
+	}
+	t.finished(target, resp.StatusCode < 400)
+
+	// The received bytes are known once the plugin is done with the body
+	resp.Body = &countingReader{
+		ReadCloser: resp.Body,
+		onClose: func(received int64) {
+			t.log.Infof("%s (attempt %d): %s in %s, sent %d bytes, received %d bytes",
+				target, attempt, resp.Status, elapsed, sent.count(), received)
+		},
+	}
+	return resp, nil
+}
+
+func (t *loggingTransport) finished(target string, success bool) {
+	t.Lock()
+	defer t.Unlock()
+	if success {
+		delete(t.failures, target)
+		return
+	}
+	t.failures[target]++
+}
+
+// redactURL returns the URL without the password and the values of the query
+// parameters, which often carry tokens.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if u.User != nil {
+		redacted.User = url.User(u.User.Username())
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key, values := range query {
+			for i := range values {
+				values[i] = "xxxxx"
+			}
+			query[key] = values
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// countingReader counts the bytes read from the wrapped body and reports the
+// count once closed.
+type countingReader struct {
+	io.ReadCloser
+	n       int64 // accessed atomically
+	onClose func(n int64)
+	once    sync.Once
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+// count returns the number of bytes read, the transport may still be sending
+// the request body.
+func (r *countingReader) count() int64 {
+	return atomic.LoadInt64(&r.n)
+}
+
+func (r *countingReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.onClose != nil {
+		r.once.Do(func() { r.onClose(r.count()) })
+	}
+	return err
+}
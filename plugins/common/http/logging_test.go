@@ -0,0 +1,80 @@
+package httpconfig
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type captureLogger struct {
+	testutil.Logger
+
+	sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) Infof(format string, args ...interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLogRequests(t *testing.T) {
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, err = w.Write([]byte("ok"))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	log := &captureLogger{}
+	cfg := HTTPClientConfig{LogRequests: true}
+	client, err := cfg.CreateClient(context.Background(), log)
+	require.NoError(t, err)
+
+	url := strings.Replace(ts.URL, "http://", "http://user:secret@", 1) + "/write?db=telegraf&token=s3cret"
+	post := func() {
+		resp, err := client.Post(url, "text/plain", strings.NewReader("cpu value=42"))
+		require.NoError(t, err)
+		_, err = ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	post()
+	post()
+	fail = false
+	post()
+	post()
+
+	target := "POST " + strings.Replace(ts.URL, "http://", "http://user@", 1) + "/write?db=xxxxx&token=xxxxx"
+	require.Len(t, log.lines, 4)
+	for i, status := range []string{"503 Service Unavailable", "503 Service Unavailable", "200 OK", "200 OK"} {
+		attempt := []int{1, 2, 3, 1}[i]
+		require.True(t, strings.HasPrefix(log.lines[i], fmt.Sprintf("%s (attempt %d): %s in ", target, attempt, status)), log.lines[i])
+		require.NotContains(t, log.lines[i], "secret")
+		require.NotContains(t, log.lines[i], "s3cret")
+	}
+	require.True(t, strings.HasSuffix(log.lines[3], "sent 12 bytes, received 2 bytes"), log.lines[3])
+}
+
+func TestLogRequestsDisabled(t *testing.T) {
+	cfg := HTTPClientConfig{}
+	client, err := cfg.CreateClient(context.Background(), &captureLogger{})
+	require.NoError(t, err)
+	_, ok := client.Transport.(*loggingTransport)
+	require.False(t, ok)
+}
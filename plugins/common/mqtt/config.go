@@ -0,0 +1,94 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+)
+
+// schemes supported by the paho client, the websocket ones being used for
+// brokers behind HTTP proxies.
+var schemes = map[string]bool{
+	"tcp":      true,
+	"mqtt":     true,
+	"ssl":      true,
+	"tls":      true,
+	"tcps":     true,
+	"mqtts":    true,
+	"mqtt+ssl": true,
+	"ws":       true,
+	"wss":      true,
+	"unix":     true,
+}
+
+// Config for the transport common to all MQTT clients.
+type Config struct {
+	tlsint.ClientConfig
+
+	TLSSessionResumption bool              `toml:"tls_session_resumption"`
+	WebsocketHeaders     map[string]string `toml:"websocket_headers"`
+}
+
+// SetConfig on the paho.ClientOptions object from the Config struct, adding
+// the servers as brokers.  Servers without a scheme connect with TCP, or TLS
+// if configured.
+func (c *Config) SetConfig(opts *paho.ClientOptions, servers []string) error {
+	tlsCfg, err := c.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		broker, err := BrokerURL(server, tlsCfg != nil)
+		if err != nil {
+			return err
+		}
+		opts.AddBroker(broker)
+	}
+
+	if c.TLSSessionResumption {
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{}
+		}
+		// The cache outlives the connections, reconnecting to any of the
+		// brokers resumes the session instead of a full handshake.
+		tlsCfg.ClientSessionCache = tls.NewLRUClientSessionCache(len(servers))
+	}
+	if tlsCfg != nil {
+		opts.SetTLSConfig(tlsCfg)
+	}
+
+	if len(c.WebsocketHeaders) > 0 {
+		headers := make(http.Header)
+		for k, v := range c.WebsocketHeaders {
+			headers.Set(k, v)
+		}
+		opts.SetHTTPHeaders(headers)
+	}
+	return nil
+}
+
+// BrokerURL returns the URL of the broker for a server given either as URL
+// or as host:port.
+func BrokerURL(server string, tlsEnabled bool) (string, error) {
+	if !strings.Contains(server, "://") {
+		if tlsEnabled {
+			return "ssl://" + server, nil
+		}
+		return "tcp://" + server, nil
+	}
+
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("invalid server %q: %v", server, err)
+	}
+	if !schemes[u.Scheme] {
+		return "", fmt.Errorf("unsupported scheme %q of server %q", u.Scheme, server)
+	}
+	return server, nil
+}
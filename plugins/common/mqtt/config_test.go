@@ -0,0 +1,87 @@
+package mqtt
+
+import (
+	"net/http"
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/require"
+
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+)
+
+func TestBrokerURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   string
+		tls      bool
+		expected string
+		err      string
+	}{
+		{
+			name:     "host and port",
+			server:   "localhost:1883",
+			expected: "tcp://localhost:1883",
+		},
+		{
+			name:     "host and port with tls",
+			server:   "localhost:8883",
+			tls:      true,
+			expected: "ssl://localhost:8883",
+		},
+		{
+			name:     "websocket",
+			server:   "ws://localhost:8080/mqtt",
+			expected: "ws://localhost:8080/mqtt",
+		},
+		{
+			name:     "secure websocket",
+			server:   "wss://broker.example.com:443/mqtt",
+			expected: "wss://broker.example.com:443/mqtt",
+		},
+		{
+			name:   "unsupported scheme",
+			server: "http://localhost:8080",
+			err:    `unsupported scheme "http" of server "http://localhost:8080"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, err := BrokerURL(tt.server, tt.tls)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, broker)
+		})
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	c := &Config{
+		ClientConfig:     tlsint.ClientConfig{InsecureSkipVerify: true},
+		WebsocketHeaders: map[string]string{"authorization": "Bearer token"},
+	}
+	opts := paho.NewClientOptions()
+	require.NoError(t, c.SetConfig(opts, []string{"localhost:8883", "wss://localhost:443/mqtt"}))
+
+	require.Len(t, opts.Servers, 2)
+	require.Equal(t, "ssl://localhost:8883", opts.Servers[0].String())
+	require.Equal(t, "wss://localhost:443/mqtt", opts.Servers[1].String())
+	require.True(t, opts.TLSConfig.InsecureSkipVerify)
+	require.Nil(t, opts.TLSConfig.ClientSessionCache)
+	require.Equal(t, http.Header{"Authorization": []string{"Bearer token"}}, opts.HTTPHeaders)
+}
+
+func TestSetConfigSessionResumption(t *testing.T) {
+	c := &Config{TLSSessionResumption: true}
+	opts := paho.NewClientOptions()
+	require.NoError(t, c.SetConfig(opts, []string{"localhost:1883", "wss://localhost:443/mqtt"}))
+
+	require.NotNil(t, opts.TLSConfig)
+	require.NotNil(t, opts.TLSConfig.ClientSessionCache)
+	// Resuming sessions does not switch servers without scheme to TLS
+	require.Equal(t, "tcp://localhost:1883", opts.Servers[0].String())
+}
@@ -16,6 +16,11 @@ type ClientConfig struct {
 	InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
 	ServerName         string `toml:"tls_server_name"`
 
+	TLSCipherSuites []string `toml:"tls_cipher_suites"`
+	TLSMinVersion   string   `toml:"tls_min_version"`
+	TLSMaxVersion   string   `toml:"tls_max_version"`
+	TLSAutoReload   bool     `toml:"tls_auto_reload"`
+
 	// Deprecated in 1.7; use TLS variables above
 	SSLCA   string `toml:"ssl_ca"`
 	SSLCert string `toml:"ssl_cert"`
@@ -30,6 +35,7 @@ type ServerConfig struct {
 	TLSCipherSuites   []string `toml:"tls_cipher_suites"`
 	TLSMinVersion     string   `toml:"tls_min_version"`
 	TLSMaxVersion     string   `toml:"tls_max_version"`
+	TLSAutoReload     bool     `toml:"tls_auto_reload"`
 }
 
 // TLSConfig returns a tls.Config, may be nil without error if TLS is not
@@ -51,9 +57,11 @@ func (c *ClientConfig) TLSConfig() (*tls.Config, error) {
 	// a TLS connection. That is, any of:
 	//     * client certificate settings,
 	//     * peer certificate authorities,
-	//     * disabled security, or
-	//     * an SNI server name.
-	if c.TLSCA == "" && c.TLSKey == "" && c.TLSCert == "" && !c.InsecureSkipVerify && c.ServerName == "" {
+	//     * disabled security,
+	//     * an SNI server name, or
+	//     * restrictions of the cipher suites or protocol versions.
+	if c.TLSCA == "" && c.TLSKey == "" && c.TLSCert == "" && !c.InsecureSkipVerify && c.ServerName == "" &&
+		len(c.TLSCipherSuites) == 0 && c.TLSMinVersion == "" && c.TLSMaxVersion == "" {
 		return nil, nil
 	}
 
@@ -63,17 +71,29 @@ func (c *ClientConfig) TLSConfig() (*tls.Config, error) {
 	}
 
 	if c.TLSCA != "" {
-		pool, err := makeCertPool([]string{c.TLSCA})
-		if err != nil {
-			return nil, err
+		if c.TLSAutoReload {
+			if err := watchRootCAs(tlsConfig, c.TLSCA); err != nil {
+				return nil, err
+			}
+		} else {
+			pool, err := makeCertPool([]string{c.TLSCA})
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
 		}
-		tlsConfig.RootCAs = pool
 	}
 
 	if c.TLSCert != "" && c.TLSKey != "" {
-		err := loadCertificate(tlsConfig, c.TLSCert, c.TLSKey)
-		if err != nil {
-			return nil, err
+		if c.TLSAutoReload {
+			if err := watchClientCertificate(tlsConfig, c.TLSCert, c.TLSKey); err != nil {
+				return nil, err
+			}
+		} else {
+			err := loadCertificate(tlsConfig, c.TLSCert, c.TLSKey)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -81,6 +101,10 @@ func (c *ClientConfig) TLSConfig() (*tls.Config, error) {
 		tlsConfig.ServerName = c.ServerName
 	}
 
+	if err := setCiphersAndVersions(tlsConfig, c.TLSCipherSuites, c.TLSMinVersion, c.TLSMaxVersion); err != nil {
+		return nil, err
+	}
+
 	return tlsConfig, nil
 }
 
@@ -94,54 +118,75 @@ func (c *ServerConfig) TLSConfig() (*tls.Config, error) {
 	tlsConfig := &tls.Config{}
 
 	if len(c.TLSAllowedCACerts) != 0 {
-		pool, err := makeCertPool(c.TLSAllowedCACerts)
-		if err != nil {
-			return nil, err
+		if c.TLSAutoReload {
+			if err := watchClientCAs(tlsConfig, c.TLSAllowedCACerts); err != nil {
+				return nil, err
+			}
+		} else {
+			pool, err := makeCertPool(c.TLSAllowedCACerts)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 		}
-		tlsConfig.ClientCAs = pool
-		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
 	if c.TLSCert != "" && c.TLSKey != "" {
-		err := loadCertificate(tlsConfig, c.TLSCert, c.TLSKey)
-		if err != nil {
-			return nil, err
+		if c.TLSAutoReload {
+			if err := watchServerCertificate(tlsConfig, c.TLSCert, c.TLSKey); err != nil {
+				return nil, err
+			}
+		} else {
+			err := loadCertificate(tlsConfig, c.TLSCert, c.TLSKey)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	if len(c.TLSCipherSuites) != 0 {
-		cipherSuites, err := ParseCiphers(c.TLSCipherSuites)
+	if err := setCiphersAndVersions(tlsConfig, c.TLSCipherSuites, c.TLSMinVersion, c.TLSMaxVersion); err != nil {
+		return nil, err
+	}
+
+	return tlsConfig, nil
+}
+
+// setCiphersAndVersions restricts the cipher suites and protocol versions of
+// the config if they are given.
+func setCiphersAndVersions(tlsConfig *tls.Config, ciphers []string, minVersion, maxVersion string) error {
+	if len(ciphers) != 0 {
+		cipherSuites, err := ParseCiphers(ciphers)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"could not parse server cipher suites %s: %v", strings.Join(c.TLSCipherSuites, ","), err)
+			return fmt.Errorf(
+				"could not parse cipher suites %s: %v", strings.Join(ciphers, ","), err)
 		}
 		tlsConfig.CipherSuites = cipherSuites
 	}
 
-	if c.TLSMaxVersion != "" {
-		version, err := ParseTLSVersion(c.TLSMaxVersion)
+	if maxVersion != "" {
+		version, err := ParseTLSVersion(maxVersion)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"could not parse tls max version %q: %v", c.TLSMaxVersion, err)
+			return fmt.Errorf(
+				"could not parse tls max version %q: %v", maxVersion, err)
 		}
 		tlsConfig.MaxVersion = version
 	}
 
-	if c.TLSMinVersion != "" {
-		version, err := ParseTLSVersion(c.TLSMinVersion)
+	if minVersion != "" {
+		version, err := ParseTLSVersion(minVersion)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"could not parse tls min version %q: %v", c.TLSMinVersion, err)
+			return fmt.Errorf(
+				"could not parse tls min version %q: %v", minVersion, err)
 		}
 		tlsConfig.MinVersion = version
 	}
 
 	if tlsConfig.MinVersion != 0 && tlsConfig.MaxVersion != 0 && tlsConfig.MinVersion > tlsConfig.MaxVersion {
-		return nil, fmt.Errorf(
-			"tls min version %q can't be greater than tls max version %q", tlsConfig.MinVersion, tlsConfig.MaxVersion)
+		return fmt.Errorf(
+			"tls min version %q can't be greater than tls max version %q", minVersion, maxVersion)
 	}
-
-	return tlsConfig, nil
+	return nil
 }
 
 func makeCertPool(certFiles []string) (*x509.CertPool, error) {
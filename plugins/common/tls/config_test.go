@@ -1,8 +1,12 @@
 package tls_test
 
 import (
+	cryptotls "crypto/tls"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -94,6 +98,33 @@ func TestClientConfig(t *testing.T) {
 			expNil: false,
 			expErr: false,
 		},
+		{
+			name: "set cipher suites and versions",
+			client: tls.ClientConfig{
+				TLSCipherSuites: []string{pki.CipherSuite()},
+				TLSMinVersion:   pki.TLSMinVersion(),
+				TLSMaxVersion:   pki.TLSMaxVersion(),
+			},
+			expNil: false,
+			expErr: false,
+		},
+		{
+			name: "invalid cipher suites",
+			client: tls.ClientConfig{
+				TLSCipherSuites: []string{"TLS_NULL"},
+			},
+			expNil: true,
+			expErr: true,
+		},
+		{
+			name: "tls min version greater than tls max version",
+			client: tls.ClientConfig{
+				TLSMinVersion: pki.TLSMaxVersion(),
+				TLSMaxVersion: pki.TLSMinVersion(),
+			},
+			expNil: true,
+			expErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -323,3 +354,179 @@ func TestConnect(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 200, resp.StatusCode)
 }
+
+func TestConnectAutoReload(t *testing.T) {
+	clientConfig := tls.ClientConfig{
+		TLSCA:         pki.CACertPath(),
+		TLSCert:       pki.ClientCertPath(),
+		TLSKey:        pki.ClientKeyPath(),
+		TLSAutoReload: true,
+	}
+
+	serverConfig := tls.ServerConfig{
+		TLSCert:           pki.ServerCertPath(),
+		TLSKey:            pki.ServerKeyPath(),
+		TLSAllowedCACerts: []string{pki.CACertPath()},
+		TLSAutoReload:     true,
+	}
+
+	serverTLSConfig, err := serverConfig.TLSConfig()
+	require.NoError(t, err)
+
+	ts := startAutoReloadServer(t, serverTLSConfig)
+	defer ts.Close()
+
+	clientTLSConfig, err := clientConfig.TLSConfig()
+	require.NoError(t, err)
+
+	client := http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: clientTLSConfig,
+		},
+		Timeout: 10 * time.Second,
+	}
+
+	// The client connects by IP address without sending a server name
+	resp, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	// Clients without a certificate of the allowed CAs are rejected
+	clientConfig = tls.ClientConfig{
+		TLSCA: pki.CACertPath(),
+	}
+	clientTLSConfig, err = clientConfig.TLSConfig()
+	require.NoError(t, err)
+	client.Transport = &http.Transport{TLSClientConfig: clientTLSConfig}
+
+	_, err = client.Get(ts.URL)
+	require.Error(t, err)
+}
+
+// startAutoReloadServer starts a server using the config as is, StartTLS of
+// httptest would add its own certificate if Certificates is empty.
+func startAutoReloadServer(t *testing.T, config *cryptotls.Config) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NotNil(t, r.TLS)
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.Listener = cryptotls.NewListener(ts.Listener, config)
+	ts.Start()
+	ts.URL = "https://" + ts.Listener.Addr().String()
+	return ts
+}
+
+func TestCertificateAuthorityReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telegraf-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	allowedFile := filepath.Join(dir, "allowed.pem")
+	writeCA := func(file, cert string, mtime time.Time) {
+		require.NoError(t, ioutil.WriteFile(file, []byte(cert), 0600))
+		require.NoError(t, os.Chtimes(file, mtime, mtime))
+	}
+	// Start with certificates not signing the ones of the peers
+	writeCA(caFile, pki.ReadClientCert(), time.Unix(1600000000, 0))
+	writeCA(allowedFile, pki.ReadServerCert(), time.Unix(1600000000, 0))
+
+	serverConfig := tls.ServerConfig{
+		TLSCert:           pki.ServerCertPath(),
+		TLSKey:            pki.ServerKeyPath(),
+		TLSAllowedCACerts: []string{allowedFile},
+		TLSAutoReload:     true,
+	}
+	serverTLSConfig, err := serverConfig.TLSConfig()
+	require.NoError(t, err)
+
+	ts := startAutoReloadServer(t, serverTLSConfig)
+	defer ts.Close()
+
+	clientConfig := tls.ClientConfig{
+		TLSCA:         caFile,
+		TLSCert:       pki.ClientCertPath(),
+		TLSKey:        pki.ClientKeyPath(),
+		TLSAutoReload: true,
+	}
+	clientTLSConfig, err := clientConfig.TLSConfig()
+	require.NoError(t, err)
+
+	get := func() error {
+		client := http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:   clientTLSConfig,
+				DisableKeepAlives: true,
+			},
+			Timeout: 10 * time.Second,
+		}
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// The client rejects the server
+	require.Error(t, get())
+
+	// The server rejects the client
+	writeCA(caFile, pki.ReadCACert(), time.Unix(1600000100, 0))
+	require.Error(t, get())
+
+	writeCA(allowedFile, pki.ReadCACert(), time.Unix(1600000100, 0))
+	require.NoError(t, get())
+}
+
+func TestCertificateReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telegraf-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeKeyPair := func(cert, key string, mtime time.Time) {
+		require.NoError(t, ioutil.WriteFile(certFile, []byte(cert), 0600))
+		require.NoError(t, ioutil.WriteFile(keyFile, []byte(key), 0600))
+		require.NoError(t, os.Chtimes(certFile, mtime, mtime))
+		require.NoError(t, os.Chtimes(keyFile, mtime, mtime))
+	}
+	writeKeyPair(pki.ReadServerCert(), pki.ReadServerKey(), time.Unix(1600000000, 0))
+
+	serverConfig := tls.ServerConfig{
+		TLSCert:       certFile,
+		TLSKey:        keyFile,
+		TLSAutoReload: true,
+	}
+	serverTLSConfig, err := serverConfig.TLSConfig()
+	require.NoError(t, err)
+
+	clientConfig := tls.ClientConfig{
+		TLSCert:       certFile,
+		TLSKey:        keyFile,
+		TLSAutoReload: true,
+	}
+	clientTLSConfig, err := clientConfig.TLSConfig()
+	require.NoError(t, err)
+
+	serverCert, err := serverTLSConfig.GetCertificate(nil)
+	require.NoError(t, err)
+	clientCert, err := clientTLSConfig.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, serverCert.Certificate, clientCert.Certificate)
+
+	// a partially written key pair keeps the previous certificate
+	writeKeyPair(pki.ReadClientCert(), "", time.Unix(1600000100, 0))
+	cert, err := serverTLSConfig.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, serverCert.Certificate, cert.Certificate)
+
+	writeKeyPair(pki.ReadClientCert(), pki.ReadClientKey(), time.Unix(1600000200, 0))
+	cert, err = serverTLSConfig.GetCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, serverCert.Certificate, cert.Certificate)
+	cert, err = clientTLSConfig.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, serverCert.Certificate, cert.Certificate)
+}
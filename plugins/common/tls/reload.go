@@ -0,0 +1,195 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// modTimes returns the modification times of the files.
+func modTimes(files []string) ([]time.Time, error) {
+	times := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, info.ModTime())
+	}
+	return times, nil
+}
+
+func sameTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fileWatcher reloads a value whenever the modification time of one of its
+// files changes.  If reloading fails, e.g. because a rotation is still in
+// progress, the previous value is kept and reloading is retried on the next
+// access.
+type fileWatcher struct {
+	files []string
+	load  func() (interface{}, error)
+
+	sync.Mutex
+	value interface{}
+	times []time.Time
+}
+
+func newFileWatcher(files []string, load func() (interface{}, error)) (*fileWatcher, error) {
+	w := &fileWatcher{
+		files: files,
+		load:  load,
+	}
+
+	times, err := modTimes(files)
+	if err != nil {
+		return nil, err
+	}
+	if w.value, err = load(); err != nil {
+		return nil, err
+	}
+	w.times = times
+	return w, nil
+}
+
+func (w *fileWatcher) get() interface{} {
+	w.Lock()
+	defer w.Unlock()
+
+	times, err := modTimes(w.files)
+	if err != nil || sameTimes(times, w.times) {
+		return w.value
+	}
+
+	value, err := w.load()
+	if err != nil {
+		return w.value
+	}
+	w.value = value
+	w.times = times
+	return w.value
+}
+
+func newKeyPairWatcher(certFile, keyFile string) (*fileWatcher, error) {
+	return newFileWatcher([]string{certFile, keyFile}, func() (interface{}, error) {
+		config := &tls.Config{}
+		if err := loadCertificate(config, certFile, keyFile); err != nil {
+			return nil, err
+		}
+		return &config.Certificates[0], nil
+	})
+}
+
+func newCertPoolWatcher(certFiles []string) (*fileWatcher, error) {
+	return newFileWatcher(certFiles, func() (interface{}, error) {
+		return makeCertPool(certFiles)
+	})
+}
+
+// watchClientCertificate presents the current client certificate on every
+// handshake.  The certificate loaded at startup is kept in Certificates for
+// users inspecting the config.
+func watchClientCertificate(config *tls.Config, certFile, keyFile string) error {
+	w, err := newKeyPairWatcher(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	config.Certificates = []tls.Certificate{*w.get().(*tls.Certificate)}
+	config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return w.get().(*tls.Certificate), nil
+	}
+	return nil
+}
+
+// watchServerCertificate presents the current server certificate on every
+// handshake.  Certificates is left empty, crypto/tls would otherwise only call
+// GetCertificate for clients sending a server name.
+func watchServerCertificate(config *tls.Config, certFile, keyFile string) error {
+	w, err := newKeyPairWatcher(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return w.get().(*tls.Certificate), nil
+	}
+	return nil
+}
+
+// watchRootCAs verifies server certificates against the current certificate
+// authorities.  The verification is done in VerifyConnection, as the config
+// might be copied by the client and RootCAs can not be swapped safely, so the
+// verification of crypto/tls is disabled by setting InsecureSkipVerify.
+// RootCAs still holds the pool loaded at startup for users inspecting the
+// config.
+func watchRootCAs(config *tls.Config, certFile string) error {
+	w, err := newCertPoolWatcher([]string{certFile})
+	if err != nil {
+		return err
+	}
+
+	config.RootCAs = w.get().(*x509.CertPool)
+	if config.InsecureSkipVerify {
+		return nil
+	}
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return verifyChain(cs.PeerCertificates, x509.VerifyOptions{
+			DNSName: cs.ServerName,
+			Roots:   w.get().(*x509.CertPool),
+		})
+	}
+	return nil
+}
+
+// watchClientCAs verifies client certificates against the current allowed
+// certificate authorities.  The verification is done in VerifyConnection,
+// which unlike VerifyPeerCertificate also runs for resumed sessions, as the
+// config might be copied by the server and ClientCAs can not be swapped
+// safely.  ClientCAs still holds the pool loaded at startup to announce the
+// acceptable authorities to clients.  As the verification is not done by
+// crypto/tls, the VerifiedChains of the connection state stay empty.
+func watchClientCAs(config *tls.Config, certFiles []string) error {
+	w, err := newCertPoolWatcher(certFiles)
+	if err != nil {
+		return err
+	}
+
+	config.ClientCAs = w.get().(*x509.CertPool)
+	config.ClientAuth = tls.RequireAnyClientCert
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return verifyChain(cs.PeerCertificates, x509.VerifyOptions{
+			Roots:     w.get().(*x509.CertPool),
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+	}
+	return nil
+}
+
+// verifyChain verifies the leaf of the peer certificates, using the others as
+// intermediates.
+func verifyChain(certs []*x509.Certificate, opts x509.VerifyOptions) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("no peer certificate provided")
+	}
+
+	opts.Intermediates = x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
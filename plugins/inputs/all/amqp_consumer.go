@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.amqp_consumer
+// +build !custom inputs inputs.amqp_consumer
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/amqp_consumer" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.azure_storage_queue
+// +build !custom inputs inputs.azure_storage_queue
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/azure_storage_queue" // register plugin
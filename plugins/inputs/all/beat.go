@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.beat
+// +build !custom inputs inputs.beat
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/beat" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.cassandra
+// +build !custom inputs inputs.cassandra
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/cassandra" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.cisco_telemetry_mdt
+// +build !custom inputs inputs.cisco_telemetry_mdt
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/cisco_telemetry_mdt" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.cloud_pubsub
+// +build !custom inputs inputs.cloud_pubsub
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/cloud_pubsub" // register plugin
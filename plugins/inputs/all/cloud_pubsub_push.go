@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.cloud_pubsub_push
+// +build !custom inputs inputs.cloud_pubsub_push
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/cloud_pubsub_push" // register plugin
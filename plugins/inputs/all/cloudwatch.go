@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.cloudwatch
+// +build !custom inputs inputs.cloudwatch
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/cloudwatch" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.conntrack
+// +build !custom inputs inputs.conntrack
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/conntrack" // register plugin
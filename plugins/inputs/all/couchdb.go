@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.couchdb
+// +build !custom inputs inputs.couchdb
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/couchdb" // register plugin
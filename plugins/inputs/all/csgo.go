@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.csgo
+// +build !custom inputs inputs.csgo
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/csgo" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.directory_monitor
+// +build !custom inputs inputs.directory_monitor
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/directory_monitor" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.dns_query
+// +build !custom inputs inputs.dns_query
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/dns_query" // register plugin
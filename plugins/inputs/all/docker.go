@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.docker
+// +build !custom inputs inputs.docker
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/docker" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.docker_log
+// +build !custom inputs inputs.docker_log
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/docker_log" // register plugin
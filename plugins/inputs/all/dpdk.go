@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.dpdk
+// +build !custom inputs inputs.dpdk
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/dpdk" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.elasticsearch
+// +build !custom inputs inputs.elasticsearch
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/elasticsearch" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.elasticsearch_query
+// +build !custom inputs inputs.elasticsearch_query
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/elasticsearch_query" // register plugin
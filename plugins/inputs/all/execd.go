@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.execd
+// +build !custom inputs inputs.execd
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/execd" // register plugin
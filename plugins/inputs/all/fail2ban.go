@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.fail2ban
+// +build !custom inputs inputs.fail2ban
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/fail2ban" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.fireboard
+// +build !custom inputs inputs.fireboard
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/fireboard" // register plugin
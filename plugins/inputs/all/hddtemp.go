@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.hddtemp
+// +build !custom inputs inputs.hddtemp
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/hddtemp" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.http_listener_v2
+// +build !custom inputs inputs.http_listener_v2
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/http_listener_v2" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.http_response
+// +build !custom inputs inputs.http_response
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/http_response" // register plugin
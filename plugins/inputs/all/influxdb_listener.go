@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.influxdb_listener
+// +build !custom inputs inputs.influxdb_listener
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/influxdb_listener" // register plugin
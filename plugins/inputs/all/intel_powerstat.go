@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.intel_powerstat
+// +build !custom inputs inputs.intel_powerstat
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/intel_powerstat" // register plugin
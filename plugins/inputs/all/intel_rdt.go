@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.intel_rdt
+// +build !custom inputs inputs.intel_rdt
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/intel_rdt" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.internet_speed
+// +build !custom inputs inputs.internet_speed
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/internet_speed" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.ipmi_sensor
+// +build !custom inputs inputs.ipmi_sensor
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/ipmi_sensor" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.jolokia
+// +build !custom inputs inputs.jolokia
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/jolokia" // register plugin
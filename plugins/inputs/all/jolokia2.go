@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.jolokia2
+// +build !custom inputs inputs.jolokia2
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/jolokia2" // register plugin
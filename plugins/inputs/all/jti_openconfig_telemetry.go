@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.jti_openconfig_telemetry
+// +build !custom inputs inputs.jti_openconfig_telemetry
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/jti_openconfig_telemetry" // register plugin
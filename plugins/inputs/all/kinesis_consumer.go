@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.kinesis_consumer
+// +build !custom inputs inputs.kinesis_consumer
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/kinesis_consumer" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.kube_inventory
+// +build !custom inputs inputs.kube_inventory
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/kube_inventory" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.lustre2
+// +build !custom inputs inputs.lustre2
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/lustre2" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.neptune_apex
+// +build !custom inputs inputs.neptune_apex
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/neptune_apex" // register plugin
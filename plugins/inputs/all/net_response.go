@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.net_response
+// +build !custom inputs inputs.net_response
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/net_response" // register plugin
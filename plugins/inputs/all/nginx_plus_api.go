@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.nginx_plus_api
+// +build !custom inputs inputs.nginx_plus_api
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/nginx_plus_api" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.nginx_vts
+// +build !custom inputs inputs.nginx_vts
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/nginx_vts" // register plugin
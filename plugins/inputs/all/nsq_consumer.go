@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.nsq_consumer
+// +build !custom inputs inputs.nsq_consumer
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/nsq_consumer" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.nvidia_smi
+// +build !custom inputs inputs.nvidia_smi
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/nvidia_smi" // register plugin
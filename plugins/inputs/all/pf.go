@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.pf
+// +build !custom inputs inputs.pf
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/pf" // register plugin
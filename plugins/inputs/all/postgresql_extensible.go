@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.postgresql_extensible
+// +build !custom inputs inputs.postgresql_extensible
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/postgresql_extensible" // register plugin
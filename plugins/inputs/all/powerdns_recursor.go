@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.powerdns_recursor
+// +build !custom inputs inputs.powerdns_recursor
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/powerdns_recursor" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.procstat
+// +build !custom inputs inputs.procstat
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/procstat" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.redfish
+// +build !custom inputs inputs.redfish
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/redfish" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.riemann_listener
+// +build !custom inputs inputs.riemann_listener
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/riemann_listener" // register plugin
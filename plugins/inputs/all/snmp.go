@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.snmp
+// +build !custom inputs inputs.snmp
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/snmp" // register plugin
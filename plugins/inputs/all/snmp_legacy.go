@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.snmp_legacy
+// +build !custom inputs inputs.snmp_legacy
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/snmp_legacy" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.sql
+// +build !custom inputs inputs.sql
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/sql" // register plugin
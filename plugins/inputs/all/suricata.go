@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.suricata
+// +build !custom inputs inputs.suricata
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/suricata" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.system
+// +build !custom inputs inputs.system
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/system" // register plugin
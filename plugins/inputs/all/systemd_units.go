@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.systemd_units
+// +build !custom inputs inputs.systemd_units
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/systemd_units" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.tomcat
+// +build !custom inputs inputs.tomcat
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/tomcat" // register plugin
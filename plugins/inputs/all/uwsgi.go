@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.uwsgi
+// +build !custom inputs inputs.uwsgi
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/uwsgi" // register plugin
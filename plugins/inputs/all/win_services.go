@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.win_services
+// +build !custom inputs inputs.win_services
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/win_services" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.wireless
+// +build !custom inputs inputs.wireless
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/wireless" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || inputs || inputs.x509_cert
+// +build !custom inputs inputs.x509_cert
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/x509_cert" // register plugin
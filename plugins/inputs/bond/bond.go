@@ -4,21 +4,15 @@ import (
 	"bufio"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
-// default host proc path
-const defaultHostProc = "/proc"
-
-// env host proc variable name
-const envProc = "HOST_PROC"
-
 type Bond struct {
 	HostProc       string   `toml:"host_proc"`
 	BondInterfaces []string `toml:"bond_interfaces"`
@@ -165,18 +159,8 @@ func (bond *Bond) gatherSlavePart(bondName string, rawFile string, acc telegraf.
 // if it is empty then try read from env variable
 func (bond *Bond) loadPath() {
 	if bond.HostProc == "" {
-		bond.HostProc = proc(envProc, defaultHostProc)
-	}
-}
-
-// proc can be used to read file paths from env
-func proc(env, path string) string {
-	// try to read full file path
-	if p := os.Getenv(env); p != "" {
-		return p
+		bond.HostProc = host.Proc()
 	}
-	// return default path
-	return path
 }
 
 func (bond *Bond) listInterfaces() ([]string, error) {
@@ -256,9 +256,6 @@ func parseServerTokens(server string) map[string]string {
 }
 
 func (c *Cassandra) Start(_ telegraf.Accumulator) error {
-	c.Log.Warn("DEPRECATED: The cassandra plugin has been deprecated. " +
-		"Please use the jolokia2 plugin instead. " +
-		"https://github.com/influxdata/telegraf/tree/master/plugins/inputs/jolokia2")
 	return nil
 }
 
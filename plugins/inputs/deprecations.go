@@ -0,0 +1,47 @@
+package inputs
+
+import "github.com/influxdata/telegraf"
+
+// Deprecations lists the deprecated inputs.
+var Deprecations = map[string]telegraf.DeprecationInfo{
+	"cassandra": {
+		Since:     "1.7.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.jolokia2' with the 'cassandra.conf' example configuration instead",
+	},
+	"httpjson": {
+		Since:     "1.6.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.http' instead",
+	},
+	"jolokia": {
+		Since:     "1.5.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.jolokia2' instead",
+	},
+	"kafka_consumer_legacy": {
+		Since:     "1.4.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.kafka_consumer' instead",
+	},
+	"logparser": {
+		Since:     "1.15.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.tail' with the 'grok' data format instead",
+	},
+	"snmp_legacy": {
+		Since:     "1.0.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.snmp' instead",
+	},
+	"tcp_listener": {
+		Since:     "1.3.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.socket_listener' instead",
+	},
+	"udp_listener": {
+		Since:     "1.3.0",
+		RemovalIn: "2.0.0",
+		Notice:    "use 'inputs.socket_listener' instead",
+	},
+}
@@ -0,0 +1,252 @@
+package docker_events
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal/docker"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+var sampleConfig = `
+  ## Docker Endpoint
+  ##   To use TCP, set endpoint = "tcp://[ip]:[port]"
+  ##   To use environment variables (ie, docker-machine), set endpoint = "ENV"
+  # endpoint = "unix:///var/run/docker.sock"
+
+  ## Container events to report, see the events of type container in
+  ## https://docs.docker.com/engine/reference/commandline/events/
+  # actions = ["start", "stop", "die", "oom"]
+
+  ## Containers to include and exclude. Globs accepted.
+  ## Note that an empty array for both will include all containers
+  # container_name_include = []
+  # container_name_exclude = []
+
+  ## docker labels to include and exclude as tags.  Globs accepted.
+  ## Note that an empty array for both will include all labels as tags
+  # docker_label_include = []
+  # docker_label_exclude = []
+
+  ## Optional TLS Config
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
+`
+
+const (
+	defaultEndpoint = "unix:///var/run/docker.sock"
+	measurement     = "docker_events"
+)
+
+var (
+	defaultActions = []string{"start", "stop", "die", "oom"}
+
+	// Attributes of the events describing the container instead of being
+	// one of its labels
+	eventAttributes = map[string]bool{"name": true, "image": true, "exitCode": true, "signal": true}
+
+	// reconnectDelay is the time waited before subscribing again after the
+	// event stream failed
+	reconnectDelay = 5 * time.Second
+
+	// ensure *DockerEvents implements telegraf.ServiceInput
+	_ telegraf.ServiceInput = (*DockerEvents)(nil)
+)
+
+type DockerEvents struct {
+	Endpoint         string   `toml:"endpoint"`
+	Actions          []string `toml:"actions"`
+	LabelInclude     []string `toml:"docker_label_include"`
+	LabelExclude     []string `toml:"docker_label_exclude"`
+	ContainerInclude []string `toml:"container_name_include"`
+	ContainerExclude []string `toml:"container_name_exclude"`
+
+	tlsint.ClientConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	newEnvClient func() (Client, error)
+	newClient    func(string, *tls.Config) (Client, error)
+
+	client          Client
+	labelFilter     filter.Filter
+	containerFilter filter.Filter
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+func (d *DockerEvents) Description() string {
+	return "Read container lifecycle events from the Docker engine"
+}
+
+func (d *DockerEvents) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *DockerEvents) Init() error {
+	if len(d.Actions) == 0 {
+		d.Actions = defaultActions
+	}
+
+	var err error
+	if d.Endpoint == "ENV" {
+		d.client, err = d.newEnvClient()
+		if err != nil {
+			return err
+		}
+	} else {
+		tlsConfig, err := d.ClientConfig.TLSConfig()
+		if err != nil {
+			return err
+		}
+		d.client, err = d.newClient(d.Endpoint, tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.containerFilter, err = filter.NewIncludeExcludeFilter(d.ContainerInclude, d.ContainerExclude)
+	if err != nil {
+		return err
+	}
+	d.labelFilter, err = filter.NewIncludeExcludeFilter(d.LabelInclude, d.LabelExclude)
+	return err
+}
+
+func (d *DockerEvents) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (d *DockerEvents) Start(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.receive(ctx, acc)
+	}()
+	return nil
+}
+
+func (d *DockerEvents) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+	if err := d.client.Close(); err != nil {
+		d.Log.Errorf("Closing the client failed: %v", err)
+	}
+}
+
+// receive subscribes to the events until the context is canceled.  The
+// subscription is renewed if the stream fails, e.g. when the daemon restarts,
+// continuing after the last received event.
+func (d *DockerEvents) receive(ctx context.Context, acc telegraf.Accumulator) {
+	args := filters.NewArgs(filters.Arg("type", events.ContainerEventType))
+	for _, action := range d.Actions {
+		args.Add("event", action)
+	}
+	options := types.EventsOptions{Filters: args}
+
+	for {
+		messages, errs := d.client.Events(ctx, options)
+		err := d.readEvents(ctx, acc, messages, errs, &options)
+		if ctx.Err() != nil {
+			return
+		}
+		acc.AddError(fmt.Errorf("receiving events failed, subscribing again in %s: %v", reconnectDelay, err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (d *DockerEvents) readEvents(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+	messages <-chan events.Message,
+	errs <-chan error,
+	options *types.EventsOptions,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case msg := <-messages:
+			d.addEvent(acc, msg)
+			// Resume after this event when subscribing again
+			if msg.TimeNano > 0 {
+				since := msg.TimeNano + 1
+				options.Since = fmt.Sprintf("%d.%09d", since/int64(time.Second), since%int64(time.Second))
+			}
+		}
+	}
+}
+
+func (d *DockerEvents) addEvent(acc telegraf.Accumulator, msg events.Message) {
+	attributes := msg.Actor.Attributes
+	name := attributes["name"]
+	if !d.containerFilter.Match(name) {
+		return
+	}
+
+	imageName, imageVersion := docker.ParseImage(attributes["image"])
+	tags := map[string]string{
+		"action":            msg.Action,
+		"container_name":    name,
+		"container_image":   imageName,
+		"container_version": imageVersion,
+	}
+	if exitCode, ok := attributes["exitCode"]; ok {
+		tags["exit_code"] = exitCode
+	}
+	if signal, ok := attributes["signal"]; ok {
+		tags["signal"] = signal
+	}
+	// The remaining attributes are the labels of the container
+	for k, v := range attributes {
+		if !eventAttributes[k] && d.labelFilter.Match(k) {
+			tags[k] = v
+		}
+	}
+
+	fields := map[string]interface{}{
+		"container_id": msg.Actor.ID,
+	}
+
+	ts := time.Unix(0, msg.TimeNano)
+	if msg.TimeNano == 0 {
+		ts = time.Unix(msg.Time, 0)
+	}
+	acc.AddFields(measurement, fields, tags, ts)
+}
+
+func init() {
+	inputs.Add("docker_events", func() telegraf.Input {
+		return &DockerEvents{
+			Endpoint:     defaultEndpoint,
+			Actions:      defaultActions,
+			newEnvClient: NewEnvClient,
+			newClient:    NewClient,
+		}
+	})
+}
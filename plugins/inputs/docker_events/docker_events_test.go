@@ -0,0 +1,146 @@
+package docker_events
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type MockClient struct {
+	sync.Mutex
+	subscriptions []types.EventsOptions
+	messages      chan events.Message
+	errs          chan error
+}
+
+func newMockClient() *MockClient {
+	return &MockClient{
+		messages: make(chan events.Message),
+		errs:     make(chan error, 1),
+	}
+}
+
+func (c *MockClient) Events(_ context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	c.Lock()
+	defer c.Unlock()
+	c.subscriptions = append(c.subscriptions, options)
+	return c.messages, c.errs
+}
+
+func (c *MockClient) Close() error {
+	return nil
+}
+
+func (c *MockClient) Subscriptions() []types.EventsOptions {
+	c.Lock()
+	defer c.Unlock()
+	return append([]types.EventsOptions(nil), c.subscriptions...)
+}
+
+func newTestPlugin(client *MockClient) *DockerEvents {
+	return &DockerEvents{
+		Log:          testutil.Logger{},
+		Endpoint:     defaultEndpoint,
+		newEnvClient: func() (Client, error) { return client, nil },
+		newClient:    func(string, *tls.Config) (Client, error) { return client, nil },
+	}
+}
+
+func TestEvents(t *testing.T) {
+	client := newMockClient()
+	plugin := newTestPlugin(client)
+	plugin.LabelInclude = []string{"com.example.*"}
+	plugin.ContainerExclude = []string{"ignored"}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	ts := time.Unix(1622000000, 123456789)
+	client.messages <- events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor: events.Actor{
+			ID:         "f6b3a2c1d2e3",
+			Attributes: map[string]string{"name": "ignored", "image": "nginx:1.21"},
+		},
+		TimeNano: ts.UnixNano(),
+	}
+	client.messages <- events.Message{
+		Type:   events.ContainerEventType,
+		Action: "die",
+		Actor: events.Actor{
+			ID: "a1b2c3d4e5f6",
+			Attributes: map[string]string{
+				"name":            "web",
+				"image":           "nginx:1.21",
+				"exitCode":        "137",
+				"com.example.app": "shop",
+				"maintainer":      "someone",
+			},
+		},
+		TimeNano: ts.UnixNano(),
+	}
+	acc.Wait(1)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"docker_events",
+			map[string]string{
+				"action":            "die",
+				"container_name":    "web",
+				"container_image":   "nginx",
+				"container_version": "1.21",
+				"exit_code":         "137",
+				"com.example.app":   "shop",
+			},
+			map[string]interface{}{
+				"container_id": "a1b2c3d4e5f6",
+			},
+			ts,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	subscriptions := client.Subscriptions()
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, []string{"container"}, subscriptions[0].Filters.Get("type"))
+	require.ElementsMatch(t, defaultActions, subscriptions[0].Filters.Get("event"))
+}
+
+func TestResubscribe(t *testing.T) {
+	defer func(delay time.Duration) { reconnectDelay = delay }(reconnectDelay)
+	reconnectDelay = time.Millisecond
+
+	client := newMockClient()
+	plugin := newTestPlugin(client)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	client.messages <- events.Message{
+		Action:   "oom",
+		Actor:    events.Actor{ID: "a1b2c3d4e5f6", Attributes: map[string]string{"name": "web"}},
+		TimeNano: time.Unix(1622000000, 999999999).UnixNano(),
+	}
+	acc.Wait(1)
+	client.errs <- errors.New("unexpected EOF")
+
+	require.Eventually(t, func() bool {
+		return len(client.Subscriptions()) == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, "1622000001.000000000", client.Subscriptions()[1].Since)
+	require.Len(t, acc.Errors, 1)
+}
@@ -0,0 +1,158 @@
+package ebpf_tcp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+var sampleConfig = `
+  ## Maximum number of destinations tracked, the least recently active
+  ## destinations are evicted when exceeded.
+  # max_destinations = 10240
+
+  ## Report the histograms of the connect latency and round trip time as
+  ## buckets tagged with their upper bound "le".  This adds up to 33 series
+  ## per destination.
+  # histograms = false
+`
+
+const (
+	measurement = "ebpf_tcp"
+
+	// numBuckets is the number of power of two buckets of the histograms,
+	// the last bucket also holds all larger values.
+	numBuckets = 32
+)
+
+// destination is the key of the statistics map, the remote address is stored
+// as IPv6 with IPv4 addresses mapped into it.
+type destination struct {
+	Addr [16]byte
+	Port uint16
+	_    [6]byte
+}
+
+// tcpStats are the cumulative statistics of a destination, shared with the
+// eBPF programs.  The histograms count the values in microseconds falling into
+// [2^i, 2^(i+1)), with the first bucket holding zero as well.
+type tcpStats struct {
+	Connects          uint64
+	ConnectFailures   uint64
+	ConnectLatencySum uint64
+	Retransmits       uint64
+	RTTCount          uint64
+	RTTSum            uint64
+	ConnectLatency    [numBuckets]uint64
+	RTT               [numBuckets]uint64
+}
+
+// tracer collects the statistics of the destinations in the kernel.
+type tracer interface {
+	stats() (map[destination]tcpStats, error)
+	close() error
+}
+
+type EBPFTCP struct {
+	MaxDestinations int             `toml:"max_destinations"`
+	Histograms      bool            `toml:"histograms"`
+	Log             telegraf.Logger `toml:"-"`
+
+	tracer tracer
+}
+
+func (e *EBPFTCP) Description() string {
+	return "Collect TCP connect latency, retransmits and RTT per destination using eBPF"
+}
+
+func (e *EBPFTCP) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *EBPFTCP) Init() error {
+	if e.MaxDestinations <= 0 {
+		return fmt.Errorf("max_destinations must be positive")
+	}
+	return nil
+}
+
+func (e *EBPFTCP) Gather(acc telegraf.Accumulator) error {
+	if e.tracer == nil {
+		return nil
+	}
+
+	stats, err := e.tracer.stats()
+	if err != nil {
+		return err
+	}
+	for dest, s := range stats {
+		addMetrics(acc, dest, s, e.Histograms)
+	}
+	return nil
+}
+
+func (e *EBPFTCP) Stop() {
+	if e.tracer == nil {
+		return
+	}
+	if err := e.tracer.close(); err != nil {
+		e.Log.Errorf("Detaching the eBPF programs failed: %v", err)
+	}
+	e.tracer = nil
+}
+
+func addMetrics(acc telegraf.Accumulator, dest destination, s tcpStats, histograms bool) {
+	tags := map[string]string{
+		"destination": net.IP(dest.Addr[:]).String(),
+		"port":        strconv.Itoa(int(dest.Port)),
+	}
+	acc.AddFields(measurement, map[string]interface{}{
+		"connects":               s.Connects,
+		"connect_failures":       s.ConnectFailures,
+		"connect_latency_us_sum": s.ConnectLatencySum,
+		"retransmits":            s.Retransmits,
+		"rtt_us_count":           s.RTTCount,
+		"rtt_us_sum":             s.RTTSum,
+	}, tags)
+
+	if !histograms {
+		return
+	}
+
+	// Cumulative buckets up to the largest observed value
+	last := -1
+	for i := 0; i < numBuckets; i++ {
+		if s.ConnectLatency[i] > 0 || s.RTT[i] > 0 {
+			last = i
+		}
+	}
+	var latencyCount, rttCount uint64
+	for i := 0; i <= last; i++ {
+		latencyCount += s.ConnectLatency[i]
+		rttCount += s.RTT[i]
+		addBucket(acc, tags, strconv.FormatUint(1<<(i+1)-1, 10), latencyCount, rttCount)
+	}
+	addBucket(acc, tags, "+Inf", s.Connects, s.RTTCount)
+}
+
+func addBucket(acc telegraf.Accumulator, tags map[string]string, le string, latencyCount, rttCount uint64) {
+	bucketTags := map[string]string{"le": le}
+	for k, v := range tags {
+		bucketTags[k] = v
+	}
+	acc.AddFields(measurement, map[string]interface{}{
+		"connect_latency_us_bucket": latencyCount,
+		"rtt_us_bucket":             rttCount,
+	}, bucketTags)
+}
+
+func init() {
+	inputs.Add("ebpf_tcp", func() telegraf.Input {
+		return &EBPFTCP{
+			MaxDestinations: 10240,
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// +build linux
+
+package ebpf_tcp
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -tags linux tcp tcp.c
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+
+	"github.com/influxdata/telegraf"
+)
+
+// tracingPaths are the mounts of tracefs checked for the tracepoints, the
+// first one holding the events is used.  Kernels before 4.1 only provide it
+// below debugfs.
+var tracingPaths = []string{"/sys/kernel/tracing", "/sys/kernel/debug/tracing"}
+
+// tracepoint is an attach point of the programs.  Only the tracking of
+// connects is required, the other statistics are skipped on kernels lacking
+// their tracepoint.
+type tracepoint struct {
+	group    string
+	name     string
+	program  string
+	required bool
+}
+
+var tracepoints = []tracepoint{
+	{group: "sock", name: "inet_sock_set_state", program: "connect", required: true},
+	{group: "tcp", name: "tcp_retransmit_skb", program: "retransmit"},
+	{group: "tcp", name: "tcp_probe", program: "rtt"},
+}
+
+func (e *EBPFTCP) Start(_ telegraf.Accumulator) error {
+	t, err := newBPFTracer(e.MaxDestinations, e.Log)
+	if err != nil {
+		return err
+	}
+	e.tracer = t
+	return nil
+}
+
+// bpfTracer collects the statistics with eBPF programs attached to the TCP
+// tracepoints of the kernel.
+type bpfTracer struct {
+	collection *ebpf.Collection
+	links      []link.Link
+}
+
+func newBPFTracer(maxDestinations int, log telegraf.Logger) (*bpfTracer, error) {
+	// Kernels before 5.11 account the memory of eBPF maps and programs to the
+	// locked memory limit
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, fmt.Errorf("raising the locked memory limit failed: %v", err)
+	}
+
+	spec, attached, err := collectionSpec(maxDestinations, log)
+	if err != nil {
+		return nil, err
+	}
+	// The layouts of the tracepoint records are relocated using the BTF of
+	// the running kernel
+	collection, err := ebpf.NewCollection(spec)
+	if err != nil {
+		return nil, fmt.Errorf("loading the eBPF programs failed: %v", err)
+	}
+
+	t := &bpfTracer{collection: collection}
+	for _, tp := range attached {
+		l, err := link.Tracepoint(tp.group, tp.name, collection.Programs[tp.program], nil)
+		if err != nil {
+			_ = t.close()
+			return nil, fmt.Errorf("attaching to tracepoint %s/%s failed: %v", tp.group, tp.name, err)
+		}
+		t.links = append(t.links, l)
+	}
+	return t, nil
+}
+
+// collectionSpec returns the compiled programs for the tracepoints of the
+// running kernel, skipping the optional ones not available.
+func collectionSpec(maxDestinations int, log telegraf.Logger) (*ebpf.CollectionSpec, []tracepoint, error) {
+	spec, err := loadTcp()
+	if err != nil {
+		return nil, nil, err
+	}
+	spec.Maps["stats"].MaxEntries = uint32(maxDestinations)
+
+	events, err := tracingEvents()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attached []tracepoint
+	for _, tp := range tracepoints {
+		if _, err := os.Stat(filepath.Join(events, tp.group, tp.name)); err != nil {
+			if tp.required {
+				return nil, nil, fmt.Errorf("tracepoint %s/%s is not available: %v", tp.group, tp.name, err)
+			}
+			log.Warnf("Tracepoint %s/%s is not available, not collecting %s statistics: %v", tp.group, tp.name, tp.program, err)
+			delete(spec.Programs, tp.program)
+			continue
+		}
+		attached = append(attached, tp)
+	}
+	return spec, attached, nil
+}
+
+// tracingEvents returns the events directory of the tracefs mount.
+func tracingEvents() (string, error) {
+	for _, path := range tracingPaths {
+		events := filepath.Join(path, "events")
+		if _, err := os.Stat(events); err == nil {
+			return events, nil
+		}
+	}
+	return "", fmt.Errorf("tracefs is not mounted at any of %v", tracingPaths)
+}
+
+func (t *bpfTracer) stats() (map[destination]tcpStats, error) {
+	stats := make(map[destination]tcpStats)
+	var key destination
+	var value tcpStats
+	iter := t.collection.Maps["stats"].Iterate()
+	for iter.Next(&key, &value) {
+		stats[key] = value
+	}
+	return stats, iter.Err()
+}
+
+func (t *bpfTracer) close() error {
+	var firstErr error
+	for _, l := range t.links {
+		if err := l.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	t.collection.Close()
+	return firstErr
+}
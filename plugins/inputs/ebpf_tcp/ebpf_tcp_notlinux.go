@@ -0,0 +1,12 @@
+// +build !linux
+
+package ebpf_tcp
+
+import (
+	"github.com/influxdata/telegraf"
+)
+
+func (e *EBPFTCP) Start(_ telegraf.Accumulator) error {
+	e.Log.Warn("Current platform is not supported")
+	return nil
+}
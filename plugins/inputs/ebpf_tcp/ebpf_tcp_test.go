@@ -0,0 +1,143 @@
+package ebpf_tcp
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTracer struct {
+	destinations map[destination]tcpStats
+	err          error
+	closed       bool
+}
+
+func (m *mockTracer) stats() (map[destination]tcpStats, error) {
+	return m.destinations, m.err
+}
+
+func (m *mockTracer) close() error {
+	m.closed = true
+	return nil
+}
+
+func newDestination(addr string, port uint16) destination {
+	dest := destination{Port: port}
+	copy(dest.Addr[:], net.ParseIP(addr).To16())
+	return dest
+}
+
+func TestGather(t *testing.T) {
+	stats := tcpStats{
+		Connects:          3,
+		ConnectFailures:   1,
+		ConnectLatencySum: 1200,
+		Retransmits:       2,
+		RTTCount:          4,
+		RTTSum:            1000,
+	}
+	// 100us, 300us and 800us connects, 250us RTT samples
+	stats.ConnectLatency[6] = 1
+	stats.ConnectLatency[8] = 1
+	stats.ConnectLatency[9] = 1
+	stats.RTT[7] = 4
+
+	tracer := &mockTracer{destinations: map[destination]tcpStats{
+		newDestination("10.0.0.1", 5432): stats,
+	}}
+	plugin := &EBPFTCP{MaxDestinations: 10, Histograms: true, Log: testutil.Logger{}, tracer: tracer}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	tags := func(le string) map[string]string {
+		tags := map[string]string{"destination": "10.0.0.1", "port": "5432"}
+		if le != "" {
+			tags["le"] = le
+		}
+		return tags
+	}
+	bucket := func(le string, latency, rtt uint64) telegraf.Metric {
+		return testutil.MustMetric("ebpf_tcp", tags(le), map[string]interface{}{
+			"connect_latency_us_bucket": latency,
+			"rtt_us_bucket":             rtt,
+		}, time.Unix(0, 0))
+	}
+	expected := []telegraf.Metric{
+		testutil.MustMetric("ebpf_tcp", tags(""), map[string]interface{}{
+			"connects":               uint64(3),
+			"connect_failures":       uint64(1),
+			"connect_latency_us_sum": uint64(1200),
+			"retransmits":            uint64(2),
+			"rtt_us_count":           uint64(4),
+			"rtt_us_sum":             uint64(1000),
+		}, time.Unix(0, 0)),
+		bucket("1", 0, 0),
+		bucket("3", 0, 0),
+		bucket("7", 0, 0),
+		bucket("15", 0, 0),
+		bucket("31", 0, 0),
+		bucket("63", 0, 0),
+		bucket("127", 1, 0),
+		bucket("255", 1, 4),
+		bucket("511", 2, 4),
+		bucket("1023", 3, 4),
+		bucket("+Inf", 3, 4),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	plugin.Stop()
+	require.True(t, tracer.closed)
+}
+
+func TestGatherWithoutHistograms(t *testing.T) {
+	stats := tcpStats{Connects: 1, ConnectLatencySum: 100, RTTCount: 1, RTTSum: 250}
+	stats.ConnectLatency[6] = 1
+	stats.RTT[7] = 1
+
+	tracer := &mockTracer{destinations: map[destination]tcpStats{
+		newDestination("10.0.0.1", 5432): stats,
+	}}
+	plugin := &EBPFTCP{MaxDestinations: 10, Log: testutil.Logger{}, tracer: tracer}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Metrics, 1)
+	require.False(t, acc.HasTag("ebpf_tcp", "le"))
+}
+
+func TestGatherIPv6(t *testing.T) {
+	tracer := &mockTracer{destinations: map[destination]tcpStats{
+		newDestination("2001:db8::1", 443): {Connects: 1},
+	}}
+	plugin := &EBPFTCP{MaxDestinations: 10, Log: testutil.Logger{}, tracer: tracer}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.True(t, acc.HasTag("ebpf_tcp", "destination"))
+	require.Equal(t, "2001:db8::1", acc.Metrics[0].Tags["destination"])
+	require.Equal(t, "443", acc.Metrics[0].Tags["port"])
+}
+
+func TestGatherError(t *testing.T) {
+	plugin := &EBPFTCP{
+		MaxDestinations: 10,
+		Log:             testutil.Logger{},
+		tracer:          &mockTracer{err: errors.New("map iteration aborted")},
+	}
+
+	var acc testutil.Accumulator
+	require.EqualError(t, plugin.Gather(&acc), "map iteration aborted")
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := &EBPFTCP{Log: testutil.Logger{}}
+	require.EqualError(t, plugin.Init(), "max_destinations must be positive")
+}
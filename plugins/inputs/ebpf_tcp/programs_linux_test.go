@@ -0,0 +1,79 @@
+// +build linux
+
+package ebpf_tcp
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/btf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// fakeTracing points the tracefs mounts to a missing directory followed by a
+// directory holding the given tracepoints.
+func fakeTracing(t *testing.T, tps ...tracepoint) {
+	paths := tracingPaths
+	t.Cleanup(func() { tracingPaths = paths })
+	dir := t.TempDir()
+	tracingPaths = []string{filepath.Join(dir, "missing"), dir}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "events"), 0755))
+	for _, tp := range tps {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "events", tp.group, tp.name), 0755))
+	}
+}
+
+func TestCollectionSpec(t *testing.T) {
+	fakeTracing(t, tracepoints...)
+
+	spec, attached, err := collectionSpec(10, testutil.Logger{})
+	require.NoError(t, err)
+	require.Len(t, attached, 3)
+	require.Contains(t, spec.Programs, "connect")
+	require.Contains(t, spec.Programs, "retransmit")
+	require.Contains(t, spec.Programs, "rtt")
+	require.Equal(t, uint32(10), spec.Maps["stats"].MaxEntries)
+	require.Equal(t, uint32(24), spec.Maps["stats"].KeySize)
+	require.Equal(t, uint32(560), spec.Maps["stats"].ValueSize)
+
+	// The verifier checks the programs when loading them, which is only
+	// permitted to privileged users.  The records are relocated to the
+	// layouts the programs were compiled with, so the test does not depend
+	// on the BTF of the running kernel.
+	types, err := btf.LoadSpecFromReader(bytes.NewReader(_TcpBytes))
+	require.NoError(t, err)
+	opts := ebpf.CollectionOptions{
+		Programs: ebpf.ProgramOptions{KernelTypes: types},
+	}
+	collection, err := ebpf.NewCollectionWithOptions(spec, opts)
+	if errors.Is(err, unix.EPERM) {
+		t.Skip("loading eBPF programs is not permitted")
+	}
+	require.NoError(t, err)
+	collection.Close()
+}
+
+func TestCollectionSpecOptionalTracepoints(t *testing.T) {
+	fakeTracing(t, tracepoints[0])
+
+	spec, attached, err := collectionSpec(10, testutil.Logger{})
+	require.NoError(t, err)
+	require.Len(t, attached, 1)
+	require.Contains(t, spec.Programs, "connect")
+	require.NotContains(t, spec.Programs, "rtt")
+
+	fakeTracing(t)
+	_, _, err = collectionSpec(10, testutil.Logger{})
+	require.Error(t, err)
+
+	tracingPaths = []string{filepath.Join(t.TempDir(), "missing")}
+	_, _, err = collectionSpec(10, testutil.Logger{})
+	require.Error(t, err)
+}
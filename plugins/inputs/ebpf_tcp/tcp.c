@@ -0,0 +1,268 @@
+// +build ignore
+
+// The programs are compiled with bpf2go by go generate, the generated object
+// is checked in.  The layouts of the tracepoint records are relocated to the
+// ones of the running kernel using its BTF when loading, the structures below
+// only list the fields used.
+
+typedef unsigned char __u8;
+typedef unsigned short __u16;
+typedef unsigned int __u32;
+typedef unsigned long long __u64;
+
+#define SEC(name) __attribute__((section(name), used))
+#define __always_inline inline __attribute__((always_inline))
+
+static void *(*bpf_map_lookup_elem)(void *map, const void *key) = (void *)1;
+static long (*bpf_map_update_elem)(void *map, const void *key, const void *value, __u64 flags) = (void *)2;
+static long (*bpf_map_delete_elem)(void *map, const void *key) = (void *)3;
+static long (*bpf_probe_read)(void *dst, __u32 size, const void *src) = (void *)4;
+static __u64 (*bpf_ktime_get_ns)(void) = (void *)5;
+
+#define BPF_ANY 0
+#define BPF_NOEXIST 1
+#define BPF_MAP_TYPE_ARRAY 2
+#define BPF_MAP_TYPE_LRU_HASH 9
+
+#define BPF_FIELD_BYTE_SIZE 1
+
+#define TCP_ESTABLISHED 1
+#define TCP_SYN_SENT 2
+#define IPPROTO_TCP 6
+#define AF_INET 2
+#define AF_INET6 10
+
+// NUM_BUCKETS matches numBuckets of the plugin
+#define NUM_BUCKETS 32
+
+// MAX_CONNECTING is the number of concurrent connects whose latency is
+// measured
+#define MAX_CONNECTING 10240
+
+// READ copies the field of a tracepoint record, relocating its offset.
+#define READ(dst, src) bpf_probe_read(&(dst), sizeof(dst), __builtin_preserve_access_index(&(src)))
+
+struct bpf_map_def {
+	unsigned int type;
+	unsigned int key_size;
+	unsigned int value_size;
+	unsigned int max_entries;
+	unsigned int map_flags;
+};
+
+// destination matches the destination of the plugin
+struct destination {
+	__u8 addr[16];
+	__u16 port;
+	__u8 pad[6];
+};
+
+// tcp_stats matches tcpStats of the plugin
+struct tcp_stats {
+	__u64 connects;
+	__u64 connect_failures;
+	__u64 connect_latency_sum;
+	__u64 retransmits;
+	__u64 rtt_count;
+	__u64 rtt_sum;
+	__u64 connect_latency[NUM_BUCKETS];
+	__u64 rtt[NUM_BUCKETS];
+};
+
+struct trace_event_raw_inet_sock_set_state {
+	const void *skaddr;
+	int oldstate;
+	int newstate;
+	__u16 dport;
+	// Kernels before 5.6 store the protocol in a single byte
+	__u16 protocol;
+	__u8 daddr_v6[16];
+} __attribute__((preserve_access_index));
+
+struct trace_event_raw_tcp_event_sk_skb {
+	__u16 dport;
+	__u8 daddr_v6[16];
+} __attribute__((preserve_access_index));
+
+struct trace_event_raw_tcp_probe {
+	// A struct sockaddr_in or sockaddr_in6
+	__u8 daddr[28];
+	__u16 dport;
+	__u32 srtt;
+} __attribute__((preserve_access_index));
+
+// The maximum number of destinations is set when loading the programs
+struct bpf_map_def SEC("maps") stats = {
+	.type = BPF_MAP_TYPE_LRU_HASH,
+	.key_size = sizeof(struct destination),
+	.value_size = sizeof(struct tcp_stats),
+	.max_entries = 1,
+};
+
+// connecting holds the start of the connects keyed by socket address
+struct bpf_map_def SEC("maps") connecting = {
+	.type = BPF_MAP_TYPE_LRU_HASH,
+	.key_size = sizeof(__u64),
+	.value_size = sizeof(__u64),
+	.max_entries = MAX_CONNECTING,
+};
+
+// The statistics are too large for the stack of the programs, new
+// destinations are initialized from this value instead
+struct bpf_map_def SEC("maps") zero = {
+	.type = BPF_MAP_TYPE_ARRAY,
+	.key_size = sizeof(__u32),
+	.value_size = sizeof(struct tcp_stats),
+	.max_entries = 1,
+};
+
+// lookup_stats returns the statistics of the destination, creating them if
+// unknown and requested.
+static __always_inline struct tcp_stats *lookup_stats(struct destination *key, int create)
+{
+	struct tcp_stats *s = bpf_map_lookup_elem(&stats, key);
+	if (s || !create)
+		return s;
+
+	__u32 zero_key = 0;
+	struct tcp_stats *init = bpf_map_lookup_elem(&zero, &zero_key);
+	if (!init)
+		return 0;
+	bpf_map_update_elem(&stats, key, init, BPF_NOEXIST);
+	// Another CPU may have created the destination meanwhile
+	return bpf_map_lookup_elem(&stats, key);
+}
+
+// bucket returns the histogram bucket of the value, its base two logarithm.
+static __always_inline __u32 bucket(__u64 value)
+{
+	__u32 b = 0;
+	__u32 shift;
+
+	shift = (value > 0xffffffff) << 5;
+	value >>= shift;
+	b |= shift;
+	shift = (value > 0xffff) << 4;
+	value >>= shift;
+	b |= shift;
+	shift = (value > 0xff) << 3;
+	value >>= shift;
+	b |= shift;
+	shift = (value > 0xf) << 2;
+	value >>= shift;
+	b |= shift;
+	shift = (value > 0x3) << 1;
+	value >>= shift;
+	b |= shift;
+	b |= (value >> 1);
+
+	if (b > NUM_BUCKETS - 1)
+		b = NUM_BUCKETS - 1;
+	return b;
+}
+
+// connect measures the time sockets spend in the SYN_SENT state.  The
+// destinations are only added to the statistics by connects, keeping the
+// clients of local servers out of them.
+SEC("tracepoint/sock/inet_sock_set_state")
+int connect(struct trace_event_raw_inet_sock_set_state *ctx)
+{
+	__u16 protocol = 0;
+	if (__builtin_preserve_field_info(ctx->protocol, BPF_FIELD_BYTE_SIZE) == 1) {
+		__u8 p;
+		READ(p, ctx->protocol);
+		protocol = p;
+	} else {
+		READ(protocol, ctx->protocol);
+	}
+	if (protocol != IPPROTO_TCP)
+		return 0;
+
+	const void *skaddr;
+	int oldstate, newstate;
+	READ(skaddr, ctx->skaddr);
+	READ(oldstate, ctx->oldstate);
+	READ(newstate, ctx->newstate);
+
+	if (newstate == TCP_SYN_SENT) {
+		__u64 start = bpf_ktime_get_ns();
+		bpf_map_update_elem(&connecting, &skaddr, &start, BPF_ANY);
+		return 0;
+	}
+
+	// Leaving SYN_SENT either established or failed the connect
+	if (oldstate != TCP_SYN_SENT)
+		return 0;
+	__u64 *start = bpf_map_lookup_elem(&connecting, &skaddr);
+	if (!start)
+		return 0;
+	__u64 latency = (bpf_ktime_get_ns() - *start) / 1000;
+	bpf_map_delete_elem(&connecting, &skaddr);
+
+	struct destination key = {};
+	READ(key.addr, ctx->daddr_v6);
+	READ(key.port, ctx->dport);
+	struct tcp_stats *s = lookup_stats(&key, 1);
+	if (!s)
+		return 0;
+
+	if (newstate != TCP_ESTABLISHED) {
+		__sync_fetch_and_add(&s->connect_failures, 1);
+		return 0;
+	}
+	__sync_fetch_and_add(&s->connects, 1);
+	__sync_fetch_and_add(&s->connect_latency_sum, latency);
+	__sync_fetch_and_add(&s->connect_latency[bucket(latency)], 1);
+	return 0;
+}
+
+// retransmit counts the retransmitted segments of known destinations.
+SEC("tracepoint/tcp/tcp_retransmit_skb")
+int retransmit(struct trace_event_raw_tcp_event_sk_skb *ctx)
+{
+	struct destination key = {};
+	READ(key.addr, ctx->daddr_v6);
+	READ(key.port, ctx->dport);
+	struct tcp_stats *s = lookup_stats(&key, 0);
+	if (!s)
+		return 0;
+
+	__sync_fetch_and_add(&s->retransmits, 1);
+	return 0;
+}
+
+// rtt samples the smoothed round trip time of known destinations on every
+// received segment.
+SEC("tracepoint/tcp/tcp_probe")
+int rtt(struct trace_event_raw_tcp_probe *ctx)
+{
+	__u8 daddr[28];
+	READ(daddr, ctx->daddr);
+
+	struct destination key = {};
+	__u16 family = *(__u16 *)daddr;
+	if (family == AF_INET6) {
+		__builtin_memcpy(key.addr, daddr + 8, 16);
+	} else if (family == AF_INET) {
+		// IPv4 addresses are mapped into IPv6 as done by the other
+		// tracepoints
+		key.addr[10] = 0xff;
+		key.addr[11] = 0xff;
+		__builtin_memcpy(key.addr + 12, daddr + 4, 4);
+	} else {
+		return 0;
+	}
+	READ(key.port, ctx->dport);
+	struct tcp_stats *s = lookup_stats(&key, 0);
+	if (!s)
+		return 0;
+
+	__u32 srtt;
+	READ(srtt, ctx->srtt);
+	__sync_fetch_and_add(&s->rtt_count, 1);
+	__sync_fetch_and_add(&s->rtt_sum, srtt);
+	__sync_fetch_and_add(&s->rtt[bucket(srtt)], 1);
+	return 0;
+}
+
+char __license[] SEC("license") = "GPL";
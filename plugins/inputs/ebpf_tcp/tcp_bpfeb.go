@@ -0,0 +1,131 @@
+// Code generated by bpf2go; DO NOT EDIT.
+//go:build (arm64be || armbe || mips || mips64 || mips64p32 || ppc64 || s390 || s390x || sparc || sparc64) && linux
+
+package ebpf_tcp
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/cilium/ebpf"
+)
+
+// loadTcp returns the embedded CollectionSpec for tcp.
+func loadTcp() (*ebpf.CollectionSpec, error) {
+	reader := bytes.NewReader(_TcpBytes)
+	spec, err := ebpf.LoadCollectionSpecFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't load tcp: %w", err)
+	}
+
+	return spec, err
+}
+
+// loadTcpObjects loads tcp and converts it into a struct.
+//
+// The following types are suitable as obj argument:
+//
+//	*tcpObjects
+//	*tcpPrograms
+//	*tcpMaps
+//
+// See ebpf.CollectionSpec.LoadAndAssign documentation for details.
+func loadTcpObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
+	spec, err := loadTcp()
+	if err != nil {
+		return err
+	}
+
+	return spec.LoadAndAssign(obj, opts)
+}
+
+// tcpSpecs contains maps and programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type tcpSpecs struct {
+	tcpProgramSpecs
+	tcpMapSpecs
+}
+
+// tcpSpecs contains programs before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type tcpProgramSpecs struct {
+	Connect    *ebpf.ProgramSpec `ebpf:"connect"`
+	Retransmit *ebpf.ProgramSpec `ebpf:"retransmit"`
+	Rtt        *ebpf.ProgramSpec `ebpf:"rtt"`
+}
+
+// tcpMapSpecs contains maps before they are loaded into the kernel.
+//
+// It can be passed ebpf.CollectionSpec.Assign.
+type tcpMapSpecs struct {
+	Connecting *ebpf.MapSpec `ebpf:"connecting"`
+	Stats      *ebpf.MapSpec `ebpf:"stats"`
+	Zero       *ebpf.MapSpec `ebpf:"zero"`
+}
+
+// tcpObjects contains all objects after they have been loaded into the kernel.
+//
+// It can be passed to loadTcpObjects or ebpf.CollectionSpec.LoadAndAssign.
+type tcpObjects struct {
+	tcpPrograms
+	tcpMaps
+}
+
+func (o *tcpObjects) Close() error {
+	return _TcpClose(
+		&o.tcpPrograms,
+		&o.tcpMaps,
+	)
+}
+
+// tcpMaps contains all maps after they have been loaded into the kernel.
+//
+// It can be passed to loadTcpObjects or ebpf.CollectionSpec.LoadAndAssign.
+type tcpMaps struct {
+	Connecting *ebpf.Map `ebpf:"connecting"`
+	Stats      *ebpf.Map `ebpf:"stats"`
+	Zero       *ebpf.Map `ebpf:"zero"`
+}
+
+func (m *tcpMaps) Close() error {
+	return _TcpClose(
+		m.Connecting,
+		m.Stats,
+		m.Zero,
+	)
+}
+
+// tcpPrograms contains all programs after they have been loaded into the kernel.
+//
+// It can be passed to loadTcpObjects or ebpf.CollectionSpec.LoadAndAssign.
+type tcpPrograms struct {
+	Connect    *ebpf.Program `ebpf:"connect"`
+	Retransmit *ebpf.Program `ebpf:"retransmit"`
+	Rtt        *ebpf.Program `ebpf:"rtt"`
+}
+
+func (p *tcpPrograms) Close() error {
+	return _TcpClose(
+		p.Connect,
+		p.Retransmit,
+		p.Rtt,
+	)
+}
+
+func _TcpClose(closers ...io.Closer) error {
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do not access this directly.
+//
+//go:embed tcp_bpfeb.o
+var _TcpBytes []byte
@@ -0,0 +1,206 @@
+package eventhub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
+)
+
+const defaultBlobFlushInterval = 10 * time.Second
+
+// blobPersister stores partition checkpoints as JSON blobs in an Azure
+// Storage container addressed by a container SAS URL.  Checkpoints are
+// written by the receivers after every event, so they are kept in memory and
+// only flushed to the container periodically and when the plugin stops.
+//
+// The event hub client starts from its configured position whenever reading
+// a checkpoint fails and immediately stores that position.  Only missing
+// blobs are therefore reported as a plain read error, other failures also
+// reject the following write so creating the receiver fails instead of
+// replaying the partition and overwriting the stored checkpoint.
+type blobPersister struct {
+	container *url.URL
+	client    *http.Client
+
+	mu          sync.Mutex
+	checkpoints map[string]persist.Checkpoint
+	dirty       map[string]bool
+	readErrs    map[string]error
+}
+
+// errNoCheckpoint is returned by Read if no checkpoint has been stored yet.
+var errNoCheckpoint = errors.New("no checkpoint stored")
+
+func newBlobPersister(containerURL string, timeout time.Duration) (*blobPersister, error) {
+	u, err := url.Parse(containerURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing blob container URL failed: %v", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("unsupported scheme %q for blob container URL", u.Scheme)
+	}
+
+	return &blobPersister{
+		container:   u,
+		client:      &http.Client{Timeout: timeout},
+		checkpoints: make(map[string]persist.Checkpoint),
+		dirty:       make(map[string]bool),
+		readErrs:    make(map[string]error),
+	}, nil
+}
+
+func blobName(namespace, name, consumerGroup, partitionID string) string {
+	return path.Join(namespace, name, consumerGroup, partitionID)
+}
+
+func (p *blobPersister) blobURL(name string) string {
+	u := *p.container
+	u.Path = path.Join(u.Path, name)
+	return u.String()
+}
+
+// Write records the checkpoint; it is uploaded on the next flush.  Writing a
+// checkpoint that could not be read fails, as it would replace the stored one.
+func (p *blobPersister) Write(namespace, name, consumerGroup, partitionID string, checkpoint persist.Checkpoint) error {
+	key := blobName(namespace, name, consumerGroup, partitionID)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err, ok := p.readErrs[key]; ok {
+		return fmt.Errorf("not overwriting checkpoint %q that could not be read: %v", key, err)
+	}
+	p.checkpoints[key] = checkpoint
+	p.dirty[key] = true
+	return nil
+}
+
+// Read returns the last known checkpoint, fetching it from the container if
+// it has not been seen by this process yet.
+func (p *blobPersister) Read(namespace, name, consumerGroup, partitionID string) (persist.Checkpoint, error) {
+	key := blobName(namespace, name, consumerGroup, partitionID)
+
+	p.mu.Lock()
+	checkpoint, ok := p.checkpoints[key]
+	p.mu.Unlock()
+	if ok {
+		return checkpoint, nil
+	}
+
+	checkpoint, err := p.download(key)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil && err != errNoCheckpoint {
+		p.readErrs[key] = err
+		return persist.NewCheckpointFromStartOfStream(), err
+	}
+	delete(p.readErrs, key)
+	if err != nil {
+		return persist.NewCheckpointFromStartOfStream(), err
+	}
+	if _, ok := p.checkpoints[key]; !ok {
+		p.checkpoints[key] = checkpoint
+	}
+	return checkpoint, nil
+}
+
+func (p *blobPersister) download(key string) (persist.Checkpoint, error) {
+	var checkpoint persist.Checkpoint
+
+	resp, err := p.client.Get(p.blobURL(key))
+	if err != nil {
+		return checkpoint, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return checkpoint, errNoCheckpoint
+	}
+	if resp.StatusCode != http.StatusOK {
+		return checkpoint, fmt.Errorf("reading checkpoint %q returned HTTP status %s", key, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	err = json.Unmarshal(body, &checkpoint)
+	return checkpoint, err
+}
+
+func (p *blobPersister) upload(key string, checkpoint persist.Checkpoint) error {
+	body, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PUT", p.blobURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("writing checkpoint %q returned HTTP status %s", key, resp.Status)
+	}
+	return nil
+}
+
+// Flush uploads all checkpoints that changed since the last flush.  Failed
+// uploads are retried on the next flush.
+func (p *blobPersister) Flush() error {
+	p.mu.Lock()
+	pending := make(map[string]persist.Checkpoint, len(p.dirty))
+	for key := range p.dirty {
+		pending[key] = p.checkpoints[key]
+	}
+	p.dirty = make(map[string]bool)
+	p.mu.Unlock()
+
+	var lastErr error
+	for key, checkpoint := range pending {
+		if err := p.upload(key, checkpoint); err != nil {
+			lastErr = err
+
+			p.mu.Lock()
+			p.dirty[key] = true
+			p.mu.Unlock()
+		}
+	}
+	return lastErr
+}
+
+// run flushes the checkpoints on every tick until the context is done.
+func (p *blobPersister) run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Flush(); err != nil {
+				onError(err)
+			}
+		}
+	}
+}
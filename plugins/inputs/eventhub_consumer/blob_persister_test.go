@@ -0,0 +1,157 @@
+package eventhub
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeContainer struct {
+	sync.Mutex
+	blobs map[string][]byte
+	puts  int
+}
+
+func (c *fakeContainer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.Lock()
+	defer c.Unlock()
+
+	switch r.Method {
+	case "GET":
+		body, ok := c.blobs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case "PUT":
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		c.blobs[r.URL.Path] = body
+		c.puts++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (c *fakeContainer) uploads() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.puts
+}
+
+func TestBlobPersisterRoundTrip(t *testing.T) {
+	container := &fakeContainer{blobs: make(map[string][]byte)}
+	ts := httptest.NewServer(container)
+	defer ts.Close()
+
+	p, err := newBlobPersister(ts.URL+"/checkpoints?sig=abc", time.Second)
+	require.NoError(t, err)
+
+	// Nothing has been stored yet
+	_, err = p.Read("ns", "hub", "$Default", "0")
+	require.Error(t, err)
+
+	enqueued := time.Unix(1600000000, 0).UTC()
+	require.NoError(t, p.Write("ns", "hub", "$Default", "0", persist.NewCheckpoint("42", 7, enqueued)))
+	require.NoError(t, p.Write("ns", "hub", "$Default", "0", persist.NewCheckpoint("43", 8, enqueued)))
+	require.Equal(t, 0, container.uploads())
+
+	require.NoError(t, p.Flush())
+	require.Equal(t, 1, container.uploads())
+	container.Lock()
+	require.Contains(t, container.blobs, "/checkpoints/ns/hub/$Default/0")
+	container.Unlock()
+
+	// Unchanged checkpoints are not uploaded again
+	require.NoError(t, p.Flush())
+	require.Equal(t, 1, container.uploads())
+
+	// A new persister picks up the stored checkpoint
+	restored, err := newBlobPersister(ts.URL+"/checkpoints?sig=abc", time.Second)
+	require.NoError(t, err)
+	checkpoint, err := restored.Read("ns", "hub", "$Default", "0")
+	require.NoError(t, err)
+	require.Equal(t, "43", checkpoint.Offset)
+	require.Equal(t, int64(8), checkpoint.SequenceNumber)
+	require.True(t, enqueued.Equal(checkpoint.EnqueueTime))
+}
+
+func TestBlobPersisterRetriesFailedUploads(t *testing.T) {
+	var fail int32
+	container := &fakeContainer{blobs: make(map[string][]byte)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		container.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	p, err := newBlobPersister(ts.URL+"/checkpoints", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, p.Write("ns", "hub", "$Default", "1", persist.NewCheckpoint("1", 1, time.Now())))
+
+	atomic.StoreInt32(&fail, 1)
+	require.Error(t, p.Flush())
+
+	atomic.StoreInt32(&fail, 0)
+	require.NoError(t, p.Flush())
+	require.Equal(t, 1, container.uploads())
+}
+
+func TestBlobPersisterReadErrors(t *testing.T) {
+	var status int32 = http.StatusForbidden
+	container := &fakeContainer{blobs: make(map[string][]byte)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s := atomic.LoadInt32(&status); s != http.StatusOK {
+			w.WriteHeader(int(s))
+			return
+		}
+		container.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	p, err := newBlobPersister(ts.URL+"/checkpoints", time.Second)
+	require.NoError(t, err)
+
+	// Failures other than a missing blob must not be replaced by the start
+	// position the client falls back to
+	for _, s := range []int32{http.StatusForbidden, http.StatusInternalServerError} {
+		atomic.StoreInt32(&status, s)
+		_, err = p.Read("ns", "hub", "$Default", "0")
+		require.Error(t, err)
+		require.Error(t, p.Write("ns", "hub", "$Default", "0", persist.NewCheckpointFromStartOfStream()))
+	}
+	atomic.StoreInt32(&status, http.StatusOK)
+	require.NoError(t, p.Flush())
+	require.Equal(t, 0, container.uploads())
+
+	// A missing blob is a new partition
+	_, err = p.Read("ns", "hub", "$Default", "0")
+	require.Equal(t, errNoCheckpoint, err)
+	require.NoError(t, p.Write("ns", "hub", "$Default", "0", persist.NewCheckpointFromStartOfStream()))
+
+	// Network errors fail as well
+	ts.Close()
+	_, err = p.Read("ns", "hub", "$Default", "1")
+	require.Error(t, err)
+	require.Error(t, p.Write("ns", "hub", "$Default", "1", persist.NewCheckpointFromStartOfStream()))
+}
+
+func TestBlobPersisterInvalidURL(t *testing.T) {
+	_, err := newBlobPersister("ftp://example.com/container", time.Second)
+	require.Error(t, err)
+}
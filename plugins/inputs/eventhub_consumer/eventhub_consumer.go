@@ -10,6 +10,7 @@ import (
 	"github.com/Azure/azure-event-hubs-go/v3/persist"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers"
@@ -25,18 +26,20 @@ type semaphore chan empty
 // EventHub is the top level struct for this plugin
 type EventHub struct {
 	// Configuration
-	ConnectionString       string    `toml:"connection_string"`
-	PersistenceDir         string    `toml:"persistence_dir"`
-	ConsumerGroup          string    `toml:"consumer_group"`
-	FromTimestamp          time.Time `toml:"from_timestamp"`
-	Latest                 bool      `toml:"latest"`
-	PrefetchCount          uint32    `toml:"prefetch_count"`
-	Epoch                  int64     `toml:"epoch"`
-	UserAgent              string    `toml:"user_agent"`
-	PartitionIDs           []string  `toml:"partition_ids"`
-	MaxUndeliveredMessages int       `toml:"max_undelivered_messages"`
-	EnqueuedTimeAsTs       bool      `toml:"enqueued_time_as_ts"`
-	IotHubEnqueuedTimeAsTs bool      `toml:"iot_hub_enqueued_time_as_ts"`
+	ConnectionString       string          `toml:"connection_string"`
+	PersistenceDir         string          `toml:"persistence_dir"`
+	PersistenceBlobURL     string          `toml:"persistence_blob_container_url"`
+	PersistenceBlobFlush   config.Duration `toml:"persistence_blob_flush_interval"`
+	ConsumerGroup          string          `toml:"consumer_group"`
+	FromTimestamp          time.Time       `toml:"from_timestamp"`
+	Latest                 bool            `toml:"latest"`
+	PrefetchCount          uint32          `toml:"prefetch_count"`
+	Epoch                  int64           `toml:"epoch"`
+	UserAgent              string          `toml:"user_agent"`
+	PartitionIDs           []string        `toml:"partition_ids"`
+	MaxUndeliveredMessages int             `toml:"max_undelivered_messages"`
+	EnqueuedTimeAsTs       bool            `toml:"enqueued_time_as_ts"`
+	IotHubEnqueuedTimeAsTs bool            `toml:"iot_hub_enqueued_time_as_ts"`
 
 	// Metadata
 	ApplicationPropertyFields     []string `toml:"application_property_fields"`
@@ -55,9 +58,10 @@ type EventHub struct {
 	Log telegraf.Logger `toml:"-"`
 
 	// Azure
-	hub    *eventhubClient.Hub
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	hub           *eventhubClient.Hub
+	blobPersister *blobPersister
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
 
 	parser parsers.Parser
 	in     chan []telegraf.Metric
@@ -94,6 +98,16 @@ func (*EventHub) SampleConfig() string {
   ## Set persistence directory to a valid folder to use a file persister instead of an in-memory persister
   # persistence_dir = ""
 
+  ## Set a container SAS URL to store checkpoints in Azure Blob Storage instead,
+  ## so consumption resumes where it stopped even when the agent moves between
+  ## hosts.  The SAS token needs read, create and write permissions.  Starting
+  ## fails if existing checkpoints can not be read, e.g. due to an expired token.
+  ## Takes precedence over persistence_dir.
+  # persistence_blob_container_url = "https://account.blob.core.windows.net/checkpoints?sv=...&sig=..."
+
+  ## How often checkpoints are flushed to the blob container.
+  # persistence_blob_flush_interval = "10s"
+
   ## Change the default consumer group
   # consumer_group = ""
 
@@ -175,7 +189,14 @@ func (e *EventHub) Init() (err error) {
 	// Set hub options
 	hubOpts := []eventhubClient.HubOption{}
 
-	if e.PersistenceDir != "" {
+	if e.PersistenceBlobURL != "" {
+		e.blobPersister, err = newBlobPersister(e.PersistenceBlobURL, 10*time.Second)
+		if err != nil {
+			return err
+		}
+
+		hubOpts = append(hubOpts, eventhubClient.HubWithOffsetPersistence(e.blobPersister))
+	} else if e.PersistenceDir != "" {
 		persister, err := persist.NewFilePersister(e.PersistenceDir)
 		if err != nil {
 			return err
@@ -214,6 +235,21 @@ func (e *EventHub) Start(acc telegraf.Accumulator) error {
 		e.startTracking(ctx, acc)
 	}()
 
+	if e.blobPersister != nil {
+		interval := time.Duration(e.PersistenceBlobFlush)
+		if interval <= 0 {
+			interval = defaultBlobFlushInterval
+		}
+
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.blobPersister.run(ctx, interval, func(err error) {
+				e.Log.Errorf("Flushing checkpoints to blob storage failed: %v", err)
+			})
+		}()
+	}
+
 	// Configure receiver options
 	receiveOpts := e.configureReceiver()
 	partitions := e.PartitionIDs
@@ -417,6 +453,12 @@ func (e *EventHub) Stop() {
 	}
 	e.cancel()
 	e.wg.Wait()
+
+	if e.blobPersister != nil {
+		if err := e.blobPersister.Flush(); err != nil {
+			e.Log.Errorf("Flushing checkpoints to blob storage failed: %v", err)
+		}
+	}
 }
 
 func init() {
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	osExec "os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,9 +31,28 @@ const sampleConfig = `
     "/tmp/collect_*.sh"
   ]
 
+  ## Environment variables
+  ## Array of "key=value" pairs to pass as environment variables
+  ## e.g. "KEY=value", "USERNAME=John Doe",
+  ## "LD_LIBRARY_PATH=/opt/custom/lib64:/usr/local/libs"
+  # environment = []
+
+  ## Directory the commands are run in, defaults to the working directory
+  ## of Telegraf.
+  # working_directory = ""
+
   ## Timeout for each command to complete.
   timeout = "5s"
 
+  ## Maximum number of commands run at the same time.  If 0 all commands are
+  ## run in parallel.
+  # max_parallel = 0
+
+  ## Pass the collection time (in unix nanoseconds) as TELEGRAF_TIMESTAMP and
+  ## the seconds elapsed since the previous collection as TELEGRAF_INTERVAL to
+  ## the commands' environment.
+  # pass_collection_info = false
+
   ## measurement name suffix (for separating different commands)
   name_suffix = "_mycollector"
 
@@ -40,17 +61,33 @@ const sampleConfig = `
   ## more about them here:
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
   data_format = "influx"
+
+  ## Settings for individual commands, overriding the ones above.  The command
+  ## must be identical to an entry of the commands array.  Environment
+  ## variables are added to the plugin wide ones.
+  # [[inputs.exec.command_options]]
+  #   command = "/usr/bin/mycollector --foo=bar"
+  #   environment = ["MODE=full"]
+  #   working_directory = "/var/lib/mycollector"
+  #   timeout = "30s"
 `
 
 const MaxStderrBytes int = 512
 
 type Exec struct {
-	Commands []string        `toml:"commands"`
-	Command  string          `toml:"command"`
-	Timeout  config.Duration `toml:"timeout"`
+	Commands           []string         `toml:"commands"`
+	Command            string           `toml:"command"`
+	Environment        []string         `toml:"environment"`
+	WorkingDirectory   string           `toml:"working_directory"`
+	Timeout            config.Duration  `toml:"timeout"`
+	MaxParallel        int              `toml:"max_parallel"`
+	PassCollectionInfo bool             `toml:"pass_collection_info"`
+	CommandOptions     []CommandOptions `toml:"command_options"`
 
 	parser parsers.Parser
 
+	lastGather time.Time
+
 	runner Runner
 	Log    telegraf.Logger `toml:"-"`
 }
@@ -62,14 +99,24 @@ func NewExec() *Exec {
 	}
 }
 
+// CommandOptions overrides the plugin wide settings for a single command.
+type CommandOptions struct {
+	Command          string          `toml:"command"`
+	Environment      []string        `toml:"environment"`
+	WorkingDirectory string          `toml:"working_directory"`
+	Timeout          config.Duration `toml:"timeout"`
+}
+
 type Runner interface {
-	Run(string, time.Duration) ([]byte, []byte, error)
+	Run(command string, environments []string, dir string, timeout time.Duration) ([]byte, []byte, error)
 }
 
 type CommandRunner struct{}
 
 func (c CommandRunner) Run(
 	command string,
+	environments []string,
+	dir string,
 	timeout time.Duration,
 ) ([]byte, []byte, error) {
 	splitCmd, err := shellquote.Split(command)
@@ -78,6 +125,10 @@ func (c CommandRunner) Run(
 	}
 
 	cmd := osExec.Command(splitCmd[0], splitCmd[1:]...)
+	if len(environments) > 0 {
+		cmd.Env = append(os.Environ(), environments...)
+	}
+	cmd.Dir = dir
 
 	var (
 		out    bytes.Buffer
@@ -137,13 +188,20 @@ func removeWindowsCarriageReturns(b bytes.Buffer) bytes.Buffer {
 	return b
 }
 
-func (e *Exec) ProcessCommand(command string, acc telegraf.Accumulator, wg *sync.WaitGroup) {
-	defer wg.Done()
+// execCommand is a command ready to run, with its settings resolved.
+type execCommand struct {
+	command      string
+	environments []string
+	dir          string
+	timeout      time.Duration
+}
+
+func (e *Exec) ProcessCommand(command execCommand, acc telegraf.Accumulator) {
 	_, isNagios := e.parser.(*nagios.NagiosParser)
 
-	out, errbuf, runErr := e.runner.Run(command, time.Duration(e.Timeout))
+	out, errbuf, runErr := e.runner.Run(command.command, command.environments, command.dir, command.timeout)
 	if !isNagios && runErr != nil {
-		err := fmt.Errorf("exec: %s for command '%s': %s", runErr, command, string(errbuf))
+		err := fmt.Errorf("exec: %s for command '%s': %s", runErr, command.command, string(errbuf))
 		acc.AddError(err)
 		return
 	}
@@ -178,6 +236,47 @@ func (e *Exec) SetParser(parser parsers.Parser) {
 	e.parser = parser
 }
 
+// commandSettings returns the environment, working directory and timeout to
+// use for the given entry of the commands array.
+func (e *Exec) commandSettings(pattern string, collectionInfo []string) ([]string, string, time.Duration) {
+	environments := make([]string, 0, len(e.Environment)+len(collectionInfo))
+	environments = append(environments, e.Environment...)
+	environments = append(environments, collectionInfo...)
+	dir := e.WorkingDirectory
+	timeout := time.Duration(e.Timeout)
+
+	for _, opts := range e.CommandOptions {
+		if opts.Command != pattern {
+			continue
+		}
+		environments = append(environments, opts.Environment...)
+		if opts.WorkingDirectory != "" {
+			dir = opts.WorkingDirectory
+		}
+		if opts.Timeout > 0 {
+			timeout = time.Duration(opts.Timeout)
+		}
+	}
+
+	return environments, dir, timeout
+}
+
+// collectionInfo returns the environment variables describing the current
+// collection if pass_collection_info is enabled.
+func (e *Exec) collectionInfo(now time.Time) []string {
+	if !e.PassCollectionInfo {
+		return nil
+	}
+
+	info := []string{"TELEGRAF_TIMESTAMP=" + strconv.FormatInt(now.UnixNano(), 10)}
+	if !e.lastGather.IsZero() {
+		elapsed := now.Sub(e.lastGather).Seconds()
+		info = append(info, "TELEGRAF_INTERVAL="+strconv.FormatFloat(elapsed, 'f', -1, 64))
+	}
+	e.lastGather = now
+	return info
+}
+
 func (e *Exec) Gather(acc telegraf.Accumulator) error {
 	var wg sync.WaitGroup
 	// Legacy single command support
@@ -186,13 +285,25 @@ func (e *Exec) Gather(acc telegraf.Accumulator) error {
 		e.Command = ""
 	}
 
-	commands := make([]string, 0, len(e.Commands))
+	collectionInfo := e.collectionInfo(time.Now())
+
+	commands := make([]execCommand, 0, len(e.Commands))
 	for _, pattern := range e.Commands {
 		cmdAndArgs := strings.SplitN(pattern, " ", 2)
 		if len(cmdAndArgs) == 0 {
 			continue
 		}
 
+		environments, dir, timeout := e.commandSettings(pattern, collectionInfo)
+		newCommand := func(command string) execCommand {
+			return execCommand{
+				command:      command,
+				environments: environments,
+				dir:          dir,
+				timeout:      timeout,
+			}
+		}
+
 		matches, err := filepath.Glob(cmdAndArgs[0])
 		if err != nil {
 			acc.AddError(err)
@@ -202,24 +313,36 @@ func (e *Exec) Gather(acc telegraf.Accumulator) error {
 		if len(matches) == 0 {
 			// There were no matches with the glob pattern, so let's assume
 			// that the command is in PATH and just run it as it is
-			commands = append(commands, pattern)
+			commands = append(commands, newCommand(pattern))
 		} else {
 			// There were matches, so we'll append each match together with
 			// the arguments to the commands slice
 			for _, match := range matches {
 				if len(cmdAndArgs) == 1 {
-					commands = append(commands, match)
+					commands = append(commands, newCommand(match))
 				} else {
 					commands = append(commands,
-						strings.Join([]string{match, cmdAndArgs[1]}, " "))
+						newCommand(strings.Join([]string{match, cmdAndArgs[1]}, " ")))
 				}
 			}
 		}
 	}
 
+	var sem chan struct{}
+	if e.MaxParallel > 0 {
+		sem = make(chan struct{}, e.MaxParallel)
+	}
+
 	wg.Add(len(commands))
 	for _, command := range commands {
-		go e.ProcessCommand(command, acc, &wg)
+		go func(command execCommand) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			e.ProcessCommand(command, acc)
+		}(command)
 	}
 	wg.Wait()
 	return nil
@@ -9,9 +9,12 @@ import (
 	"bytes"
 	"fmt"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/parsers"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
@@ -74,10 +77,40 @@ func newRunnerMock(out []byte, errout []byte, err error) Runner {
 	}
 }
 
-func (r runnerMock) Run(_ string, _ time.Duration) ([]byte, []byte, error) {
+func (r runnerMock) Run(_ string, _ []string, _ string, _ time.Duration) ([]byte, []byte, error) {
 	return r.out, r.errout, r.err
 }
 
+type runnerCall struct {
+	environments []string
+	dir          string
+	timeout      time.Duration
+}
+
+type recordingRunner struct {
+	sync.Mutex
+	calls   map[string]runnerCall
+	running int
+	maxSeen int
+}
+
+func (r *recordingRunner) Run(command string, environments []string, dir string, timeout time.Duration) ([]byte, []byte, error) {
+	r.Lock()
+	r.calls[command] = runnerCall{environments: environments, dir: dir, timeout: timeout}
+	r.running++
+	if r.running > r.maxSeen {
+		r.maxSeen = r.running
+	}
+	r.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	r.Lock()
+	r.running--
+	r.Unlock()
+	return []byte("value 1"), nil, nil
+}
+
 func TestExec(t *testing.T) {
 	parser, _ := parsers.NewParser(&parsers.Config{
 		DataFormat: "json",
@@ -190,6 +223,83 @@ func TestExecCommandWithoutGlobAndPath(t *testing.T) {
 	acc.AssertContainsFields(t, "metric", fields)
 }
 
+func TestExecCommandOptions(t *testing.T) {
+	parser, _ := parsers.NewValueParser("metric", "string", "", nil)
+	runner := &recordingRunner{calls: make(map[string]runnerCall)}
+	e := &Exec{
+		Log:              testutil.Logger{},
+		runner:           runner,
+		Commands:         []string{"cmd_a", "cmd_b --flag"},
+		Environment:      []string{"COMMON=1"},
+		WorkingDirectory: "/tmp",
+		Timeout:          config.Duration(5 * time.Second),
+		CommandOptions: []CommandOptions{
+			{
+				Command:          "cmd_b --flag",
+				Environment:      []string{"COMMON=2", "ONLY_B=1"},
+				WorkingDirectory: "/var/tmp",
+				Timeout:          config.Duration(30 * time.Second),
+			},
+		},
+		parser: parser,
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(e.Gather))
+
+	require.Equal(t, runnerCall{
+		environments: []string{"COMMON=1"},
+		dir:          "/tmp",
+		timeout:      5 * time.Second,
+	}, runner.calls["cmd_a"])
+	require.Equal(t, runnerCall{
+		environments: []string{"COMMON=1", "COMMON=2", "ONLY_B=1"},
+		dir:          "/var/tmp",
+		timeout:      30 * time.Second,
+	}, runner.calls["cmd_b --flag"])
+}
+
+func TestExecPassCollectionInfo(t *testing.T) {
+	parser, _ := parsers.NewValueParser("metric", "string", "", nil)
+	runner := &recordingRunner{calls: make(map[string]runnerCall)}
+	e := &Exec{
+		Log:                testutil.Logger{},
+		runner:             runner,
+		Commands:           []string{"cmd"},
+		PassCollectionInfo: true,
+		parser:             parser,
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(e.Gather))
+	env := runner.calls["cmd"].environments
+	require.Len(t, env, 1)
+	require.True(t, strings.HasPrefix(env[0], "TELEGRAF_TIMESTAMP="))
+
+	require.NoError(t, acc.GatherError(e.Gather))
+	env = runner.calls["cmd"].environments
+	require.Len(t, env, 2)
+	require.True(t, strings.HasPrefix(env[0], "TELEGRAF_TIMESTAMP="))
+	require.True(t, strings.HasPrefix(env[1], "TELEGRAF_INTERVAL="))
+}
+
+func TestExecMaxParallel(t *testing.T) {
+	parser, _ := parsers.NewValueParser("metric", "string", "", nil)
+	runner := &recordingRunner{calls: make(map[string]runnerCall)}
+	e := &Exec{
+		Log:         testutil.Logger{},
+		runner:      runner,
+		Commands:    []string{"cmd1", "cmd2", "cmd3", "cmd4", "cmd5"},
+		MaxParallel: 2,
+		parser:      parser,
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(e.Gather))
+	require.Len(t, runner.calls, 5)
+	require.LessOrEqual(t, runner.maxSeen, 2)
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		name string
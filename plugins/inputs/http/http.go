@@ -1,9 +1,9 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -34,6 +34,9 @@ type HTTP struct {
 	SuccessStatusCodes []int `toml:"success_status_codes"`
 
 	client *http.Client
+
+	// The request body after applying the content encoding.
+	requestBody []byte
 	httpconfig.HTTPClientConfig
 	Log telegraf.Logger `toml:"-"`
 
@@ -65,8 +68,9 @@ var sampleConfig = `
   ## HTTP entity-body to send with POST/PUT requests.
   # body = ""
 
-  ## HTTP Content-Encoding for write request body, can be set to "gzip" to
-  ## compress body or "identity" to apply no encoding.
+  ## HTTP Content-Encoding for write request body, can be set to "gzip",
+  ## "zlib", "snappy" or "zstd" to compress body or "identity" to apply no
+  ## encoding.
   # content_encoding = "identity"
 
   ## HTTP Proxy support
@@ -97,6 +101,11 @@ var sampleConfig = `
   ## Amount of time allowed to complete the HTTP request
   # timeout = "5s"
 
+  ## Log the method, URL, status, duration and byte counts of every request
+  ## for troubleshooting.  Credentials and query parameter values are
+  ## redacted, consecutive failed requests to a URL are counted as attempts.
+  # log_requests = false
+
   ## List of success status codes
   # success_status_codes = [200]
 
@@ -118,6 +127,14 @@ func (*HTTP) Description() string {
 }
 
 func (h *HTTP) Init() error {
+	encoder, err := internal.NewContentEncoder(h.ContentEncoding)
+	if err != nil {
+		return err
+	}
+	if h.requestBody, err = encoder.Encode([]byte(h.Body)); err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 	client, err := h.HTTPClientConfig.CreateClient(ctx, h.Log)
 	if err != nil {
@@ -168,13 +185,7 @@ func (h *HTTP) gatherURL(
 	acc telegraf.Accumulator,
 	url string,
 ) error {
-	body, err := makeRequestBodyReader(h.ContentEncoding, h.Body)
-	if err != nil {
-		return err
-	}
-	defer body.Close()
-
-	request, err := http.NewRequest(h.Method, url, body)
+	request, err := http.NewRequest(h.Method, url, bytes.NewReader(h.requestBody))
 	if err != nil {
 		return err
 	}
@@ -188,8 +199,8 @@ func (h *HTTP) gatherURL(
 		request.Header.Set("Authorization", bearer)
 	}
 
-	if h.ContentEncoding == "gzip" {
-		request.Header.Set("Content-Encoding", "gzip")
+	if h.ContentEncoding != "" && h.ContentEncoding != "identity" {
+		request.Header.Set("Content-Encoding", h.ContentEncoding)
 	}
 
 	for k, v := range h.Headers {
@@ -245,18 +256,6 @@ func (h *HTTP) gatherURL(
 	return nil
 }
 
-func makeRequestBodyReader(contentEncoding, body string) (io.ReadCloser, error) {
-	var reader io.Reader = strings.NewReader(body)
-	if contentEncoding == "gzip" {
-		rc, err := internal.CompressWithGzip(reader)
-		if err != nil {
-			return nil, err
-		}
-		return rc, nil
-	}
-	return ioutil.NopCloser(reader), nil
-}
-
 func init() {
 	inputs.Add("http", func() telegraf.Input {
 		return &HTTP{
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/golang/snappy"
 	httpconfig "github.com/influxdata/telegraf/plugins/common/http"
 	oauth "github.com/influxdata/telegraf/plugins/common/oauth"
 	plugin "github.com/influxdata/telegraf/plugins/inputs/http"
@@ -236,6 +237,25 @@ func TestBodyAndContentEncoding(t *testing.T) {
 				w.WriteHeader(http.StatusOK)
 			},
 		},
+		{
+			name: "snappy encoding",
+			plugin: &plugin.HTTP{
+				URLs:            []string{url},
+				Method:          "POST",
+				Body:            "test",
+				ContentEncoding: "snappy",
+			},
+			queryHandlerFunc: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, r.Header.Get("Content-Encoding"), "snappy")
+
+				encoded, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				body, err := snappy.Decode(nil, encoded)
+				require.NoError(t, err)
+				require.Equal(t, []byte("test"), body)
+				w.WriteHeader(http.StatusOK)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,352 @@
+package internet_speed
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	defaultServerListURL = "https://www.speedtest.net/api/js/servers?engine=js&limit=10"
+	defaultTimeout       = config.Duration(30 * time.Second)
+	defaultConnections   = 4
+
+	// number of closest servers probed when selecting a server
+	serverCandidates = 5
+	// number of requests used to measure latency and jitter
+	latencyProbes = 5
+)
+
+var (
+	downloadSizes = []int{350, 750, 1500, 2000}
+	uploadSizes   = []int{256 * 1024, 512 * 1024, 1024 * 1024}
+)
+
+// InternetSpeed is used to store configuration values.
+type InternetSpeed struct {
+	ServerIDInclude []string        `toml:"server_id_include"`
+	ServerIDExclude []string        `toml:"server_id_exclude"`
+	Cache           bool            `toml:"cache"`
+	Connections     int             `toml:"connections"`
+	Timeout         config.Duration `toml:"timeout"`
+	Log             telegraf.Logger `toml:"-"`
+
+	serverListURL string
+	serverFilter  filter.Filter
+	client        *http.Client
+	server        *server
+}
+
+// server is an entry of the speedtest.net server list.
+type server struct {
+	URL     string `json:"url"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Sponsor string `json:"sponsor"`
+	ID      string `json:"id"`
+
+	latency time.Duration
+	jitter  time.Duration
+}
+
+const sampleConfig = `
+  ## Only use the servers with the given ids, glob patterns are supported.
+  ## If empty the closest servers are used.
+  # server_id_include = []
+
+  ## Never use the servers with the given ids.
+  # server_id_exclude = []
+
+  ## Keep the selected server between collections instead of probing the
+  ## closest servers on every run.
+  # cache = false
+
+  ## Number of parallel connections used for the download and upload tests.
+  # connections = 4
+
+  ## Timeout for each HTTP request.
+  # timeout = "30s"
+
+  ## Speed tests transfer a noticeable amount of data, run them rarely.
+  interval = "60m"
+`
+
+func (is *InternetSpeed) Description() string {
+	return "Monitors internet speed using speedtest.net service"
+}
+
+func (is *InternetSpeed) SampleConfig() string {
+	return sampleConfig
+}
+
+func (is *InternetSpeed) Init() error {
+	if is.Connections < 1 {
+		return fmt.Errorf("connections must be at least 1")
+	}
+
+	f, err := filter.NewIncludeExcludeFilter(is.ServerIDInclude, is.ServerIDExclude)
+	if err != nil {
+		return fmt.Errorf("creating server id filter failed: %v", err)
+	}
+	is.serverFilter = f
+
+	is.client = &http.Client{Timeout: time.Duration(is.Timeout)}
+	return nil
+}
+
+func (is *InternetSpeed) Gather(acc telegraf.Accumulator) error {
+	s := is.server
+	if s == nil || !is.Cache {
+		var err error
+		if s, err = is.selectServer(); err != nil {
+			return err
+		}
+		if is.Cache {
+			is.server = s
+		}
+	} else if err := is.measureLatency(s); err != nil {
+		return fmt.Errorf("measuring latency to server %s failed: %v", s.ID, err)
+	}
+
+	download, err := is.downloadTest(s)
+	if err != nil {
+		return fmt.Errorf("download test failed: %v", err)
+	}
+
+	upload, err := is.uploadTest(s)
+	if err != nil {
+		return fmt.Errorf("upload test failed: %v", err)
+	}
+
+	fields := map[string]interface{}{
+		"download": download,
+		"upload":   upload,
+		"latency":  float64(s.latency) / float64(time.Millisecond),
+		"jitter":   float64(s.jitter) / float64(time.Millisecond),
+	}
+	tags := map[string]string{
+		"server_id": s.ID,
+		"location":  s.Name,
+		"country":   s.Country,
+		"sponsor":   s.Sponsor,
+	}
+	acc.AddFields("internet_speed", fields, tags)
+	return nil
+}
+
+// selectServer returns the server with the lowest latency among the closest
+// servers matching the server id filter.
+func (is *InternetSpeed) selectServer() (*server, error) {
+	servers, err := is.fetchServers()
+	if err != nil {
+		return nil, fmt.Errorf("fetching server list failed: %v", err)
+	}
+
+	var best *server
+	candidates := 0
+	for i := range servers {
+		s := &servers[i]
+		if !is.serverFilter.Match(s.ID) {
+			continue
+		}
+		if candidates >= serverCandidates {
+			break
+		}
+		candidates++
+
+		if err := is.measureLatency(s); err != nil {
+			is.Log.Debugf("Skipping server %s: %v", s.ID, err)
+			continue
+		}
+		if best == nil || s.latency < best.latency {
+			best = s
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no reachable server found")
+	}
+	return best, nil
+}
+
+func (is *InternetSpeed) fetchServers() ([]server, error) {
+	resp, err := is.client.Get(is.serverListURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP status %s", is.serverListURL, resp.Status)
+	}
+
+	var servers []server
+	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// baseURL returns the URL of the directory containing the upload handler of
+// the server, which also serves the latency and download files.
+func (s *server) baseURL(name string) (string, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(path.Dir(u.Path), name)
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// measureLatency sets the minimum round trip time and the mean deviation
+// between consecutive round trips as latency and jitter of the server.
+func (is *InternetSpeed) measureLatency(s *server) error {
+	addr, err := s.baseURL("latency.txt")
+	if err != nil {
+		return err
+	}
+
+	samples := make([]time.Duration, 0, latencyProbes)
+	for i := 0; i < latencyProbes; i++ {
+		start := time.Now()
+		if _, err := is.get(addr); err != nil {
+			return err
+		}
+		samples = append(samples, time.Since(start))
+	}
+
+	s.latency = samples[0]
+	var deviation time.Duration
+	for i, sample := range samples {
+		if sample < s.latency {
+			s.latency = sample
+		}
+		if i > 0 {
+			deviation += time.Duration(math.Abs(float64(sample - samples[i-1])))
+		}
+	}
+	s.jitter = deviation / time.Duration(len(samples)-1)
+	return nil
+}
+
+// get requests the address and returns the number of bytes received.
+func (is *InternetSpeed) get(addr string) (int64, error) {
+	resp, err := is.client.Get(addr)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s returned HTTP status %s", addr, resp.Status)
+	}
+	return io.Copy(ioutil.Discard, resp.Body)
+}
+
+// downloadTest returns the download speed in Mbit/s.
+func (is *InternetSpeed) downloadTest(s *server) (float64, error) {
+	addrs := make([]string, 0, len(downloadSizes))
+	for _, size := range downloadSizes {
+		addr, err := s.baseURL(fmt.Sprintf("random%dx%d.jpg", size, size))
+		if err != nil {
+			return 0, err
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return is.transfer(func() (int64, error) {
+		var total int64
+		for _, addr := range addrs {
+			n, err := is.get(addr)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+		return total, nil
+	})
+}
+
+// uploadTest returns the upload speed in Mbit/s.
+func (is *InternetSpeed) uploadTest(s *server) (float64, error) {
+	payload := make([]byte, uploadSizes[len(uploadSizes)-1])
+	if _, err := rand.Read(payload); err != nil {
+		return 0, err
+	}
+
+	return is.transfer(func() (int64, error) {
+		var total int64
+		for _, size := range uploadSizes {
+			resp, err := is.client.Post(s.URL, "application/octet-stream", bytes.NewReader(payload[:size]))
+			if err != nil {
+				return total, err
+			}
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return total, fmt.Errorf("%s returned HTTP status %s", s.URL, resp.Status)
+			}
+			total += int64(size)
+		}
+		return total, nil
+	})
+}
+
+// transfer runs fn on all connections in parallel and returns the achieved
+// throughput in Mbit/s.
+func (is *InternetSpeed) transfer(fn func() (int64, error)) (float64, error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		total   int64
+		lastErr error
+	)
+
+	start := time.Now()
+	for i := 0; i < is.Connections; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := fn()
+
+			mu.Lock()
+			defer mu.Unlock()
+			total += n
+			if err != nil {
+				lastErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if lastErr != nil {
+		return 0, lastErr
+	}
+	return float64(total) * 8 / 1e6 / elapsed.Seconds(), nil
+}
+
+func init() {
+	inputs.Add("internet_speed", func() telegraf.Input {
+		return &InternetSpeed{
+			Connections:   defaultConnections,
+			Timeout:       defaultTimeout,
+			serverListURL: defaultServerListURL,
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package internet_speed
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSpeedtest struct {
+	sync.Mutex
+	servers  []server
+	uploaded int64
+	probed   map[string]int
+}
+
+func (f *fakeSpeedtest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	switch {
+	case r.URL.Path == "/servers":
+		_ = json.NewEncoder(w).Encode(f.servers)
+	case strings.HasSuffix(r.URL.Path, "/latency.txt"):
+		f.probed[strings.Split(r.URL.Path, "/")[1]]++
+		_, _ = w.Write([]byte("test=test"))
+	case strings.HasSuffix(r.URL.Path, ".jpg"):
+		_, _ = w.Write(make([]byte, 1024))
+	case strings.HasSuffix(r.URL.Path, "/upload.php") && r.Method == "POST":
+		n, _ := io.Copy(ioutil.Discard, r.Body)
+		f.uploaded += n
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestPlugin(t *testing.T, fake *fakeSpeedtest, ts *httptest.Server) *InternetSpeed {
+	for i := range fake.servers {
+		fake.servers[i].URL = ts.URL + "/" + fake.servers[i].ID + "/speedtest/upload.php"
+	}
+
+	is := &InternetSpeed{
+		Connections:   2,
+		Timeout:       defaultTimeout,
+		Log:           testutil.Logger{},
+		serverListURL: ts.URL + "/servers",
+	}
+	require.NoError(t, is.Init())
+	return is
+}
+
+func TestGather(t *testing.T) {
+	fake := &fakeSpeedtest{
+		servers: []server{
+			{ID: "1", Name: "Berlin", Country: "Germany", Sponsor: "ISP A"},
+		},
+		probed: make(map[string]int),
+	}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	is := newTestPlugin(t, fake, ts)
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(is.Gather))
+
+	require.Len(t, acc.Metrics, 1)
+	m := acc.Metrics[0]
+	require.Equal(t, "internet_speed", m.Measurement)
+	require.Equal(t, map[string]string{
+		"server_id": "1",
+		"location":  "Berlin",
+		"country":   "Germany",
+		"sponsor":   "ISP A",
+	}, m.Tags)
+	for _, field := range []string{"download", "upload", "latency", "jitter"} {
+		require.Contains(t, m.Fields, field)
+	}
+	require.Greater(t, m.Fields["download"].(float64), 0.0)
+	require.Greater(t, m.Fields["upload"].(float64), 0.0)
+
+	fake.Lock()
+	defer fake.Unlock()
+	var expected int64
+	for _, size := range uploadSizes {
+		expected += int64(size)
+	}
+	require.Equal(t, 2*expected, fake.uploaded)
+}
+
+func TestServerFilter(t *testing.T) {
+	fake := &fakeSpeedtest{
+		servers: []server{
+			{ID: "1"},
+			{ID: "2"},
+			{ID: "3"},
+		},
+		probed: make(map[string]int),
+	}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	is := newTestPlugin(t, fake, ts)
+	is.ServerIDInclude = []string{"2", "3"}
+	is.ServerIDExclude = []string{"3"}
+	require.NoError(t, is.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(is.Gather))
+	require.Len(t, acc.Metrics, 1)
+	require.Equal(t, "2", acc.Metrics[0].Tags["server_id"])
+
+	fake.Lock()
+	defer fake.Unlock()
+	require.Equal(t, map[string]int{"2": latencyProbes}, fake.probed)
+}
+
+func TestCache(t *testing.T) {
+	fake := &fakeSpeedtest{
+		servers: []server{{ID: "1"}},
+		probed:  make(map[string]int),
+	}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	is := newTestPlugin(t, fake, ts)
+	is.Cache = true
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(is.Gather))
+
+	// The server list is not fetched again once a server was selected
+	fake.Lock()
+	fake.servers = nil
+	fake.Unlock()
+
+	require.NoError(t, acc.GatherError(is.Gather))
+	require.Len(t, acc.Metrics, 2)
+	require.Equal(t, "1", acc.Metrics[1].Tags["server_id"])
+}
+
+func TestNoServer(t *testing.T) {
+	fake := &fakeSpeedtest{
+		servers: []server{{ID: "1"}},
+		probed:  make(map[string]int),
+	}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	is := newTestPlugin(t, fake, ts)
+	is.ServerIDExclude = []string{"*"}
+	require.NoError(t, is.Init())
+
+	var acc testutil.Accumulator
+	require.Error(t, acc.GatherError(is.Gather))
+}
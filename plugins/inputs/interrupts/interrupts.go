@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -109,7 +110,7 @@ func gatherTagsFields(irq IRQ) (map[string]string, map[string]interface{}) {
 }
 
 func (s *Interrupts) Gather(acc telegraf.Accumulator) error {
-	for measurement, file := range map[string]string{"interrupts": "/proc/interrupts", "soft_interrupts": "/proc/softirqs"} {
+	for measurement, file := range map[string]string{"interrupts": host.Proc("interrupts"), "soft_interrupts": host.Proc("softirqs")} {
 		f, err := os.Open(file)
 		if err != nil {
 			acc.AddError(fmt.Errorf("Could not open file: %s", file))
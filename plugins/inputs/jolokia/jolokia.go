@@ -259,10 +259,6 @@ func (j *Jolokia) extractValues(measurement string, value interface{}, fields ma
 
 func (j *Jolokia) Gather(acc telegraf.Accumulator) error {
 	if j.jClient == nil {
-		j.Log.Warn("DEPRECATED: the jolokia plugin has been deprecated " +
-			"in favor of the jolokia2 plugin " +
-			"(https://github.com/influxdata/telegraf/tree/master/plugins/inputs/jolokia2)")
-
 		tr := &http.Transport{ResponseHeaderTimeout: time.Duration(j.ResponseHeaderTimeout)}
 		j.jClient = &JolokiaClientImpl{&http.Client{
 			Transport: tr,
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -119,8 +120,8 @@ func (k *Kernel) getProcStat() ([]byte, error) {
 func init() {
 	inputs.Add("kernel", func() telegraf.Input {
 		return &Kernel{
-			statFile:        "/proc/stat",
-			entropyStatFile: "/proc/sys/kernel/random/entropy_avail",
+			statFile:        host.Proc("stat"),
+			entropyStatFile: host.Proc("sys/kernel/random/entropy_avail"),
 		}
 	})
 }
@@ -10,6 +10,7 @@ import (
 	"strconv"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -71,7 +72,7 @@ func (k *KernelVmstat) getProcVmstat() ([]byte, error) {
 func init() {
 	inputs.Add("kernel_vmstat", func() telegraf.Input {
 		return &KernelVmstat{
-			statFile: "/proc/vmstat",
+			statFile: host.Proc("vmstat"),
 		}
 	})
 }
@@ -11,6 +11,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"github.com/influxdata/telegraf/internal/k8s"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 )
 
@@ -21,7 +22,7 @@ type client struct {
 }
 
 func newClient(baseURL, namespace, bearerToken string, timeout time.Duration, tlsConfig tls.ClientConfig) (*client, error) {
-	c, err := kubernetes.NewForConfig(&rest.Config{
+	c, err := k8s.Client(&rest.Config{
 		TLSClientConfig: rest.TLSClientConfig{
 			ServerName: baseURL,
 			Insecure:   tlsConfig.InsecureSkipVerify,
@@ -0,0 +1,140 @@
+package kube_state
+
+import (
+	"fmt"
+
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/internal/k8s"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+var sampleConfig = `
+  ## Location of the kubernetes config file, the in-cluster config of the
+  ## service account is used when running in a pod.
+  # kube_config = "/path/to/kubernetes.config"
+
+  ## Namespace to watch, all namespaces when empty.  Nodes are not namespaced
+  ## and always reported.
+  # namespace = ""
+
+  ## Resources to watch, any of "deployments", "jobs", "nodes" and "pods".
+  # resources = ["deployments", "jobs", "nodes", "pods"]
+`
+
+var availableResources = []string{"deployments", "jobs", "nodes", "pods"}
+
+type KubeState struct {
+	KubeConfig string          `toml:"kube_config"`
+	Namespace  string          `toml:"namespace"`
+	Resources  []string        `toml:"resources"`
+	Log        telegraf.Logger `toml:"-"`
+
+	informers *k8s.Informers
+	// synced reports whether the informer of a resource listed all objects,
+	// resources without are always synced
+	synced map[string]cache.InformerSynced
+
+	deployments appslisters.DeploymentLister
+	jobs        batchlisters.JobLister
+	nodes       corelisters.NodeLister
+	pods        corelisters.PodLister
+}
+
+func (k *KubeState) Description() string {
+	return "Report the state of Kubernetes deployments, jobs, nodes and pods watched with informers"
+}
+
+func (k *KubeState) SampleConfig() string {
+	return sampleConfig
+}
+
+func (k *KubeState) Init() error {
+	if len(k.Resources) == 0 {
+		k.Resources = availableResources
+	}
+	if err := choice.CheckSlice(k.Resources, availableResources); err != nil {
+		return fmt.Errorf("invalid resources: %v", err)
+	}
+	return nil
+}
+
+func (k *KubeState) Start(_ telegraf.Accumulator) error {
+	config, err := k8s.LoadConfig(k.KubeConfig)
+	if err != nil {
+		return err
+	}
+	// The informers are shared with other plugins watching the namespace
+	informers, err := k8s.AcquireInformers(config, k8s.InformerOptions{Namespace: k.Namespace})
+	if err != nil {
+		return err
+	}
+	k.informers = informers
+
+	k.synced = make(map[string]cache.InformerSynced)
+	for _, resource := range k.Resources {
+		switch resource {
+		case "deployments":
+			informer := informers.Apps().V1().Deployments()
+			k.deployments = informer.Lister()
+			k.synced[resource] = informer.Informer().HasSynced
+		case "jobs":
+			informer := informers.Batch().V1().Jobs()
+			k.jobs = informer.Lister()
+			k.synced[resource] = informer.Informer().HasSynced
+		case "nodes":
+			informer := informers.Core().V1().Nodes()
+			k.nodes = informer.Lister()
+			k.synced[resource] = informer.Informer().HasSynced
+		case "pods":
+			informer := informers.Core().V1().Pods()
+			k.pods = informer.Lister()
+			k.synced[resource] = informer.Informer().HasSynced
+		}
+	}
+	informers.Start()
+	return nil
+}
+
+func (k *KubeState) Stop() {
+	if k.informers != nil {
+		k.informers.Release()
+		k.informers = nil
+	}
+}
+
+func (k *KubeState) Gather(acc telegraf.Accumulator) error {
+	for _, resource := range k.Resources {
+		if synced, ok := k.synced[resource]; ok && !synced() {
+			k.Log.Debugf("Skipping %s until they are listed", resource)
+			continue
+		}
+
+		var err error
+		switch resource {
+		case "deployments":
+			err = k.gatherDeployments(acc)
+		case "jobs":
+			err = k.gatherJobs(acc)
+		case "nodes":
+			err = k.gatherNodes(acc)
+		case "pods":
+			err = k.gatherPods(acc)
+		}
+		if err != nil {
+			acc.AddError(fmt.Errorf("gathering %s failed: %v", resource, err))
+		}
+	}
+	return nil
+}
+
+func init() {
+	inputs.Add("kube_state", func() telegraf.Input {
+		return &KubeState{}
+	})
+}
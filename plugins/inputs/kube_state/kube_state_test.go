@@ -0,0 +1,252 @@
+package kube_state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newIndexer(t *testing.T, objects ...runtime.Object) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	for _, obj := range objects {
+		require.NoError(t, indexer.Add(obj))
+	}
+	return indexer
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestGatherDeployments(t *testing.T) {
+	plugin := &KubeState{Resources: []string{"deployments"}, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	plugin.deployments = appslisters.NewDeploymentLister(newIndexer(t,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+			Status: appsv1.DeploymentStatus{
+				ReadyReplicas:       2,
+				AvailableReplicas:   2,
+				UpdatedReplicas:     3,
+				UnavailableReplicas: 1,
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"},
+		},
+	))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("kube_state_deployment",
+			map[string]string{"namespace": "default", "deployment": "web"},
+			map[string]interface{}{
+				"replicas_desired":     int64(3),
+				"replicas_ready":       int64(2),
+				"replicas_available":   int64(2),
+				"replicas_updated":     int64(3),
+				"replicas_unavailable": int64(1),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_deployment",
+			map[string]string{"namespace": "default", "deployment": "worker"},
+			map[string]interface{}{
+				"replicas_desired":     int64(1),
+				"replicas_ready":       int64(0),
+				"replicas_available":   int64(0),
+				"replicas_updated":     int64(0),
+				"replicas_unavailable": int64(0),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_objects",
+			map[string]string{"namespace": "default", "resource": "deployments"},
+			map[string]interface{}{"count": int64(2)},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestGatherJobs(t *testing.T) {
+	plugin := &KubeState{Resources: []string{"jobs"}, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	plugin.jobs = batchlisters.NewJobLister(newIndexer(t,
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "backup"},
+			Spec:       batchv1.JobSpec{Completions: int32Ptr(2)},
+			Status: batchv1.JobStatus{
+				Succeeded: 2,
+				Failed:    1,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+				},
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "report"},
+			Status:     batchv1.JobStatus{Active: 1},
+		},
+	))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("kube_state_job",
+			map[string]string{"namespace": "batch", "job": "backup"},
+			map[string]interface{}{
+				"active":              int64(0),
+				"succeeded":           int64(2),
+				"failed":              int64(1),
+				"completions_desired": int64(2),
+				"complete":            true,
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_job",
+			map[string]string{"namespace": "batch", "job": "report"},
+			map[string]interface{}{
+				"active":    int64(1),
+				"succeeded": int64(0),
+				"failed":    int64(0),
+				"complete":  false,
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_objects",
+			map[string]string{"namespace": "batch", "resource": "jobs"},
+			map[string]interface{}{"count": int64(2)},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestGatherNodes(t *testing.T) {
+	plugin := &KubeState{Resources: []string{"nodes"}, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	plugin.nodes = corelisters.NewNodeLister(newIndexer(t,
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+					{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+					{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+					{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
+					{Type: "KernelDeadlock", Status: corev1.ConditionFalse},
+				},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionUnknown},
+				},
+			},
+		},
+	))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("kube_state_node",
+			map[string]string{"node": "node-1"},
+			map[string]interface{}{
+				"unschedulable":   false,
+				"ready":           int64(1),
+				"memory_pressure": int64(0),
+				"disk_pressure":   int64(0),
+				"pid_pressure":    int64(0),
+				"kernel_deadlock": int64(0),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_node",
+			map[string]string{"node": "node-2"},
+			map[string]interface{}{
+				"unschedulable": true,
+				"ready":         int64(-1),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("kube_state_objects",
+			map[string]string{"resource": "nodes"},
+			map[string]interface{}{"count": int64(2)},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestGatherPods(t *testing.T) {
+	pod := func(namespace, name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	plugin := &KubeState{Resources: []string{"pods"}, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	plugin.pods = corelisters.NewPodLister(newIndexer(t,
+		pod("default", "web-1", corev1.PodRunning),
+		pod("default", "web-2", corev1.PodRunning),
+		pod("default", "web-3", corev1.PodPending),
+		pod("batch", "backup-1", corev1.PodSucceeded),
+		pod("batch", "backup-2", ""),
+	))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	count := func(namespace, phase string, count int64) telegraf.Metric {
+		return testutil.MustMetric("kube_state_objects",
+			map[string]string{"namespace": namespace, "phase": phase, "resource": "pods"},
+			map[string]interface{}{"count": count},
+			time.Unix(0, 0))
+	}
+	expected := []telegraf.Metric{
+		count("default", "Running", 2),
+		count("default", "Pending", 1),
+		count("batch", "Succeeded", 1),
+		count("batch", "Unknown", 1),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestGatherSkipsUnsynced(t *testing.T) {
+	plugin := &KubeState{Resources: []string{"nodes"}, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	plugin.nodes = corelisters.NewNodeLister(newIndexer(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	))
+	plugin.synced = map[string]cache.InformerSynced{"nodes": func() bool { return false }}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestInit(t *testing.T) {
+	plugin := &KubeState{}
+	require.NoError(t, plugin.Init())
+	require.Equal(t, []string{"deployments", "jobs", "nodes", "pods"}, plugin.Resources)
+
+	plugin = &KubeState{Resources: []string{"nodes", "services"}}
+	require.EqualError(t, plugin.Init(), "invalid resources: unknown choice services")
+}
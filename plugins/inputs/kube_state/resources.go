@@ -0,0 +1,149 @@
+package kube_state
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+)
+
+// conditionStatus maps the status of node conditions to a value usable in
+// alerts.
+var conditionStatus = map[corev1.ConditionStatus]int64{
+	corev1.ConditionTrue:    1,
+	corev1.ConditionFalse:   0,
+	corev1.ConditionUnknown: -1,
+}
+
+func (k *KubeState) gatherDeployments(acc telegraf.Accumulator) error {
+	deployments, err := k.deployments.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[objectsKey]int64)
+	for _, d := range deployments {
+		counts[objectsKey{namespace: d.Namespace}]++
+
+		// Deployments without replicas default to one
+		desired := int64(1)
+		if d.Spec.Replicas != nil {
+			desired = int64(*d.Spec.Replicas)
+		}
+		fields := map[string]interface{}{
+			"replicas_desired":     desired,
+			"replicas_ready":       int64(d.Status.ReadyReplicas),
+			"replicas_available":   int64(d.Status.AvailableReplicas),
+			"replicas_updated":     int64(d.Status.UpdatedReplicas),
+			"replicas_unavailable": int64(d.Status.UnavailableReplicas),
+		}
+		tags := map[string]string{
+			"namespace":  d.Namespace,
+			"deployment": d.Name,
+		}
+		acc.AddFields("kube_state_deployment", fields, tags)
+	}
+	addObjectCounts(acc, "deployments", counts)
+	return nil
+}
+
+func (k *KubeState) gatherJobs(acc telegraf.Accumulator) error {
+	jobs, err := k.jobs.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[objectsKey]int64)
+	for _, j := range jobs {
+		counts[objectsKey{namespace: j.Namespace}]++
+
+		fields := map[string]interface{}{
+			"active":    int64(j.Status.Active),
+			"succeeded": int64(j.Status.Succeeded),
+			"failed":    int64(j.Status.Failed),
+			"complete":  false,
+		}
+		if j.Spec.Completions != nil {
+			fields["completions_desired"] = int64(*j.Spec.Completions)
+		}
+		for _, c := range j.Status.Conditions {
+			if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+				fields["complete"] = true
+			}
+		}
+		tags := map[string]string{
+			"namespace": j.Namespace,
+			"job":       j.Name,
+		}
+		acc.AddFields("kube_state_job", fields, tags)
+	}
+	addObjectCounts(acc, "jobs", counts)
+	return nil
+}
+
+func (k *KubeState) gatherNodes(acc telegraf.Accumulator) error {
+	nodes, err := k.nodes.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		fields := map[string]interface{}{
+			"unschedulable": n.Spec.Unschedulable,
+		}
+		// Conditions include those of node problem detectors, such as
+		// KernelDeadlock, next to the built-in ones
+		for _, c := range n.Status.Conditions {
+			status, ok := conditionStatus[c.Status]
+			if !ok {
+				status = conditionStatus[corev1.ConditionUnknown]
+			}
+			fields[internal.SnakeCase(string(c.Type))] = status
+		}
+		acc.AddFields("kube_state_node", fields, map[string]string{"node": n.Name})
+	}
+	addObjectCounts(acc, "nodes", map[objectsKey]int64{{}: int64(len(nodes))})
+	return nil
+}
+
+func (k *KubeState) gatherPods(acc telegraf.Accumulator) error {
+	pods, err := k.pods.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	// Only the counts are reported, metrics per pod are left to the
+	// kube_inventory input as their number grows with the cluster
+	counts := make(map[objectsKey]int64)
+	for _, p := range pods {
+		phase := string(p.Status.Phase)
+		if phase == "" {
+			phase = string(corev1.PodUnknown)
+		}
+		counts[objectsKey{namespace: p.Namespace, phase: phase}]++
+	}
+	addObjectCounts(acc, "pods", counts)
+	return nil
+}
+
+// objectsKey groups the objects counted, nodes are not namespaced and only
+// pods have a phase.
+type objectsKey struct {
+	namespace string
+	phase     string
+}
+
+func addObjectCounts(acc telegraf.Accumulator, resource string, counts map[objectsKey]int64) {
+	for key, count := range counts {
+		tags := map[string]string{"resource": resource}
+		if key.namespace != "" {
+			tags["namespace"] = key.namespace
+		}
+		if key.phase != "" {
+			tags["phase"] = key.phase
+		}
+		acc.AddFields("kube_state_objects", map[string]interface{}{"count": count}, tags)
+	}
+}
@@ -7,9 +7,8 @@ import (
 	"os"
 	"strconv"
 
-	"path"
-
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -102,18 +101,10 @@ func (sfs *SysctlFS) Gather(acc telegraf.Accumulator) error {
 	return nil
 }
 
-func GetHostProc() string {
-	procPath := "/proc"
-	if os.Getenv("HOST_PROC") != "" {
-		procPath = os.Getenv("HOST_PROC")
-	}
-	return procPath
-}
-
 func init() {
 	inputs.Add("linux_sysctl_fs", func() telegraf.Input {
 		return &SysctlFS{
-			path: path.Join(GetHostProc(), "/sys/fs"),
+			path: host.Proc("sys/fs"),
 		}
 	})
 }
@@ -137,11 +137,6 @@ func (l *LogParserPlugin) Description() string {
 	return "Stream and parse log file(s)."
 }
 
-func (l *LogParserPlugin) Init() error {
-	l.Log.Warnf(`The logparser plugin is deprecated; please use the 'tail' input with the 'grok' data_format`)
-	return nil
-}
-
 // Gather is the primary function to collect the metrics for the plugin
 func (l *LogParserPlugin) Gather(_ telegraf.Accumulator) error {
 	l.Lock()
@@ -12,7 +12,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
-	"github.com/influxdata/telegraf/plugins/common/tls"
+	mqttconfig "github.com/influxdata/telegraf/plugins/common/mqtt"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers"
 )
@@ -60,7 +60,7 @@ type MQTTConsumer struct {
 
 	PersistentSession bool
 	ClientID          string `toml:"client_id"`
-	tls.ClientConfig
+	mqttconfig.Config
 
 	Log telegraf.Logger
 
@@ -84,6 +84,7 @@ var sampleConfig = `
   ##   example: servers = ["tcp://localhost:1883"]
   ##            servers = ["ssl://localhost:1883"]
   ##            servers = ["ws://localhost:1883"]
+  ##            servers = ["wss://broker.example.com:443/mqtt"]
   servers = ["tcp://127.0.0.1:1883"]
 
   ## Topics that will be subscribed to.
@@ -139,6 +140,12 @@ var sampleConfig = `
   # tls_key = "/etc/telegraf/key.pem"
   ## Use TLS but skip chain & host verification
   # insecure_skip_verify = false
+  ## Resume TLS sessions when reconnecting, sparing the full handshake.
+  # tls_session_resumption = false
+
+  ## HTTP headers sent when connecting over websockets.
+  # [inputs.mqtt_consumer.websocket_headers]
+  #   Authorization = "Bearer token"
 
   ## Data format to consume.
   ## Each data format has its own unique set of configuration options, read
@@ -329,15 +336,6 @@ func (m *MQTTConsumer) createOpts() (*mqtt.ClientOptions, error) {
 		opts.SetClientID(m.ClientID)
 	}
 
-	tlsCfg, err := m.ClientConfig.TLSConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	if tlsCfg != nil {
-		opts.SetTLSConfig(tlsCfg)
-	}
-
 	user := m.Username
 	if user != "" {
 		opts.SetUsername(user)
@@ -355,14 +353,10 @@ func (m *MQTTConsumer) createOpts() (*mqtt.ClientOptions, error) {
 		// Preserve support for host:port style servers; deprecated in Telegraf 1.4.4
 		if !strings.Contains(server, "://") {
 			m.Log.Warnf("Server %q should be updated to use `scheme://host:port` format", server)
-			if tlsCfg == nil {
-				server = "tcp://" + server
-			} else {
-				server = "ssl://" + server
-			}
 		}
-
-		opts.AddBroker(server)
+	}
+	if err := m.Config.SetConfig(opts, m.Servers); err != nil {
+		return nil, err
 	}
 	opts.SetAutoReconnect(false)
 	opts.SetKeepAlive(time.Second * 60)
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -201,7 +202,7 @@ func proc(env, path string) string {
 	// try to read root path, or use default root path
 	root := os.Getenv(EnvRoot)
 	if root == "" {
-		root = NetProc
+		root = host.Proc()
 	}
 	return root + path
 }
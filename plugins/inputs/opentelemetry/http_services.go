@@ -0,0 +1,113 @@
+package opentelemetry
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	otlpcollectorlogs "github.com/influxdata/influxdb-observability/otlp/collector/logs/v1"
+	otlpcollectormetrics "github.com/influxdata/influxdb-observability/otlp/collector/metrics/v1"
+	otlpcollectortrace "github.com/influxdata/influxdb-observability/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxBodySize is the maximum size of the decompressed OTLP/HTTP requests,
+// matching the default maximum message size of the gRPC server.
+const maxBodySize = 4 * 1024 * 1024
+
+// exportFunc passes the decoded request to the service, returning its
+// response.
+type exportFunc func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// newHTTPHandler serves the OTLP/HTTP endpoints with the services of the
+// gRPC server.  Only the binary protobuf encoding is supported.
+func newHTTPHandler(ts *traceService, ms *metricsService, ls *logsService) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v1/traces", exportHandler(
+		func() proto.Message { return &otlpcollectortrace.ExportTraceServiceRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return ts.Export(ctx, req.(*otlpcollectortrace.ExportTraceServiceRequest))
+		},
+	))
+	mux.Handle("/v1/metrics", exportHandler(
+		func() proto.Message { return &otlpcollectormetrics.ExportMetricsServiceRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return ms.Export(ctx, req.(*otlpcollectormetrics.ExportMetricsServiceRequest))
+		},
+	))
+	mux.Handle("/v1/logs", exportHandler(
+		func() proto.Message { return &otlpcollectorlogs.ExportLogsServiceRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return ls.Export(ctx, req.(*otlpcollectorlogs.ExportLogsServiceRequest))
+		},
+	))
+	return mux
+}
+
+func exportHandler(newRequest func() proto.Message, export exportFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if req.Header.Get("Content-Type") != "application/x-protobuf" {
+			http.Error(res, "unsupported content type, only application/x-protobuf is accepted", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := readBody(req)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request := newRequest()
+		if err := proto.Unmarshal(body, request); err != nil {
+			http.Error(res, fmt.Sprintf("decoding request failed: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		response, err := export(req.Context(), request)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := proto.Marshal(response)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.Header().Set("Content-Type", "application/x-protobuf")
+		res.WriteHeader(http.StatusOK)
+		//nolint:errcheck,revive // the client is gone if writing fails
+		res.Write(data)
+	}
+}
+
+// readBody reads the optionally gzip compressed request body.
+func readBody(req *http.Request) ([]byte, error) {
+	var body io.Reader = req.Body
+	switch encoding := req.Header.Get("Content-Encoding"); encoding {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing request failed: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading request failed: %w", err)
+	}
+	if len(data) > maxBodySize {
+		return nil, fmt.Errorf("request larger than %d bytes", maxBodySize)
+	}
+	return data, nil
+}
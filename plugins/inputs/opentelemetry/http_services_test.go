@@ -0,0 +1,120 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	otlpcollectormetrics "github.com/influxdata/influxdb-observability/otlp/collector/metrics/v1"
+	otlpcommon "github.com/influxdata/influxdb-observability/otlp/common/v1"
+	otlpmetrics "github.com/influxdata/influxdb-observability/otlp/metrics/v1"
+	otlpresource "github.com/influxdata/influxdb-observability/otlp/resource/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTestHTTPHandler(t *testing.T, acc telegraf.Accumulator) http.Handler {
+	logger := &otelLogger{testutil.Logger{}}
+	writer := &writeToAccumulator{acc}
+	ms, err := newMetricsService(logger, writer, "prometheus-v1")
+	require.NoError(t, err)
+	return newHTTPHandler(newTraceService(logger, writer), ms, newLogsService(logger, writer))
+}
+
+func TestHTTPExportMetrics(t *testing.T) {
+	var acc testutil.Accumulator
+	ts := httptest.NewServer(newTestHTTPHandler(t, &acc))
+	defer ts.Close()
+
+	now := time.Unix(1600000000, 0)
+	req := &otlpcollectormetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlpmetrics.ResourceMetrics{
+			{
+				Resource: &otlpresource.Resource{
+					Attributes: []*otlpcommon.KeyValue{
+						{
+							Key:   "service.name",
+							Value: &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: "checkout"}},
+						},
+					},
+				},
+				InstrumentationLibraryMetrics: []*otlpmetrics.InstrumentationLibraryMetrics{
+					{
+						InstrumentationLibrary: &otlpcommon.InstrumentationLibrary{Name: "checkout"},
+						Metrics: []*otlpmetrics.Metric{
+							{
+								Name: "cpu_temp",
+								Data: &otlpmetrics.Metric_DoubleGauge{
+									DoubleGauge: &otlpmetrics.DoubleGauge{
+										DataPoints: []*otlpmetrics.DoubleDataPoint{
+											{
+												Labels:       []*otlpcommon.StringKeyValue{{Key: "foo", Value: "bar"}},
+												TimeUnixNano: uint64(now.UnixNano()),
+												Value:        87.332,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	httpReq, err := http.NewRequest("POST", ts.URL+"/v1/metrics", &buf)
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu_temp",
+			map[string]string{"foo": "bar", "otel.library.name": "checkout", "service.name": "checkout"},
+			map[string]interface{}{"gauge": 87.332},
+			now, telegraf.Gauge),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestHTTPExportInvalid(t *testing.T) {
+	var acc testutil.Accumulator
+	ts := httptest.NewServer(newTestHTTPHandler(t, &acc))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/metrics", "application/json", bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Post(ts.URL+"/v1/metrics", "application/x-protobuf", bytes.NewReader([]byte{0xff, 0xff}))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/v1/traces")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
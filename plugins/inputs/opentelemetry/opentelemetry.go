@@ -1,8 +1,11 @@
 package opentelemetry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
@@ -21,9 +24,13 @@ type OpenTelemetry struct {
 
 	MetricsSchema string `toml:"metrics_schema"`
 
+	// Address of the OTLP/HTTP service, empty disables it
+	HTTPServiceAddress string `toml:"http_service_address"`
+
 	Log telegraf.Logger `toml:"-"`
 
 	grpcServer *grpc.Server
+	httpServer *http.Server
 
 	wg sync.WaitGroup
 }
@@ -32,6 +39,10 @@ const sampleConfig = `
   ## Override the OpenTelemetry gRPC service address:port 
   # service_address = "0.0.0.0:4317"
 
+  ## Address:port of the OTLP/HTTP service accepting protobuf encoded
+  ## requests at /v1/traces, /v1/metrics and /v1/logs, disabled if empty
+  # http_service_address = "0.0.0.0:4318"
+
   ## Override the default request timeout
   # timeout = "5s"
 
@@ -46,7 +57,7 @@ func (o *OpenTelemetry) SampleConfig() string {
 }
 
 func (o *OpenTelemetry) Description() string {
-	return "Receive OpenTelemetry traces, metrics, and logs over gRPC"
+	return "Receive OpenTelemetry traces, metrics, and logs over gRPC and HTTP"
 }
 
 func (o *OpenTelemetry) Gather(_ telegraf.Accumulator) error {
@@ -63,13 +74,37 @@ func (o *OpenTelemetry) Start(accumulator telegraf.Accumulator) error {
 	influxWriter := &writeToAccumulator{accumulator}
 	o.grpcServer = grpc.NewServer()
 
-	otlpcollectortrace.RegisterTraceServiceServer(o.grpcServer, newTraceService(logger, influxWriter))
+	ts := newTraceService(logger, influxWriter)
+	otlpcollectortrace.RegisterTraceServiceServer(o.grpcServer, ts)
 	ms, err := newMetricsService(logger, influxWriter, o.MetricsSchema)
 	if err != nil {
 		return err
 	}
 	otlpcollectormetrics.RegisterMetricsServiceServer(o.grpcServer, ms)
-	otlpcollectorlogs.RegisterLogsServiceServer(o.grpcServer, newLogsService(logger, influxWriter))
+	ls := newLogsService(logger, influxWriter)
+	otlpcollectorlogs.RegisterLogsServiceServer(o.grpcServer, ls)
+
+	if o.HTTPServiceAddress != "" {
+		httpListener, err := net.Listen("tcp", o.HTTPServiceAddress)
+		if err != nil {
+			//nolint:errcheck,revive // the listen error is more relevant
+			listener.Close()
+			return err
+		}
+		o.httpServer = &http.Server{
+			Handler:      newHTTPHandler(ts, ms, ls),
+			ReadTimeout:  time.Duration(o.Timeout),
+			WriteTimeout: time.Duration(o.Timeout),
+		}
+
+		o.wg.Add(1)
+		go func() {
+			if err := o.httpServer.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				accumulator.AddError(fmt.Errorf("failed to stop OpenTelemetry HTTP service: %w", err))
+			}
+			o.wg.Done()
+		}()
+	}
 
 	o.wg.Add(1)
 	go func() {
@@ -86,6 +121,11 @@ func (o *OpenTelemetry) Stop() {
 	if o.grpcServer != nil {
 		o.grpcServer.Stop()
 	}
+	if o.httpServer != nil {
+		if err := o.httpServer.Shutdown(context.Background()); err != nil {
+			o.Log.Errorf("Shutting down HTTP service failed: %v", err)
+		}
+	}
 
 	o.wg.Wait()
 }
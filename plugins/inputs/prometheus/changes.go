@@ -0,0 +1,134 @@
+package prometheus
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// seriesValues are the values last forwarded for the series of a target.
+type seriesValues struct {
+	values map[string]uint64
+	synced time.Time
+}
+
+// changeFilter is an accumulator forwarding only the series whose values
+// changed since they were last forwarded.
+type changeFilter struct {
+	telegraf.Accumulator
+	values map[string]uint64
+
+	// The scrape time changes with every scrape and is not compared
+	ignoreField string
+	ignoreTag   string
+}
+
+// newChangeFilter returns the filter for a scrape of the target.  All series
+// are forwarded on the first scrape and every resync_interval, forgetting
+// the series which disappeared in between.
+func (p *Prometheus) newChangeFilter(key string, acc telegraf.Accumulator, now time.Time) *changeFilter {
+	p.changesLock.Lock()
+	defer p.changesLock.Unlock()
+
+	if p.changes == nil {
+		p.changes = make(map[string]*seriesValues)
+	}
+	cache, ok := p.changes[key]
+	if !ok {
+		cache = &seriesValues{}
+		p.changes[key] = cache
+	}
+	resync := time.Duration(p.ResyncInterval)
+	if cache.values == nil || (resync > 0 && now.Sub(cache.synced) >= resync) {
+		cache.values = make(map[string]uint64)
+		cache.synced = now
+	}
+
+	return &changeFilter{
+		Accumulator: acc,
+		values:      cache.values,
+		ignoreField: p.ScrapeTimeField,
+		ignoreTag:   p.ScrapeTimeTag,
+	}
+}
+
+// expireChanges forgets the values of targets no longer scraped.
+func (p *Prometheus) expireChanges(targets map[string]URLAndAddress) {
+	p.changesLock.Lock()
+	defer p.changesLock.Unlock()
+
+	for key := range p.changes {
+		if _, ok := targets[key]; !ok {
+			delete(p.changes, key)
+		}
+	}
+}
+
+// changed records the values of the series and reports whether they differ
+// from the values last forwarded.
+func (f *changeFilter) changed(name string, fields map[string]interface{}, tags map[string]string) bool {
+	if f.ignoreTag != "" {
+		if _, ok := tags[f.ignoreTag]; ok {
+			filtered := make(map[string]string, len(tags))
+			for k, v := range tags {
+				if k != f.ignoreTag {
+					filtered[k] = v
+				}
+			}
+			tags = filtered
+		}
+	}
+	id := seriesID(name, tags)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != f.ignoreField {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%v\x00", k, fields[k])
+	}
+	sum := h.Sum64()
+
+	if previous, ok := f.values[id]; ok && previous == sum {
+		return false
+	}
+	f.values[id] = sum
+	return true
+}
+
+func (f *changeFilter) AddFields(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	if f.changed(name, fields, tags) {
+		f.Accumulator.AddFields(name, fields, tags, t...)
+	}
+}
+
+func (f *changeFilter) AddGauge(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	if f.changed(name, fields, tags) {
+		f.Accumulator.AddGauge(name, fields, tags, t...)
+	}
+}
+
+func (f *changeFilter) AddCounter(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	if f.changed(name, fields, tags) {
+		f.Accumulator.AddCounter(name, fields, tags, t...)
+	}
+}
+
+func (f *changeFilter) AddSummary(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	if f.changed(name, fields, tags) {
+		f.Accumulator.AddSummary(name, fields, tags, t...)
+	}
+}
+
+func (f *changeFilter) AddHistogram(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	if f.changed(name, fields, tags) {
+		f.Accumulator.AddHistogram(name, fields, tags, t...)
+	}
+}
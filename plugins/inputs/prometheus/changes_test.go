@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSeriesOnly(t *testing.T) {
+	body := "queue_length{queue=\"a\"} 1\nqueue_length{queue=\"b\"} 2\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, body)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:               testutil.Logger{},
+		URLs:              []string{ts.URL},
+		MetricVersion:     2,
+		ScrapeTimeField:   "scrape_time",
+		ChangedSeriesOnly: true,
+		ResyncInterval:    config.Duration(time.Hour),
+	}
+	require.NoError(t, p.Init())
+
+	gather := func() []string {
+		var acc testutil.Accumulator
+		require.NoError(t, acc.GatherError(p.Gather))
+		var queues []string
+		for _, m := range acc.GetTelegrafMetrics() {
+			queue, _ := m.GetTag("queue")
+			queues = append(queues, queue)
+		}
+		return queues
+	}
+
+	// The first scrape adds all series, unchanged ones are dropped after
+	require.ElementsMatch(t, []string{"a", "b"}, gather())
+	require.Empty(t, gather())
+
+	body = "queue_length{queue=\"a\"} 1\nqueue_length{queue=\"b\"} 3\nqueue_length{queue=\"c\"} 0\n"
+	require.ElementsMatch(t, []string{"b", "c"}, gather())
+
+	// A resync adds all series again
+	p.ResyncInterval = config.Duration(time.Nanosecond)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, gather())
+}
+
+func TestChangedSeriesOnlyStaleMarkers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, "queue_length{queue=\"a\"} 1\n")
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:               testutil.Logger{},
+		URLs:              []string{ts.URL},
+		MetricVersion:     2,
+		StaleMarkers:      true,
+		StaleMarkerField:  "stale",
+		ChangedSeriesOnly: true,
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+
+	// Unchanged series are neither added nor marked as stale
+	acc.ClearMetrics()
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
@@ -0,0 +1,146 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/influxdata/telegraf/config"
+)
+
+// ConsulConfig discovers the urls to scrape from the healthy instances of
+// services registered in a Consul catalog.
+type ConsulConfig struct {
+	Agent           string              `toml:"agent"`
+	Datacenter      string              `toml:"datacenter"`
+	Token           string              `toml:"token"`
+	Services        []string            `toml:"services"`
+	ServiceTags     []string            `toml:"service_tags"`
+	MetaTags        []string            `toml:"meta_tags"`
+	Scheme          string              `toml:"scheme"`
+	MetricsPath     string              `toml:"metrics_path"`
+	RefreshInterval config.Duration     `toml:"refresh_interval"`
+	Params          map[string][]string `toml:"params"`
+}
+
+func (c *ConsulConfig) init() error {
+	if len(c.Services) == 0 {
+		return errors.New("consul_config requires at least one service")
+	}
+	if c.Scheme == "" {
+		c.Scheme = "http"
+	}
+	if c.MetricsPath == "" {
+		c.MetricsPath = "/metrics"
+	}
+	if c.RefreshInterval == 0 {
+		c.RefreshInterval = config.Duration(time.Minute)
+	}
+	return nil
+}
+
+func (c *ConsulConfig) client() (*api.Client, error) {
+	cfg := api.DefaultConfig()
+	if c.Agent != "" {
+		cfg.Address = c.Agent
+	}
+	if c.Datacenter != "" {
+		cfg.Datacenter = c.Datacenter
+	}
+	if c.Token != "" {
+		cfg.Token = c.Token
+	}
+	return api.NewClient(cfg)
+}
+
+// serviceURL returns the url of the service instance tagged with the Consul
+// service and node metadata.
+func (c *ConsulConfig) serviceURL(entry *api.ServiceEntry) URLAndAddress {
+	address := entry.Service.Address
+	if address == "" {
+		address = entry.Node.Address
+	}
+
+	u := &url.URL{
+		Scheme: c.Scheme,
+		Host:   net.JoinHostPort(address, strconv.Itoa(entry.Service.Port)),
+		Path:   c.MetricsPath,
+	}
+	u = addParams(u, c.Params)
+
+	tags := map[string]string{
+		"consul_service": entry.Service.Service,
+		"consul_node":    entry.Node.Node,
+	}
+	if entry.Node.Datacenter != "" {
+		tags["consul_datacenter"] = entry.Node.Datacenter
+	}
+	// Service metadata takes precedence over node metadata
+	for _, key := range c.MetaTags {
+		if v, ok := entry.Node.Meta[key]; ok {
+			tags[key] = v
+		}
+		if v, ok := entry.Service.Meta[key]; ok {
+			tags[key] = v
+		}
+	}
+
+	return URLAndAddress{URL: u, OriginalURL: u, Address: address, Tags: tags}
+}
+
+func (p *Prometheus) startConsul(ctx context.Context) error {
+	client, err := p.ConsulConfig.client()
+	if err != nil {
+		return err
+	}
+
+	if err := p.refreshConsulServices(client); err != nil {
+		p.Log.Errorf("Unable to discover consul services: %s", err.Error())
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(time.Duration(p.ConsulConfig.RefreshInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.refreshConsulServices(client); err != nil {
+					p.Log.Errorf("Unable to discover consul services: %s", err.Error())
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// refreshConsulServices replaces the discovered urls with the passing
+// instances of the configured services.  The previous urls are kept if
+// Consul cannot be queried.
+func (p *Prometheus) refreshConsulServices(client *api.Client) error {
+	c := p.ConsulConfig
+	urls := make(map[string]URLAndAddress)
+	for _, name := range c.Services {
+		entries, _, err := client.Health().ServiceMultipleTags(name, c.ServiceTags, true, nil)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			u := c.serviceURL(entry)
+			urls[u.URL.String()] = u
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.consulServices = urls
+	return nil
+}
@@ -0,0 +1,91 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulServices(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/health/service/web", r.URL.Path)
+		require.Equal(t, "1", r.URL.Query().Get("passing"))
+		require.Equal(t, []string{"prometheus"}, r.URL.Query()["tag"])
+
+		entries := []*api.ServiceEntry{
+			{
+				Node: &api.Node{
+					Node:       "node1",
+					Address:    "10.0.0.1",
+					Datacenter: "dc1",
+					Meta:       map[string]string{"rack": "r1", "version": "0.9"},
+				},
+				Service: &api.AgentService{
+					Service: "web",
+					Port:    9100,
+					Meta:    map[string]string{"version": "1.0"},
+				},
+			},
+			{
+				Node: &api.Node{Node: "node2", Address: "10.0.0.2"},
+				Service: &api.AgentService{
+					Service: "web",
+					Address: "172.17.0.2",
+					Port:    9101,
+				},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(entries))
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log: testutil.Logger{},
+		ConsulConfig: &ConsulConfig{
+			Agent:       ts.URL,
+			Services:    []string{"web"},
+			ServiceTags: []string{"prometheus"},
+			MetaTags:    []string{"rack", "version"},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	client, err := p.ConsulConfig.client()
+	require.NoError(t, err)
+	require.NoError(t, p.refreshConsulServices(client))
+
+	urls, err := p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+
+	u, ok := urls["http://10.0.0.1:9100/metrics"]
+	require.True(t, ok)
+	require.Equal(t, "10.0.0.1", u.Address)
+	require.Equal(t, map[string]string{
+		"consul_service":    "web",
+		"consul_node":       "node1",
+		"consul_datacenter": "dc1",
+		"rack":              "r1",
+		"version":           "1.0",
+	}, u.Tags)
+
+	u, ok = urls["http://172.17.0.2:9101/metrics"]
+	require.True(t, ok)
+	require.Equal(t, map[string]string{
+		"consul_service": "web",
+		"consul_node":    "node2",
+	}, u.Tags)
+}
+
+func TestConsulConfigWithoutServices(t *testing.T) {
+	p := &Prometheus{
+		Log:          testutil.Logger{},
+		ConsulConfig: &ConsulConfig{},
+	}
+	require.EqualError(t, p.Init(), "consul_config requires at least one service")
+}
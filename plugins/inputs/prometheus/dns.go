@@ -0,0 +1,143 @@
+package prometheus
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// initDNSDiscovery checks and completes the DNS service discovery settings.
+func (p *Prometheus) initDNSDiscovery() error {
+	if len(p.DNSSDNames) == 0 {
+		return nil
+	}
+
+	switch strings.ToUpper(p.DNSSDType) {
+	case "", "SRV":
+		p.DNSSDType = "SRV"
+	case "A":
+		p.DNSSDType = "A"
+		if p.DNSSDPort <= 0 {
+			return fmt.Errorf("dns_sd_port is required for dns_sd_type %q", p.DNSSDType)
+		}
+	default:
+		return fmt.Errorf("invalid dns_sd_type %q, must be \"SRV\" or \"A\"", p.DNSSDType)
+	}
+
+	if p.DNSSDScheme == "" {
+		p.DNSSDScheme = "http"
+	}
+	if p.DNSSDPath == "" {
+		p.DNSSDPath = "/metrics"
+	}
+	if p.lookupSRV == nil {
+		p.lookupSRV = net.LookupSRV
+	}
+	if p.lookupHost == nil {
+		p.lookupHost = net.LookupHost
+	}
+	return nil
+}
+
+// dnsDiscoveredURLs returns the targets discovered from the DNS records,
+// resolving the names again once the refresh interval elapsed.  If a name
+// cannot be resolved its previous targets are kept.
+func (p *Prometheus) dnsDiscoveredURLs() map[string]URLAndAddress {
+	if len(p.DNSSDNames) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if p.dnsTargets != nil && now.Sub(p.dnsRefreshed) < time.Duration(p.DNSSDRefreshInterval) {
+		return p.dnsTargets
+	}
+
+	targets := make(map[string]URLAndAddress)
+	for _, name := range p.DNSSDNames {
+		var found map[string]URLAndAddress
+		var err error
+		if p.DNSSDType == "A" {
+			found, err = p.resolveA(name)
+		} else {
+			found, err = p.resolveSRV(name)
+		}
+		if err != nil {
+			p.Log.Errorf("Could not resolve %q, keeping its previous targets. Error: %s", name, err)
+			found = previousDNSTargets(p.dnsTargets, name)
+		}
+		for k, v := range found {
+			targets[k] = v
+		}
+	}
+
+	p.dnsTargets = targets
+	p.dnsRefreshed = now
+	return targets
+}
+
+// resolveSRV returns a target for every SRV record of the name using the
+// port of the record.
+func (p *Prometheus) resolveSRV(name string) (map[string]URLAndAddress, error) {
+	_, records, err := p.lookupSRV("", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]URLAndAddress, len(records))
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		u := p.dnsTargetURL(target, int(record.Port))
+		targets[u.String()] = URLAndAddress{
+			URL:         u,
+			OriginalURL: u,
+			Tags: map[string]string{
+				"dns_name":   name,
+				"srv_target": target,
+			},
+		}
+	}
+	return targets, nil
+}
+
+// resolveA returns a target for every address of the name using the
+// configured port.
+func (p *Prometheus) resolveA(name string) (map[string]URLAndAddress, error) {
+	addresses, err := p.lookupHost(name)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]URLAndAddress, len(addresses))
+	for _, address := range addresses {
+		u := p.dnsTargetURL(address, p.DNSSDPort)
+		targets[u.String()] = URLAndAddress{
+			URL:         u,
+			OriginalURL: u,
+			Address:     address,
+			Tags:        map[string]string{"dns_name": name},
+		}
+	}
+	return targets, nil
+}
+
+func (p *Prometheus) dnsTargetURL(host string, port int) *url.URL {
+	u := &url.URL{
+		Scheme: p.DNSSDScheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   p.DNSSDPath,
+	}
+	return addParams(u, p.DNSSDParams)
+}
+
+func previousDNSTargets(targets map[string]URLAndAddress, name string) map[string]URLAndAddress {
+	previous := make(map[string]URLAndAddress)
+	for k, v := range targets {
+		if v.Tags["dns_name"] == name {
+			previous[k] = v
+		}
+	}
+	return previous
+}
@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitDNSDiscoveryErrors(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, DNSSDNames: []string{"example.org"}, DNSSDType: "MX"}
+	require.Error(t, p.initDNSDiscovery())
+
+	p = &Prometheus{Log: testutil.Logger{}, DNSSDNames: []string{"example.org"}, DNSSDType: "A"}
+	require.Error(t, p.initDNSDiscovery())
+}
+
+func TestDNSDiscoverySRV(t *testing.T) {
+	lookups := 0
+	p := &Prometheus{
+		Log:                  testutil.Logger{},
+		DNSSDNames:           []string{"_metrics._tcp.example.org"},
+		DNSSDRefreshInterval: config.Duration(time.Hour),
+		lookupSRV: func(_, _, name string) (string, []*net.SRV, error) {
+			lookups++
+			return "", []*net.SRV{
+				{Target: "node1.example.org.", Port: 9100},
+				{Target: "node2.example.org.", Port: 9200},
+			}, nil
+		},
+	}
+	require.NoError(t, p.initDNSDiscovery())
+
+	targets := p.dnsDiscoveredURLs()
+	require.Len(t, targets, 2)
+	target, ok := targets["http://node2.example.org:9200/metrics"]
+	require.True(t, ok)
+	require.Equal(t, map[string]string{
+		"dns_name":   "_metrics._tcp.example.org",
+		"srv_target": "node2.example.org",
+	}, target.Tags)
+
+	// Names are not resolved again before the refresh interval elapsed
+	p.dnsDiscoveredURLs()
+	require.Equal(t, 1, lookups)
+}
+
+func TestDNSDiscoveryA(t *testing.T) {
+	fail := false
+	p := &Prometheus{
+		Log:        testutil.Logger{},
+		DNSSDNames: []string{"metrics.example.org"},
+		DNSSDType:  "a",
+		DNSSDPort:  9273,
+		DNSSDPath:  "/custom",
+		lookupHost: func(host string) ([]string, error) {
+			if fail {
+				return nil, errors.New("no such host")
+			}
+			return []string{"10.0.0.1", "10.0.0.2"}, nil
+		},
+	}
+	require.NoError(t, p.initDNSDiscovery())
+
+	targets := p.dnsDiscoveredURLs()
+	require.Len(t, targets, 2)
+	target, ok := targets["http://10.0.0.1:9273/custom"]
+	require.True(t, ok)
+	require.Equal(t, "10.0.0.1", target.Address)
+
+	// The previous targets are kept if the name cannot be resolved
+	fail = true
+	require.Len(t, p.dnsDiscoveredURLs(), 2)
+}
@@ -0,0 +1,122 @@
+package prometheus
+
+import (
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// reshapesHistograms reports whether histograms and summaries are emitted in
+// another shape than the default of metric_version 2.
+func (p *Prometheus) reshapesHistograms() bool {
+	return p.HistogramBuckets == "delta" || p.ExcludeSumCount || p.BucketLabels == "field"
+}
+
+// deltaBuckets replaces the cumulative counts of the histogram buckets with
+// the number of observations falling into each bucket.
+func deltaBuckets(families map[string]*dto.MetricFamily) {
+	for _, mf := range families {
+		if mf.GetType() != dto.MetricType_HISTOGRAM {
+			continue
+		}
+		for _, m := range mf.Metric {
+			var previous uint64
+			for _, b := range m.GetHistogram().GetBucket() {
+				cumulative := b.GetCumulativeCount()
+				// Counts of misbehaving exporters decreasing with the bound
+				// are clamped to zero
+				delta := uint64(0)
+				if cumulative > previous {
+					delta = cumulative - previous
+				}
+				b.CumulativeCount = &delta
+				previous = cumulative
+			}
+		}
+	}
+}
+
+// reshapeHistograms drops the sum and count of histograms and summaries and
+// moves the bucket bounds and quantiles from tags into the field names, as
+// configured.  It expects metrics of metric_version 2.
+func (p *Prometheus) reshapeHistograms(metrics []telegraf.Metric) []telegraf.Metric {
+	reshaped := metrics[:0]
+	merged := make(map[uint64]telegraf.Metric)
+	for _, m := range metrics {
+		if m.Type() != telegraf.Histogram && m.Type() != telegraf.Summary {
+			reshaped = append(reshaped, m)
+			continue
+		}
+
+		label := "le"
+		if m.Type() == telegraf.Summary {
+			label = "quantile"
+		}
+		bound, isBucket := m.GetTag(label)
+
+		if !isBucket && p.ExcludeSumCount {
+			var keys []string
+			for _, field := range m.FieldList() {
+				if strings.HasSuffix(field.Key, "_sum") || strings.HasSuffix(field.Key, "_count") {
+					keys = append(keys, field.Key)
+				}
+			}
+			for _, key := range keys {
+				m.RemoveField(key)
+			}
+			if len(m.FieldList()) == 0 {
+				continue
+			}
+		}
+
+		if p.BucketLabels != "field" {
+			reshaped = append(reshaped, m)
+			continue
+		}
+
+		if isBucket {
+			m.RemoveTag(label)
+			suffixBucketFields(m, bound)
+		}
+
+		// The buckets and the sum and count of a series are merged into a
+		// single metric
+		id := m.HashID()
+		if target, ok := merged[id]; ok && target.Time().Equal(m.Time()) {
+			for _, field := range m.FieldList() {
+				target.AddField(field.Key, field.Value)
+			}
+			continue
+		}
+		merged[id] = m
+		reshaped = append(reshaped, m)
+	}
+	return reshaped
+}
+
+// suffixBucketFields appends the bound of the bucket or quantile to the name
+// of its value field, keeping the exemplar fields extending that name.
+func suffixBucketFields(m telegraf.Metric, bound string) {
+	fields := append([]*telegraf.Field(nil), m.FieldList()...)
+	if len(fields) == 0 {
+		return
+	}
+	name := fields[0].Key
+	for _, field := range fields[1:] {
+		if len(field.Key) < len(name) {
+			name = field.Key
+		}
+	}
+
+	renamed := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		renamed[name+"_"+bound+strings.TrimPrefix(field.Key, name)] = field.Value
+	}
+	for _, field := range fields {
+		m.RemoveField(field.Key)
+	}
+	for key, value := range renamed {
+		m.AddField(key, value)
+	}
+}
@@ -0,0 +1,127 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleHistogramTextFormat = `# TYPE http_duration_seconds histogram
+http_duration_seconds_bucket{le="0.1"} 2
+http_duration_seconds_bucket{le="1"} 5
+http_duration_seconds_bucket{le="+Inf"} 6
+http_duration_seconds_sum 4.2
+http_duration_seconds_count 6
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.2
+rpc_duration_seconds_sum 3
+rpc_duration_seconds_count 10
+`
+
+func TestHistogramShapes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleHistogramTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		buckets  string
+		exclude  bool
+		labels   string
+		expected []telegraf.Metric
+	}{
+		{
+			name:    "delta buckets",
+			buckets: "delta",
+			expected: []telegraf.Metric{
+				testutil.MustMetric("prometheus", map[string]string{},
+					map[string]interface{}{"http_duration_seconds_sum": 4.2, "http_duration_seconds_count": 6.0},
+					time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"le": "0.1"},
+					map[string]interface{}{"http_duration_seconds_bucket": 2.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"le": "1"},
+					map[string]interface{}{"http_duration_seconds_bucket": 3.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"le": "+Inf"},
+					map[string]interface{}{"http_duration_seconds_bucket": 1.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{},
+					map[string]interface{}{"rpc_duration_seconds_sum": 3.0, "rpc_duration_seconds_count": 10.0},
+					time.Unix(0, 0), telegraf.Summary),
+				testutil.MustMetric("prometheus", map[string]string{"quantile": "0.5"},
+					map[string]interface{}{"rpc_duration_seconds": 0.2}, time.Unix(0, 0), telegraf.Summary),
+			},
+		},
+		{
+			name:    "exclude sum and count",
+			exclude: true,
+			expected: []telegraf.Metric{
+				testutil.MustMetric("prometheus", map[string]string{"le": "0.1"},
+					map[string]interface{}{"http_duration_seconds_bucket": 2.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"le": "1"},
+					map[string]interface{}{"http_duration_seconds_bucket": 5.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"le": "+Inf"},
+					map[string]interface{}{"http_duration_seconds_bucket": 6.0}, time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{"quantile": "0.5"},
+					map[string]interface{}{"rpc_duration_seconds": 0.2}, time.Unix(0, 0), telegraf.Summary),
+			},
+		},
+		{
+			name:   "bounds as fields",
+			labels: "field",
+			expected: []telegraf.Metric{
+				testutil.MustMetric("prometheus", map[string]string{},
+					map[string]interface{}{
+						"http_duration_seconds_sum":         4.2,
+						"http_duration_seconds_count":       6.0,
+						"http_duration_seconds_bucket_0.1":  2.0,
+						"http_duration_seconds_bucket_1":    5.0,
+						"http_duration_seconds_bucket_+Inf": 6.0,
+					},
+					time.Unix(0, 0), telegraf.Histogram),
+				testutil.MustMetric("prometheus", map[string]string{},
+					map[string]interface{}{
+						"rpc_duration_seconds_sum":   3.0,
+						"rpc_duration_seconds_count": 10.0,
+						"rpc_duration_seconds_0.5":   0.2,
+					},
+					time.Unix(0, 0), telegraf.Summary),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Prometheus{
+				Log:              testutil.Logger{},
+				URLs:             []string{ts.URL},
+				MetricVersion:    2,
+				HistogramBuckets: tt.buckets,
+				ExcludeSumCount:  tt.exclude,
+				BucketLabels:     tt.labels,
+			}
+			require.NoError(t, p.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, acc.GatherError(p.Gather))
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics(),
+				testutil.IgnoreTime(), testutil.SortMetrics())
+		})
+	}
+}
+
+func TestHistogramShapesInvalid(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, MetricVersion: 2, HistogramBuckets: "sparse"}
+	require.EqualError(t, p.Init(), `unknown histogram_buckets "sparse"`)
+
+	p = &Prometheus{Log: testutil.Logger{}, MetricVersion: 2, BucketLabels: "suffix"}
+	require.EqualError(t, p.Init(), `unknown bucket_labels "suffix"`)
+
+	p = &Prometheus{Log: testutil.Logger{}, MetricVersion: 1, ExcludeSumCount: true}
+	require.EqualError(t, p.Init(), "histogram_buckets, exclude_sum_count and bucket_labels require metric_version = 2")
+}
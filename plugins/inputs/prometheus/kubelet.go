@@ -0,0 +1,169 @@
+package prometheus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const serviceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// defaultKubeletScrapePaths are the endpoints of the kubelet exposing the
+// container metrics of cAdvisor and the resource usage of pods and nodes.
+var defaultKubeletScrapePaths = []string{"/metrics/cadvisor", "/metrics/resource"}
+
+func (p *Prometheus) initKubeletScrape() {
+	if !p.KubeletScrape {
+		return
+	}
+	if len(p.KubeletScrapePaths) == 0 {
+		p.KubeletScrapePaths = defaultKubeletScrapePaths
+	}
+	if p.KubeletBearerToken == "" {
+		p.KubeletBearerToken = serviceAccountToken
+	}
+	if p.kubeletClient == nil {
+		p.kubeletClient = newKubeletClient(p.proxy)
+	}
+}
+
+// watchNodes watches the nodes of the cluster to scrape their kubelets.  With
+// the node scrape scope and a known node name only the kubelet of that node is
+// scraped.
+func (p *Prometheus) watchNodes(config *rest.Config) error {
+	var selector string
+	if p.isNodeScrapeScope && p.NodeName != "" {
+		selector = fields.OneTermEqualSelector("metadata.name", p.NodeName).String()
+	}
+	informers, err := k8s.AcquireInformers(config, k8s.InformerOptions{FieldSelector: selector})
+	if err != nil {
+		return fmt.Errorf("watching nodes failed: %w", err)
+	}
+	p.informers = append(p.informers, informers)
+
+	informers.AddEventHandler(informers.Core().V1().Nodes().Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if node, ok := obj.(*corev1.Node); ok {
+				p.handleNodeEvent(watch.Added, node)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if node, ok := obj.(*corev1.Node); ok {
+				p.handleNodeEvent(watch.Modified, node)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if node, ok := obj.(*corev1.Node); ok {
+				p.handleNodeEvent(watch.Deleted, node)
+			}
+		},
+	})
+	informers.Start()
+	return nil
+}
+
+func (p *Prometheus) handleNodeEvent(eventType watch.EventType, node *corev1.Node) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.kubeletNodes == nil {
+		p.kubeletNodes = make(map[string][]URLAndAddress)
+	}
+	if eventType == watch.Deleted {
+		delete(p.kubeletNodes, node.Name)
+		return
+	}
+
+	urls := p.kubeletNodeURLs(node)
+	if len(urls) == 0 {
+		p.Log.Debugf("Node %q has no address, not scraping its kubelet", node.Name)
+		delete(p.kubeletNodes, node.Name)
+		return
+	}
+	p.kubeletNodes[node.Name] = urls
+}
+
+// kubeletNodeURLs returns the scrape urls of the kubelet of the node, at its
+// internal address and the port it advertises.
+func (p *Prometheus) kubeletNodeURLs(node *corev1.Node) []URLAndAddress {
+	address := nodeAddress(node)
+	if address == "" {
+		return nil
+	}
+	port := kubeletPort
+	if endpoint := node.Status.DaemonEndpoints.KubeletEndpoint.Port; endpoint > 0 {
+		port = strconv.Itoa(int(endpoint))
+	}
+
+	urls := make([]URLAndAddress, 0, len(p.KubeletScrapePaths))
+	for _, path := range p.KubeletScrapePaths {
+		u := &url.URL{
+			Scheme: "https",
+			Host:   net.JoinHostPort(address, port),
+			Path:   path,
+		}
+		urls = append(urls, URLAndAddress{
+			URL:         u,
+			OriginalURL: u,
+			Address:     address,
+			Tags:        map[string]string{"node_name": node.Name},
+			Kubelet:     true,
+		})
+	}
+	return urls
+}
+
+// nodeAddress returns the internal IP of the node, falling back to its host
+// name.
+func nodeAddress(node *corev1.Node) string {
+	var hostname string
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeInternalIP:
+			return address.Address
+		case corev1.NodeHostName:
+			hostname = address.Address
+		}
+	}
+	return hostname
+}
+
+// kubeletURLs returns the scrape urls of the watched kubelets.
+func (p *Prometheus) kubeletURLs() map[string]URLAndAddress {
+	urls := make(map[string]URLAndAddress)
+	for _, nodeURLs := range p.kubeletNodes {
+		for _, u := range nodeURLs {
+			urls[u.URL.String()] = u
+		}
+	}
+	return urls
+}
+
+// setKubeletAuth authorizes the request to the kubelet with the bearer token.
+// The token is read on every request as service account tokens are rotated
+// by the kubelet.
+func (p *Prometheus) setKubeletAuth(req *http.Request) error {
+	if p.KubeletBearerToken == "" {
+		return nil
+	}
+	token, err := ioutil.ReadFile(p.KubeletBearerToken)
+	if err != nil {
+		return fmt.Errorf("reading kubelet_bearer_token failed: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return nil
+}
@@ -0,0 +1,109 @@
+package prometheus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func kubeletNode(name, address string, port int32) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: name},
+				{Type: corev1.NodeInternalIP, Address: address},
+			},
+			DaemonEndpoints: corev1.NodeDaemonEndpoints{
+				KubeletEndpoint: corev1.DaemonEndpoint{Port: port},
+			},
+		},
+	}
+}
+
+func TestKubeletNodeURLs(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, KubeletScrape: true}
+	require.NoError(t, p.Init())
+
+	p.handleNodeEvent(watch.Added, kubeletNode("node-1", "10.0.0.1", 0))
+	p.handleNodeEvent(watch.Added, kubeletNode("node-2", "10.0.0.2", 10255))
+	p.handleNodeEvent(watch.Added, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}})
+
+	urls, err := p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 4)
+	for _, key := range []string{
+		"https://10.0.0.1:10250/metrics/cadvisor",
+		"https://10.0.0.1:10250/metrics/resource",
+		"https://10.0.0.2:10255/metrics/cadvisor",
+		"https://10.0.0.2:10255/metrics/resource",
+	} {
+		require.Contains(t, urls, key)
+		require.True(t, urls[key].Kubelet)
+	}
+	require.Equal(t, map[string]string{"node_name": "node-2"}, urls["https://10.0.0.2:10255/metrics/cadvisor"].Tags)
+
+	p.handleNodeEvent(watch.Deleted, kubeletNode("node-1", "10.0.0.1", 0))
+	urls, err = p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+}
+
+func TestKubeletScrape(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, err := fmt.Fprint(w, "# TYPE container_cpu_usage_seconds_total counter\n"+
+			`container_cpu_usage_seconds_total{container="app",pod="app-1"} 12.5`+"\n")
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	host, portStr, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "prometheus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	token := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(token, []byte("s3cret\n"), 0600))
+
+	p := &Prometheus{
+		Log:                testutil.Logger{},
+		URLTag:             "url",
+		MetricVersion:      2,
+		KubeletScrape:      true,
+		KubeletScrapePaths: []string{"/metrics/cadvisor"},
+		KubeletBearerToken: token,
+		// The plugin authorization is not sent to the kubelets
+		BearerTokenString: "other",
+	}
+	require.NoError(t, p.Init())
+	p.handleNodeEvent(watch.Added, kubeletNode("node-1", host, int32(port)))
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Len(t, acc.Metrics, 1)
+	m := acc.Metrics[0]
+	require.Equal(t, "node-1", m.Tags["node_name"])
+	require.Equal(t, "app-1", m.Tags["pod"])
+	require.Equal(t, 12.5, m.Fields["container_cpu_usage_seconds_total"])
+}
@@ -5,24 +5,22 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"net/url"
-	"os/user"
-	"path/filepath"
-	"sync"
+	"strings"
 	"time"
 
-	"github.com/ghodss/yaml"
+	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 type podMetadata struct {
@@ -37,124 +35,173 @@ type podResponse struct {
 	Items      []*corev1.Pod `json:"items,string,omitempty"`
 }
 
-const cAdvisorPodListDefaultInterval = 60
+const (
+	cAdvisorPodListDefaultInterval = 60
+	kubeletPort                    = "10250"
+	defaultPodAnnotationPrefix     = "prometheus.io"
+)
 
-// loadClient parses a kubeconfig from a file and returns a Kubernetes
-// client. It does not support extensions or client auth providers.
-func loadClient(kubeconfigPath string) (*kubernetes.Clientset, error) {
-	data, err := ioutil.ReadFile(kubeconfigPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed reading '%s': %v", kubeconfigPath, err)
+// podAnnotationSettings are the scrape settings read from pod annotations.
+var podAnnotationSettings = []string{"scrape", "scheme", "path", "port"}
+
+func (p *Prometheus) start(ctx context.Context) error {
+	// Listing the pods from the kubelet neither needs the kubernetes config
+	// nor the api server
+	if p.pollsKubelet() {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+					if err := p.cAdvisor(ctx); err != nil {
+						p.Log.Errorf("Unable to monitor pods with node scrape scope: %s", err.Error())
+					}
+				}
+			}
+		}()
+		return nil
 	}
 
-	// Unmarshal YAML into a Kubernetes config object.
-	var config rest.Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	config, err := k8s.LoadConfig(p.KubeConfig)
+	if err != nil {
+		return err
+	}
+	if p.proxy != nil {
+		config.Proxy = p.proxy
 	}
-	return kubernetes.NewForConfig(&config)
+	return p.watchPods(config)
 }
 
-func (p *Prometheus) start(ctx context.Context) error {
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return fmt.Errorf("failed to get InClusterConfig - %v", err)
+// An edge case exists if a pod goes offline at the same time a new pod is created
+// (without the scrape annotations). K8s may re-assign the old pod ip to the non-scrape
+// pod, causing errors in the logs. This is only true if the pod going offline is not
+// directed to do so by K8s.
+func (p *Prometheus) watchPods(config *rest.Config) error {
+	namespaces := p.podNamespaces()
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
 	}
-	client, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		u, err := user.Current()
+	for _, namespace := range namespaces {
+		// Restrict the watch to the selected pods on the server side, the
+		// informers are shared with other plugins using the same selection.
+		informers, err := k8s.AcquireInformers(config, k8s.InformerOptions{
+			Namespace:     namespace,
+			LabelSelector: p.podLabelSelector.String(),
+			FieldSelector: p.watchFieldSelector(),
+		})
 		if err != nil {
-			return fmt.Errorf("failed to get current user - %v", err)
+			p.releaseInformers()
+			return fmt.Errorf("watching pods in namespace %q failed: %w", namespace, err)
 		}
+		p.informers = append(p.informers, informers)
 
-		configLocation := filepath.Join(u.HomeDir, ".kube/config")
-		if p.KubeConfig != "" {
-			configLocation = p.KubeConfig
-		}
-		client, err = loadClient(configLocation)
-		if err != nil {
-			return err
-		}
+		informers.AddEventHandler(informers.Core().V1().Pods().Informer(), cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if pod, ok := obj.(*corev1.Pod); ok {
+					p.handlePodEvent(watch.Added, pod)
+				}
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				if pod, ok := obj.(*corev1.Pod); ok {
+					p.handlePodEvent(watch.Modified, pod)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if pod, ok := obj.(*corev1.Pod); ok {
+					p.handlePodEvent(watch.Deleted, pod)
+				}
+			},
+		})
+		informers.Start()
 	}
+	return nil
+}
 
-	p.wg = sync.WaitGroup{}
+// pollsKubelet returns true if the pods of the node scrape scope are listed
+// from the kubelet, either configured by kubelet_url or because the name of
+// the node is unknown.
+func (p *Prometheus) pollsKubelet() bool {
+	return p.kubeletURL != ""
+}
 
-	p.wg.Add(1)
-	go func() {
-		defer p.wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(time.Second):
-				if p.isNodeScrapeScope {
-					err = p.cAdvisor(ctx)
-					if err != nil {
-						p.Log.Errorf("Unable to monitor pods with node scrape scope: %s", err.Error())
-					}
-				} else {
-					err = p.watchPod(ctx, client)
-					if err != nil {
-						p.Log.Errorf("Unable to watch resources: %s", err.Error())
-					}
-				}
-			}
-		}
-	}()
+// newKubeletClient returns the client listing the pods from the kubelet.  The
+// serving certificate of the kubelet is usually self-signed, so it is not
+// verified.  A nil proxy falls back to the environment.
+func newKubeletClient(proxy func(*http.Request) (*url.URL, error)) *http.Client {
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
 
-	return nil
+// watchFieldSelector returns the field selector of the pod watch, restricted
+// to the pods of the node with the node scrape scope.
+func (p *Prometheus) watchFieldSelector() string {
+	selector := p.podFieldSelector.String()
+	if !p.isNodeScrapeScope {
+		return selector
+	}
+	node := fields.OneTermEqualSelector("spec.nodeName", p.NodeName).String()
+	if selector == "" {
+		return node
+	}
+	return selector + "," + node
 }
 
-// An edge case exists if a pod goes offline at the same time a new pod is created
-// (without the scrape annotations). K8s may re-assign the old pod ip to the non-scrape
-// pod, causing errors in the logs. This is only true if the pod going offline is not
-// directed to do so by K8s.
-func (p *Prometheus) watchPod(ctx context.Context, client *kubernetes.Clientset) error {
-	watcher, err := client.CoreV1().Pods(p.PodNamespace).Watch(ctx, metav1.ListOptions{
-		LabelSelector: p.KubernetesLabelSelector,
-		FieldSelector: p.KubernetesFieldSelector,
-	})
-	if err != nil {
-		return err
+// releaseInformers drops the references to the shared informers.
+func (p *Prometheus) releaseInformers() {
+	for _, informers := range p.informers {
+		informers.Release()
 	}
-	pod := &corev1.Pod{}
-	go func() {
-		for event := range watcher.ResultChan() {
-			pod = &corev1.Pod{}
-			// If the pod is not "ready", there will be no ip associated with it.
-			if pod.Annotations["prometheus.io/scrape"] != "true" ||
-				!podReady(pod.Status.ContainerStatuses) {
-				continue
-			}
+	p.informers = nil
+}
 
-			switch event.Type {
-			case watch.Added:
-				registerPod(pod, p)
-			case watch.Modified:
-				// To avoid multiple actions for each event, unregister on the first event
-				// in the delete sequence, when the containers are still "ready".
-				if pod.GetDeletionTimestamp() != nil {
-					unregisterPod(pod, p)
-				} else {
-					registerPod(pod, p)
-				}
-			}
+func (p *Prometheus) handlePodEvent(eventType watch.EventType, pod *corev1.Pod) {
+	if pod.Annotations[p.podAnnotation("scrape")] != "true" {
+		return
+	}
+	if eventType == watch.Deleted {
+		unregisterPod(pod, p)
+		return
+	}
+	// If the pod is not "ready", there will be no ip associated with it.
+	if !p.podScrapable(pod) {
+		// With readiness gating, stop scraping pods once they are
+		// no longer ready or are terminating.
+		if eventType == watch.Modified && (p.PodRequireReady || p.PodSkipTerminating) {
+			unregisterPod(pod, p)
 		}
-	}()
+		return
+	}
 
-	return nil
+	switch eventType {
+	case watch.Added:
+		registerPod(pod, p)
+	case watch.Modified:
+		// To avoid multiple actions for each event, unregister on the first event
+		// in the delete sequence, when the containers are still "ready".
+		if pod.GetDeletionTimestamp() != nil {
+			unregisterPod(pod, p)
+		} else {
+			registerPod(pod, p)
+		}
+	}
 }
 
 func (p *Prometheus) cAdvisor(ctx context.Context) error {
-	// The request will be the same each time
-	podsURL := fmt.Sprintf("https://%s:10250/pods", p.NodeIP)
-	req, err := http.NewRequest("GET", podsURL, nil)
-	if err != nil {
-		return fmt.Errorf("error when creating request to %s to get pod list: %w", podsURL, err)
-	}
-
 	// Update right away so code is not waiting the length of the specified scrape interval initially
-	err = updateCadvisorPodList(p, req)
+	err := updateCadvisorPodList(ctx, p)
 	if err != nil {
 		return fmt.Errorf("error initially updating pod list: %w", err)
 	}
@@ -169,7 +216,7 @@ func (p *Prometheus) cAdvisor(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case <-time.After(time.Duration(scrapeInterval) * time.Second):
-			err := updateCadvisorPodList(p, req)
+			err := updateCadvisorPodList(ctx, p)
 			if err != nil {
 				return fmt.Errorf("error updating pod list: %w", err)
 			}
@@ -177,22 +224,28 @@ func (p *Prometheus) cAdvisor(ctx context.Context) error {
 	}
 }
 
-func updateCadvisorPodList(p *Prometheus, req *http.Request) error {
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	httpClient := http.Client{}
+func updateCadvisorPodList(ctx context.Context, p *Prometheus) error {
+	podsURL := p.kubeletURL + "/pods"
+	req, err := http.NewRequestWithContext(ctx, "GET", podsURL, nil)
+	if err != nil {
+		return fmt.Errorf("error when creating request to %s to get pod list: %w", podsURL, err)
+	}
+
+	if err := p.setKubeletAuth(req); err != nil {
+		return err
+	}
 
-	resp, err := httpClient.Do(req)
+	resp, err := p.kubeletClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("error when making request for pod list: %w", err)
 	}
+	defer resp.Body.Close()
 
 	// If err is nil, still check response code
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("error when making request for pod list with status %s", resp.Status)
 	}
 
-	defer resp.Body.Close()
-
 	cadvisorPodsResponse := podResponse{}
 
 	// Will have expected type errors for some parts of corev1.Pod struct for some unused fields
@@ -210,8 +263,8 @@ func updateCadvisorPodList(p *Prometheus, req *http.Request) error {
 	// and if namespace and selectors are specified and match
 	for _, pod := range pods {
 		if necessaryPodFieldsArePresent(pod) &&
-			pod.Annotations["prometheus.io/scrape"] == "true" &&
-			podReady(pod.Status.ContainerStatuses) &&
+			pod.Annotations[p.podAnnotation("scrape")] == "true" &&
+			p.podScrapable(pod) &&
 			podHasMatchingNamespace(pod, p) &&
 			podHasMatchingLabelSelector(pod, p.podLabelSelector) &&
 			podHasMatchingFieldSelector(pod, p.podFieldSelector) {
@@ -265,11 +318,43 @@ func podHasMatchingFieldSelector(pod *corev1.Pod, fieldSelector fields.Selector)
 }
 
 /*
- * If a namespace is specified and the pod doesn't have that namespace, return false
+ * If namespaces are specified and the pod doesn't have one of them, return false
  * Else return true
  */
 func podHasMatchingNamespace(pod *corev1.Pod, p *Prometheus) bool {
-	return !(p.PodNamespace != "" && pod.Namespace != p.PodNamespace)
+	namespaces := p.podNamespaces()
+	return len(namespaces) == 0 || choice.Contains(pod.Namespace, namespaces)
+}
+
+// podNamespaces returns the namespaces to monitor pods in, none selects all
+// namespaces.
+func (p *Prometheus) podNamespaces() []string {
+	if p.PodNamespace == "" || choice.Contains(p.PodNamespace, p.PodNamespaces) {
+		return p.PodNamespaces
+	}
+	return append([]string{p.PodNamespace}, p.PodNamespaces...)
+}
+
+// podScrapable returns true if the pod is ready to be scraped according to
+// the readiness settings.
+func (p *Prometheus) podScrapable(pod *corev1.Pod) bool {
+	if p.PodSkipTerminating && pod.GetDeletionTimestamp() != nil {
+		return false
+	}
+	if p.PodRequireReady {
+		return podReadyCondition(pod.Status.Conditions)
+	}
+	return podReady(pod.Status.ContainerStatuses)
+}
+
+// podReadyCondition returns true if the Ready condition of the pod is true.
+func podReadyCondition(conditions []corev1.PodCondition) bool {
+	for _, c := range conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
 }
 
 func podReady(statuss []corev1.ContainerStatus) bool {
@@ -288,16 +373,17 @@ func registerPod(pod *corev1.Pod, p *Prometheus) {
 	if p.kubernetesPods == nil {
 		p.kubernetesPods = map[string]URLAndAddress{}
 	}
-	targetURL := getScrapeURL(pod)
+	targetURL := getScrapeURL(pod, p)
 	if targetURL == nil {
 		return
 	}
 
 	log.Printf("D! [inputs.prometheus] will scrape metrics from %q", *targetURL)
-	// add annotation as metrics tags
-	tags := pod.Annotations
-	if tags == nil {
-		tags = map[string]string{}
+	// add annotation as metrics tags, copying them as the pod is shared with
+	// other plugins through the informer cache
+	tags := make(map[string]string, len(pod.Annotations)+len(pod.Labels)+2)
+	for k, v := range pod.Annotations {
+		tags[k] = v
 	}
 	tags["pod_name"] = pod.Name
 	tags["namespace"] = pod.Namespace
@@ -314,19 +400,33 @@ func registerPod(pod *corev1.Pod, p *Prometheus) {
 
 	// Locks earlier if using cAdvisor calls - makes a new list each time
 	// rather than updating and removing from the same list
-	if !p.isNodeScrapeScope {
+	if !p.pollsKubelet() {
 		p.lock.Lock()
 		defer p.lock.Unlock()
 	}
 	p.kubernetesPods[podURL.String()] = URLAndAddress{
-		URL:         podURL,
+		URL:         addParams(podURL, p.podParams(pod)),
 		Address:     URL.Hostname(),
 		OriginalURL: URL,
 		Tags:        tags,
 	}
 }
 
-func getScrapeURL(pod *corev1.Pod) *string {
+// podAnnotation returns the name of the pod annotation holding the scrape
+// setting, either set in pod_annotation_keys or below the
+// pod_annotation_prefix.
+func (p *Prometheus) podAnnotation(setting string) string {
+	if key, ok := p.PodAnnotationKeys[setting]; ok {
+		return key
+	}
+	prefix := p.PodAnnotationPrefix
+	if prefix == "" {
+		prefix = defaultPodAnnotationPrefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + setting
+}
+
+func getScrapeURL(pod *corev1.Pod, p *Prometheus) *string {
 	ip := pod.Status.PodIP
 	if ip == "" {
 		// return as if scrape was disabled, we will be notified again once the pod
@@ -334,9 +434,9 @@ func getScrapeURL(pod *corev1.Pod) *string {
 		return nil
 	}
 
-	scheme := pod.Annotations["prometheus.io/scheme"]
-	path := pod.Annotations["prometheus.io/path"]
-	port := pod.Annotations["prometheus.io/port"]
+	scheme := pod.Annotations[p.podAnnotation("scheme")]
+	path := pod.Annotations[p.podAnnotation("path")]
+	port := pod.Annotations[p.podAnnotation("port")]
 
 	if scheme == "" {
 		scheme = "http"
@@ -360,7 +460,7 @@ func getScrapeURL(pod *corev1.Pod) *string {
 }
 
 func unregisterPod(pod *corev1.Pod, p *Prometheus) {
-	url := getScrapeURL(pod)
+	url := getScrapeURL(pod, p)
 	if url == nil {
 		return
 	}
@@ -1,12 +1,20 @@
 package prometheus
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,7 +23,7 @@ import (
 func TestScrapeURLNoAnnotations(t *testing.T) {
 	p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{}}
 	p.Annotations = map[string]string{}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Nil(t, url)
 }
 
@@ -23,38 +31,68 @@ func TestScrapeURLAnnotationsNoScrape(t *testing.T) {
 	p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{}}
 	p.Name = "myPod"
 	p.Annotations = map[string]string{"prometheus.io/scrape": "false"}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Nil(t, url)
 }
 
 func TestScrapeURLAnnotations(t *testing.T) {
 	p := pod()
 	p.Annotations = map[string]string{"prometheus.io/scrape": "true"}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Equal(t, "http://127.0.0.1:9102/metrics", *url)
 }
 
 func TestScrapeURLAnnotationsCustomPort(t *testing.T) {
 	p := pod()
 	p.Annotations = map[string]string{"prometheus.io/scrape": "true", "prometheus.io/port": "9000"}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Equal(t, "http://127.0.0.1:9000/metrics", *url)
 }
 
 func TestScrapeURLAnnotationsCustomPath(t *testing.T) {
 	p := pod()
 	p.Annotations = map[string]string{"prometheus.io/scrape": "true", "prometheus.io/path": "mymetrics"}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Equal(t, "http://127.0.0.1:9102/mymetrics", *url)
 }
 
 func TestScrapeURLAnnotationsCustomPathWithSep(t *testing.T) {
 	p := pod()
 	p.Annotations = map[string]string{"prometheus.io/scrape": "true", "prometheus.io/path": "/mymetrics"}
-	url := getScrapeURL(p)
+	url := getScrapeURL(p, &Prometheus{})
 	assert.Equal(t, "http://127.0.0.1:9102/mymetrics", *url)
 }
 
+func TestScrapeURLAnnotationPrefix(t *testing.T) {
+	prom := &Prometheus{
+		Log:                 testutil.Logger{},
+		PodAnnotationPrefix: "telegraf.io",
+		PodAnnotationKeys:   map[string]string{"port": "prometheus.io/port"},
+	}
+	require.NoError(t, prom.Init())
+
+	p := pod()
+	p.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/path":   "/prometheus",
+		"prometheus.io/port":   "9000",
+		"telegraf.io/path":     "/telegraf",
+	}
+	p.Status.ContainerStatuses = []corev1.ContainerStatus{{Ready: true}}
+	url := getScrapeURL(p, prom)
+	assert.Equal(t, "http://127.0.0.1:9000/telegraf", *url)
+
+	// Only pods annotated for telegraf are scraped
+	prom.handlePodEvent(watch.Added, p)
+	assert.Empty(t, prom.kubernetesPods)
+	p.Annotations["telegraf.io/scrape"] = "true"
+	prom.handlePodEvent(watch.Added, p)
+	assert.Equal(t, 1, len(prom.kubernetesPods))
+
+	prom.PodAnnotationKeys = map[string]string{"address": "telegraf.io/address"}
+	require.EqualError(t, prom.Init(), `unknown setting "address" in pod_annotation_keys`)
+}
+
 func TestAddPod(t *testing.T) {
 	prom := &Prometheus{Log: testutil.Logger{}}
 
@@ -112,6 +150,30 @@ func TestPodHasMatchingNamespace(t *testing.T) {
 	assert.Equal(t, false, shouldNotMatch)
 }
 
+func TestPodHasMatchingNamespaces(t *testing.T) {
+	prom := &Prometheus{
+		Log:           testutil.Logger{},
+		PodNamespace:  "default",
+		PodNamespaces: []string{"monitoring", "default"},
+	}
+	require.Equal(t, []string{"monitoring", "default"}, prom.podNamespaces())
+
+	pod := pod()
+	pod.Namespace = "monitoring"
+	require.True(t, podHasMatchingNamespace(pod, prom))
+	pod.Namespace = "default"
+	require.True(t, podHasMatchingNamespace(pod, prom))
+	pod.Namespace = "kube-system"
+	require.False(t, podHasMatchingNamespace(pod, prom))
+
+	prom = &Prometheus{Log: testutil.Logger{}, PodNamespace: "default", PodNamespaces: []string{"monitoring"}}
+	require.Equal(t, []string{"default", "monitoring"}, prom.podNamespaces())
+
+	prom = &Prometheus{Log: testutil.Logger{}}
+	require.Empty(t, prom.podNamespaces())
+	require.True(t, podHasMatchingNamespace(pod, prom))
+}
+
 func TestPodHasMatchingLabelSelector(t *testing.T) {
 	labelSelectorString := "label0==label0,label1=label1,label2!=label,label3 in (label1,label2, label3),label4 notin (label1, label2,label3),label5,!label6"
 	prom := &Prometheus{Log: testutil.Logger{}, KubernetesLabelSelector: labelSelectorString}
@@ -153,6 +215,72 @@ func TestInvalidFieldSelector(t *testing.T) {
 	assert.NotEqual(t, err, nil)
 }
 
+func TestPodScrapable(t *testing.T) {
+	pod := pod()
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Ready: true}}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+
+	prom := &Prometheus{Log: testutil.Logger{}}
+	assert.True(t, prom.podScrapable(pod))
+
+	prom.PodRequireReady = true
+	assert.False(t, prom.podScrapable(pod))
+
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	assert.True(t, prom.podScrapable(pod))
+
+	prom.PodSkipTerminating = true
+	pod.DeletionTimestamp = &metav1.Time{}
+	assert.False(t, prom.podScrapable(pod))
+}
+
+func TestKubeletPodList(t *testing.T) {
+	scraped := pod()
+	scraped.Annotations = map[string]string{"prometheus.io/scrape": "true"}
+	scraped.Labels = map[string]string{"app": "nginx"}
+	scraped.Status.ContainerStatuses = []corev1.ContainerStatus{{Ready: true}}
+	ignored := pod()
+	ignored.Name = "otherPod"
+	ignored.Status.PodIP = "127.0.0.2"
+	ignored.Annotations = map[string]string{}
+	ignored.Labels = map[string]string{}
+	ignored.Status.ContainerStatuses = []corev1.ContainerStatus{{Ready: true}}
+
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pods" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewEncoder(w).Encode(podResponse{
+			Kind:  "PodList",
+			Items: []*corev1.Pod{scraped, ignored},
+		}))
+	}))
+	defer ts.Close()
+
+	token := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(token, []byte("abc_123\n"), 0600))
+
+	p := &Prometheus{
+		Log:                testutil.Logger{},
+		MonitorPods:        true,
+		KubeletURL:         ts.URL + "/",
+		KubeletBearerToken: token,
+	}
+	require.NoError(t, p.Init())
+	require.True(t, p.pollsKubelet())
+
+	require.NoError(t, updateCadvisorPodList(context.Background(), p))
+	require.Equal(t, "Bearer abc_123", authorization)
+	require.Len(t, p.kubernetesPods, 1)
+	require.Contains(t, p.kubernetesPods, "http://127.0.0.1:9102/metrics")
+
+	p.KubeletURL = "127.0.0.1:10255"
+	require.EqualError(t, p.Init(), `kubelet_url "127.0.0.1:10255" must be an http or https url`)
+}
+
 func pod() *corev1.Pod {
 	p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{}, Status: corev1.PodStatus{}, Spec: corev1.PodSpec{}}
 	p.Status.PodIP = "127.0.0.1"
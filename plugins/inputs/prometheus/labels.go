@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+)
+
+// mapsLabels reports whether labels are renamed, dropped or converted.
+func (p *Prometheus) mapsLabels() bool {
+	return len(p.LabelRename) > 0 || len(p.LabelDrop) > 0 || len(p.LabelFields) > 0
+}
+
+func (p *Prometheus) initLabelMapping() error {
+	dropped := make(map[string]bool, len(p.LabelDrop))
+	for _, label := range p.LabelDrop {
+		dropped[label] = true
+	}
+	for label := range p.LabelRename {
+		if dropped[label] {
+			return fmt.Errorf("label %q is both dropped and renamed", label)
+		}
+	}
+	for _, label := range p.LabelFields {
+		if dropped[label] {
+			return fmt.Errorf("label %q is both dropped and converted to a field", label)
+		}
+	}
+	return nil
+}
+
+// mapLabels drops the label_drop labels, converts the label_fields labels
+// to float fields and renames the label_rename labels.  Values which are not
+// numbers are kept as tags.
+func (p *Prometheus) mapLabels(metrics []telegraf.Metric) {
+	for _, m := range metrics {
+		for _, label := range p.LabelDrop {
+			m.RemoveTag(label)
+		}
+
+		for _, label := range p.LabelFields {
+			value, ok := m.GetTag(label)
+			if !ok {
+				continue
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				p.Log.Debugf("Keeping label %q of %q as tag: %v", label, m.Name(), err)
+				continue
+			}
+			m.RemoveTag(label)
+			m.AddField(p.labelName(label), number)
+		}
+
+		for label, name := range p.LabelRename {
+			if value, ok := m.GetTag(label); ok {
+				m.RemoveTag(label)
+				m.AddTag(name, value)
+			}
+		}
+	}
+}
+
+// labelName returns the name of the tag or field of the label.
+func (p *Prometheus) labelName(label string) string {
+	if name, ok := p.LabelRename[label]; ok {
+		return name
+	}
+	return label
+}
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelMapping(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, `# TYPE http_duration_seconds histogram
+http_duration_seconds_bucket{code="200",le="0.1"} 2
+http_duration_seconds_bucket{code="200",le="+Inf"} 6
+http_duration_seconds_sum{code="200"} 4.2
+http_duration_seconds_count{code="200"} 6
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.2
+rpc_duration_seconds_sum 3
+rpc_duration_seconds_count 10
+`)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:           testutil.Logger{},
+		URLs:          []string{ts.URL},
+		MetricVersion: 2,
+		LabelRename:   map[string]string{"quantile": "percentile"},
+		LabelDrop:     []string{"code"},
+		LabelFields:   []string{"le", "quantile"},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus", map[string]string{},
+			map[string]interface{}{"http_duration_seconds_bucket": 2.0, "le": 0.1}, time.Unix(0, 0), telegraf.Histogram),
+		testutil.MustMetric("prometheus", map[string]string{},
+			map[string]interface{}{"http_duration_seconds_bucket": 6.0, "le": math.Inf(1)}, time.Unix(0, 0), telegraf.Histogram),
+		testutil.MustMetric("prometheus", map[string]string{},
+			map[string]interface{}{"http_duration_seconds_sum": 4.2, "http_duration_seconds_count": 6.0},
+			time.Unix(0, 0), telegraf.Histogram),
+		testutil.MustMetric("prometheus", map[string]string{},
+			map[string]interface{}{"rpc_duration_seconds": 0.2, "percentile": 0.5}, time.Unix(0, 0), telegraf.Summary),
+		testutil.MustMetric("prometheus", map[string]string{},
+			map[string]interface{}{"rpc_duration_seconds_sum": 3.0, "rpc_duration_seconds_count": 10.0},
+			time.Unix(0, 0), telegraf.Summary),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestLabelMappingInvalid(t *testing.T) {
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		LabelDrop:   []string{"le"},
+		LabelFields: []string{"le"},
+	}
+	require.EqualError(t, p.Init(), `label "le" is both dropped and converted to a field`)
+}
@@ -0,0 +1,154 @@
+package prometheus
+
+import (
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// filterFamilies removes the families not passing the family_pass and
+// family_drop filters, before any metric of them is created.
+func (p *Prometheus) filterFamilies(families map[string]*dto.MetricFamily) {
+	for name, mf := range families {
+		if !p.familyFilter.Match(mf.GetName()) {
+			delete(families, name)
+		}
+	}
+}
+
+// droppedFamilies counts the families of a target dropped for exceeding
+// max_family_series.
+type droppedFamilies struct {
+	target   URLAndAddress
+	families int64
+	series   int64
+	warned   map[string]bool
+}
+
+// limitFamilySeries drops all metrics of the families with more label
+// combinations than max_family_series in a single scrape.
+func (p *Prometheus) limitFamilySeries(key string, u URLAndAddress, metrics []telegraf.Metric) []telegraf.Metric {
+	series := make(map[string]map[string]bool)
+	for _, m := range metrics {
+		family := p.metricFamily(m)
+		if series[family] == nil {
+			series[family] = make(map[string]bool)
+		}
+		series[family][seriesKey(m)] = true
+	}
+
+	exceeded := make(map[string]int)
+	for family, s := range series {
+		if len(s) > p.MaxFamilySeries {
+			exceeded[family] = len(s)
+		}
+	}
+	if len(exceeded) == 0 {
+		return metrics
+	}
+
+	filtered := metrics[:0]
+	for _, m := range metrics {
+		if _, ok := exceeded[p.metricFamily(m)]; !ok {
+			filtered = append(filtered, m)
+		}
+	}
+
+	p.recordDropped(key, u, exceeded)
+	return filtered
+}
+
+// metricFamily returns the name of the Prometheus metric family of the
+// metric.  With metric_version 2 the family is derived from the field name.
+func (p *Prometheus) metricFamily(m telegraf.Metric) string {
+	if p.MetricVersion != 2 || len(m.FieldList()) == 0 {
+		return m.Name()
+	}
+
+	// Exemplars are stored in fields extending the name of the value field
+	name := m.FieldList()[0].Key
+	for _, field := range m.FieldList()[1:] {
+		if len(field.Key) < len(name) {
+			name = field.Key
+		}
+	}
+	if m.Type() == telegraf.Histogram || m.Type() == telegraf.Summary {
+		for _, suffix := range []string{"_bucket", "_count", "_sum"} {
+			if strings.HasSuffix(name, suffix) {
+				return strings.TrimSuffix(name, suffix)
+			}
+		}
+	}
+	return name
+}
+
+// seriesKey identifies the label combination of the metric; buckets and
+// quantiles belong to the series of their histogram or summary.
+func seriesKey(m telegraf.Metric) string {
+	var b strings.Builder
+	for _, tag := range m.TagList() {
+		if tag.Key == "le" || tag.Key == "quantile" {
+			continue
+		}
+		b.WriteString(tag.Key)
+		b.WriteByte('=')
+		b.WriteString(tag.Value)
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func (p *Prometheus) recordDropped(key string, u URLAndAddress, exceeded map[string]int) {
+	p.droppedLock.Lock()
+	defer p.droppedLock.Unlock()
+
+	if p.dropped == nil {
+		p.dropped = make(map[string]*droppedFamilies)
+	}
+	dropped, ok := p.dropped[key]
+	if !ok {
+		dropped = &droppedFamilies{warned: make(map[string]bool)}
+		p.dropped[key] = dropped
+	}
+	dropped.target = u
+
+	for family, count := range exceeded {
+		dropped.families++
+		dropped.series += int64(count)
+		if !dropped.warned[family] {
+			dropped.warned[family] = true
+			p.Log.Warnf("Dropping family %q of %s with %d series exceeding max_family_series",
+				family, u.URL.Redacted(), count)
+		}
+	}
+}
+
+// gatherDropped adds the number of dropped families and series of all
+// current targets.  Targets which are no longer scraped are forgotten.
+func (p *Prometheus) gatherDropped(acc telegraf.Accumulator, targets map[string]URLAndAddress) {
+	p.droppedLock.Lock()
+	defer p.droppedLock.Unlock()
+
+	now := time.Now()
+	for key, dropped := range p.dropped {
+		if _, ok := targets[key]; !ok {
+			delete(p.dropped, key)
+			continue
+		}
+
+		u := *dropped.target.URL
+		u.User = nil
+		tags := map[string]string{"url": u.String()}
+		if dropped.target.Address != "" {
+			tags["address"] = dropped.target.Address
+		}
+
+		fields := map[string]interface{}{
+			"dropped_families": dropped.families,
+			"dropped_series":   dropped.series,
+		}
+		acc.AddCounter("prometheus_series_limit", fields, tags, now)
+	}
+}
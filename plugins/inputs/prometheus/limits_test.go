@@ -0,0 +1,140 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleCardinalityTextFormat = `# TYPE requests_total counter
+requests_total{path="/a"} 1
+requests_total{path="/b"} 2
+requests_total{path="/c"} 3
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{path="/a",le="0.1"} 1
+request_duration_seconds_bucket{path="/a",le="+Inf"} 1
+request_duration_seconds_sum{path="/a"} 0.05
+request_duration_seconds_count{path="/a"} 1
+request_duration_seconds_bucket{path="/b",le="0.1"} 2
+request_duration_seconds_bucket{path="/b",le="+Inf"} 2
+request_duration_seconds_sum{path="/b"} 0.1
+request_duration_seconds_count{path="/b"} 2
+`
+
+func TestMaxFamilySeries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleCardinalityTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	for _, version := range []int{1, 2} {
+		t.Run(fmt.Sprintf("metric_version %d", version), func(t *testing.T) {
+			p := &Prometheus{
+				Log:             testutil.Logger{},
+				URLs:            []string{ts.URL},
+				URLTag:          "url",
+				MetricVersion:   version,
+				MaxFamilySeries: 2,
+			}
+			require.NoError(t, p.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, acc.GatherError(p.Gather))
+			require.NoError(t, acc.GatherError(p.Gather))
+
+			families := make(map[string]bool)
+			for _, m := range acc.GetTelegrafMetrics() {
+				if m.Name() == "prometheus_series_limit" {
+					continue
+				}
+				families[p.metricFamily(m)] = true
+			}
+			require.Equal(t, map[string]bool{"request_duration_seconds": true}, families)
+
+			expected := []telegraf.Metric{
+				testutil.MustMetric(
+					"prometheus_series_limit",
+					map[string]string{"url": ts.URL + "/metrics"},
+					map[string]interface{}{
+						"dropped_families": int64(2),
+						"dropped_series":   int64(6),
+					},
+					time.Unix(0, 0),
+					telegraf.Counter,
+				),
+			}
+			var actual []telegraf.Metric
+			for _, m := range acc.GetTelegrafMetrics() {
+				if m.Name() == "prometheus_series_limit" {
+					actual = append(actual, m)
+				}
+			}
+			require.Len(t, actual, 2)
+			testutil.RequireMetricsEqual(t, expected, actual[1:], testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestFamilyFilter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleCardinalityTextFormat+sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		pass     []string
+		drop     []string
+		expected []string
+	}{
+		{
+			name:     "drop",
+			drop:     []string{"request*"},
+			expected: []string{"go_goroutines"},
+		},
+		{
+			name:     "pass",
+			pass:     []string{"request*"},
+			expected: []string{"request_duration_seconds", "requests_total"},
+		},
+		{
+			name:     "pass and drop",
+			pass:     []string{"request*"},
+			drop:     []string{"requests_total"},
+			expected: []string{"request_duration_seconds"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Prometheus{
+				Log:           testutil.Logger{},
+				URLs:          []string{ts.URL},
+				MetricVersion: 2,
+				FamilyPass:    tt.pass,
+				FamilyDrop:    tt.drop,
+			}
+			require.NoError(t, p.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, acc.GatherError(p.Gather))
+
+			families := make(map[string]bool)
+			for _, m := range acc.GetTelegrafMetrics() {
+				families[p.metricFamily(m)] = true
+			}
+			var actual []string
+			for family := range families {
+				actual = append(actual, family)
+			}
+			require.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
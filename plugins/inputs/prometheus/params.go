@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// addParams returns a copy of the url with the query parameters added.
+func addParams(u *url.URL, params map[string][]string) *url.URL {
+	if len(params) == 0 {
+		return u
+	}
+
+	withParams := *u
+	query := withParams.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	withParams.RawQuery = query.Encode()
+	return &withParams
+}
+
+// initPodParams parses the templates of the query parameters of pods.
+func (p *Prometheus) initPodParams() error {
+	p.podParamTemplates = make(map[string][]*template.Template, len(p.PodParams))
+	for key, values := range p.PodParams {
+		for _, value := range values {
+			tmpl, err := template.New(key).Option("missingkey=zero").Parse(value)
+			if err != nil {
+				return fmt.Errorf("parsing template of pod parameter %q failed: %w", key, err)
+			}
+			p.podParamTemplates[key] = append(p.podParamTemplates[key], tmpl)
+		}
+	}
+	return nil
+}
+
+// podParams renders the query parameters for the pod.  Empty values are
+// skipped, so parameters can depend on optional annotations.
+func (p *Prometheus) podParams(pod *corev1.Pod) map[string][]string {
+	params := make(map[string][]string, len(p.podParamTemplates))
+	for key, templates := range p.podParamTemplates {
+		for _, tmpl := range templates {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, pod); err != nil {
+				p.Log.Errorf("Rendering pod parameter %q for %q failed: %s", key, pod.Name, err.Error())
+				continue
+			}
+			if b.Len() > 0 {
+				params[key] = append(params[key], b.String())
+			}
+		}
+	}
+	return params
+}
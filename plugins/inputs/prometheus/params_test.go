@@ -0,0 +1,62 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodParams(t *testing.T) {
+	prom := &Prometheus{
+		Log: testutil.Logger{},
+		PodParams: map[string][]string{
+			"module":    {`{{ index .Annotations "prometheus.io/module" }}`},
+			"collect[]": {"{{ .Namespace }}", `{{ index .Labels "missing" }}`},
+		},
+	}
+	require.NoError(t, prom.Init())
+
+	p := pod()
+	p.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/module": "http_2xx",
+	}
+	registerPod(p, prom)
+	require.Len(t, prom.kubernetesPods, 1)
+
+	// The pods are still identified by the url without parameters
+	u, ok := prom.kubernetesPods["http://127.0.0.1:9102/metrics"]
+	require.True(t, ok)
+	require.Equal(t, "http://127.0.0.1:9102/metrics?collect%5B%5D=default&module=http_2xx", u.URL.String())
+
+	unregisterPod(p, prom)
+	require.Empty(t, prom.kubernetesPods)
+}
+
+func TestPodParamsInvalidTemplate(t *testing.T) {
+	prom := &Prometheus{
+		Log:       testutil.Logger{},
+		PodParams: map[string][]string{"module": {"{{ .Annotations"}},
+	}
+	require.Error(t, prom.Init())
+}
+
+func TestTargetParams(t *testing.T) {
+	prom := &Prometheus{
+		Log: testutil.Logger{},
+		Targets: []Target{
+			{
+				URL:    "http://localhost:9090/federate?match%5B%5D=up",
+				Params: map[string][]string{"match[]": {`{job="node"}`}},
+			},
+		},
+	}
+	require.NoError(t, prom.Init())
+
+	urls := prom.targetURLs()
+	require.Len(t, urls, 1)
+	for _, u := range urls {
+		require.Equal(t, []string{"up", `{job="node"}`}, u.URL.Query()["match[]"])
+	}
+}
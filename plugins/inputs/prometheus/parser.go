@@ -1,12 +1,10 @@
 package prometheus
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"math"
-	"mime"
 	"net/http"
 	"time"
 
@@ -14,44 +12,29 @@ import (
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers/prometheus/common"
 
-	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	dto "github.com/prometheus/client_model/go"
-	"github.com/prometheus/common/expfmt"
 )
 
 func Parse(buf []byte, header http.Header) ([]telegraf.Metric, error) {
-	var parser expfmt.TextParser
 	var metrics []telegraf.Metric
-	var err error
-	// parse even if the buffer begins with a newline
-	buf = bytes.TrimPrefix(buf, []byte("\n"))
-	// Read raw data
-	buffer := bytes.NewBuffer(buf)
-	reader := bufio.NewReader(buffer)
-
-	// Prepare output
-	metricFamilies := make(map[string]*dto.MetricFamily)
-
-	if isProtobuf(header) {
-		for {
-			mf := &dto.MetricFamily{}
-			if _, ierr := pbutil.ReadDelimited(reader, mf); ierr != nil {
-				if ierr == io.EOF {
-					break
-				}
-				return nil, fmt.Errorf("reading metric family protocol buffer failed: %s", ierr)
-			}
-			metricFamilies[mf.GetName()] = mf
+	now := time.Now()
+	decoder := common.NewFamilyDecoder(bytes.NewReader(buf), header)
+	for {
+		metricFamilies, err := decoder.Decode()
+		if err == io.EOF {
+			return metrics, nil
 		}
-	} else {
-		metricFamilies, err = parser.TextToMetricFamilies(reader)
 		if err != nil {
-			return nil, fmt.Errorf("reading text format failed: %s", err)
+			return nil, err
 		}
+		metrics = append(metrics, parseFamilies(metricFamilies, now)...)
 	}
+}
 
-	now := time.Now()
-	// read metrics
+// parseFamilies converts the metric families into metrics, using now as the
+// time of samples without a timestamp.
+func parseFamilies(metricFamilies map[string]*dto.MetricFamily, now time.Time) []telegraf.Metric {
+	var metrics []telegraf.Metric
 	for metricName, mf := range metricFamilies {
 		for _, m := range mf.Metric {
 			// reading tags
@@ -86,19 +69,17 @@ func Parse(buf []byte, header http.Header) ([]telegraf.Metric, error) {
 			}
 		}
 	}
-
-	return metrics, err
+	return metrics
 }
 
-func isProtobuf(header http.Header) bool {
-	mediatype, params, err := mime.ParseMediaType(header.Get("Content-Type"))
-	if err != nil {
-		return false
+// stripTimestamps removes the timestamps of the metrics, so they are stamped
+// with the time of the scrape.
+func stripTimestamps(families map[string]*dto.MetricFamily) {
+	for _, mf := range families {
+		for _, m := range mf.Metric {
+			m.TimestampMs = nil
+		}
 	}
-
-	return mediatype == "application/vnd.google.protobuf" &&
-		params["encoding"] == "delimited" &&
-		params["proto"] == "io.prometheus.client.MetricFamily"
 }
 
 // Get Quantiles from summary metric
@@ -116,7 +97,9 @@ func makeQuantiles(m *dto.Metric) map[string]interface{} {
 func makeBuckets(m *dto.Metric) map[string]interface{} {
 	fields := make(map[string]interface{})
 	for _, b := range m.GetHistogram().Bucket {
-		fields[fmt.Sprint(b.GetUpperBound())] = float64(b.GetCumulativeCount())
+		name := fmt.Sprint(b.GetUpperBound())
+		fields[name] = float64(b.GetCumulativeCount())
+		common.AddExemplarFields(fields, name, b.GetExemplar())
 	}
 	return fields
 }
@@ -131,6 +114,7 @@ func getNameAndValue(m *dto.Metric) map[string]interface{} {
 	} else if m.Counter != nil {
 		if !math.IsNaN(m.GetCounter().GetValue()) {
 			fields["counter"] = float64(m.GetCounter().GetValue())
+			common.AddExemplarFields(fields, "counter", m.GetCounter().GetExemplar())
 		}
 	} else if m.Untyped != nil {
 		if !math.IsNaN(m.GetUntyped().GetValue()) {
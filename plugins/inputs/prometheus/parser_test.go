@@ -103,3 +103,47 @@ func TestParseValidPrometheus(t *testing.T) {
 		map[string]string{"verb": "POST", "resource": "bindings"},
 		metrics[0].Tags())
 }
+
+const validOpenMetrics = `# TYPE http_requests counter
+http_requests_total{code="200"} 1027 # {trace_id="abc123"} 1
+http_requests_created{code="200"} 1600000000
+# TYPE rpc_duration_seconds histogram
+rpc_duration_seconds_bucket{le="0.5"} 3 # {trace_id="def456"} 0.2
+rpc_duration_seconds_bucket{le="+Inf"} 5
+rpc_duration_seconds_count 5
+rpc_duration_seconds_sum 4.5
+# EOF
+`
+
+func TestParseOpenMetrics(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+
+	metrics, err := Parse([]byte(validOpenMetrics), header)
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 3)
+
+	fields := make(map[string]map[string]interface{})
+	for _, m := range metrics {
+		fields[m.Name()] = m.Fields()
+	}
+	assert.Equal(t, map[string]interface{}{
+		"counter":                   1027.0,
+		"counter_exemplar":          1.0,
+		"counter_exemplar_trace_id": "abc123",
+	}, fields["http_requests_total"])
+	assert.Equal(t, map[string]interface{}{
+		"gauge": 1600000000.0,
+	}, fields["http_requests_created"])
+	assert.Equal(t, map[string]interface{}{
+		"0.5":                   3.0,
+		"0.5_exemplar":          0.2,
+		"0.5_exemplar_trace_id": "def456",
+		"+Inf":                  5.0,
+		"count":                 5.0,
+		"sum":                   4.5,
+	}, fields["rpc_duration_seconds"])
+
+	_, err = Parse([]byte("# TYPE up gauge\nup 1\n"), header)
+	assert.Error(t, err)
+}
@@ -4,37 +4,82 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/internal/k8s"
+	"github.com/influxdata/telegraf/internal/resolver"
+	"github.com/influxdata/telegraf/plugins/common/oauth"
+	"github.com/influxdata/telegraf/plugins/common/proxy"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	parser_v2 "github.com/influxdata/telegraf/plugins/parsers/prometheus"
+	"github.com/influxdata/telegraf/plugins/parsers/prometheus/common"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
-const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
+const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,application/openmetrics-text;version=1.0.0;q=0.5,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
 
 type Prometheus struct {
 	// An array of urls to scrape metrics from.
 	URLs []string `toml:"urls"`
 
+	// Urls scraped at their own interval
+	Targets    []Target `toml:"targets"`
+	lastGather time.Time
+	lastScrape map[string]time.Time
+
+	// Exporters listening on unix sockets, the clients are kept per socket
+	UnixSockets []UnixSocket `toml:"unix_sockets"`
+	unixClients map[string]*http.Client
+	unixLock    sync.Mutex
+
+	// Spread the scrapes of a gather over this duration
+	ScrapeOffsetSpread config.Duration `toml:"scrape_offset_spread"`
+
+	// Additional urls read from a file or an environment variable
+	URLsFromFile string `toml:"urls_from_file"`
+	URLsFromEnv  string `toml:"urls_from_env"`
+	fileURLs     []string
+	fileModTime  time.Time
+
 	// An array of Kubernetes services to scrape metrics from.
 	KubernetesServices []string
 
 	// Location of kubernetes config file
 	KubeConfig string
 
+	// Discover urls from services registered in Consul
+	ConsulConfig *ConsulConfig `toml:"consul_config"`
+
+	// DNS based service discovery
+	DNSSDNames           []string            `toml:"dns_sd_names"`
+	DNSSDType            string              `toml:"dns_sd_type"`
+	DNSSDPort            int                 `toml:"dns_sd_port"`
+	DNSSDScheme          string              `toml:"dns_sd_scheme"`
+	DNSSDPath            string              `toml:"dns_sd_path"`
+	DNSSDRefreshInterval config.Duration     `toml:"dns_sd_refresh_interval"`
+	DNSSDParams          map[string][]string `toml:"dns_sd_params"`
+	dnsTargets           map[string]URLAndAddress
+	dnsRefreshed         time.Time
+	lookupSRV            func(service, proto, name string) (string, []*net.SRV, error)
+	lookupHost           func(host string) ([]string, error)
+
 	// Label Selector/s for Kubernetes
 	KubernetesLabelSelector string `toml:"kubernetes_label_selector"`
 
@@ -49,29 +94,118 @@ type Prometheus struct {
 	Username string `toml:"username"`
 	Password string `toml:"password"`
 
+	// OAuth2 client credentials
+	oauth.OAuth2Config
+
 	ResponseTimeout config.Duration `toml:"response_timeout"`
+	MaxBodySize     config.Size     `toml:"max_body_size"`
+
+	// Keep the connections to the urls open between gathers
+	ReuseConnections    bool `toml:"reuse_connections"`
+	MaxIdleConnsPerHost int  `toml:"max_idle_conns_per_host"`
+
+	// HTTP request sent to scrape the urls
+	Method      string            `toml:"method"`
+	Body        string            `toml:"body"`
+	HTTPHeaders map[string]string `toml:"http_headers"`
 
 	MetricVersion int `toml:"metric_version"`
 
+	// Name the measurements of metric_version 2 after the metric family
+	FamilyMeasurement bool `toml:"family_measurement"`
+
 	URLTag string `toml:"url_tag"`
 
+	// Stamp the metrics with the time of the scrape and optionally record it
+	IgnoreTimestamp     bool            `toml:"ignore_timestamp"`
+	ScrapeTimeField     string          `toml:"scrape_time_field"`
+	ScrapeTimeTag       string          `toml:"scrape_time_tag"`
+	ScrapeTimePrecision config.Duration `toml:"scrape_time_precision"`
+
 	tls.ClientConfig
 
+	// Scrape and talk to the Kubernetes API through a HTTP or SOCKS5 proxy
+	proxy.HTTPProxy
+	proxy func(*http.Request) (*url.URL, error)
+
 	Log telegraf.Logger
 
-	client  *http.Client
-	headers map[string]string
+	client    *http.Client
+	transport *http.Transport
+	headers   map[string]string
 
 	// Should we scrape Kubernetes services for prometheus annotations
-	MonitorPods       bool   `toml:"monitor_kubernetes_pods"`
-	PodScrapeScope    string `toml:"pod_scrape_scope"`
-	NodeIP            string `toml:"node_ip"`
-	PodScrapeInterval int    `toml:"pod_scrape_interval"`
-	PodNamespace      string `toml:"monitor_kubernetes_pods_namespace"`
-	lock              sync.Mutex
-	kubernetesPods    map[string]URLAndAddress
-	cancel            context.CancelFunc
-	wg                sync.WaitGroup
+	MonitorPods        bool                `toml:"monitor_kubernetes_pods"`
+	PodScrapeScope     string              `toml:"pod_scrape_scope"`
+	NodeIP             string              `toml:"node_ip"`
+	NodeName           string              `toml:"node_name"`
+	KubeletURL         string              `toml:"kubelet_url"`
+	KubeletBearerToken string              `toml:"kubelet_bearer_token"`
+	PodScrapeInterval  int                 `toml:"pod_scrape_interval"`
+	PodNamespace       string              `toml:"monitor_kubernetes_pods_namespace"`
+	PodNamespaces      []string            `toml:"monitor_kubernetes_pods_namespaces"`
+	PodRequireReady    bool                `toml:"pod_require_ready"`
+	PodSkipTerminating bool                `toml:"pod_skip_terminating"`
+	PodParams          map[string][]string `toml:"pod_params"`
+	podParamTemplates  map[string][]*template.Template
+	lock               sync.Mutex
+	kubernetesPods     map[string]URLAndAddress
+	consulServices     map[string]URLAndAddress
+	informers          []*k8s.Informers
+	kubeletURL         string
+	kubeletClient      *http.Client
+	cancel             context.CancelFunc
+	wg                 sync.WaitGroup
+
+	// Scrape the cAdvisor and resource metrics of the kubelet of every node
+	KubeletScrape      bool     `toml:"kubelet_scrape"`
+	KubeletScrapePaths []string `toml:"kubelet_scrape_paths"`
+	kubeletNodes       map[string][]URLAndAddress
+
+	// Names of the pod annotations configuring the scrapes
+	PodAnnotationPrefix string            `toml:"pod_annotation_prefix"`
+	PodAnnotationKeys   map[string]string `toml:"pod_annotation_keys"`
+
+	// Mark series disappearing from the scrapes as stale
+	StaleMarkers     bool   `toml:"stale_markers"`
+	StaleMarkerField string `toml:"stale_marker_field"`
+	series           map[string]map[string]series
+	seriesLock       sync.Mutex
+
+	// Report scrape statistics per target and discovered pods per namespace
+	ScrapeStats bool `toml:"scrape_stats"`
+	stats       map[string]*targetStats
+	statsLock   sync.Mutex
+
+	// Add the up, scrape_duration_seconds and scrape_samples_scraped
+	// metrics of Prometheus per scrape
+	EnableScrapeMetrics bool `toml:"enable_scrape_metrics"`
+
+	// Only forward the series whose values changed since the last scrape
+	ChangedSeriesOnly bool            `toml:"changed_series_only"`
+	ResyncInterval    config.Duration `toml:"resync_interval"`
+	changes           map[string]*seriesValues
+	changesLock       sync.Mutex
+
+	// Shape of the histograms and summaries of metric_version 2
+	HistogramBuckets string `toml:"histogram_buckets"`
+	ExcludeSumCount  bool   `toml:"exclude_sum_count"`
+	BucketLabels     string `toml:"bucket_labels"`
+
+	// Rename, drop or convert well-known labels such as le and quantile
+	LabelRename map[string]string `toml:"label_rename"`
+	LabelDrop   []string          `toml:"label_drop"`
+	LabelFields []string          `toml:"label_fields"`
+
+	// Filter the families by name before they are parsed
+	FamilyPass   []string `toml:"family_pass"`
+	FamilyDrop   []string `toml:"family_drop"`
+	familyFilter filter.Filter
+
+	// Drop families with more label combinations in a single scrape
+	MaxFamilySeries int `toml:"max_family_series"`
+	dropped         map[string]*droppedFamilies
+	droppedLock     sync.Mutex
 
 	// Only for monitor_kubernetes_pods=true and pod_scrape_scope="node"
 	podLabelSelector  labels.Selector
@@ -83,6 +217,15 @@ var sampleConfig = `
   ## An array of urls to scrape metrics from.
   urls = ["http://localhost:9100/metrics"]
 
+  ## File with additional urls to scrape, one per line.  Empty lines and lines
+  ## starting with '#' are ignored and environment variables like ${HOST} are
+  ## replaced.  The file is read again when it changes.
+  # urls_from_file = "/etc/telegraf/prometheus_urls.txt"
+
+  ## Environment variable containing additional urls to scrape, separated by
+  ## commas or whitespace.
+  # urls_from_env = "PROMETHEUS_URLS"
+
   ## Metric version controls the mapping from Prometheus metrics into
   ## Telegraf metrics.  When using the prometheus_client output, use the same
   ## value in both plugins to ensure metrics are round-tripped without
@@ -92,12 +235,39 @@ var sampleConfig = `
   ##            metric_version = 2; recommended version
   # metric_version = 1
 
+  ## Name the measurements after the metric family instead of using the
+  ## prometheus measurement for all metrics, requires metric_version = 2.
+  # family_measurement = false
+
   ## Url tag name (tag containing scrapped url. optional, default is "url")
   # url_tag = "url"
 
+  ## Ignore the timestamps provided by the exporters and use the time of the
+  ## scrape instead, useful for exporters with skewed clocks.
+  # ignore_timestamp = false
+
+  ## Record the time of the scrape as an integer field and/or tag, in units
+  ## of scrape_time_precision.  The precision defaults to nanoseconds.
+  # scrape_time_field = ""
+  # scrape_time_tag = ""
+  # scrape_time_precision = "1s"
+
   ## An array of Kubernetes services to scrape metrics from.
   # kubernetes_services = ["http://my-service-dns.my-namespace:9100/metrics"]
 
+  ## DNS names to discover targets from.  For SRV records the port of each
+  ## record is used and the record target is added as the srv_target tag, for
+  ## A records every address is scraped on the dns_sd_port.  The names are
+  ## resolved again after the refresh interval.
+  # dns_sd_names = ["_metrics._tcp.example.org"]
+  # dns_sd_type = "SRV"
+  # dns_sd_port = 9100
+  # dns_sd_scheme = "http"
+  # dns_sd_path = "/metrics"
+  # dns_sd_refresh_interval = "30s"
+  ## Query parameters added to the scrape urls of discovered targets
+  # dns_sd_params = {"collect[]" = ["cpu", "meminfo"]}
+
   ## Kubernetes config file to create client from.
   # kube_config = "/path/to/kubernetes.config"
 
@@ -108,12 +278,41 @@ var sampleConfig = `
   ## - prometheus.io/path: If the metrics path is not /metrics, define it with this annotation.
   ## - prometheus.io/port: If port is not 9102 use this annotation
   # monitor_kubernetes_pods = true
+  ## Prefix of the annotations above, e.g. to only scrape the pods annotated
+  ## with telegraf.io/scrape instead of the pods scraped by a Prometheus server.
+  # pod_annotation_prefix = "prometheus.io"
+  ## Names of individual annotations overriding the prefix, the settings are
+  ## scrape, scheme, path and port.
+  # pod_annotation_keys = {"scrape" = "telegraf.io/scrape"}
   ## Get the list of pods to scrape with either the scope of
   ## - cluster: the kubernetes watch api (default, no need to specify)
-  ## - node: the local cadvisor api; for scalability. Note that the config node_ip or the environment variable NODE_IP must be set to the host IP.
+  ## - node: only the pods of the node telegraf is running on; for scalability.
+  ##   With node_name or the environment variable NODE_NAME the pods of the
+  ##   node are watched, otherwise they are listed from the local kubelet api
+  ##   and node_ip or the environment variable NODE_IP must be set.
   # pod_scrape_scope = "cluster"
+  ## List the pods of the node from this kubelet url instead of the api
+  ## server, implies the node scrape scope.  No cluster permissions are
+  ## needed with the read-only port, e.g. "http://127.0.0.1:10255", the
+  ## authenticated port, e.g. "https://$NODE_IP:10250", requires a token.
+  # kubelet_url = ""
+  ## Bearer token file authenticating to the kubelet, e.g. the service account
+  ## token at /run/secrets/kubernetes.io/serviceaccount/token.
+  # kubelet_bearer_token = ""
+  ## Scrape the kubelet of every node of the cluster at the given paths,
+  ## regardless of pod annotations, for the container metrics of cAdvisor.
+  ## The nodes are watched with the kubernetes api and the kubelets are
+  ## authenticated with kubelet_bearer_token, defaulting to the token of the
+  ## service account.  The metrics are tagged with node_name.  With the node
+  ## scrape scope and a known node_name only the local kubelet is scraped.
+  # kubelet_scrape = false
+  # kubelet_scrape_paths = ["/metrics/cadvisor", "/metrics/resource"]
+  ## Only for node scrape scope: name of the node that telegraf is running on.
+  ## If empty the environment variable NODE_NAME is used.
+  # node_name = ""
   ## Only for node scrape scope: node IP of the node that telegraf is running on.
-  ## Either this config or the environment variable NODE_IP must be set.
+  ## Either this config or the environment variable NODE_IP must be set when
+  ## the node name is unknown.
   # node_ip = "10.180.1.1"
 	## Only for node scrape scope: interval in seconds for how often to get updated pod list for scraping.
 	## Default is 60 seconds.
@@ -121,11 +320,75 @@ var sampleConfig = `
   ## Restricts Kubernetes monitoring to a single namespace
   ##   ex: monitor_kubernetes_pods_namespace = "default"
   # monitor_kubernetes_pods_namespace = ""
-  # label selector to target pods which have the label
+  ## Restricts Kubernetes monitoring to several namespaces.  The pods of each
+  ## namespace are watched separately, so namespaced roles are sufficient.
+  ##   ex: monitor_kubernetes_pods_namespaces = ["default", "monitoring"]
+  # monitor_kubernetes_pods_namespaces = []
+  ## Restricts Kubernetes monitoring to pods matching the selectors.  With
+  ## the cluster scrape scope, the selectors are applied by the api server.
+  ## Label selector to target pods which have the label
   # kubernetes_label_selector = "env=dev,app=nginx"
-  # field selector to target pods
-  # eg. To scrape pods on a specific node
+  ## Field selector to target pods
+  ##   eg. To scrape pods on a specific node
   # kubernetes_field_selector = "spec.nodeName=$HOSTNAME"
+  ## Only scrape pods with a true Ready condition instead of pods with all
+  ## containers ready; pods losing readiness are no longer scraped.
+  # pod_require_ready = false
+  ## Stop scraping pods as soon as they are terminating.
+  # pod_skip_terminating = false
+  ## Query parameters added to the scrape urls of pods.  The values are
+  ## templates executed on the pod; parameters rendering empty are skipped.
+  ##   ex: pod_params = {"module" = ['{{ index .Annotations "prometheus.io/module" }}']}
+  # pod_params = {}
+
+  ## Add a metric with the stale_marker_field set to true for series that
+  ## disappeared from a scrape, failed to be scraped or whose target is no
+  ## longer discovered, so outputs can expire them.
+  # stale_markers = false
+  # stale_marker_field = "stale"
+
+  ## Report the scrape successes, failures and last error per target and the
+  ## number of discovered pods per namespace as prometheus_scrape and
+  ## prometheus_discovery metrics.
+  # scrape_stats = false
+
+  ## Add the up, scrape_duration_seconds and scrape_samples_scraped metrics
+  ## of every scrape like Prometheus does, following the metric_version.
+  # enable_scrape_metrics = false
+
+  ## Only add the series whose value or labels changed since the previous
+  ## scrape of the target.  All series are added every resync_interval, 0
+  ## only adds them on the first scrape.
+  # changed_series_only = false
+  # resync_interval = "10m"
+
+  ## Shape of the histograms and summaries, requires metric_version = 2.
+  ## histogram_buckets is "cumulative" for the counts of observations up to
+  ## the bound of each bucket or "delta" for the counts within each bucket.
+  ## exclude_sum_count drops the _sum and _count fields.  bucket_labels is
+  ## "tag" to add the le and quantile tags or "field" to append the bound to
+  ## the field names, e.g. http_duration_seconds_bucket_0.5, merging the
+  ## buckets of a series into a single metric.
+  # histogram_buckets = "cumulative"
+  # exclude_sum_count = false
+  # bucket_labels = "tag"
+
+  ## Labels to rename, to drop, or to convert from tags into float fields,
+  ## e.g. for schemas expecting numeric bounds.  Renamed labels converted to
+  ## fields use the new name.  Applied after the histogram options.
+  # label_rename = {le = "bucket", quantile = "percentile"}
+  # label_drop = []
+  # label_fields = ["le", "quantile"]
+
+  ## Glob patterns of the metric families to keep or drop, applied to the
+  ## names of the families before they are parsed into metrics.
+  # family_pass = []
+  # family_drop = ["go_*", "process_*"]
+
+  ## Drop all metrics of a family exceeding this number of label combinations
+  ## in a single scrape.  The number of dropped families and series per target
+  ## is reported in the prometheus_series_limit metric.  0 means unlimited.
+  # max_family_series = 0
 
   ## Use bearer token for authorization. ('bearer_token' takes priority)
   # bearer_token = "/path/to/bearer/token"
@@ -137,15 +400,100 @@ var sampleConfig = `
   # username = ""
   # password = ""
 
+  ## OAuth2 client credentials flow, the token is requested from token_url
+  ## and refreshed automatically before it expires.  Can't be combined with
+  ## the bearer token or basic authentication.
+  # client_id = "clientid"
+  # client_secret = "secret"
+  # token_url = "https://identityprovider/oauth2/v1/token"
+  # scopes = ["metrics:read"]
+
   ## Specify timeout duration for slower prometheus clients (default is 3s)
   # response_timeout = "3s"
 
+  ## Maximum size of the decompressed response body, reading stops with an
+  ## error at the limit.  By default the size is unlimited.
+  # max_body_size = "100MB"
+
+  ## Keep the connections open between gathers instead of connecting to the
+  ## targets on every gather, avoiding the TLS handshakes when scraping many
+  ## https targets.  max_idle_conns_per_host limits the open connections per
+  ## host, 0 uses the default of 2.
+  # reuse_connections = false
+  # max_idle_conns_per_host = 0
+
+  ## HTTP method and body of the scrape requests, either GET or POST.
+  # method = "GET"
+  # body = ""
+
+  ## Additional HTTP headers of the scrape requests.  The Host header
+  ## overrides the host of the request.
+  # http_headers = {"X-Api-Key" = "secret"}
+
   ## Optional TLS Config
   # tls_ca = /path/to/cafile
   # tls_cert = /path/to/certfile
   # tls_key = /path/to/keyfile
   ## Use TLS but skip chain & host verification
   # insecure_skip_verify = false
+
+  ## HTTP or SOCKS5 proxy used for the scrapes, the Kubernetes API and the
+  ## kubelet, e.g. "socks5://proxy:1080".  If unset the scrapes are not
+  ## proxied and the Kubernetes clients use the HTTP_PROXY environment
+  ## variables.
+  # http_proxy_url = ""
+
+  ## Scrape the passing instances of services registered in Consul.  The
+  ## instances are tagged with consul_service, consul_node and
+  ## consul_datacenter as well as the service or node metadata listed in
+  ## meta_tags.
+  # [inputs.prometheus.consul_config]
+  #   agent = "http://localhost:8500"
+  #   datacenter = ""
+  #   token = ""
+  #   services = ["web"]
+  #   ## Only scrape instances with all of these tags
+  #   service_tags = ["prometheus"]
+  #   meta_tags = ["version"]
+  #   scheme = "http"
+  #   metrics_path = "/metrics"
+  #   refresh_interval = "1m"
+  #   ## Query parameters added to the scrape urls of the instances
+  #   params = {"collect[]" = ["cpu", "meminfo"]}
+
+  ## Delay the scrape of each url by an offset within this duration, hashed
+  ## from the url, instead of scraping all urls at once on every gather.
+  ## Must be shorter than the interval minus the response_timeout.
+  # scrape_offset_spread = "0s"
+
+  ## Urls scraped at their own interval, e.g. expensive exporters, instead of
+  ## on every gather.  The interval should be a multiple of the interval of
+  ## the plugin, since targets are only scraped when the plugin gathers.
+  # [[inputs.prometheus.targets]]
+  #   url = "http://localhost:9187/metrics"
+  #   interval = "60s"
+  #   ## Query parameters added to the url, e.g. for federation
+  #   params = {"match[]" = ['{job="node"}']}
+  #   ## Method, body and additional headers of the requests, overriding the
+  #   ## settings of the plugin
+  #   method = "POST"
+  #   body = ""
+  #   http_headers = {"Host" = "metrics.example.org"}
+  ## The url and tags of targets are templates, expanded for every index of
+  ## the range as {{.Index}}, padded like its start.  The url can use the tags
+  ## as {{.Tags.<key>}}, {{env "VAR"}} and {{file "/path"}} interpolate
+  ## environment variables and secrets.
+  # [[inputs.prometheus.targets]]
+  #   range = "001..300"
+  #   url = "http://{{.Tags.host}}.example.org:9100/metrics"
+  #   tags = {host = "host{{.Index}}"}
+
+  ## Exporters listening on a unix socket, scraped at metrics_path with the
+  ## additional headers.
+  # [[inputs.prometheus.unix_sockets]]
+  #   socket_path = "/var/run/exporter.sock"
+  #   metrics_path = "/metrics"
+  #   http_headers = {"Host" = "exporter"}
 `
 
 func (p *Prometheus) SampleConfig() string {
@@ -157,10 +505,34 @@ func (p *Prometheus) Description() string {
 }
 
 func (p *Prometheus) Init() error {
+	p.proxy = nil
+	if p.HTTPProxyURL != "" {
+		u, err := url.Parse(p.HTTPProxyURL)
+		if err != nil || !choice.Contains(u.Scheme, []string{"http", "https", "socks5"}) || u.Host == "" {
+			return fmt.Errorf("http_proxy_url %q must be an http, https or socks5 url", p.HTTPProxyURL)
+		}
+		if p.proxy, err = p.HTTPProxy.Proxy(); err != nil {
+			return err
+		}
+	}
 
 	// Config proccessing for node scrape scope for monitor_kubernetes_pods
-	p.isNodeScrapeScope = strings.EqualFold(p.PodScrapeScope, "node")
-	if p.isNodeScrapeScope {
+	p.isNodeScrapeScope = strings.EqualFold(p.PodScrapeScope, "node") || p.KubeletURL != ""
+	if p.isNodeScrapeScope && p.NodeName == "" && p.KubeletURL == "" {
+		p.NodeName = os.Getenv("NODE_NAME")
+	}
+	p.kubeletURL = ""
+	if p.KubeletURL != "" {
+		u, err := url.Parse(p.KubeletURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("kubelet_url %q must be an http or https url", p.KubeletURL)
+		}
+		p.kubeletURL = strings.TrimSuffix(p.KubeletURL, "/")
+		p.Log.Infof("Using pod scrape scope at node level to get pod list from the kubelet at %q.", p.kubeletURL)
+	} else if p.isNodeScrapeScope && p.NodeName != "" {
+		// Watch the pods of the node instead of polling the kubelet
+		p.Log.Infof("Using pod scrape scope at node level watching the pods of node %q.", p.NodeName)
+	} else if p.isNodeScrapeScope {
 		// Need node IP to make cAdvisor call for pod list. Check if set in config and valid IP address
 		if p.NodeIP == "" || net.ParseIP(p.NodeIP) == nil {
 			p.Log.Infof("The config node_ip is empty or invalid. Using NODE_IP env var as default.")
@@ -174,27 +546,105 @@ func (p *Prometheus) Init() error {
 
 			p.NodeIP = envVarNodeIP
 		}
+		p.kubeletURL = "https://" + net.JoinHostPort(p.NodeIP, kubeletPort)
+		p.Log.Infof("Using pod scrape scope at node level to get pod list using cAdvisor.")
+	}
+	if p.pollsKubelet() {
+		p.kubeletClient = newKubeletClient(p.proxy)
+	}
+	p.initKubeletScrape()
 
-		// Parse label and field selectors - will be used to filter pods after cAdvisor call
-		var err error
-		p.podLabelSelector, err = labels.Parse(p.KubernetesLabelSelector)
-		if err != nil {
-			return fmt.Errorf("error parsing the specified label selector(s): %s", err.Error())
-		}
-		p.podFieldSelector, err = fields.ParseSelector(p.KubernetesFieldSelector)
-		if err != nil {
-			return fmt.Errorf("error parsing the specified field selector(s): %s", err.Error())
-		}
+	// Parse label and field selectors - passed to the watch api for cluster
+	// scrape scope, used to filter pods after cAdvisor call for node scope
+	var err error
+	p.podLabelSelector, err = labels.Parse(p.KubernetesLabelSelector)
+	if err != nil {
+		return fmt.Errorf("error parsing the specified label selector(s): %s", err.Error())
+	}
+	p.podFieldSelector, err = fields.ParseSelector(p.KubernetesFieldSelector)
+	if err != nil {
+		return fmt.Errorf("error parsing the specified field selector(s): %s", err.Error())
+	}
+	// Field selectors are matched locally when polling the kubelet
+	if p.pollsKubelet() {
 		isValid, invalidSelector := fieldSelectorIsSupported(p.podFieldSelector)
 		if !isValid {
 			return fmt.Errorf("the field selector %s is not supported for pods", invalidSelector)
 		}
+	}
 
-		p.Log.Infof("Using pod scrape scope at node level to get pod list using cAdvisor.")
+	if p.MonitorPods {
 		p.Log.Infof("Using the label selector: %v and field selector: %v", p.podLabelSelector, p.podFieldSelector)
 	}
 
-	return nil
+	if p.ScrapeOffsetSpread < 0 {
+		return errors.New("scrape_offset_spread must not be negative")
+	}
+
+	if !choice.Contains(p.HistogramBuckets, []string{"", "cumulative", "delta"}) {
+		return fmt.Errorf("unknown histogram_buckets %q", p.HistogramBuckets)
+	}
+	if !choice.Contains(p.BucketLabels, []string{"", "tag", "field"}) {
+		return fmt.Errorf("unknown bucket_labels %q", p.BucketLabels)
+	}
+	if p.MetricVersion != 2 && p.reshapesHistograms() {
+		return errors.New("histogram_buckets, exclude_sum_count and bucket_labels require metric_version = 2")
+	}
+	if p.MetricVersion != 2 && p.FamilyMeasurement {
+		return errors.New("family_measurement requires metric_version = 2")
+	}
+	if err := p.initLabelMapping(); err != nil {
+		return err
+	}
+
+	if len(p.FamilyPass) > 0 || len(p.FamilyDrop) > 0 {
+		f, err := filter.NewIncludeExcludeFilter(p.FamilyPass, p.FamilyDrop)
+		if err != nil {
+			return fmt.Errorf("creating family filter failed: %w", err)
+		}
+		p.familyFilter = f
+	}
+
+	if p.StaleMarkers && p.StaleMarkerField == "" {
+		return errors.New("stale_marker_field must not be empty")
+	}
+	if p.ResyncInterval < 0 {
+		return errors.New("resync_interval must not be negative")
+	}
+
+	if p.ConsulConfig != nil {
+		if err := p.ConsulConfig.init(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.initDNSDiscovery(); err != nil {
+		return err
+	}
+
+	for setting := range p.PodAnnotationKeys {
+		if !choice.Contains(setting, podAnnotationSettings) {
+			return fmt.Errorf("unknown setting %q in pod_annotation_keys", setting)
+		}
+	}
+
+	if err := p.initPodParams(); err != nil {
+		return err
+	}
+
+	if err := checkMethod(p.Method); err != nil {
+		return err
+	}
+
+	if err := p.checkAuth(); err != nil {
+		return err
+	}
+
+	if err := p.initUnixSockets(); err != nil {
+		return err
+	}
+
+	return p.initTargets()
 }
 
 var ErrProtocolError = errors.New("prometheus protocol error")
@@ -223,11 +673,21 @@ type URLAndAddress struct {
 	URL         *url.URL
 	Address     string
 	Tags        map[string]string
+	// Interval is the scrape interval of the url, 0 scrapes it on every
+	// gather.
+	Interval time.Duration
+	// Method, Body and Headers override the request settings of the plugin.
+	Method  string
+	Body    string
+	Headers map[string]string
+	// Kubelet urls are scraped with the client and token of the kubelet
+	// instead of the client and authorization of the plugin.
+	Kubelet bool
 }
 
 func (p *Prometheus) GetAllURLs() (map[string]URLAndAddress, error) {
 	allURLs := make(map[string]URLAndAddress)
-	for _, u := range p.URLs {
+	for _, u := range p.configuredURLs() {
 		URL, err := url.Parse(u)
 		if err != nil {
 			p.Log.Errorf("Could not parse %q, skipping it. Error: %s", u, err.Error())
@@ -236,6 +696,18 @@ func (p *Prometheus) GetAllURLs() (map[string]URLAndAddress, error) {
 		allURLs[URL.String()] = URLAndAddress{URL: URL, OriginalURL: URL}
 	}
 
+	for k, v := range p.targetURLs() {
+		allURLs[k] = v
+	}
+
+	for k, v := range p.unixSocketURLs() {
+		allURLs[k] = v
+	}
+
+	for k, v := range p.dnsDiscoveredURLs() {
+		allURLs[k] = v
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	// loop through all pods scraped via the prometheus annotation on the pods
@@ -243,13 +715,21 @@ func (p *Prometheus) GetAllURLs() (map[string]URLAndAddress, error) {
 		allURLs[k] = v
 	}
 
+	for k, v := range p.consulServices {
+		allURLs[k] = v
+	}
+
+	for k, v := range p.kubeletURLs() {
+		allURLs[k] = v
+	}
+
 	for _, service := range p.KubernetesServices {
 		URL, err := url.Parse(service)
 		if err != nil {
 			return nil, err
 		}
 
-		resolvedAddresses, err := net.LookupHost(URL.Hostname())
+		resolvedAddresses, err := resolver.Default().LookupHost(context.Background(), URL.Hostname())
 		if err != nil {
 			p.Log.Errorf("Could not resolve %q, skipping it. Error: %s", URL.Host, err.Error())
 			continue
@@ -276,8 +756,9 @@ func (p *Prometheus) Gather(acc telegraf.Accumulator) error {
 		}
 		p.client = client
 		p.headers = map[string]string{
-			"User-Agent": internal.ProductToken(),
-			"Accept":     acceptHeader,
+			"User-Agent":      internal.ProductToken(),
+			"Accept":          acceptHeader,
+			"Accept-Encoding": "gzip, deflate",
 		}
 	}
 
@@ -287,15 +768,64 @@ func (p *Prometheus) Gather(acc telegraf.Accumulator) error {
 	if err != nil {
 		return err
 	}
-	for _, URL := range allURLs {
+	now := time.Now()
+	for key, URL := range allURLs {
+		if !p.scrapeDue(key, URL.Interval, now) {
+			continue
+		}
 		wg.Add(1)
-		go func(serviceURL URLAndAddress) {
+		go func(key string, serviceURL URLAndAddress) {
 			defer wg.Done()
-			acc.AddError(p.gatherURL(serviceURL, acc))
-		}(URL)
+			if offset := p.scrapeOffset(key); offset > 0 {
+				time.Sleep(offset)
+			}
+			// Unchanged series are dropped last, so they are still counted
+			// as scraped and not marked as stale
+			targetAcc := acc
+			if p.ChangedSeriesOnly {
+				targetAcc = p.newChangeFilter(key, acc, time.Now())
+			}
+			var recorder *seriesRecorder
+			if p.StaleMarkers {
+				recorder = newSeriesRecorder(targetAcc)
+				targetAcc = recorder
+			}
+			var counter *sampleCounter
+			if p.EnableScrapeMetrics {
+				counter = &sampleCounter{Accumulator: targetAcc}
+				targetAcc = counter
+			}
+			start := time.Now()
+			err := p.gatherURL(key, serviceURL, targetAcc)
+			if counter != nil {
+				p.addScrapeMetrics(serviceURL, start, counter.samples, err, acc)
+			}
+			if recorder != nil {
+				p.updateSeries(key, recorder, err, acc)
+			}
+			if p.ScrapeStats {
+				p.recordScrape(key, serviceURL, err)
+			}
+			acc.AddError(err)
+		}(key, URL)
 	}
 
 	wg.Wait()
+	p.lastGather = now
+
+	if p.StaleMarkers {
+		p.expireSeries(allURLs, acc)
+	}
+	if p.ChangedSeriesOnly {
+		p.expireChanges(allURLs)
+	}
+	if p.ScrapeStats {
+		p.gatherStats(acc, allURLs)
+	}
+
+	if p.MaxFamilySeries > 0 {
+		p.gatherDropped(acc, allURLs)
+	}
 
 	return nil
 }
@@ -308,57 +838,53 @@ func (p *Prometheus) createHTTPClient() (*http.Client, error) {
 
 	client := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig:   tlsCfg,
-			DisableKeepAlives: true,
+			Proxy:               p.proxy,
+			TLSClientConfig:     tlsCfg,
+			DisableKeepAlives:   !p.ReuseConnections,
+			MaxIdleConnsPerHost: p.MaxIdleConnsPerHost,
 		},
 		Timeout: time.Duration(p.ResponseTimeout),
 	}
+	p.transport = client.Transport.(*http.Transport)
 
-	return client, nil
+	return p.OAuth2Config.CreateOauth2Client(context.Background(), client), nil
 }
 
-func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error {
+func (p *Prometheus) gatherURL(key string, u URLAndAddress, acc telegraf.Accumulator) error {
 	var req *http.Request
 	var err error
 	var uClient *http.Client
-	var metrics []telegraf.Metric
 	if u.URL.Scheme == "unix" {
 		path := u.URL.Query().Get("path")
 		if path == "" {
 			path = "/metrics"
 		}
 		addr := "http://localhost" + path
-		req, err = http.NewRequest("GET", addr, nil)
+		req, err = p.newRequest(u, addr)
 		if err != nil {
-			return fmt.Errorf("unable to create new request '%s': %s", addr, err)
-		}
-
-		// ignore error because it's been handled before getting here
-		tlsCfg, _ := p.ClientConfig.TLSConfig()
-		uClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig:   tlsCfg,
-				DisableKeepAlives: true,
-				Dial: func(network, addr string) (net.Conn, error) {
-					c, err := net.Dial("unix", u.URL.Path)
-					return c, err
-				},
-			},
-			Timeout: time.Duration(p.ResponseTimeout),
+			return err
+		}
+
+		uClient, err = p.unixClient(u.URL.Path)
+		if err != nil {
+			return err
 		}
 	} else {
 		if u.URL.Path == "" {
 			u.URL.Path = "/metrics"
 		}
-		req, err = http.NewRequest("GET", u.URL.String(), nil)
+		req, err = p.newRequest(u, u.URL.String())
 		if err != nil {
-			return fmt.Errorf("unable to create new request '%s': %s", u.URL.String(), err)
+			return err
 		}
 	}
 
-	p.addHeaders(req)
-
-	if p.BearerToken != "" {
+	if u.Kubelet {
+		uClient = p.kubeletClient
+		if err := p.setKubeletAuth(req); err != nil {
+			return err
+		}
+	} else if p.BearerToken != "" {
 		token, err := ioutil.ReadFile(p.BearerToken)
 		if err != nil {
 			return err
@@ -371,7 +897,7 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 	}
 
 	var resp *http.Response
-	if u.URL.Scheme != "unix" {
+	if uClient == nil {
 		resp, err = p.client.Do(req)
 	} else {
 		resp, err = uClient.Do(req)
@@ -385,27 +911,100 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 		return fmt.Errorf("%s returned HTTP status %s", u.URL, resp.Status)
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := p.bodyReader(resp)
 	if err != nil {
-		return fmt.Errorf("error reading body: %s", err)
+		return fmt.Errorf("error reading body of %s: %s", u.URL, err)
 	}
+	defer func() {
+		// Closing the response first stops decoders still reading it
+		resp.Body.Close()
+		body.Close()
+	}()
+
+	// Metrics are added family by family while the body is read, so the
+	// memory used by large scrapes is bounded by their largest family.
+	now := time.Now()
+	parser := parser_v2.Parser{Header: resp.Header}
+	decoder := common.NewFamilyDecoder(body, resp.Header)
+	for {
+		families, err := decoder.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading metrics for %s: %s",
+				u.URL, err)
+		}
 
-	if p.MetricVersion == 2 {
-		parser := parser_v2.Parser{Header: resp.Header}
-		metrics, err = parser.Parse(body)
-	} else {
-		metrics, err = Parse(body, resp.Header)
+		if p.familyFilter != nil {
+			p.filterFamilies(families)
+		}
+		if p.IgnoreTimestamp {
+			stripTimestamps(families)
+		}
+		if p.HistogramBuckets == "delta" {
+			deltaBuckets(families)
+		}
+
+		var metrics []telegraf.Metric
+		if p.MetricVersion == 2 {
+			metrics = parser.ParseFamilies(families, now)
+			if p.FamilyMeasurement {
+				p.nameByFamily(metrics)
+			}
+		} else {
+			metrics = parseFamilies(families, now)
+		}
+
+		if p.MaxFamilySeries > 0 {
+			metrics = p.limitFamilySeries(key, u, metrics)
+		}
+		if p.ExcludeSumCount || p.BucketLabels == "field" {
+			metrics = p.reshapeHistograms(metrics)
+		}
+		if p.mapsLabels() {
+			p.mapLabels(metrics)
+		}
+		p.addScrapeTime(metrics, now)
+		p.addMetrics(u, metrics, acc)
 	}
+}
 
-	if err != nil {
-		return fmt.Errorf("error reading metrics for %s: %s",
-			u.URL, err)
+// nameByFamily names the measurements of the metrics after their family,
+// keeping the field names of the Prometheus metrics.
+func (p *Prometheus) nameByFamily(metrics []telegraf.Metric) {
+	for _, metric := range metrics {
+		metric.SetName(p.metricFamily(metric))
+	}
+}
+
+// addScrapeTime records the time of the scrape in the metrics.
+func (p *Prometheus) addScrapeTime(metrics []telegraf.Metric, now time.Time) {
+	if p.ScrapeTimeField == "" && p.ScrapeTimeTag == "" {
+		return
 	}
 
+	precision := time.Duration(p.ScrapeTimePrecision)
+	if precision <= 0 {
+		precision = time.Nanosecond
+	}
+	ts := now.UnixNano() / int64(precision)
+	for _, metric := range metrics {
+		if p.ScrapeTimeField != "" {
+			metric.AddField(p.ScrapeTimeField, ts)
+		}
+		if p.ScrapeTimeTag != "" {
+			metric.AddTag(p.ScrapeTimeTag, strconv.FormatInt(ts, 10))
+		}
+	}
+}
+
+// addMetrics adds the metrics scraped from the url with its tags.
+func (p *Prometheus) addMetrics(u URLAndAddress, metrics []telegraf.Metric, acc telegraf.Accumulator) {
+	// strip user and password from URL
+	u.OriginalURL.User = nil
 	for _, metric := range metrics {
 		tags := metric.Tags()
-		// strip user and password from URL
-		u.OriginalURL.User = nil
 		if p.URLTag != "" {
 			tags[p.URLTag] = u.OriginalURL.String()
 		}
@@ -429,8 +1028,6 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 			acc.AddFields(metric.Name(), metric.Fields(), tags, metric.Time())
 		}
 	}
-
-	return nil
 }
 
 func (p *Prometheus) addHeaders(req *http.Request) {
@@ -465,19 +1062,46 @@ func fieldSelectorIsSupported(fieldSelector fields.Selector) (bool, string) {
 
 // Start will start the Kubernetes scraping if enabled in the configuration
 func (p *Prometheus) Start(_ telegraf.Accumulator) error {
+	if !p.MonitorPods && p.ConsulConfig == nil && !p.KubeletScrape {
+		return nil
+	}
+
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
 	if p.MonitorPods {
-		var ctx context.Context
-		ctx, p.cancel = context.WithCancel(context.Background())
-		return p.start(ctx)
+		if err := p.start(ctx); err != nil {
+			return err
+		}
+	}
+	if p.KubeletScrape {
+		config, err := k8s.LoadConfig(p.KubeConfig)
+		if err != nil {
+			return err
+		}
+		if p.proxy != nil {
+			config.Proxy = p.proxy
+		}
+		if err := p.watchNodes(config); err != nil {
+			return err
+		}
+	}
+	if p.ConsulConfig != nil {
+		return p.startConsul(ctx)
 	}
 	return nil
 }
 
 func (p *Prometheus) Stop() {
-	if p.MonitorPods {
+	if p.cancel != nil {
 		p.cancel()
 	}
 	p.wg.Wait()
+	p.releaseInformers()
+	// The transport is wrapped when using OAuth2
+	if p.transport != nil {
+		p.transport.CloseIdleConnections()
+	}
+	p.closeUnixClients()
 }
 
 func init() {
@@ -486,6 +1110,10 @@ func init() {
 			ResponseTimeout: config.Duration(time.Second * 3),
 			kubernetesPods:  map[string]URLAndAddress{},
 			URLTag:          "url",
+
+			DNSSDRefreshInterval: config.Duration(30 * time.Second),
+			StaleMarkerField:     "stale",
+			ResyncInterval:       config.Duration(10 * time.Minute),
 		}
 	})
 }
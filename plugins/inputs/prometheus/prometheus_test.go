@@ -7,10 +7,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/proxy"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -219,6 +222,52 @@ go_gc_duration_seconds_count 42
 		testutil.IgnoreTime(), testutil.SortMetrics())
 }
 
+func TestPrometheusGeneratesMetricsFamilyMeasurement(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:                 testutil.Logger{},
+		URLs:                []string{ts.URL},
+		MetricVersion:       2,
+		FamilyMeasurement:   true,
+		EnableScrapeMetrics: true,
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+
+	names := make(map[string]string)
+	var quantiles int
+	for _, m := range acc.GetTelegrafMetrics() {
+		for _, field := range m.FieldList() {
+			names[field.Key] = m.Name()
+		}
+		if m.HasTag("quantile") {
+			quantiles++
+		}
+	}
+	require.Equal(t, map[string]string{
+		"go_gc_duration_seconds":       "go_gc_duration_seconds",
+		"go_gc_duration_seconds_sum":   "go_gc_duration_seconds",
+		"go_gc_duration_seconds_count": "go_gc_duration_seconds",
+		"go_goroutines":                "go_goroutines",
+		"test_metric":                  "test_metric",
+		"up":                           "up",
+		"scrape_duration_seconds":      "scrape_duration_seconds",
+		"scrape_samples_scraped":       "scrape_samples_scraped",
+	}, names)
+	require.Equal(t, 5, quantiles)
+	require.True(t, acc.HasTag("test_metric", "label"))
+
+	p = &Prometheus{Log: testutil.Logger{}, MetricVersion: 1, FamilyMeasurement: true}
+	require.EqualError(t, p.Init(), "family_measurement requires metric_version = 2")
+}
+
 func TestPrometheusGeneratesGaugeMetricsV2(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, err := fmt.Fprintln(w, sampleGaugeTextFormat)
@@ -242,6 +291,36 @@ func TestPrometheusGeneratesGaugeMetricsV2(t *testing.T) {
 	assert.True(t, acc.HasTimestamp("prometheus", time.Unix(1490802350, 0)))
 }
 
+func TestPrometheusIgnoreTimestamp(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		URLs:                []string{ts.URL},
+		MetricVersion:       2,
+		IgnoreTimestamp:     true,
+		ScrapeTimeField:     "scrape_time",
+		ScrapeTimeTag:       "scrape_time",
+		ScrapeTimePrecision: config.Duration(time.Second),
+	}
+
+	var acc testutil.Accumulator
+	before := time.Now()
+	require.NoError(t, acc.GatherError(p.Gather))
+
+	m, ok := acc.Get("prometheus")
+	require.True(t, ok)
+	require.False(t, m.Time.Before(before))
+
+	scrapeTime, ok := m.Fields["scrape_time"].(int64)
+	require.True(t, ok)
+	require.Equal(t, m.Time.Unix(), scrapeTime)
+	require.Equal(t, strconv.FormatInt(scrapeTime, 10), m.Tags["scrape_time"])
+}
+
 func TestUnsupportedFieldSelector(t *testing.T) {
 	fieldSelectorString := "spec.containerName=container"
 	prom := &Prometheus{Log: testutil.Logger{}, KubernetesFieldSelector: fieldSelectorString}
@@ -288,3 +367,305 @@ func TestInitConfigErrors(t *testing.T) {
 	expectedMessage = "the field selector spec.containerNames is not supported for pods"
 	require.Error(t, err, expectedMessage)
 }
+
+func TestInitClusterScopeSelectors(t *testing.T) {
+	p := &Prometheus{
+		MetricVersion: 2,
+		Log:           testutil.Logger{},
+		URLTag:        "url",
+		MonitorPods:   true,
+	}
+
+	p.KubernetesLabelSelector = "label0==label0, label0 in (=)"
+	require.Error(t, p.Init())
+	p.KubernetesLabelSelector = "app=nginx"
+
+	p.KubernetesFieldSelector = "field,"
+	require.Error(t, p.Init())
+
+	// The api server supports more fields than matched locally
+	p.KubernetesFieldSelector = "metadata.name=nginx"
+	require.NoError(t, p.Init())
+	require.Equal(t, "app=nginx", p.podLabelSelector.String())
+	require.Equal(t, "metadata.name=nginx", p.podFieldSelector.String())
+}
+
+func TestInitNodeScopeNodeName(t *testing.T) {
+	value, ok := os.LookupEnv("NODE_NAME")
+	if ok {
+		defer os.Setenv("NODE_NAME", value)
+	} else {
+		defer os.Unsetenv("NODE_NAME")
+	}
+	require.NoError(t, os.Setenv("NODE_NAME", "node-1"))
+
+	// The pods of the node are watched without the node IP
+	p := &Prometheus{
+		Log:                     testutil.Logger{},
+		MonitorPods:             true,
+		PodScrapeScope:          "node",
+		KubernetesFieldSelector: "metadata.name=nginx",
+	}
+	require.NoError(t, p.Init())
+	require.Equal(t, "node-1", p.NodeName)
+	require.False(t, p.pollsKubelet())
+	require.Equal(t, "metadata.name=nginx,spec.nodeName=node-1", p.watchFieldSelector())
+
+	p = &Prometheus{
+		Log:            testutil.Logger{},
+		MonitorPods:    true,
+		PodScrapeScope: "node",
+		NodeName:       "node-2",
+	}
+	require.NoError(t, p.Init())
+	require.Equal(t, "spec.nodeName=node-2", p.watchFieldSelector())
+
+	// The cluster scope ignores the node name
+	p = &Prometheus{
+		Log:         testutil.Logger{},
+		MonitorPods: true,
+	}
+	require.NoError(t, p.Init())
+	require.Equal(t, "", p.watchFieldSelector())
+}
+
+func TestPrometheusTargetInterval(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log: testutil.Logger{},
+		Targets: []Target{
+			{URL: ts.URL, Interval: config.Duration(time.Hour)},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 1, requests)
+	require.True(t, acc.HasFloatField("go_goroutines", "gauge"))
+
+	// The interval elapsed
+	for key := range p.lastScrape {
+		p.lastScrape[key] = p.lastScrape[key].Add(-time.Hour)
+	}
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 2, requests)
+}
+
+func TestPrometheusScrapeOffsetSpread(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}}
+	require.Equal(t, time.Duration(0), p.scrapeOffset("http://localhost:9100/metrics"))
+
+	p.ScrapeOffsetSpread = config.Duration(time.Minute)
+	offset := p.scrapeOffset("http://localhost:9100/metrics")
+	require.True(t, offset >= 0 && offset < time.Minute)
+	require.Equal(t, offset, p.scrapeOffset("http://localhost:9100/metrics"))
+	require.NotEqual(t, offset, p.scrapeOffset("http://localhost:9101/metrics"))
+
+	p.ScrapeOffsetSpread = config.Duration(-time.Second)
+	require.EqualError(t, p.Init(), "scrape_offset_spread must not be negative")
+
+	// The gather waits for the delayed scrapes
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p = &Prometheus{
+		Log:                testutil.Logger{},
+		URLs:               []string{ts.URL + "/a", ts.URL + "/b"},
+		URLTag:             "url",
+		ScrapeOffsetSpread: config.Duration(50 * time.Millisecond),
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Len(t, acc.Metrics, 2)
+}
+
+func TestPrometheusTargetInvalid(t *testing.T) {
+	p := &Prometheus{
+		Log:     testutil.Logger{},
+		Targets: []Target{{Interval: config.Duration(time.Minute)}},
+	}
+	require.EqualError(t, p.Init(), "url of target 1 must not be empty")
+}
+
+func TestPrometheusProxy(t *testing.T) {
+	var proxied []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests to a proxy carry the absolute url of the target
+		proxied = append(proxied, r.URL.String())
+		_, err := fmt.Fprint(w, sampleTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:       testutil.Logger{},
+		URLs:      []string{"http://exporter.dmz:9100/metrics"},
+		HTTPProxy: proxy.HTTPProxy{HTTPProxyURL: ts.URL},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, []string{"http://exporter.dmz:9100/metrics"}, proxied)
+	require.True(t, acc.HasFloatField("go_goroutines", "gauge"))
+
+	p.HTTPProxyURL = "ftp://proxy:21"
+	require.EqualError(t, p.Init(), `http_proxy_url "ftp://proxy:21" must be an http, https or socks5 url`)
+}
+
+func TestPrometheusScrapeMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		URLs:                []string{ts.URL + "/metrics", ts.URL + "/fail"},
+		URLTag:              "url",
+		MetricVersion:       2,
+		EnableScrapeMetrics: true,
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"url": ts.URL + "/fail"},
+			map[string]interface{}{
+				"up":                      0.0,
+				"scrape_duration_seconds": 0.0,
+				"scrape_samples_scraped":  0.0,
+			},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"url": ts.URL + "/metrics"},
+			map[string]interface{}{
+				"up":                      1.0,
+				"scrape_duration_seconds": 0.0,
+				"scrape_samples_scraped":  1.0,
+			},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if _, ok := m.GetField("up"); ok {
+			require.Greater(t, m.Fields()["scrape_duration_seconds"], 0.0)
+			m.AddField("scrape_duration_seconds", 0.0)
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// Version 1 adds a gauge per metric
+	p = &Prometheus{
+		URLs:                []string{ts.URL + "/metrics"},
+		EnableScrapeMetrics: true,
+	}
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	require.True(t, acc.HasPoint("up", map[string]string{}, "gauge", 1.0))
+	require.True(t, acc.HasPoint("scrape_samples_scraped", map[string]string{}, "gauge", 1.0))
+	require.True(t, acc.HasFloatField("scrape_duration_seconds", "gauge"))
+}
+
+func TestPrometheusScrapeStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, err := fmt.Fprint(w, sampleTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	okURL, err := url.Parse(ts.URL + "/metrics")
+	require.NoError(t, err)
+	failURL, err := url.Parse(ts.URL + "/fail")
+	require.NoError(t, err)
+
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		ScrapeStats: true,
+		kubernetesPods: map[string]URLAndAddress{
+			okURL.String(): {
+				URL:         okURL,
+				OriginalURL: okURL,
+				Tags:        map[string]string{"namespace": "default", "pod_name": "ok"},
+			},
+			failURL.String(): {
+				URL:         failURL,
+				OriginalURL: failURL,
+				Tags:        map[string]string{"namespace": "default", "pod_name": "fail"},
+			},
+		},
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus_discovery",
+			map[string]string{"namespace": "default"},
+			map[string]interface{}{"pods": int64(2), "failing_pods": int64(1)},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"prometheus_scrape",
+			map[string]string{"url": failURL.String(), "namespace": "default", "pod_name": "fail"},
+			map[string]interface{}{
+				"successes":  int64(0),
+				"failures":   int64(2),
+				"last_error": failURL.String() + " returned HTTP status 500 Internal Server Error",
+			},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"prometheus_scrape",
+			map[string]string{"url": okURL.String(), "namespace": "default", "pod_name": "ok"},
+			map[string]interface{}{"successes": int64(2), "failures": int64(0), "last_error": ""},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "prometheus_scrape" || m.Name() == "prometheus_discovery" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// Statistics of removed pods are dropped
+	delete(p.kubernetesPods, failURL.String())
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, p.stats, 1)
+}
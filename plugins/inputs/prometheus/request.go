@@ -0,0 +1,111 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+// checkMethod returns an error if the scrape requests can't use the method.
+func checkMethod(method string) error {
+	switch method {
+	case "", http.MethodGet, http.MethodPost:
+		return nil
+	}
+	return fmt.Errorf("method %q not supported, use GET or POST", method)
+}
+
+// checkAuth returns an error if the OAuth2 client credentials are combined
+// with other authorization methods.
+func (p *Prometheus) checkAuth() error {
+	if p.ClientID == "" && p.ClientSecret == "" && p.TokenURL == "" {
+		return nil
+	}
+	if p.ClientID == "" || p.ClientSecret == "" || p.TokenURL == "" {
+		return errors.New("client_id, client_secret and token_url are required for oauth2")
+	}
+	if p.BearerToken != "" || p.BearerTokenString != "" || p.Username != "" || p.Password != "" {
+		return errors.New("oauth2 can't be combined with bearer_token, bearer_token_string, username or password")
+	}
+	return nil
+}
+
+// newRequest creates the scrape request of the url, with the method, body and
+// headers of the url taking precedence over the ones of the plugin.
+func (p *Prometheus) newRequest(u URLAndAddress, addr string) (*http.Request, error) {
+	method := u.Method
+	if method == "" {
+		method = p.Method
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+	body := u.Body
+	if body == "" {
+		body = p.Body
+	}
+
+	req, err := http.NewRequest(method, addr, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new request '%s': %s", addr, err)
+	}
+
+	p.addHeaders(req)
+	setHeaders(req, p.HTTPHeaders)
+	setHeaders(req, u.Headers)
+	return req, nil
+}
+
+func setHeaders(req *http.Request, headers map[string]string) {
+	for k, v := range headers {
+		if strings.ToLower(k) == "host" {
+			req.Host = v
+		} else {
+			req.Header.Set(k, v)
+		}
+	}
+}
+
+// bodyReader returns a reader of the decompressed body of the response, which
+// must be closed to release the decoder.  Reading beyond max_body_size fails,
+// so misbehaving exporters can't keep the agent busy indefinitely.
+func (p *Prometheus) bodyReader(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(resp.Header.Get("Content-Encoding"))
+	if encoding == "deflate" {
+		// The deflate coding of http is the zlib format
+		encoding = "zlib"
+	}
+	r, err := internal.NewStreamContentDecoder(encoding, resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding content encoding %q failed: %w", encoding, err)
+	}
+
+	if p.MaxBodySize == 0 {
+		return r, nil
+	}
+	return &limitedReader{r: r, limit: int64(p.MaxBodySize), remaining: int64(p.MaxBodySize)}, nil
+}
+
+// limitedReader fails once more than limit bytes are read.
+type limitedReader struct {
+	r         io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	n, err := l.r.Read(b)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("body exceeds max_body_size of %d bytes", l.limit)
+	}
+	return n, err
+}
+
+func (l *limitedReader) Close() error {
+	return l.r.Close()
+}
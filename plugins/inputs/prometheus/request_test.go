@@ -0,0 +1,273 @@
+package prometheus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/plugin":
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		case "/target":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "query", string(body))
+			require.Equal(t, "other", r.Header.Get("X-Api-Key"))
+			require.Equal(t, "metrics.example.org", r.Host)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err = fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		URLs:        []string{ts.URL + "/plugin"},
+		HTTPHeaders: map[string]string{"X-Api-Key": "secret"},
+		Targets: []Target{
+			{
+				URL:    ts.URL + "/target",
+				Method: http.MethodPost,
+				Body:   "query",
+				HTTPHeaders: map[string]string{
+					"X-Api-Key": "other",
+					"Host":      "metrics.example.org",
+				},
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 2, int(acc.NMetrics()))
+}
+
+func TestPrometheusRequestInvalidMethod(t *testing.T) {
+	p := &Prometheus{
+		Log:    testutil.Logger{},
+		Method: "PUT",
+	}
+	require.EqualError(t, p.Init(), `method "PUT" not supported, use GET or POST`)
+
+	p = &Prometheus{
+		Log:     testutil.Logger{},
+		Targets: []Target{{URL: "http://localhost:9090/metrics", Method: "DELETE"}},
+	}
+	require.EqualError(t, p.Init(), `target 1: method "DELETE" not supported, use GET or POST`)
+}
+
+func TestPrometheusCompressedBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "gzip, deflate", r.Header.Get("Accept-Encoding"))
+
+		var buf bytes.Buffer
+		var wc io.WriteCloser
+		switch r.URL.Path {
+		case "/gzip":
+			wc = gzip.NewWriter(&buf)
+		case "/deflate":
+			wc = zlib.NewWriter(&buf)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := fmt.Fprint(wc, sampleGaugeTextFormat)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		w.Header().Set("Content-Encoding", r.URL.Path[1:])
+		_, err = w.Write(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:  testutil.Logger{},
+		URLs: []string{ts.URL + "/gzip", ts.URL + "/deflate"},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 2, int(acc.NMetrics()))
+	require.True(t, acc.HasFloatField("go_goroutines", "gauge"))
+}
+
+func TestPrometheusMaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		URLs:        []string{ts.URL},
+		MaxBodySize: config.Size(len(sampleGaugeTextFormat) - 1),
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	err := acc.GatherError(p.Gather)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("body exceeds max_body_size of %d bytes", len(sampleGaugeTextFormat)-1))
+	require.Equal(t, 0, int(acc.NMetrics()))
+
+	// The body fits exactly
+	p.MaxBodySize = config.Size(len(sampleGaugeTextFormat))
+	acc = testutil.Accumulator{}
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 1, int(acc.NMetrics()))
+
+	// The size is unlimited by default
+	require.Zero(t, inputs.Inputs["prometheus"]().(*Prometheus).MaxBodySize)
+}
+
+func TestPrometheusReuseConnections(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+		require.NoError(t, err)
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	for _, tt := range []struct {
+		reuse bool
+		conns int32
+	}{
+		{reuse: false, conns: 3},
+		{reuse: true, conns: 1},
+	} {
+		atomic.StoreInt32(&conns, 0)
+		p := &Prometheus{
+			Log:              testutil.Logger{},
+			URLs:             []string{ts.URL},
+			ReuseConnections: tt.reuse,
+		}
+		require.NoError(t, p.Init())
+
+		var acc testutil.Accumulator
+		for i := 0; i < 3; i++ {
+			require.NoError(t, acc.GatherError(p.Gather))
+		}
+		p.Stop()
+		require.Equal(t, tt.conns, atomic.LoadInt32(&conns))
+	}
+}
+
+func TestPrometheusStreamsFamilies(t *testing.T) {
+	var acc testutil.Accumulator
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A family is complete once the first line of the next one is read
+		next := strings.SplitAfterN(sampleSummaryTextFormat, "\n", 2)
+		_, err := fmt.Fprint(w, sampleGaugeTextFormat, next[0])
+		require.NoError(t, err)
+		w.(http.Flusher).Flush()
+
+		// The first family is added before the response is complete
+		acc.Wait(1)
+		_, err = fmt.Fprint(w, next[1])
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:  testutil.Logger{},
+		URLs: []string{ts.URL},
+	}
+	require.NoError(t, p.Init())
+
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Equal(t, 2, int(acc.NMetrics()))
+}
+
+func TestPrometheusOAuth2(t *testing.T) {
+	var tokens int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+			require.Equal(t, "metrics:read", r.Form.Get("scope"))
+			atomic.AddInt32(&tokens, 1)
+			w.Header().Set("Content-Type", "application/json")
+			_, err := fmt.Fprint(w, `{"access_token":"token-1","token_type":"bearer","expires_in":3600}`)
+			require.NoError(t, err)
+		case "/metrics":
+			if r.Header.Get("Authorization") != "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, err := fmt.Fprint(w, sampleGaugeTextFormat)
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:  testutil.Logger{},
+		URLs: []string{ts.URL + "/metrics"},
+	}
+	p.ClientID = "client"
+	p.ClientSecret = "secret"
+	p.TokenURL = ts.URL + "/token"
+	p.Scopes = []string{"metrics:read"}
+	require.NoError(t, p.Init())
+
+	// The token is reused until it expires
+	var acc testutil.Accumulator
+	for i := 0; i < 2; i++ {
+		require.NoError(t, acc.GatherError(p.Gather))
+	}
+	require.Equal(t, 2, int(acc.NMetrics()))
+	require.Equal(t, int32(1), atomic.LoadInt32(&tokens))
+}
+
+func TestPrometheusOAuth2Invalid(t *testing.T) {
+	p := &Prometheus{
+		Log: testutil.Logger{},
+	}
+	p.ClientID = "client"
+	require.EqualError(t, p.Init(), "client_id, client_secret and token_url are required for oauth2")
+
+	p = &Prometheus{
+		Log:               testutil.Logger{},
+		BearerTokenString: "abc_123",
+	}
+	p.ClientID = "client"
+	p.ClientSecret = "secret"
+	p.TokenURL = "https://identityprovider/oauth2/v1/token"
+	require.EqualError(t, p.Init(), "oauth2 can't be combined with bearer_token, bearer_token_string, username or password")
+}
@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// series identifies a metric reported by a target.
+type series struct {
+	name string
+	tags map[string]string
+}
+
+// seriesRecorder is an accumulator recording the series of a scrape.
+type seriesRecorder struct {
+	telegraf.Accumulator
+	series map[string]series
+}
+
+func newSeriesRecorder(acc telegraf.Accumulator) *seriesRecorder {
+	return &seriesRecorder{
+		Accumulator: acc,
+		series:      make(map[string]series),
+	}
+}
+
+// seriesID identifies the series of the name and tags.
+func seriesID(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var id strings.Builder
+	id.WriteString(name)
+	for _, k := range keys {
+		id.WriteString("\x00" + k + "=" + tags[k])
+	}
+	return id.String()
+}
+
+func (r *seriesRecorder) record(name string, tags map[string]string) {
+	id := seriesID(name, tags)
+	if _, ok := r.series[id]; ok {
+		return
+	}
+	copied := make(map[string]string, len(tags))
+	for k, v := range tags {
+		copied[k] = v
+	}
+	r.series[id] = series{name: name, tags: copied}
+}
+
+func (r *seriesRecorder) AddFields(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	r.record(name, tags)
+	r.Accumulator.AddFields(name, fields, tags, t...)
+}
+
+func (r *seriesRecorder) AddGauge(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	r.record(name, tags)
+	r.Accumulator.AddGauge(name, fields, tags, t...)
+}
+
+func (r *seriesRecorder) AddCounter(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	r.record(name, tags)
+	r.Accumulator.AddCounter(name, fields, tags, t...)
+}
+
+func (r *seriesRecorder) AddSummary(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	r.record(name, tags)
+	r.Accumulator.AddSummary(name, fields, tags, t...)
+}
+
+func (r *seriesRecorder) AddHistogram(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	r.record(name, tags)
+	r.Accumulator.AddHistogram(name, fields, tags, t...)
+}
+
+// updateSeries replaces the series of the target with the series of the
+// latest scrape and adds a stale marker for every series which disappeared.
+// A failed scrape marks all series of the target as stale.
+func (p *Prometheus) updateSeries(key string, recorder *seriesRecorder, err error, acc telegraf.Accumulator) {
+	current := recorder.series
+	if err != nil {
+		current = map[string]series{}
+	}
+
+	p.seriesLock.Lock()
+	previous := p.series[key]
+	if p.series == nil {
+		p.series = make(map[string]map[string]series)
+	}
+	p.series[key] = current
+	p.seriesLock.Unlock()
+
+	p.addStaleMarkers(previous, current, acc)
+}
+
+// expireSeries marks all series of targets no longer scraped as stale.
+func (p *Prometheus) expireSeries(targets map[string]URLAndAddress, acc telegraf.Accumulator) {
+	p.seriesLock.Lock()
+	defer p.seriesLock.Unlock()
+
+	for key, previous := range p.series {
+		if _, ok := targets[key]; ok {
+			continue
+		}
+		p.addStaleMarkers(previous, nil, acc)
+		delete(p.series, key)
+	}
+}
+
+func (p *Prometheus) addStaleMarkers(previous, current map[string]series, acc telegraf.Accumulator) {
+	now := time.Now()
+	for id, s := range previous {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		acc.AddFields(s.name, map[string]interface{}{p.StaleMarkerField: true}, s.tags, now)
+	}
+}
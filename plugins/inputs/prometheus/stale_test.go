@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleMarkers(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, StaleMarkerField: "stale"}
+	var acc testutil.Accumulator
+
+	recorder := newSeriesRecorder(&acc)
+	recorder.AddGauge("cpu", map[string]interface{}{"value": 1.0}, map[string]string{"cpu": "0"})
+	recorder.AddGauge("cpu", map[string]interface{}{"value": 2.0}, map[string]string{"cpu": "1"})
+	p.updateSeries("target", recorder, nil, &acc)
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+
+	// A series disappearing from the scrape is stale
+	acc.ClearMetrics()
+	recorder = newSeriesRecorder(&acc)
+	recorder.AddGauge("cpu", map[string]interface{}{"value": 1.0}, map[string]string{"cpu": "0"})
+	p.updateSeries("target", recorder, nil, &acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"cpu": "0"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0), telegraf.Gauge),
+		testutil.MustMetric("cpu", map[string]string{"cpu": "1"}, map[string]interface{}{"stale": true}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// A failed scrape marks all series stale
+	acc.ClearMetrics()
+	p.updateSeries("target", newSeriesRecorder(&acc), errors.New("connection refused"), &acc)
+	expected = []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"cpu": "0"}, map[string]interface{}{"stale": true}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestStaleMarkersExpiredTarget(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, StaleMarkerField: "stale"}
+	var acc testutil.Accumulator
+
+	recorder := newSeriesRecorder(&acc)
+	recorder.AddCounter("requests", map[string]interface{}{"counter": 42.0}, map[string]string{"url": "http://a"})
+	p.updateSeries("http://a", recorder, nil, &acc)
+
+	acc.ClearMetrics()
+	p.expireSeries(map[string]URLAndAddress{}, &acc)
+	expected := []telegraf.Metric{
+		testutil.MustMetric("requests", map[string]string{"url": "http://a"}, map[string]interface{}{"stale": true}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+	require.Empty(t, p.series)
+}
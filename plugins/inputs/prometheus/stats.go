@@ -0,0 +1,150 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// targetStats are the scrape statistics of a single target.
+type targetStats struct {
+	target    URLAndAddress
+	successes int64
+	failures  int64
+	lastError string
+}
+
+// recordScrape updates the statistics of the target with the result of a
+// scrape.
+func (p *Prometheus) recordScrape(key string, u URLAndAddress, err error) {
+	p.statsLock.Lock()
+	defer p.statsLock.Unlock()
+
+	if p.stats == nil {
+		p.stats = make(map[string]*targetStats)
+	}
+	stats, ok := p.stats[key]
+	if !ok {
+		stats = &targetStats{}
+		p.stats[key] = stats
+	}
+	stats.target = u
+	if err != nil {
+		stats.failures++
+		stats.lastError = err.Error()
+		return
+	}
+	stats.successes++
+	stats.lastError = ""
+}
+
+// gatherStats adds the scrape statistics of all current targets and the
+// number of discovered pods per namespace.  Targets which are no longer
+// scraped, e.g. deleted pods, are forgotten.
+func (p *Prometheus) gatherStats(acc telegraf.Accumulator, targets map[string]URLAndAddress) {
+	p.statsLock.Lock()
+	defer p.statsLock.Unlock()
+
+	now := time.Now()
+	pods := make(map[string]int64)
+	failing := make(map[string]int64)
+	for key, stats := range p.stats {
+		if _, ok := targets[key]; !ok {
+			delete(p.stats, key)
+			continue
+		}
+
+		u := *stats.target.URL
+		u.User = nil
+		tags := map[string]string{"url": u.String()}
+		if stats.target.Address != "" {
+			tags["address"] = stats.target.Address
+		}
+		namespace, isPod := stats.target.Tags["namespace"]
+		if isPod {
+			tags["namespace"] = namespace
+			tags["pod_name"] = stats.target.Tags["pod_name"]
+			pods[namespace]++
+			if stats.lastError != "" {
+				failing[namespace]++
+			}
+		}
+
+		fields := map[string]interface{}{
+			"successes":  stats.successes,
+			"failures":   stats.failures,
+			"last_error": stats.lastError,
+		}
+		acc.AddFields("prometheus_scrape", fields, tags, now)
+	}
+
+	for namespace, count := range pods {
+		fields := map[string]interface{}{
+			"pods":         count,
+			"failing_pods": failing[namespace],
+		}
+		acc.AddFields("prometheus_discovery", fields, map[string]string{"namespace": namespace}, now)
+	}
+}
+
+// sampleCounter is an accumulator counting the samples of a scrape, every
+// field being a sample.
+type sampleCounter struct {
+	telegraf.Accumulator
+	samples int64
+}
+
+func (c *sampleCounter) AddFields(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	c.samples += int64(len(fields))
+	c.Accumulator.AddFields(name, fields, tags, t...)
+}
+
+func (c *sampleCounter) AddGauge(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	c.samples += int64(len(fields))
+	c.Accumulator.AddGauge(name, fields, tags, t...)
+}
+
+func (c *sampleCounter) AddCounter(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	c.samples += int64(len(fields))
+	c.Accumulator.AddCounter(name, fields, tags, t...)
+}
+
+func (c *sampleCounter) AddSummary(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	c.samples += int64(len(fields))
+	c.Accumulator.AddSummary(name, fields, tags, t...)
+}
+
+func (c *sampleCounter) AddHistogram(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	c.samples += int64(len(fields))
+	c.Accumulator.AddHistogram(name, fields, tags, t...)
+}
+
+// addScrapeMetrics adds the synthetic metrics Prometheus records for every
+// scrape, stamped with the start of the scrape and tagged like the scraped
+// metrics.
+func (p *Prometheus) addScrapeMetrics(u URLAndAddress, start time.Time, samples int64, err error, acc telegraf.Accumulator) {
+	up := 1.0
+	if err != nil {
+		up = 0.0
+	}
+	fields := map[string]interface{}{
+		"up":                      up,
+		"scrape_duration_seconds": time.Since(start).Seconds(),
+		"scrape_samples_scraped":  float64(samples),
+	}
+
+	var metrics []telegraf.Metric
+	if p.MetricVersion == 2 && !p.FamilyMeasurement {
+		metrics = append(metrics, metric.New("prometheus", map[string]string{}, fields, start, telegraf.Gauge))
+	} else if p.MetricVersion == 2 {
+		for name, value := range fields {
+			metrics = append(metrics, metric.New(name, map[string]string{}, map[string]interface{}{name: value}, start, telegraf.Gauge))
+		}
+	} else {
+		for name, value := range fields {
+			metrics = append(metrics, metric.New(name, map[string]string{}, map[string]interface{}{"gauge": value}, start, telegraf.Gauge))
+		}
+	}
+	p.addMetrics(u, metrics, acc)
+}
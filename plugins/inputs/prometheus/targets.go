@@ -0,0 +1,223 @@
+package prometheus
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+)
+
+// maxTargetRange limits the number of urls a target expands to.
+const maxTargetRange = 10000
+
+// Target is an url scraped at its own interval instead of on every gather.
+// The url and tags are templates, expanded once per index of the range.
+type Target struct {
+	URL         string              `toml:"url"`
+	Interval    config.Duration     `toml:"interval"`
+	Params      map[string][]string `toml:"params"`
+	Method      string              `toml:"method"`
+	Body        string              `toml:"body"`
+	HTTPHeaders map[string]string   `toml:"http_headers"`
+	Range       string              `toml:"range"`
+	Tags        map[string]string   `toml:"tags"`
+
+	expanded []expandedTarget
+}
+
+// expandedTarget is an url of a target with its tags.
+type expandedTarget struct {
+	url  *url.URL
+	tags map[string]string
+}
+
+// targetData is passed to the url and tag templates of a target.  The tag
+// templates only see the index.
+type targetData struct {
+	Index string
+	Tags  map[string]string
+}
+
+// targetFuncs interpolate the environment and secrets into the templates.
+var targetFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"file": func(path string) (string, error) {
+		content, err := ioutil.ReadFile(path)
+		return strings.TrimSpace(string(content)), err
+	},
+}
+
+func (p *Prometheus) initTargets() error {
+	for i := range p.Targets {
+		t := &p.Targets[i]
+		if t.URL == "" {
+			return fmt.Errorf("url of target %d must not be empty", i+1)
+		}
+		if err := checkMethod(t.Method); err != nil {
+			return fmt.Errorf("target %d: %w", i+1, err)
+		}
+		expanded, err := t.expand()
+		if err != nil {
+			return fmt.Errorf("target %d: %w", i+1, err)
+		}
+		t.expanded = expanded
+	}
+	return nil
+}
+
+// expand renders the url and tags of the target for every index of its
+// range, or once without a range.
+func (t *Target) expand() ([]expandedTarget, error) {
+	indexes, err := parseTargetRange(t.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	urlTemplate, err := template.New("url").Funcs(targetFuncs).Option("missingkey=zero").Parse(t.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url template failed: %w", err)
+	}
+	tagTemplates := make(map[string]*template.Template, len(t.Tags))
+	for key, value := range t.Tags {
+		tmpl, err := template.New(key).Funcs(targetFuncs).Option("missingkey=zero").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template of tag %q failed: %w", key, err)
+		}
+		tagTemplates[key] = tmpl
+	}
+
+	expanded := make([]expandedTarget, 0, len(indexes))
+	for _, index := range indexes {
+		data := targetData{Index: index}
+		tags := make(map[string]string, len(tagTemplates))
+		for key, tmpl := range tagTemplates {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				return nil, fmt.Errorf("rendering tag %q failed: %w", key, err)
+			}
+			tags[key] = b.String()
+		}
+
+		data.Tags = tags
+		var b strings.Builder
+		if err := urlTemplate.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("rendering url failed: %w", err)
+		}
+		u, err := url.Parse(b.String())
+		if err != nil {
+			return nil, fmt.Errorf("parsing url failed: %w", err)
+		}
+
+		if len(tags) == 0 {
+			tags = nil
+		}
+		expanded = append(expanded, expandedTarget{url: addParams(u, t.Params), tags: tags})
+	}
+	return expanded, nil
+}
+
+// parseTargetRange returns the indexes of a range of the form "start..end".
+// A start with leading zeros pads all indexes to its width, e.g. "001..300".
+func parseTargetRange(r string) ([]string, error) {
+	if r == "" {
+		return []string{""}, nil
+	}
+
+	bounds := strings.Split(r, "..")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("range %q must be of the form start..end", r)
+	}
+	start, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start of range %q: %w", r, err)
+	}
+	end, err := strconv.Atoi(bounds[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid end of range %q: %w", r, err)
+	}
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("range %q must not be negative or decreasing", r)
+	}
+	if end-start >= maxTargetRange {
+		return nil, fmt.Errorf("range %q exceeds %d urls", r, maxTargetRange)
+	}
+
+	width := 0
+	if len(bounds[0]) > 1 && bounds[0][0] == '0' {
+		width = len(bounds[0])
+	}
+	indexes := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		indexes = append(indexes, fmt.Sprintf("%0*d", width, i))
+	}
+	return indexes, nil
+}
+
+func (p *Prometheus) targetURLs() map[string]URLAndAddress {
+	urls := make(map[string]URLAndAddress, len(p.Targets))
+	for _, t := range p.Targets {
+		expanded := t.expanded
+		if expanded == nil {
+			// Init was not called
+			var err error
+			if expanded, err = t.expand(); err != nil {
+				p.Log.Errorf("Could not expand %q, skipping it. Error: %s", t.URL, err.Error())
+				continue
+			}
+		}
+		for _, e := range expanded {
+			// Copy the url, scraping sets the default path on it
+			u := *e.url
+			urls[u.String()] = URLAndAddress{
+				URL:         &u,
+				OriginalURL: &u,
+				Tags:        e.tags,
+				Interval:    time.Duration(t.Interval),
+				Method:      t.Method,
+				Body:        t.Body,
+				Headers:     t.HTTPHeaders,
+			}
+		}
+	}
+	return urls
+}
+
+// scrapeDue reports whether the url is due to be scraped.  Urls with their
+// own interval are scraped on the first gather after the interval elapsed,
+// with half the time between gathers as tolerance for their jitter.
+func (p *Prometheus) scrapeDue(key string, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return true
+	}
+	if last, ok := p.lastScrape[key]; ok {
+		tolerance := now.Sub(p.lastGather) / 2
+		if now.Sub(last)+tolerance < interval {
+			return false
+		}
+	}
+	if p.lastScrape == nil {
+		p.lastScrape = make(map[string]time.Time)
+	}
+	p.lastScrape[key] = now
+	return true
+}
+
+// scrapeOffset returns the delay of the scrape of the url within the gather,
+// spreading the scrapes of all urls evenly over the scrape_offset_spread.
+// The offset is hashed from the url so it stays the same between gathers.
+func (p *Prometheus) scrapeOffset(key string) time.Duration {
+	spread := time.Duration(p.ScrapeOffsetSpread)
+	if spread <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return time.Duration(h.Sum64() % uint64(spread))
+}
@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetTemplates(t *testing.T) {
+	require.NoError(t, os.Setenv("TELEGRAF_TEST_DOMAIN", "example.org"))
+	defer os.Unsetenv("TELEGRAF_TEST_DOMAIN")
+
+	dir, err := ioutil.TempDir("", "prometheus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	secret := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(secret, []byte("s3cret\n"), 0600))
+
+	p := &Prometheus{
+		Log: testutil.Logger{},
+		Targets: []Target{
+			{
+				URL:   `http://{{.Tags.host}}.{{env "TELEGRAF_TEST_DOMAIN"}}:9100/metrics?token={{file "` + secret + `"}}`,
+				Range: "008..011",
+				Tags:  map[string]string{"host": "host{{.Index}}"},
+			},
+			{
+				URL: "http://localhost:9090/metrics",
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	urls := p.targetURLs()
+	require.Len(t, urls, 5)
+	for _, host := range []string{"host008", "host009", "host010", "host011"} {
+		u, ok := urls["http://"+host+".example.org:9100/metrics?token=s3cret"]
+		require.True(t, ok, host)
+		require.Equal(t, map[string]string{"host": host}, u.Tags)
+	}
+	u, ok := urls["http://localhost:9090/metrics"]
+	require.True(t, ok)
+	require.Nil(t, u.Tags)
+}
+
+func TestParseTargetRange(t *testing.T) {
+	indexes, err := parseTargetRange("")
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, indexes)
+
+	indexes, err = parseTargetRange("9..11")
+	require.NoError(t, err)
+	require.Equal(t, []string{"9", "10", "11"}, indexes)
+
+	indexes, err = parseTargetRange("0..2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1", "2"}, indexes)
+
+	for _, r := range []string{"1", "1..2..3", "a..2", "1..b", "3..1", "-1..1", "0..10000"} {
+		_, err := parseTargetRange(r)
+		require.Error(t, err, r)
+	}
+}
+
+func TestTargetTemplateInvalid(t *testing.T) {
+	p := &Prometheus{
+		Log:     testutil.Logger{},
+		Targets: []Target{{URL: "http://host{{.Index:9100/metrics", Range: "1..2"}},
+	}
+	require.Error(t, p.Init())
+
+	p.Targets = []Target{{URL: "http://localhost:9100/metrics", Range: "1-2"}}
+	require.EqualError(t, p.Init(), `target 1: range "1-2" must be of the form start..end`)
+}
@@ -0,0 +1,101 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UnixSocket is an exporter listening on a unix socket.
+type UnixSocket struct {
+	SocketPath  string            `toml:"socket_path"`
+	MetricsPath string            `toml:"metrics_path"`
+	HTTPHeaders map[string]string `toml:"http_headers"`
+}
+
+func (p *Prometheus) initUnixSockets() error {
+	for i := range p.UnixSockets {
+		s := &p.UnixSockets[i]
+		if s.SocketPath == "" {
+			return fmt.Errorf("socket_path of unix socket %d must not be empty", i+1)
+		}
+		if s.MetricsPath == "" {
+			s.MetricsPath = "/metrics"
+		}
+		if !strings.HasPrefix(s.MetricsPath, "/") {
+			return fmt.Errorf("metrics_path %q of unix socket %d must start with a slash", s.MetricsPath, i+1)
+		}
+	}
+	return nil
+}
+
+// unixSocketURLs returns the unix sockets as urls of the unix scheme, with
+// the metrics path as path parameter like the socket urls given in urls.
+func (p *Prometheus) unixSocketURLs() map[string]URLAndAddress {
+	urls := make(map[string]URLAndAddress, len(p.UnixSockets))
+	for _, s := range p.UnixSockets {
+		metricsPath := s.MetricsPath
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+		u := &url.URL{
+			Scheme:   "unix",
+			Path:     s.SocketPath,
+			RawQuery: url.Values{"path": []string{metricsPath}}.Encode(),
+		}
+		urls[u.String()] = URLAndAddress{
+			URL:         u,
+			OriginalURL: u,
+			Headers:     s.HTTPHeaders,
+		}
+	}
+	return urls
+}
+
+// unixClient returns the client connecting to the socket, created on first
+// use and kept for the following scrapes.
+func (p *Prometheus) unixClient(socketPath string) (*http.Client, error) {
+	p.unixLock.Lock()
+	defer p.unixLock.Unlock()
+
+	if client, ok := p.unixClients[socketPath]; ok {
+		return client, nil
+	}
+
+	tlsCfg, err := p.ClientConfig.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   tlsCfg,
+			DisableKeepAlives: !p.ReuseConnections,
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+		Timeout: time.Duration(p.ResponseTimeout),
+	}
+
+	if p.unixClients == nil {
+		p.unixClients = make(map[string]*http.Client)
+	}
+	p.unixClients[socketPath] = client
+	return client, nil
+}
+
+// closeUnixClients closes the idle connections to the sockets.
+func (p *Prometheus) closeUnixClients() {
+	p.unixLock.Lock()
+	defer p.unixLock.Unlock()
+
+	for _, client := range p.unixClients {
+		client.CloseIdleConnections()
+	}
+	p.unixClients = nil
+}
@@ -0,0 +1,73 @@
+package prometheus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSockets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not available on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "prometheus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "exporter.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom/metrics" || r.Header.Get("X-Exporter") != "node" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := fmt.Fprint(w, sampleTextFormat)
+		require.NoError(t, err)
+	})}
+	go server.Serve(listener) //nolint:errcheck // ends with the test
+	defer server.Close()
+
+	p := &Prometheus{
+		Log:    testutil.Logger{},
+		URLTag: "url",
+		UnixSockets: []UnixSocket{
+			{
+				SocketPath:  socket,
+				MetricsPath: "/custom/metrics",
+				HTTPHeaders: map[string]string{"X-Exporter": "node"},
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.True(t, acc.HasFloatField("go_goroutines", "gauge"))
+	require.True(t, acc.HasTag("go_goroutines", "url"))
+	m, _ := acc.Get("go_goroutines")
+	require.Equal(t, "unix://"+socket+"?path=%2Fcustom%2Fmetrics", m.Tags["url"])
+
+	// The client is kept for the following scrapes
+	client := p.unixClients[socket]
+	require.NotNil(t, client)
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.Same(t, client, p.unixClients[socket])
+}
+
+func TestUnixSocketsInvalid(t *testing.T) {
+	p := &Prometheus{Log: testutil.Logger{}, UnixSockets: []UnixSocket{{MetricsPath: "/metrics"}}}
+	require.EqualError(t, p.Init(), "socket_path of unix socket 1 must not be empty")
+
+	p.UnixSockets = []UnixSocket{{SocketPath: "/run/exporter.sock", MetricsPath: "metrics"}}
+	require.EqualError(t, p.Init(), `metrics_path "metrics" of unix socket 1 must start with a slash`)
+}
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// configuredURLs returns the urls of the configuration together with the
+// urls from the environment variable and the urls file.
+func (p *Prometheus) configuredURLs() []string {
+	urls := make([]string, 0, len(p.URLs))
+	urls = append(urls, p.URLs...)
+
+	if p.URLsFromEnv != "" {
+		urls = append(urls, splitURLs(os.Getenv(p.URLsFromEnv))...)
+	}
+
+	if p.URLsFromFile != "" {
+		if err := p.loadURLsFile(); err != nil {
+			p.Log.Errorf("Reading urls from %q failed, using the previous urls: %v", p.URLsFromFile, err)
+		}
+		urls = append(urls, p.fileURLs...)
+	}
+
+	return urls
+}
+
+// loadURLsFile reads the urls file if it changed since it was last read.
+func (p *Prometheus) loadURLsFile() error {
+	info, err := os.Stat(p.URLsFromFile)
+	if err != nil {
+		return err
+	}
+	if p.fileURLs != nil && info.ModTime().Equal(p.fileModTime) {
+		return nil
+	}
+
+	f, err := os.Open(p.URLsFromFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	urls := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, os.ExpandEnv(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.Log.Debugf("Read %d urls from %q", len(urls), p.URLsFromFile)
+	p.fileURLs = urls
+	p.fileModTime = info.ModTime()
+	return nil
+}
+
+// splitURLs splits a list of urls separated by commas or whitespace.
+func splitURLs(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfiguredURLsFromEnv(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_PROMETHEUS_URLS", "http://a:9100/metrics, http://b:9100/metrics\nhttp://c:9100/metrics"))
+	defer os.Unsetenv("TEST_PROMETHEUS_URLS")
+
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		URLs:        []string{"http://localhost:9100/metrics"},
+		URLsFromEnv: "TEST_PROMETHEUS_URLS",
+	}
+	require.Equal(t, []string{
+		"http://localhost:9100/metrics",
+		"http://a:9100/metrics",
+		"http://b:9100/metrics",
+		"http://c:9100/metrics",
+	}, p.configuredURLs())
+}
+
+func TestConfiguredURLsFromFile(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_PROMETHEUS_HOST", "example.org"))
+	defer os.Unsetenv("TEST_PROMETHEUS_HOST")
+
+	dir, err := ioutil.TempDir("", "prometheus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "urls.txt")
+	content := "# targets\nhttp://${TEST_PROMETHEUS_HOST}:9100/metrics\n\n  http://localhost:9273/metrics  \n"
+	require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+
+	p := &Prometheus{
+		Log:          testutil.Logger{},
+		URLsFromFile: filename,
+	}
+	require.Equal(t, []string{
+		"http://example.org:9100/metrics",
+		"http://localhost:9273/metrics",
+	}, p.configuredURLs())
+
+	// The file is read again after it changed
+	require.NoError(t, ioutil.WriteFile(filename, []byte("http://other:9100/metrics\n"), 0644))
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	require.Equal(t, []string{"http://other:9100/metrics"}, p.configuredURLs())
+
+	// The previous urls are kept if the file cannot be read
+	require.NoError(t, os.Remove(filename))
+	require.Equal(t, []string{"http://other:9100/metrics"}, p.configuredURLs())
+}
@@ -0,0 +1,352 @@
+package prometheus_remote_write
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+// defaultMaxBodySize is the default maximum size of the compressed request
+// body, in bytes.  Larger requests are rejected with an HTTP 413 error.
+const defaultMaxBodySize = 32 * 1024 * 1024
+
+// defaultMaxDecompressedSize is the default maximum size of the decompressed
+// request body, in bytes.  The size is checked before decompressing.
+const defaultMaxDecompressedSize = 128 * 1024 * 1024
+
+const measurement = "prometheus_remote_write"
+
+type PrometheusRemoteWrite struct {
+	ServiceAddress string          `toml:"service_address"`
+	Path           string          `toml:"path"`
+	ReadTimeout    config.Duration `toml:"read_timeout"`
+	WriteTimeout   config.Duration `toml:"write_timeout"`
+	MaxBodySize    config.Size     `toml:"max_body_size"`
+	BasicUsername  string          `toml:"basic_username"`
+	BasicPassword  string          `toml:"basic_password"`
+
+	MaxDecompressedSize config.Size `toml:"max_decompressed_size"`
+	tlsint.ServerConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	acc      telegraf.Accumulator
+
+	// types holds the type of the metric families announced in the metadata
+	// sent by Prometheus, keyed by the name of the family
+	types     map[string]telegraf.ValueType
+	typesLock sync.RWMutex
+}
+
+const sampleConfig = `
+  ## Address and port to listen for remote_write requests on
+  service_address = ":9201"
+
+  ## Path to accept remote_write requests on, the url in the remote_write
+  ## section of the Prometheus configuration is http://<address>/<path>
+  # path = "/api/v1/write"
+
+  ## Maximum duration before timing out read of the request
+  # read_timeout = "10s"
+  ## Maximum duration before timing out write of the response
+  # write_timeout = "10s"
+
+  ## Maximum allowed size of the compressed request body.
+  # max_body_size = "32MiB"
+
+  ## Maximum allowed size of the request body after decompressing it.
+  # max_decompressed_size = "128MiB"
+
+  ## Set one or more allowed client CA certificate file names to
+  ## enable mutually authenticated TLS connections
+  # tls_allowed_cacerts = ["/etc/telegraf/clientca.pem"]
+
+  ## Add service certificate and key
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+
+  ## Optional username and password to accept for HTTP basic authentication.
+  ## You probably want to make sure you have TLS configured above for this.
+  # basic_username = "foobar"
+  # basic_password = "barfoo"
+`
+
+func (p *PrometheusRemoteWrite) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *PrometheusRemoteWrite) Description() string {
+	return "Receive samples sent by Prometheus servers with remote_write"
+}
+
+func (p *PrometheusRemoteWrite) Init() error {
+	if p.MaxBodySize == 0 {
+		p.MaxBodySize = config.Size(defaultMaxBodySize)
+	}
+	if p.MaxDecompressedSize == 0 {
+		p.MaxDecompressedSize = config.Size(defaultMaxDecompressedSize)
+	}
+	if p.ReadTimeout < config.Duration(time.Second) {
+		p.ReadTimeout = config.Duration(time.Second * 10)
+	}
+	if p.WriteTimeout < config.Duration(time.Second) {
+		p.WriteTimeout = config.Duration(time.Second * 10)
+	}
+	p.types = make(map[string]telegraf.ValueType)
+	return nil
+}
+
+func (p *PrometheusRemoteWrite) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *PrometheusRemoteWrite) Start(acc telegraf.Accumulator) error {
+	p.acc = acc
+
+	tlsConf, err := p.ServerConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:         p.ServiceAddress,
+		Handler:      p,
+		ReadTimeout:  time.Duration(p.ReadTimeout),
+		WriteTimeout: time.Duration(p.WriteTimeout),
+		TLSConfig:    tlsConf,
+	}
+
+	var listener net.Listener
+	if tlsConf != nil {
+		listener, err = tls.Listen("tcp", p.ServiceAddress, tlsConf)
+	} else {
+		listener, err = net.Listen("tcp", p.ServiceAddress)
+	}
+	if err != nil {
+		return err
+	}
+	p.listener = listener
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := server.Serve(p.listener); err != nil && !errors.Is(err, net.ErrClosed) {
+			p.Log.Errorf("Serve failed: %v", err)
+		}
+	}()
+
+	p.Log.Infof("Listening on %s", listener.Addr().String())
+	return nil
+}
+
+func (p *PrometheusRemoteWrite) Stop() {
+	if p.listener != nil {
+		// Ignore the returned error as we cannot do anything about it anyway
+		//nolint:errcheck,revive
+		p.listener.Close()
+	}
+	p.wg.Wait()
+}
+
+func (p *PrometheusRemoteWrite) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != p.Path {
+		http.NotFound(res, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		http.Error(res, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	if p.BasicUsername != "" && p.BasicPassword != "" {
+		username, password, ok := req.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(username), []byte(p.BasicUsername)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(p.BasicPassword)) != 1 {
+			http.Error(res, "Unauthorized.", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if req.ContentLength > int64(p.MaxBodySize) {
+		http.Error(res, "Request body too large.", http.StatusRequestEntityTooLarge)
+		return
+	}
+	compressed, err := ioutil.ReadAll(http.MaxBytesReader(res, req.Body, int64(p.MaxBodySize)))
+	if err != nil {
+		p.Log.Debugf("Reading request body failed: %v", err)
+		http.Error(res, "Request body too large.", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Prometheus does not retry requests failing with a client error, so
+	// undecodable requests are dropped.  The decoded length is taken from
+	// the header of the body and allocated up front, so it is checked first.
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		p.Log.Debugf("Decompressing request body failed: %v", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(size) > int64(p.MaxDecompressedSize) {
+		p.Log.Debugf("Decompressed request body of %d bytes exceeds the limit", size)
+		http.Error(res, "Decompressed request body too large.", http.StatusRequestEntityTooLarge)
+		return
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		p.Log.Debugf("Decompressing request body failed: %v", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var wr prompb.WriteRequest
+	if err := proto.Unmarshal(data, &wr); err != nil {
+		p.Log.Debugf("Decoding request body failed: %v", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := p.updateTypes(wr.XXX_unrecognized); err != nil {
+		p.Log.Debugf("Decoding metadata failed: %v", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, m := range p.toMetrics(wr.Timeseries, time.Now()) {
+		p.acc.AddMetric(m)
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// toMetrics converts the samples into metrics with a field named like the
+// series, typed as announced by the metadata of its family.
+func (p *PrometheusRemoteWrite) toMetrics(series []prompb.TimeSeries, now time.Time) []telegraf.Metric {
+	p.typesLock.RLock()
+	defer p.typesLock.RUnlock()
+
+	var metrics []telegraf.Metric
+	for _, ts := range series {
+		tags := make(map[string]string, len(ts.Labels))
+		for _, l := range ts.Labels {
+			tags[l.Name] = l.Value
+		}
+		name := tags[model.MetricNameLabel]
+		if name == "" {
+			p.Log.Debugf("Dropping series without %s label", model.MetricNameLabel)
+			continue
+		}
+		delete(tags, model.MetricNameLabel)
+		tp := p.familyType(name)
+
+		for _, s := range ts.Samples {
+			// Prometheus marks stale series with a NaN
+			if math.IsNaN(s.Value) {
+				continue
+			}
+			t := now
+			if s.Timestamp > 0 {
+				t = time.Unix(0, s.Timestamp*int64(time.Millisecond))
+			}
+			fields := map[string]interface{}{name: s.Value}
+			metrics = append(metrics, metric.New(measurement, tags, fields, t, tp))
+		}
+	}
+	return metrics
+}
+
+// familyType returns the type of the family of the series, which is named
+// like the series without the suffixes of histograms and summaries.
+func (p *PrometheusRemoteWrite) familyType(name string) telegraf.ValueType {
+	if tp, ok := p.types[name]; ok {
+		return tp
+	}
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if tp, ok := p.types[strings.TrimSuffix(name, suffix)]; ok && strings.HasSuffix(name, suffix) {
+			return tp
+		}
+	}
+	return telegraf.Untyped
+}
+
+// metadata is the metadata of the write request, sent periodically by
+// Prometheus 2.23 and later, which the vendored prompb does not know yet.
+type metadata struct {
+	Metadata []*metricMetadata `protobuf:"bytes,3,rep,name=metadata,proto3"`
+}
+
+func (m *metadata) Reset()         { *m = metadata{} }
+func (m *metadata) String() string { return proto.CompactTextString(m) }
+func (*metadata) ProtoMessage()    {}
+
+type metricMetadata struct {
+	Type             int32  `protobuf:"varint,1,opt,name=type,proto3"`
+	MetricFamilyName string `protobuf:"bytes,2,opt,name=metric_family_name,json=metricFamilyName,proto3"`
+	Help             string `protobuf:"bytes,4,opt,name=help,proto3"`
+	Unit             string `protobuf:"bytes,5,opt,name=unit,proto3"`
+}
+
+func (m *metricMetadata) Reset()         { *m = metricMetadata{} }
+func (m *metricMetadata) String() string { return proto.CompactTextString(m) }
+func (*metricMetadata) ProtoMessage()    {}
+
+// valueTypes maps the metric types of the metadata to the value types.
+var valueTypes = map[int32]telegraf.ValueType{
+	1: telegraf.Counter,
+	2: telegraf.Gauge,
+	3: telegraf.Histogram,
+	5: telegraf.Summary,
+}
+
+// updateTypes records the types of the families in the metadata decoded from
+// the fields of the request unknown to prompb.
+func (p *PrometheusRemoteWrite) updateTypes(unrecognized []byte) error {
+	if len(unrecognized) == 0 {
+		return nil
+	}
+	var md metadata
+	if err := proto.Unmarshal(unrecognized, &md); err != nil {
+		return fmt.Errorf("unable to unmarshal metadata: %w", err)
+	}
+
+	p.typesLock.Lock()
+	defer p.typesLock.Unlock()
+	for _, m := range md.Metadata {
+		if m == nil || m.MetricFamilyName == "" {
+			continue
+		}
+		if tp, ok := valueTypes[m.Type]; ok {
+			p.types[m.MetricFamilyName] = tp
+		} else {
+			delete(p.types, m.MetricFamilyName)
+		}
+	}
+	return nil
+}
+
+func init() {
+	inputs.Add("prometheus_remote_write", func() telegraf.Input {
+		return &PrometheusRemoteWrite{
+			ServiceAddress: ":9201",
+			Path:           "/api/v1/write",
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package prometheus_remote_write
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTestReceiver(t *testing.T) (*PrometheusRemoteWrite, *testutil.Accumulator, string) {
+	p := &PrometheusRemoteWrite{
+		Log:            testutil.Logger{},
+		ServiceAddress: "localhost:0",
+		Path:           "/api/v1/write",
+	}
+	require.NoError(t, p.Init())
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, p.Start(acc))
+	return p, acc, "http://" + p.listener.Addr().String() + "/api/v1/write"
+}
+
+func post(t *testing.T, url string, data []byte) *http.Response {
+	resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func TestReceiveSamples(t *testing.T) {
+	p, acc, url := newTestReceiver(t)
+	defer p.Stop()
+
+	wr := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "http_requests_total"},
+					{Name: "code", Value: "200"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 10, Timestamp: 1600000000000},
+					{Value: math.NaN(), Timestamp: 1600000015000},
+				},
+			},
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "rpc_duration_seconds_sum"},
+				},
+				Samples: []prompb.Sample{{Value: 4.2, Timestamp: 1600000000000}},
+			},
+		},
+	}
+	data, err := proto.Marshal(wr)
+	require.NoError(t, err)
+
+	// Metadata is sent in the same or a separate request
+	md, err := proto.Marshal(&metadata{Metadata: []*metricMetadata{
+		{Type: 1, MetricFamilyName: "http_requests_total"},
+		{Type: 5, MetricFamilyName: "rpc_duration_seconds"},
+	}})
+	require.NoError(t, err)
+
+	resp := post(t, url, data)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = post(t, url, append(md, data...))
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	acc.Wait(4)
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus_remote_write",
+			map[string]string{"code": "200"},
+			map[string]interface{}{"http_requests_total": 10.0},
+			time.Unix(1600000000, 0), telegraf.Untyped),
+		testutil.MustMetric("prometheus_remote_write",
+			map[string]string{},
+			map[string]interface{}{"rpc_duration_seconds_sum": 4.2},
+			time.Unix(1600000000, 0), telegraf.Untyped),
+		testutil.MustMetric("prometheus_remote_write",
+			map[string]string{"code": "200"},
+			map[string]interface{}{"http_requests_total": 10.0},
+			time.Unix(1600000000, 0), telegraf.Counter),
+		testutil.MustMetric("prometheus_remote_write",
+			map[string]string{},
+			map[string]interface{}{"rpc_duration_seconds_sum": 4.2},
+			time.Unix(1600000000, 0), telegraf.Summary),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestReceiveInvalid(t *testing.T) {
+	p, acc, url := newTestReceiver(t)
+	defer p.Stop()
+
+	resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader([]byte("not snappy")))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = post(t, url, []byte{0xff, 0xff})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = post(t, url[:len(url)-len("write")]+"read", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestReceiveDecompressedSizeLimit(t *testing.T) {
+	p, acc, url := newTestReceiver(t)
+	defer p.Stop()
+
+	// The header of the body claims 1GiB of decompressed data
+	resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80, 0x04}))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	p.MaxDecompressedSize = 16
+	resp = post(t, url, make([]byte, 17))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestReceiveBasicAuth(t *testing.T) {
+	p := &PrometheusRemoteWrite{
+		Log:            testutil.Logger{},
+		ServiceAddress: "localhost:0",
+		Path:           "/api/v1/write",
+		BasicUsername:  "prometheus",
+		BasicPassword:  "s3cret",
+	}
+	require.NoError(t, p.Init())
+	acc := &testutil.Accumulator{}
+	require.NoError(t, p.Start(acc))
+	defer p.Stop()
+	url := "http://" + p.listener.Addr().String() + "/api/v1/write"
+
+	resp := post(t, url, nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(snappy.Encode(nil, nil)))
+	require.NoError(t, err)
+	req.SetBasicAuth("prometheus", "s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
@@ -66,7 +66,7 @@ var sampleConfig = `
   ## Path to mib files
   # path = ["/usr/share/snmp/mibs"]
   ##
-  ## Timeout running snmptranslate command
+  ## Unused, OIDs are resolved without running snmptranslate
   # timeout = "5s"
   ## Snmp version, defaults to 2c
   # version = "2c"
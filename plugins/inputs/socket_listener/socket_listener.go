@@ -124,6 +124,11 @@ func (ssl *streamSocketListener) read(c net.Conn) {
 		ssl.Log.Error("Read error: %v", err)
 		return
 	}
+	defer func() {
+		// Closing the connection first stops decoders still reading it
+		c.Close()
+		decoder.Close()
+	}()
 
 	scnr := bufio.NewScanner(decoder)
 	for {
@@ -272,8 +277,10 @@ func (sl *SocketListener) SampleConfig() string {
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
   # data_format = "influx"
 
-  ## Content encoding for message payloads, can be set to "gzip" to or
-  ## "identity" to apply no encoding.
+  ## Content encoding for message payloads, can be set to "gzip", "zlib",
+  ## "snappy" or "zstd" to decompress the payloads or "identity" to apply no encoding.
+  ## Stream sockets expect the snappy framing format, packet sockets the
+  ## snappy block format.
   # content_encoding = "identity"
 `
 }
@@ -21,6 +21,9 @@ const (
 	eventSuccess = "success"
 )
 
+// serviceCheckStatuses are the names of the service check statuses by value
+var serviceCheckStatuses = []string{"ok", "warning", "critical", "unknown"}
+
 var uncommenter = strings.NewReplacer("\\n", "\n")
 
 func (s *Statsd) parseEventMessage(now time.Time, message string, defaultHostname string) error {
@@ -137,6 +140,78 @@ func (s *Statsd) parseEventMessage(now time.Time, message string, defaultHostnam
 	return nil
 }
 
+func (s *Statsd) parseServiceCheckMessage(now time.Time, message string, defaultHostname string) error {
+	// _sc|name|status
+	//  [
+	//   |d:timestamp
+	//   |h:hostname
+	//   |#tag1,tag2
+	//   |m:service_check_message
+	//  ]
+	//
+	// the message comes last and may contain pipes
+	var rawMessage string
+	hasMessage := false
+	if i := strings.Index(message, "|m:"); i >= 0 {
+		rawMessage = message[i+3:]
+		message = message[:i]
+		hasMessage = true
+	}
+
+	rawFields := strings.Split(message, "|")
+	if len(rawFields) < 3 || rawFields[0] != "_sc" {
+		return fmt.Errorf("invalid service check format")
+	}
+	name := rawFields[1]
+	if name == "" {
+		return fmt.Errorf("invalid service check format: empty 'name' field")
+	}
+	status, err := strconv.ParseInt(rawFields[2], 10, 64)
+	if err != nil || status < 0 || status >= int64(len(serviceCheckStatuses)) {
+		return fmt.Errorf("invalid service check status: '%s'", rawFields[2])
+	}
+
+	tags := make(map[string]string, strings.Count(message, ",")+2)
+	if defaultHostname != "" {
+		tags["source"] = defaultHostname
+	}
+	fields := make(map[string]interface{}, 4)
+	fields["status"] = status
+	fields["status_name"] = serviceCheckStatuses[status]
+	if hasMessage {
+		fields["message"] = uncommenter.Replace(rawMessage)
+	}
+
+	for _, rawField := range rawFields[3:] {
+		if len(rawField) < 2 {
+			return errors.New("too short metadata field")
+		}
+		switch rawField[:2] {
+		case "d:":
+			ts, err := strconv.ParseInt(rawField[2:], 10, 64)
+			if err != nil {
+				continue
+			}
+			fields["ts"] = ts
+		case "h:":
+			tags["source"] = rawField[2:]
+		default:
+			if rawField[0] == '#' {
+				parseDataDogTags(tags, rawField[1:])
+			} else {
+				return fmt.Errorf("unknown metadata type: '%s'", rawField)
+			}
+		}
+	}
+	// Same as for events, the host tag is reported as source
+	if host, ok := tags["host"]; ok {
+		delete(tags, "host")
+		tags["source"] = host
+	}
+	s.acc.AddFields(name, fields, tags, now)
+	return nil
+}
+
 func parseDataDogTags(tags map[string]string, message string) {
 	if len(message) == 0 {
 		return
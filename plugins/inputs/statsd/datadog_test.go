@@ -1,9 +1,11 @@
 package statsd
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf/selfstat"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -482,3 +484,108 @@ func TestEventError(t *testing.T) {
 	err = s.parseEventMessage(now, "_e{5,4}:title|text|x:1234", "default-hostname")
 	require.Error(t, err)
 }
+
+func TestServiceChecks(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		message  string
+		hostname string
+		title    string
+		tags     map[string]string
+		fields   map[string]interface{}
+	}{
+		{
+			name:     "minimal",
+			message:  "_sc|redis.can_connect|0",
+			hostname: "default-hostname",
+			title:    "redis.can_connect",
+			tags:     map[string]string{"source": "default-hostname"},
+			fields: map[string]interface{}{
+				"status":      int64(0),
+				"status_name": "ok",
+			},
+		},
+		{
+			name:     "full",
+			message:  "_sc|redis.can_connect|2|d:21|h:localhost|#env:prod,primary|m:connection refused|retrying",
+			hostname: "default-hostname",
+			title:    "redis.can_connect",
+			tags:     map[string]string{"source": "localhost", "env": "prod", "primary": "true"},
+			fields: map[string]interface{}{
+				"status":      int64(2),
+				"status_name": "critical",
+				"ts":          int64(21),
+				"message":     "connection refused|retrying",
+			},
+		},
+		{
+			name:    "host tag",
+			message: "_sc|disk|1|#host:db01|m:almost full\\nclean up",
+			title:   "disk",
+			tags:    map[string]string{"source": "db01"},
+			fields: map[string]interface{}{
+				"status":      int64(1),
+				"status_name": "warning",
+				"message":     "almost full\nclean up",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := &testutil.Accumulator{}
+			s := NewTestStatsd()
+			s.acc = acc
+			require.NoError(t, s.parseServiceCheckMessage(now, tt.message, tt.hostname))
+			require.Len(t, acc.Metrics, 1)
+			require.Equal(t, tt.title, acc.Metrics[0].Measurement)
+			require.Equal(t, tt.tags, acc.Metrics[0].Tags)
+			require.Equal(t, tt.fields, acc.Metrics[0].Fields)
+			require.Equal(t, now, acc.Metrics[0].Time)
+		})
+	}
+}
+
+func TestServiceCheckError(t *testing.T) {
+	now := time.Now()
+	s := NewTestStatsd()
+	s.acc = &testutil.Accumulator{}
+
+	for _, message := range []string{
+		"_sc|redis.can_connect",
+		"_sc||0",
+		"_sc|redis.can_connect|4",
+		"_sc|redis.can_connect|ok",
+		"_sc|redis.can_connect|0|x:1234",
+		"_sc|redis.can_connect|0||#env:prod",
+	} {
+		require.Error(t, s.parseServiceCheckMessage(now, message, "default-hostname"), message)
+	}
+}
+
+func TestParserSkipsInvalidDataDogExtensions(t *testing.T) {
+	acc := &testutil.Accumulator{}
+	s := NewTestStatsd()
+	s.DataDogExtensions = true
+	s.ParseTimeNS = selfstat.Register("statsd", "parse_time_ns", map[string]string{})
+	s.acc = acc
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.parser()
+	}()
+
+	s.in <- input{
+		Buffer: bytes.NewBufferString("_e{5,4}:title\n_sc|check|9\n_sc|check|1|m:degraded"),
+		Time:   time.Now(),
+		Addr:   "localhost",
+	}
+	acc.Wait(1)
+	close(s.done)
+	require.NoError(t, <-errs)
+
+	require.Len(t, acc.Metrics, 1)
+	require.Equal(t, "check", acc.Metrics[0].Measurement)
+	require.Equal(t, map[string]string{"source": "localhost"}, acc.Metrics[0].Tags)
+	require.Equal(t, "degraded", acc.Metrics[0].Fields["message"])
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -32,7 +33,7 @@ const (
 	defaultSeparator           = "_"
 	defaultAllowPendingMessage = 10000
 
-	parserGoRoutines = 5
+	defaultNumberWorkerThreads = 5
 )
 
 var errParsing = errors.New("error parsing statsd line")
@@ -64,10 +65,10 @@ type Statsd struct {
 	MetricSeparator string
 	// This flag enables parsing of tags in the dogstatsd extension to the
 	// statsd protocol (http://docs.datadoghq.com/guides/dogstatsd/)
-	ParseDataDogTags bool // depreciated in 1.10; use datadog_extensions
+	ParseDataDogTags bool `deprecated:"1.10.0;2.0.0;use 'datadog_extensions' instead"`
 
 	// Parses extensions to statsd in the datadog statsd format
-	// currently supports metrics and datadog tags.
+	// currently supports metrics, datadog tags, events and service checks.
 	// http://docs.datadoghq.com/guides/dogstatsd/
 	DataDogExtensions bool `toml:"datadog_extensions"`
 
@@ -84,6 +85,9 @@ type Statsd struct {
 
 	ReadBufferSize int `toml:"read_buffer_size"`
 
+	// Number of goroutines parsing the queued messages
+	NumberWorkerThreads int `toml:"number_workers_threads"`
+
 	sync.Mutex
 	// Lock for preventing a data race during resource cleanup
 	cleanup sync.Mutex
@@ -93,7 +97,7 @@ type Statsd struct {
 	// accept the connection
 	accept chan bool
 	// drops tracks the number of dropped metrics.
-	drops int
+	drops int64
 
 	// Channel for all incoming statsd packets
 	in   chan input
@@ -136,6 +140,7 @@ type Statsd struct {
 	TotalConnections   selfstat.Stat
 	TCPPacketsRecv     selfstat.Stat
 	TCPBytesRecv       selfstat.Stat
+	TCPPacketsDrop     selfstat.Stat
 	UDPPacketsRecv     selfstat.Stat
 	UDPPacketsDrop     selfstat.Stat
 	UDPBytesRecv       selfstat.Stat
@@ -246,7 +251,8 @@ const sampleConfig = `
   ## http://docs.datadoghq.com/guides/dogstatsd/
   parse_data_dog_tags = false
 
-  ## Parses datadog extensions to the statsd format
+  ## Parses datadog extensions to the statsd format, including events and
+  ## service checks
   datadog_extensions = false
 
   ## Parses distributions metric as specified in the datadog statsd format
@@ -263,11 +269,18 @@ const sampleConfig = `
   ## the statsd server will start dropping packets
   allowed_pending_messages = 10000
 
+  ## Number of worker threads used to parse the incoming messages.
+  # number_workers_threads = 5
+
   ## Number of timing/histogram values to track per-measurement in the
   ## calculation of percentiles. Raising this limit increases the accuracy
   ## of percentiles but also increases the memory usage and cpu time.
   percentile_limit = 1000
 
+  ## Maximum socket buffer size in bytes, once the buffer fills up, metrics
+  ## will start dropping.  Defaults to the OS default.
+  # read_buffer_size = 65535
+
   ## Max duration (TTL) for each metric to stay cached/reported without being updated.
   #max_ttl = "1000h"
 `
@@ -350,7 +363,6 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 func (s *Statsd) Start(ac telegraf.Accumulator) error {
 	if s.ParseDataDogTags {
 		s.DataDogExtensions = true
-		s.Log.Warn("'parse_data_dog_tags' config option is deprecated, please use 'datadog_extensions' instead")
 	}
 
 	s.acc = ac
@@ -374,6 +386,7 @@ func (s *Statsd) Start(ac telegraf.Accumulator) error {
 	s.TotalConnections = selfstat.Register("statsd", "tcp_total_connections", tags)
 	s.TCPPacketsRecv = selfstat.Register("statsd", "tcp_packets_received", tags)
 	s.TCPBytesRecv = selfstat.Register("statsd", "tcp_bytes_received", tags)
+	s.TCPPacketsDrop = selfstat.Register("statsd", "tcp_packets_dropped", tags)
 	s.UDPPacketsRecv = selfstat.Register("statsd", "udp_packets_received", tags)
 	s.UDPPacketsDrop = selfstat.Register("statsd", "udp_packets_dropped", tags)
 	s.UDPBytesRecv = selfstat.Register("statsd", "udp_bytes_received", tags)
@@ -443,7 +456,11 @@ func (s *Statsd) Start(ac telegraf.Accumulator) error {
 		}()
 	}
 
-	for i := 1; i <= parserGoRoutines; i++ {
+	workers := s.NumberWorkerThreads
+	if workers < 1 {
+		workers = defaultNumberWorkerThreads
+	}
+	for i := 1; i <= workers; i++ {
 		// Start the line parser
 		s.wg.Add(1)
 		go func() {
@@ -536,18 +553,24 @@ func (s *Statsd) udpListen(conn *net.UDPConn) error {
 				Time:   time.Now(),
 				Addr:   addr.IP.String()}:
 			default:
-				s.UDPPacketsDrop.Incr(1)
-				s.drops++
-				if s.drops == 1 || s.AllowedPendingMessages == 0 || s.drops%s.AllowedPendingMessages == 0 {
-					s.Log.Errorf("Statsd message queue full. "+
-						"We have dropped %d messages so far. "+
-						"You may want to increase allowed_pending_messages in the config", s.drops)
-				}
+				s.drop(s.UDPPacketsDrop)
 			}
 		}
 	}
 }
 
+// drop counts a message dropped because the queue of pending messages is
+// full, logging the number of drops from time to time.
+func (s *Statsd) drop(stat selfstat.Stat) {
+	stat.Incr(1)
+	drops := atomic.AddInt64(&s.drops, 1)
+	if drops == 1 || s.AllowedPendingMessages == 0 || drops%int64(s.AllowedPendingMessages) == 0 {
+		s.Log.Errorf("Statsd message queue full. "+
+			"We have dropped %d messages so far. "+
+			"You may want to increase allowed_pending_messages or number_workers_threads in the config", drops)
+	}
+}
+
 // parser monitors the s.in channel, if there is a packet ready, it parses the
 // packet into statsd strings and then calls parseStatsdLine, which parses a
 // single statsd metric into a struct.
@@ -566,7 +589,11 @@ func (s *Statsd) parser() error {
 				case line == "":
 				case s.DataDogExtensions && strings.HasPrefix(line, "_e"):
 					if err := s.parseEventMessage(in.Time, line, in.Addr); err != nil {
-						return err
+						s.Log.Errorf("Parsing event %q failed: %v", line, err)
+					}
+				case s.DataDogExtensions && strings.HasPrefix(line, "_sc|"):
+					if err := s.parseServiceCheckMessage(in.Time, line, in.Addr); err != nil {
+						s.Log.Errorf("Parsing service check %q failed: %v", line, err)
 					}
 				default:
 					if err := s.parseStatsdLine(line); err != nil {
@@ -949,12 +976,7 @@ func (s *Statsd) handler(conn *net.TCPConn, id string) {
 			select {
 			case s.in <- input{Buffer: b, Time: time.Now(), Addr: remoteIP}:
 			default:
-				s.drops++
-				if s.drops == 1 || s.drops%s.AllowedPendingMessages == 0 {
-					s.Log.Errorf("Statsd message queue full. "+
-						"We have dropped %d messages so far. "+
-						"You may want to increase allowed_pending_messages in the config", s.drops)
-				}
+				s.drop(s.TCPPacketsDrop)
 			}
 		}
 	}
@@ -1068,6 +1090,7 @@ func init() {
 			TCPKeepAlive:           false,
 			MetricSeparator:        "_",
 			AllowedPendingMessages: defaultAllowPendingMessage,
+			NumberWorkerThreads:    defaultNumberWorkerThreads,
 			DeleteCounters:         true,
 			DeleteGauges:           true,
 			DeleteSets:             true,
@@ -9,6 +9,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/selfstat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -119,6 +120,18 @@ func TestCloseConcurrentConns(t *testing.T) {
 	listener.Stop()
 }
 
+func TestDropCounting(t *testing.T) {
+	s := NewTestStatsd()
+	s.AllowedPendingMessages = 2
+	stat := selfstat.Register("statsd", "test_packets_dropped", map[string]string{})
+
+	for i := 0; i < 3; i++ {
+		s.drop(stat)
+	}
+	require.Equal(t, int64(3), stat.Get())
+	require.Equal(t, int64(3), s.drops)
+}
+
 // benchmark how long it takes to accept & process 100,000 metrics:
 func BenchmarkUDP(b *testing.B) {
 	listener := Statsd{
@@ -1,10 +1,8 @@
 package synproxy
 
 import (
-	"os"
-	"path"
-
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -23,18 +21,10 @@ func (k *Synproxy) SampleConfig() string {
 	return ""
 }
 
-func getHostProc() string {
-	procPath := "/proc"
-	if os.Getenv("HOST_PROC") != "" {
-		procPath = os.Getenv("HOST_PROC")
-	}
-	return procPath
-}
-
 func init() {
 	inputs.Add("synproxy", func() telegraf.Input {
 		return &Synproxy{
-			statFile: path.Join(getHostProc(), "/net/stat/synproxy"),
+			statFile: host.Proc("net/stat/synproxy"),
 		}
 	})
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/selfstat"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -424,3 +426,32 @@ func TestTimeIncrement_udp(t *testing.T) {
 	}
 	testutil.RequireMetricsEqual(t, want, acc.GetTelegrafMetrics())
 }
+
+func TestWorkers_udp(t *testing.T) {
+	receiver := newUDPSyslogReceiver("udp://"+address, false, syslogRFC5424)
+	receiver.ReadBufferSize = config.Size(1024 * 1024)
+	receiver.NumberWorkerThreads = 4
+
+	// The statistics are shared with the other receivers on the address
+	tags := map[string]string{"address": address}
+	received := selfstat.Register("syslog", "packets_received", tags).Get()
+	dropped := selfstat.Register("syslog", "packets_dropped", tags).Get()
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, receiver.Start(acc))
+	defer receiver.Stop()
+
+	conn, err := net.Dial("udp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for i := 0; i < 20; i++ {
+		_, err := conn.Write([]byte(fmt.Sprintf("<1>1 - - - - - - %d", i)))
+		require.NoError(t, err)
+	}
+	acc.Wait(20)
+
+	require.Equal(t, received+20, receiver.packetsRecv.Get())
+	require.Equal(t, dropped, receiver.packetsDrop.Get())
+	require.Empty(t, acc.Errors)
+}
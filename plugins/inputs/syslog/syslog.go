@@ -23,6 +23,7 @@ import (
 	framing "github.com/influxdata/telegraf/internal/syslog"
 	tlsConfig "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/selfstat"
 )
 
 type syslogRFC string
@@ -31,6 +32,7 @@ const defaultReadTimeout = time.Second * 5
 const ipMaxPacketSize = 64 * 1024
 const syslogRFC3164 = "RFC3164"
 const syslogRFC5424 = "RFC5424"
+const defaultAllowedPendingMessages = 10000
 
 // Syslog is a syslog plugin
 type Syslog struct {
@@ -45,8 +47,14 @@ type Syslog struct {
 	BestEffort      bool
 	Separator       string `toml:"sdparam_separator"`
 
+	// Tuning of packet sockets
+	ReadBufferSize         config.Size `toml:"read_buffer_size"`
+	AllowedPendingMessages int         `toml:"allowed_pending_messages"`
+	NumberWorkerThreads    int         `toml:"number_workers_threads"`
+
 	now      func() time.Time
 	lastTime time.Time
+	timeMu   sync.Mutex
 
 	mu sync.Mutex
 	wg sync.WaitGroup
@@ -59,6 +67,9 @@ type Syslog struct {
 	connectionsMu sync.Mutex
 
 	udpListener net.PacketConn
+	packetsRecv selfstat.Stat
+	packetsDrop selfstat.Stat
+	drops       int64
 }
 
 var sampleConfig = `
@@ -113,6 +124,19 @@ var sampleConfig = `
   ## For each combination a field is created.
   ## Its name is created concatenating identifier, sdparam_separator, and parameter name.
   # sdparam_separator = "_"
+
+  ## Maximum socket buffer size in bytes for packet sockets (e.g. UDP), once
+  ## the buffer fills up, messages are dropped by the OS.
+  ## Defaults to the OS default.
+  # read_buffer_size = "64KiB"
+
+  ## Number of messages read from packet sockets allowed to queue up for
+  ## parsing, once filled messages are dropped (default = 10000).
+  # allowed_pending_messages = 10000
+
+  ## Number of worker threads parsing the messages read from packet sockets.
+  ## With more than one worker the messages may be reordered (default = 1).
+  # number_workers_threads = 1
 `
 
 // SampleConfig returns sample configuration message
@@ -178,6 +202,20 @@ func (s *Syslog) Start(acc telegraf.Accumulator) error {
 		s.Closer = l
 		s.udpListener = l
 
+		if s.ReadBufferSize > 0 {
+			srb, ok := l.(interface{ SetReadBuffer(int) error })
+			if !ok {
+				return fmt.Errorf("unable to set read buffer on a %s socket", scheme)
+			}
+			if err := srb.SetReadBuffer(int(s.ReadBufferSize)); err != nil {
+				return err
+			}
+		}
+
+		tags := map[string]string{"address": s.Address}
+		s.packetsRecv = selfstat.Register("syslog", "packets_received", tags)
+		s.packetsDrop = selfstat.Register("syslog", "packets_dropped", tags)
+
 		s.wg.Add(1)
 		go s.listenPacket(acc)
 	}
@@ -234,9 +272,60 @@ func getAddressParts(a string) (string, string, error) {
 	return u.Scheme, host, nil
 }
 
+// listenPacket reads the messages of the packet socket and queues them for
+// the workers parsing them, dropping messages while the queue is full.
 func (s *Syslog) listenPacket(acc telegraf.Accumulator) {
 	defer s.wg.Done()
+
+	pending := s.AllowedPendingMessages
+	if pending < 1 {
+		pending = defaultAllowedPendingMessages
+	}
+	workers := s.NumberWorkerThreads
+	if workers < 1 {
+		workers = 1
+	}
+
+	packets := make(chan []byte, pending)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.parsePackets(packets, acc)
+		}()
+	}
+	defer wg.Wait()
+	defer close(packets)
+
 	b := make([]byte, ipMaxPacketSize)
+	for {
+		n, _, err := s.udpListener.ReadFrom(b)
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), ": use of closed network connection") {
+				acc.AddError(err)
+			}
+			break
+		}
+		s.packetsRecv.Incr(1)
+
+		packet := make([]byte, n)
+		copy(packet, b[:n])
+		select {
+		case packets <- packet:
+		default:
+			s.packetsDrop.Incr(1)
+			s.drops++
+			if s.drops == 1 || s.drops%int64(pending) == 0 {
+				acc.AddError(fmt.Errorf("message queue full, dropped %d messages so far; "+
+					"you may want to increase allowed_pending_messages or number_workers_threads", s.drops))
+			}
+		}
+	}
+}
+
+// parsePackets parses the queued messages until the queue is closed.
+func (s *Syslog) parsePackets(packets <-chan []byte, acc telegraf.Accumulator) {
 	var p syslog.Machine
 	switch {
 	case !s.BestEffort && s.SyslogStandard == syslogRFC5424:
@@ -248,16 +337,8 @@ func (s *Syslog) listenPacket(acc telegraf.Accumulator) {
 	case s.BestEffort && s.SyslogStandard == syslogRFC3164:
 		p = rfc3164.NewParser(rfc3164.WithYear(rfc3164.CurrentYear{}), rfc3164.WithBestEffort())
 	}
-	for {
-		n, _, err := s.udpListener.ReadFrom(b)
-		if err != nil {
-			if !strings.HasSuffix(err.Error(), ": use of closed network connection") {
-				acc.AddError(err)
-			}
-			break
-		}
-
-		message, err := p.Parse(b[:n])
+	for packet := range packets {
+		message, err := p.Parse(packet)
 		if message != nil {
 			acc.AddFields("syslog", fields(message, s), tags(message), s.time())
 		}
@@ -474,6 +555,9 @@ func (uc unixCloser) Close() error {
 }
 
 func (s *Syslog) time() time.Time {
+	s.timeMu.Lock()
+	defer s.timeMu.Unlock()
+
 	t := s.now()
 	if t == s.lastTime {
 		t = t.Add(time.Nanosecond)
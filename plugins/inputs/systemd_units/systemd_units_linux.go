@@ -1,27 +1,37 @@
 package systemd_units
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
-	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
 // SystemdUnits is a telegraf plugin to gather systemd unit status
 type SystemdUnits struct {
-	Timeout   config.Duration
-	UnitType  string `toml:"unittype"`
-	systemctl systemctl
+	Timeout  config.Duration
+	UnitType string `toml:"unittype"`
+
+	// Gather the restart count and the cgroup accounting of loaded units
+	Details bool `toml:"details"`
+
+	client client
+	// connect opens a connection to systemd, replaced in tests
+	connect func(ctx context.Context) (client, error)
 }
 
-type systemctl func(timeout config.Duration, unitType string) (*bytes.Buffer, error)
+// client queries systemd, implemented by dbus.Conn.
+type client interface {
+	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
+	GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error)
+	Close()
+}
 
 const measurement = "systemd_units"
 
@@ -112,6 +122,17 @@ var subMap = map[string]int{
 	"elapsed": 0x00a0,
 }
 
+// accountedTypes are the unit types running processes in a cgroup, which
+// may have CPU and memory accounting enabled.
+var accountedTypes = map[string]bool{
+	"service": true,
+	"socket":  true,
+	"mount":   true,
+	"swap":    true,
+	"slice":   true,
+	"scope":   true,
+}
+
 var (
 	defaultTimeout  = config.Duration(time.Second)
 	defaultUnitType = "service"
@@ -125,41 +146,55 @@ func (s *SystemdUnits) Description() string {
 // SampleConfig returns sample configuration options.
 func (s *SystemdUnits) SampleConfig() string {
 	return `
-  ## Set timeout for systemctl execution
+  ## Set timeout for the queries to systemd
   # timeout = "1s"
   #
   ## Filter for a specific unit type, default is "service", other possible
   ## values are "socket", "target", "device", "mount", "automount", "swap",
   ## "timer", "path", "slice" and "scope ":
   # unittype = "service"
+  #
+  ## Gather the restart count of services and the CPU and memory accounting
+  ## of loaded units, this queries systemd once per unit.
+  # details = false
 `
 }
 
-// Gather parses systemctl outputs and adds counters to the Accumulator
+func connectSystemBus(ctx context.Context) (client, error) {
+	return dbus.NewSystemConnectionContext(ctx)
+}
+
+// Gather queries systemd over D-Bus and adds the unit states to the Accumulator
 func (s *SystemdUnits) Gather(acc telegraf.Accumulator) error {
-	out, err := s.systemctl(s.Timeout, s.UnitType)
-	if err != nil {
-		return err
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.Timeout))
+	defer cancel()
+
+	if s.client == nil {
+		c, err := s.connect(ctx)
+		if err != nil {
+			return fmt.Errorf("connecting to systemd failed: %v", err)
+		}
+		s.client = c
 	}
 
-	scanner := bufio.NewScanner(out)
-	for scanner.Scan() {
-		line := scanner.Text()
+	units, err := s.client.ListUnitsContext(ctx)
+	if err != nil {
+		// Reconnect on the next gather, systemd may have been restarted
+		s.client.Close()
+		s.client = nil
+		return fmt.Errorf("listing units failed: %v", err)
+	}
 
-		data := strings.Fields(line)
-		if len(data) < 4 {
-			acc.AddError(fmt.Errorf("Error parsing line (expected at least 4 fields): %s", line))
+	suffix := "." + s.UnitType
+	for _, unit := range units {
+		if !strings.HasSuffix(unit.Name, suffix) {
 			continue
 		}
-		name := data[0]
-		load := data[1]
-		active := data[2]
-		sub := data[3]
 		tags := map[string]string{
-			"name":   name,
-			"load":   load,
-			"active": active,
-			"sub":    sub,
+			"name":   unit.Name,
+			"load":   unit.LoadState,
+			"active": unit.ActiveState,
+			"sub":    unit.SubState,
 		}
 
 		var (
@@ -168,16 +203,16 @@ func (s *SystemdUnits) Gather(acc telegraf.Accumulator) error {
 			subCode    int
 			ok         bool
 		)
-		if loadCode, ok = loadMap[load]; !ok {
-			acc.AddError(fmt.Errorf("Error parsing field 'load', value not in map: %s", load))
+		if loadCode, ok = loadMap[unit.LoadState]; !ok {
+			acc.AddError(fmt.Errorf("Error parsing field 'load', value not in map: %s", unit.LoadState))
 			continue
 		}
-		if activeCode, ok = activeMap[active]; !ok {
-			acc.AddError(fmt.Errorf("Error parsing field 'active', value not in map: %s", active))
+		if activeCode, ok = activeMap[unit.ActiveState]; !ok {
+			acc.AddError(fmt.Errorf("Error parsing field 'active', value not in map: %s", unit.ActiveState))
 			continue
 		}
-		if subCode, ok = subMap[sub]; !ok {
-			acc.AddError(fmt.Errorf("Error parsing field 'sub', value not in map: %s", sub))
+		if subCode, ok = subMap[unit.SubState]; !ok {
+			acc.AddError(fmt.Errorf("Error parsing field 'sub', value not in map: %s", unit.SubState))
 			continue
 		}
 		fields := map[string]interface{}{
@@ -186,35 +221,48 @@ func (s *SystemdUnits) Gather(acc telegraf.Accumulator) error {
 			"sub_code":    subCode,
 		}
 
+		if s.Details && unit.LoadState == "loaded" && accountedTypes[s.UnitType] {
+			if err := s.addDetails(ctx, unit.Name, fields); err != nil {
+				acc.AddError(fmt.Errorf("querying properties of %q failed: %v", unit.Name, err))
+			}
+		}
+
 		acc.AddFields(measurement, fields, tags)
 	}
 
 	return nil
 }
 
-func setSystemctl(timeout config.Duration, unitType string) (*bytes.Buffer, error) {
-	// is systemctl available ?
-	systemctlPath, err := exec.LookPath("systemctl")
+// addDetails adds the restart count and the accounting of the unit to the
+// fields.  Accounting values systemd does not track are left out.
+func (s *SystemdUnits) addDetails(ctx context.Context, name string, fields map[string]interface{}) error {
+	// The properties are found on the interface of the unit type, e.g.
+	// org.freedesktop.systemd1.Service
+	unitType := strings.ToUpper(s.UnitType[:1]) + s.UnitType[1:]
+	properties, err := s.client.GetUnitTypePropertiesContext(ctx, name, unitType)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	cmd := exec.Command(systemctlPath, "list-units", "--all", "--plain", fmt.Sprintf("--type=%s", unitType), "--no-legend")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err = internal.RunTimeout(cmd, time.Duration(timeout))
-	if err != nil {
-		return &out, fmt.Errorf("error running systemctl list-units --all --plain --type=%s --no-legend: %s", unitType, err)
+	if restarts, ok := properties["NRestarts"].(uint32); ok {
+		fields["restarts"] = int64(restarts)
 	}
-
-	return &out, nil
+	for property, field := range map[string]string{
+		"CPUUsageNSec":  "cpu_usage_nsec",
+		"MemoryCurrent": "memory_current",
+	} {
+		// Untracked values are reported as the maximum value
+		if v, ok := properties[property].(uint64); ok && v != math.MaxUint64 {
+			fields[field] = v
+		}
+	}
+	return nil
 }
 
 func init() {
 	inputs.Add("systemd_units", func() telegraf.Input {
 		return &SystemdUnits{
-			systemctl: setSystemctl,
+			connect:   connectSystemBus,
 			Timeout:   defaultTimeout,
 			UnitType:  defaultUnitType,
 		}
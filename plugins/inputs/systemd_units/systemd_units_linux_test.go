@@ -1,19 +1,52 @@
 package systemd_units
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
-	"github.com/influxdata/telegraf/config"
+	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
 )
 
+type mockClient struct {
+	units      []dbus.UnitStatus
+	properties map[string]map[string]interface{}
+	err        error
+	closed     bool
+}
+
+func (c *mockClient) ListUnitsContext(_ context.Context) ([]dbus.UnitStatus, error) {
+	return c.units, c.err
+}
+
+func (c *mockClient) GetUnitTypePropertiesContext(_ context.Context, unit string, unitType string) (map[string]interface{}, error) {
+	if unitType != "Service" {
+		return nil, fmt.Errorf("unexpected unit type %q", unitType)
+	}
+	properties, ok := c.properties[unit]
+	if !ok {
+		return nil, errors.New("unit not found")
+	}
+	return properties, nil
+}
+
+func (c *mockClient) Close() {
+	c.closed = true
+}
+
+func unitStatus(name, load, active, sub string) dbus.UnitStatus {
+	return dbus.UnitStatus{Name: name, LoadState: load, ActiveState: active, SubState: sub}
+}
+
 func TestSystemdUnits(t *testing.T) {
 	tests := []struct {
 		name   string
-		line   string
+		unit   dbus.UnitStatus
 		tags   map[string]string
 		fields map[string]interface{}
 		status int
@@ -21,7 +54,7 @@ func TestSystemdUnits(t *testing.T) {
 	}{
 		{
 			name: "example loaded active running",
-			line: "example.service                loaded active running example service description",
+			unit: unitStatus("example.service", "loaded", "active", "running"),
 			tags: map[string]string{"name": "example.service", "load": "loaded", "active": "active", "sub": "running"},
 			fields: map[string]interface{}{
 				"load_code":   0,
@@ -31,7 +64,7 @@ func TestSystemdUnits(t *testing.T) {
 		},
 		{
 			name: "example loaded active exited",
-			line: "example.service                loaded active exited  example service description",
+			unit: unitStatus("example.service", "loaded", "active", "exited"),
 			tags: map[string]string{"name": "example.service", "load": "loaded", "active": "active", "sub": "exited"},
 			fields: map[string]interface{}{
 				"load_code":   0,
@@ -41,7 +74,7 @@ func TestSystemdUnits(t *testing.T) {
 		},
 		{
 			name: "example loaded failed failed",
-			line: "example.service                loaded failed failed  example service description",
+			unit: unitStatus("example.service", "loaded", "failed", "failed"),
 			tags: map[string]string{"name": "example.service", "load": "loaded", "active": "failed", "sub": "failed"},
 			fields: map[string]interface{}{
 				"load_code":   0,
@@ -51,7 +84,7 @@ func TestSystemdUnits(t *testing.T) {
 		},
 		{
 			name: "example not-found inactive dead",
-			line: "example.service                not-found inactive dead  example service description",
+			unit: unitStatus("example.service", "not-found", "inactive", "dead"),
 			tags: map[string]string{"name": "example.service", "load": "not-found", "active": "inactive", "sub": "dead"},
 			fields: map[string]interface{}{
 				"load_code":   2,
@@ -61,22 +94,21 @@ func TestSystemdUnits(t *testing.T) {
 		},
 		{
 			name: "example unknown unknown unknown",
-			line: "example.service                unknown unknown unknown  example service description",
+			unit: unitStatus("example.service", "unknown", "unknown", "unknown"),
 			err:  fmt.Errorf("Error parsing field 'load', value not in map: %s", "unknown"),
 		},
 		{
-			name: "example too few fields",
-			line: "example.service                loaded fai",
-			err:  fmt.Errorf("Error parsing line (expected at least 4 fields): %s", "example.service                loaded fai"),
+			name: "other unit type",
+			unit: unitStatus("example.socket", "loaded", "active", "listening"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			systemdUnits := &SystemdUnits{
-				systemctl: func(timeout config.Duration, unitType string) (*bytes.Buffer, error) {
-					return bytes.NewBufferString(tt.line), nil
-				},
+				Timeout:  defaultTimeout,
+				UnitType: defaultUnitType,
+				client:   &mockClient{units: []dbus.UnitStatus{tt.unit}},
 			}
 			acc := new(testutil.Accumulator)
 			err := acc.GatherError(systemdUnits.Gather)
@@ -98,3 +130,65 @@ func TestSystemdUnits(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemdUnitsDetails(t *testing.T) {
+	c := &mockClient{
+		units: []dbus.UnitStatus{
+			unitStatus("example.service", "loaded", "active", "running"),
+			unitStatus("missing.service", "not-found", "inactive", "dead"),
+		},
+		properties: map[string]map[string]interface{}{
+			"example.service": {
+				"NRestarts":     uint32(3),
+				"CPUUsageNSec":  uint64(1500000000),
+				"MemoryCurrent": uint64(math.MaxUint64),
+			},
+		},
+	}
+	systemdUnits := &SystemdUnits{
+		Timeout:  defaultTimeout,
+		UnitType: defaultUnitType,
+		Details:  true,
+		connect: func(_ context.Context) (client, error) {
+			return c, nil
+		},
+	}
+
+	acc := new(testutil.Accumulator)
+	require.NoError(t, acc.GatherError(systemdUnits.Gather))
+	require.Len(t, acc.Metrics, 2)
+	require.Equal(t, map[string]interface{}{
+		"load_code":      0,
+		"active_code":    0,
+		"sub_code":       0,
+		"restarts":       int64(3),
+		"cpu_usage_nsec": uint64(1500000000),
+	}, acc.Metrics[0].Fields)
+	require.Equal(t, map[string]interface{}{
+		"load_code":   2,
+		"active_code": 2,
+		"sub_code":    1,
+	}, acc.Metrics[1].Fields)
+}
+
+func TestSystemdUnitsReconnect(t *testing.T) {
+	c := &mockClient{err: errors.New("connection closed")}
+	connects := 0
+	systemdUnits := &SystemdUnits{
+		Timeout:  defaultTimeout,
+		UnitType: defaultUnitType,
+		connect: func(_ context.Context) (client, error) {
+			connects++
+			return c, nil
+		},
+	}
+
+	acc := new(testutil.Accumulator)
+	require.Error(t, acc.GatherError(systemdUnits.Gather))
+	require.True(t, c.closed)
+	require.Nil(t, systemdUnits.client)
+
+	c.err = nil
+	require.NoError(t, acc.GatherError(systemdUnits.Gather))
+	require.Equal(t, 2, connects)
+}
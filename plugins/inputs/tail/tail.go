@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -420,6 +421,7 @@ func (t *Tail) Stop() {
 			offset, err := tailer.Tell()
 			if err == nil {
 				t.Log.Debugf("Recording offset %d for %q", offset, tailer.Filename)
+				t.offsets[tailer.Filename] = offset
 			} else {
 				t.Log.Errorf("Recording offset for %q: %s", tailer.Filename, err.Error())
 			}
@@ -441,6 +443,24 @@ func (t *Tail) Stop() {
 	offsetsMutex.Unlock()
 }
 
+// GetState returns the offsets of the tailed files recorded on Stop.
+func (t *Tail) GetState() interface{} {
+	return t.offsets
+}
+
+// SetState restores the offsets of the files tailed before a restart, used
+// unless from_beginning is set.
+func (t *Tail) SetState(state interface{}) error {
+	offsets, ok := state.(map[string]int64)
+	if !ok {
+		return fmt.Errorf("invalid state type %T", state)
+	}
+	for k, v := range offsets {
+		t.offsets[k] = v
+	}
+	return nil
+}
+
 func (t *Tail) SetParserFunc(fn parsers.ParserFunc) {
 	t.parserFunc = fn
 }
@@ -588,6 +588,54 @@ func TestTailEOF(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTailState(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+	_, err = tmpfile.WriteString("cpu usage_idle=100\n")
+	require.NoError(t, err)
+	require.NoError(t, tmpfile.Sync())
+
+	tt := NewTestTail()
+	tt.Log = testutil.Logger{}
+	tt.offsets = make(map[string]int64)
+	tt.Files = []string{tmpfile.Name()}
+	tt.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, tt.Init())
+	require.NoError(t, tt.SetState(map[string]int64{tmpfile.Name(): 0}))
+
+	acc := testutil.Accumulator{}
+	require.NoError(t, tt.Start(&acc))
+	acc.Wait(1)
+	tt.Stop()
+	state := tt.GetState()
+	require.Equal(t, map[string]int64{tmpfile.Name(): 19}, state)
+
+	_, err = tmpfile.WriteString("cpu2 usage_idle=200\n")
+	require.NoError(t, err)
+	require.NoError(t, tmpfile.Close())
+
+	tt = NewTestTail()
+	tt.Log = testutil.Logger{}
+	tt.offsets = make(map[string]int64)
+	tt.Files = []string{tmpfile.Name()}
+	tt.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, tt.Init())
+	require.NoError(t, tt.SetState(state))
+
+	acc = testutil.Accumulator{}
+	require.NoError(t, tt.Start(&acc))
+	defer tt.Stop()
+	acc.Wait(1)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+	acc.AssertContainsFields(t, "cpu2",
+		map[string]interface{}{
+			"usage_idle": float64(200),
+		})
+
+	require.Error(t, tt.SetState("invalid"))
+}
+
 func getTestdataDir() string {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -3,7 +3,6 @@ package tcp_listener
 import (
 	"bufio"
 	"fmt"
-	"log"
 	"net"
 	"sync"
 
@@ -88,10 +87,6 @@ func (t *TCPListener) Start(acc telegraf.Accumulator) error {
 	t.Lock()
 	defer t.Unlock()
 
-	log.Println("W! DEPRECATED: the TCP listener plugin has been deprecated " +
-		"in favor of the socket_listener plugin " +
-		"(https://github.com/influxdata/telegraf/tree/master/plugins/inputs/socket_listener)")
-
 	tags := map[string]string{
 		"address": t.ServiceAddress,
 	}
@@ -2,7 +2,6 @@ package udp_listener
 
 import (
 	"fmt"
-	"log"
 	"net"
 	"sync"
 	"time"
@@ -96,10 +95,6 @@ func (u *UDPListener) Start(acc telegraf.Accumulator) error {
 	u.Lock()
 	defer u.Unlock()
 
-	log.Println("W! DEPRECATED: the UDP listener plugin has been deprecated " +
-		"in favor of the socket_listener plugin " +
-		"(https://github.com/influxdata/telegraf/tree/master/plugins/inputs/socket_listener)")
-
 	tags := map[string]string{
 		"address": u.ServiceAddress,
 	}
@@ -9,6 +9,8 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -33,6 +35,18 @@ var sampleConfig = `
   ## Example: "Application"
   # eventlog_name = ""
 
+  ## Start reading at the oldest record of the subscribed channels instead of
+  ## only processing events written after Telegraf started
+  # from_beginning = false
+
+  ## File used to remember the last processed event. When set, the position is
+  ## saved after every gather and reading resumes after that event on restart,
+  ## so events written while Telegraf was not running are not lost.
+  # bookmark_file = 'C:\Program Files\Telegraf\win_eventlog.bookmark'
+
+  ## Number of events requested from the subscription in one call
+  # event_batch_size = 5
+
   ## xpath_query can be in defined short form like "Event/System[EventID=999]"
   ## or you can form a XML Query. Refer to the Consuming Events article:
   ## https://docs.microsoft.com/en-us/windows/win32/wes/consuming-events
@@ -112,13 +126,19 @@ type WinEventLog struct {
 	EventFields            []string `toml:"event_fields"`
 	ExcludeFields          []string `toml:"exclude_fields"`
 	ExcludeEmpty           []string `toml:"exclude_empty"`
+	FromBeginning          bool     `toml:"from_beginning"`
+	BookmarkFile           string   `toml:"bookmark_file"`
+	EventBatchSize         uint32   `toml:"event_batch_size"`
 	subscription           EvtHandle
+	bookmark               EvtHandle
 	buf                    []byte
 	Log                    telegraf.Logger
 }
 
 var bufferSize = 1 << 14
 
+const defaultEventBatchSize = 5
+
 var description = "Input plugin to collect Windows Event Log messages"
 
 // Description for win_eventlog
@@ -131,6 +151,27 @@ func (w *WinEventLog) SampleConfig() string {
 	return sampleConfig
 }
 
+// Start is a no-op, the subscription is created on the first Gather
+func (w *WinEventLog) Start(_ telegraf.Accumulator) error {
+	return nil
+}
+
+// Stop closes the subscription and the bookmark
+func (w *WinEventLog) Stop() {
+	if w.subscription != 0 {
+		if err := _EvtClose(w.subscription); err != nil {
+			w.Log.Errorf("Closing subscription failed: %v", err)
+		}
+		w.subscription = 0
+	}
+	if w.bookmark != 0 {
+		if err := _EvtClose(w.bookmark); err != nil {
+			w.Log.Errorf("Closing bookmark failed: %v", err)
+		}
+		w.bookmark = 0
+	}
+}
+
 // Gather Windows Event Log entries
 func (w *WinEventLog) Gather(acc telegraf.Accumulator) error {
 
@@ -275,6 +316,12 @@ loop:
 		}
 	}
 
+	if w.BookmarkFile != "" {
+		if err := w.saveBookmark(); err != nil {
+			return fmt.Errorf("saving bookmark to %q failed: %v", w.BookmarkFile, err)
+		}
+	}
+
 	return nil
 }
 
@@ -342,20 +389,113 @@ func (w *WinEventLog) evtSubscribe(logName, xquery string) (EvtHandle, error) {
 		return 0, err
 	}
 
+	bookmarkXML, err := w.loadBookmark()
+	if err != nil {
+		return 0, err
+	}
+	flags := subscribeFlags(w.FromBeginning, bookmarkXML)
+
+	w.bookmark, err = createBookmark(bookmarkXML)
+	if err != nil {
+		return 0, fmt.Errorf("creating bookmark failed: %v", err)
+	}
+
+	var bookmark EvtHandle
+	if flags == EvtSubscribeStartAfterBookmark {
+		bookmark = w.bookmark
+	}
+
 	subsHandle, err := _EvtSubscribe(0, uintptr(sigEvent), logNamePtr, xqueryPtr,
-		0, 0, 0, EvtSubscribeToFutureEvents)
+		bookmark, 0, 0, flags)
 	if err != nil {
+		if cerr := _EvtClose(w.bookmark); cerr != nil {
+			w.Log.Errorf("Closing bookmark failed: %v", cerr)
+		}
+		w.bookmark = 0
 		return 0, err
 	}
 
 	return subsHandle, nil
 }
 
+// subscribeFlags selects where the subscription starts: after the saved
+// bookmark if any, otherwise at the oldest record or with future events
+// depending on fromBeginning.
+func subscribeFlags(fromBeginning bool, bookmarkXML string) EvtSubscribeFlag {
+	switch {
+	case bookmarkXML != "":
+		return EvtSubscribeStartAfterBookmark
+	case fromBeginning:
+		return EvtSubscribeStartAtOldestRecord
+	default:
+		return EvtSubscribeToFutureEvents
+	}
+}
+
+// loadBookmark returns the bookmark XML saved by a previous run, or an empty
+// string if there is none.
+func (w *WinEventLog) loadBookmark() (string, error) {
+	if w.BookmarkFile == "" {
+		return "", nil
+	}
+
+	content, err := ioutil.ReadFile(w.BookmarkFile)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading bookmark from %q failed: %v", w.BookmarkFile, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func (w *WinEventLog) saveBookmark() error {
+	var bufferUsed, propertyCount uint32
+	err := _EvtRender(0, w.bookmark, EvtRenderBookmark, uint32(len(w.buf)), &w.buf[0], &bufferUsed, &propertyCount)
+	if err != nil {
+		return err
+	}
+
+	bookmarkXML, err := DecodeUTF16(w.buf[:bufferUsed])
+	if err != nil {
+		return err
+	}
+	bookmarkXML = bytes.Trim(bookmarkXML, "\x00")
+
+	return writeBookmarkFile(w.BookmarkFile, bookmarkXML)
+}
+
+// writeBookmarkFile replaces the bookmark file through a temporary file, so
+// the file is never left partially written.
+func writeBookmarkFile(path string, bookmarkXML []byte) error {
+	tmpFile := path + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, bookmarkXML, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, path)
+}
+
+// createBookmark creates a bookmark handle from its XML representation, or a
+// new empty bookmark if bookmarkXML is empty.
+func createBookmark(bookmarkXML string) (EvtHandle, error) {
+	var bookmarkPtr *uint16
+	if bookmarkXML != "" {
+		var err error
+		bookmarkPtr, err = syscall.UTF16PtrFromString(bookmarkXML)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return _EvtCreateBookmark(bookmarkPtr)
+}
+
 func (w *WinEventLog) fetchEventHandles(subsHandle EvtHandle) ([]EvtHandle, error) {
-	var eventsNumber uint32
 	var evtReturned uint32
 
-	eventsNumber = 5
+	eventsNumber := w.EventBatchSize
+	if eventsNumber == 0 {
+		eventsNumber = defaultEventBatchSize
+	}
 
 	eventHandles := make([]EvtHandle, eventsNumber)
 
@@ -385,6 +525,11 @@ func (w *WinEventLog) fetchEvents(subsHandle EvtHandle) ([]Event, error) {
 				// w.Log.Debugf("Got event: %v", event)
 				events = append(events, event)
 			}
+			if w.bookmark != 0 {
+				if err := _EvtUpdateBookmark(w.bookmark, eventHandle); err != nil {
+					w.Log.Errorf("Updating bookmark failed: %v", err)
+				}
+			}
 		}
 	}
 
@@ -524,6 +669,7 @@ func init() {
 			Separator:              "_",
 			OnlyFirstLineOfMessage: true,
 			TimeStampFromEvent:     true,
+			EventBatchSize:         defaultEventBatchSize,
 			EventTags:              []string{"Source", "EventID", "Level", "LevelText", "Keywords", "Channel", "Computer"},
 			EventFields:            []string{"*"},
 			ExcludeEmpty:           []string{"Task", "Opcode", "*ActivityID", "UserID"},
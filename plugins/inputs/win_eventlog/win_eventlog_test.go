@@ -5,7 +5,12 @@
 package win_eventlog
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestWinEventLog_shouldExcludeEmptyField(t *testing.T) {
@@ -134,3 +139,73 @@ func TestWinEventLog_shouldProcessField(t *testing.T) {
 		})
 	}
 }
+
+func TestWinEventLog_subscribeFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		fromBeginning bool
+		bookmarkXML   string
+		want          EvtSubscribeFlag
+	}{
+		{
+			name: "future events by default",
+			want: EvtSubscribeToFutureEvents,
+		},
+		{
+			name:          "from beginning",
+			fromBeginning: true,
+			want:          EvtSubscribeStartAtOldestRecord,
+		},
+		{
+			name:        "after bookmark",
+			bookmarkXML: "<BookmarkList/>",
+			want:        EvtSubscribeStartAfterBookmark,
+		},
+		{
+			name:          "bookmark takes precedence over from beginning",
+			fromBeginning: true,
+			bookmarkXML:   "<BookmarkList/>",
+			want:          EvtSubscribeStartAfterBookmark,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, subscribeFlags(tt.fromBeginning, tt.bookmarkXML))
+		})
+	}
+}
+
+func TestWinEventLog_bookmarkFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "win_eventlog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w := &WinEventLog{BookmarkFile: filepath.Join(dir, "win_eventlog.bookmark")}
+
+	// A missing file means no bookmark
+	bookmarkXML, err := w.loadBookmark()
+	require.NoError(t, err)
+	require.Empty(t, bookmarkXML)
+
+	saved := "<BookmarkList>\r\n  <Bookmark Channel='Application' RecordId='42' IsCurrent='true'/>\r\n</BookmarkList>"
+	require.NoError(t, writeBookmarkFile(w.BookmarkFile, []byte(saved+"\r\n")))
+	_, err = os.Stat(w.BookmarkFile + ".tmp")
+	require.True(t, os.IsNotExist(err))
+
+	bookmarkXML, err = w.loadBookmark()
+	require.NoError(t, err)
+	require.Equal(t, saved, bookmarkXML)
+
+	// Overwriting replaces the previous bookmark
+	require.NoError(t, writeBookmarkFile(w.BookmarkFile, []byte("<BookmarkList/>")))
+	bookmarkXML, err = w.loadBookmark()
+	require.NoError(t, err)
+	require.Equal(t, "<BookmarkList/>", bookmarkXML)
+}
+
+func TestWinEventLog_noBookmarkFile(t *testing.T) {
+	w := &WinEventLog{}
+	bookmarkXML, err := w.loadBookmark()
+	require.NoError(t, err)
+	require.Empty(t, bookmarkXML)
+}
@@ -27,6 +27,11 @@ var sampleConfig = `
   # and in case of localized Windows, counter paths will be also localized. It also returns instance indexes in instance names.
   # If false, wildcards (not partial) in instance names will still be expanded, but instance indexes will not be returned in instance names.
   #UseWildcardsExpansion = false
+  # When running on a localized version of Windows and with UseWildcardsExpansion = true, Windows will
+  # localize object and counter names. When LocalizeWildcardsExpansion = false, the configured ObjectName and Counters
+  # are reported instead and only the instance names are taken from the expansion. Counters containing wildcards
+  # are still reported with their localized names.
+  #LocalizeWildcardsExpansion = true
   # Period after which counters will be reread from configuration and wildcards in counter paths expanded
   CountersRefreshInterval="1m"
 
@@ -145,6 +150,9 @@ type Win_PerfCounters struct {
 	Object                  []perfobject
 	CountersRefreshInterval config.Duration
 	UseWildcardsExpansion   bool
+	// LocalizeWildcardsExpansion selects whether object and counter names
+	// resulting from wildcard expansion are reported in the system language
+	LocalizeWildcardsExpansion bool
 
 	Log telegraf.Logger
 
@@ -263,6 +271,8 @@ func (m *Win_PerfCounters) AddItem(counterPath string, objectName string, instan
 
 	if m.UseWildcardsExpansion {
 		origInstance := instance
+		origObjectName := objectName
+		origCounterName := counterName
 		counterPath, err = m.query.GetCounterPath(counterHandle)
 		if err != nil {
 			return err
@@ -273,9 +283,6 @@ func (m *Win_PerfCounters) AddItem(counterPath string, objectName string, instan
 		}
 
 		for _, counterPath := range counters {
-			var err error
-			counterHandle, err := m.query.AddCounterToQuery(counterPath)
-
 			objectName, instance, counterName, err = extractCounterInfoFromCounterPath(counterPath)
 			if err != nil {
 				return err
@@ -285,6 +292,30 @@ func (m *Win_PerfCounters) AddItem(counterPath string, objectName string, instan
 				continue
 			}
 
+			var counterHandle PDH_HCOUNTER
+			if !m.LocalizeWildcardsExpansion && !strings.Contains(origCounterName, "*") {
+				// The expanded paths are localized on non-English systems.
+				// Keep the configured object and counter names and only take
+				// the expanded instance, so the metrics stay in English.
+				objectName = origObjectName
+				counterName = origCounterName
+				if instance == "" {
+					// Single instance objects have no instance part
+					counterPath = "\\" + objectName + "\\" + counterName
+				} else {
+					counterPath = formatPath(objectName, instance, counterName)
+				}
+				counterHandle, err = m.query.AddEnglishCounterToQuery(counterPath)
+			} else {
+				counterHandle, err = m.query.AddCounterToQuery(counterPath)
+			}
+			if err != nil {
+				// The expanded counters may vanish before being added,
+				// e.g. when a process exits, skip them
+				m.Log.Errorf("Adding counter %q failed, skipping it: %v", counterPath, err)
+				continue
+			}
+
 			newItem := &counter{counterPath, objectName, counterName, instance, measurement,
 				includeTotal, counterHandle}
 			m.counters = append(m.counters, newItem)
@@ -305,20 +336,23 @@ func (m *Win_PerfCounters) AddItem(counterPath string, objectName string, instan
 	return nil
 }
 
+// formatPath builds a counter path, omitting the instance part for the
+// "------" placeholder of single instance objects
+func formatPath(objectName string, instance string, counterName string) string {
+	if instance == "------" {
+		return "\\" + objectName + "\\" + counterName
+	}
+	return "\\" + objectName + "(" + instance + ")\\" + counterName
+}
+
 func (m *Win_PerfCounters) ParseConfig() error {
-	var counterPath string
 
 	if len(m.Object) > 0 {
 		for _, PerfObject := range m.Object {
 			for _, counter := range PerfObject.Counters {
 				for _, instance := range PerfObject.Instances {
 					objectname := PerfObject.ObjectName
-
-					if instance == "------" {
-						counterPath = "\\" + objectname + "\\" + counter
-					} else {
-						counterPath = "\\" + objectname + "(" + instance + ")\\" + counter
-					}
+					counterPath := formatPath(objectname, instance, counter)
 
 					err := m.AddItem(counterPath, objectname, instance, counter, PerfObject.Measurement, PerfObject.IncludeTotal)
 
@@ -477,6 +511,10 @@ func isKnownCounterDataError(err error) bool {
 
 func init() {
 	inputs.Add("win_perf_counters", func() telegraf.Input {
-		return &Win_PerfCounters{query: &PerformanceQueryImpl{}, CountersRefreshInterval: config.Duration(time.Second * 60)}
+		return &Win_PerfCounters{
+			query:                      &PerformanceQueryImpl{},
+			CountersRefreshInterval:    config.Duration(time.Second * 60),
+			LocalizeWildcardsExpansion: true,
+		}
 	})
 }
@@ -507,6 +507,81 @@ func TestParseConfigExpand(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestParseConfigExpandLocalized(t *testing.T) {
+	localizedPaths := []string{"\\LO(I1)\\LC", "\\LO(I2)\\LC"}
+	englishPaths := []string{"\\O(I1)\\C", "\\O(I2)\\C"}
+
+	newQuery := func() *FakePerformanceQuery {
+		counters := createCounterMap(append(localizedPaths, englishPaths...), []float64{1, 2, 1, 2}, []uint32{0, 0, 0, 0})
+		// The configured path resolves to its localized form
+		counters["\\O(*)\\C"] = testCounter{PDH_HCOUNTER(4), "\\LO(*)\\LC", 0, 0}
+		return &FakePerformanceQuery{
+			counters: counters,
+			expandPaths: map[string][]string{
+				"\\LO(*)\\LC": localizedPaths,
+			},
+			vistaAndNewer: true,
+		}
+	}
+
+	for _, tt := range []struct {
+		localize bool
+		object   string
+		counter  string
+		paths    []string
+	}{
+		{true, "LO", "LC", localizedPaths},
+		{false, "O", "C", englishPaths},
+	} {
+		m := Win_PerfCounters{
+			Log:                        testutil.Logger{},
+			UseWildcardsExpansion:      true,
+			LocalizeWildcardsExpansion: tt.localize,
+			Object:                     createPerfObject("m", "O", []string{"*"}, []string{"C"}, true, false),
+			query:                      newQuery(),
+		}
+		require.NoError(t, m.query.Open())
+		require.NoError(t, m.ParseConfig())
+		require.Len(t, m.counters, 2)
+		for i, c := range m.counters {
+			require.Equal(t, tt.paths[i], c.counterPath)
+			require.Equal(t, tt.object, c.objectName)
+			require.Equal(t, tt.counter, c.counter)
+		}
+		require.NoError(t, m.query.Close())
+	}
+}
+
+func TestParseConfigExpandSkipsInvalidCounters(t *testing.T) {
+	counters := createCounterMap([]string{"\\O(I1)\\C"}, []float64{1}, []uint32{0})
+	// The configured paths resolve to their localized form
+	counters["\\O(*)\\C"] = testCounter{PDH_HCOUNTER(2), "\\LO(*)\\LC", 0, 0}
+	counters["\\S\\C"] = testCounter{PDH_HCOUNTER(3), "\\LS\\LC", 0, 0}
+	m := Win_PerfCounters{
+		Log:                   testutil.Logger{},
+		UseWildcardsExpansion: true,
+		Object: append(
+			createPerfObject("m", "O", []string{"*"}, []string{"C"}, true, false),
+			createPerfObject("m", "S", []string{"------"}, []string{"C"}, true, false)...),
+		query: &FakePerformanceQuery{
+			counters: counters,
+			expandPaths: map[string][]string{
+				// I2 vanished before being added
+				"\\LO(*)\\LC": {"\\LO(I1)\\LC", "\\LO(I2)\\LC"},
+				"\\LS\\LC":    {"\\LS\\LC"},
+			},
+			vistaAndNewer: true,
+		},
+	}
+	require.NoError(t, m.query.Open())
+	require.NoError(t, m.ParseConfig())
+	require.Len(t, m.counters, 2)
+	require.Equal(t, "\\O(I1)\\C", m.counters[0].counterPath)
+	// Single instance objects are added without an instance part
+	require.Equal(t, "\\S\\C", m.counters[1].counterPath)
+	require.NoError(t, m.query.Close())
+}
+
 func TestSimpleGather(t *testing.T) {
 	var err error
 	if testing.Short() {
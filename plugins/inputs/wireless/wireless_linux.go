@@ -6,21 +6,15 @@ import (
 	"bytes"
 	"io/ioutil"
 	"log"
-	"os"
 	"path"
 	"strconv"
 	"strings"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/host"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
-// default host proc path
-const defaultHostProc = "/proc"
-
-// env host proc variable name
-const envProc = "HOST_PROC"
-
 // length of wireless interface fields
 const interfaceFieldLength = 10
 
@@ -123,18 +117,8 @@ func loadWirelessTable(table []byte) ([]*wirelessInterface, error) {
 // if it is empty then try read from env variable
 func (w *Wireless) loadPath() {
 	if w.HostProc == "" {
-		w.HostProc = proc(envProc, defaultHostProc)
-	}
-}
-
-// proc can be used to read file paths from env
-func proc(env, path string) string {
-	// try to read full file path
-	if p := os.Getenv(env); p != "" {
-		return p
+		w.HostProc = host.Proc()
 	}
-	// return default path
-	return path
 }
 
 func init() {
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.application_insights
+// +build !custom outputs outputs.application_insights
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/application_insights" // register plugin
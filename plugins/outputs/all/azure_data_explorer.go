@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.azure_data_explorer
+// +build !custom outputs outputs.azure_data_explorer
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/azure_data_explorer" // register plugin
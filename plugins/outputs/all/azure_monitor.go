@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.azure_monitor
+// +build !custom outputs outputs.azure_monitor
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/azure_monitor" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.bigquery
+// +build !custom outputs outputs.bigquery
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/bigquery" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.cloud_pubsub
+// +build !custom outputs outputs.cloud_pubsub
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/cloud_pubsub" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.cloudwatch
+// +build !custom outputs outputs.cloudwatch
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/cloudwatch" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.cloudwatch_logs
+// +build !custom outputs outputs.cloudwatch_logs
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/cloudwatch_logs" // register plugin
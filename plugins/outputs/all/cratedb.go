@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.cratedb
+// +build !custom outputs outputs.cratedb
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/cratedb" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.dynatrace
+// +build !custom outputs outputs.dynatrace
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/dynatrace" // register plugin
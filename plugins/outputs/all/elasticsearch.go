@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.elasticsearch
+// +build !custom outputs outputs.elasticsearch
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/elasticsearch" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.influxdb_v2
+// +build !custom outputs outputs.influxdb_v2
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/influxdb_v2" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.loki
+// +build !custom outputs outputs.loki
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/loki" // register plugin
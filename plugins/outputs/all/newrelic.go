@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.newrelic
+// +build !custom outputs outputs.newrelic
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/newrelic" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.prometheus_client
+// +build !custom outputs outputs.prometheus_client
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/prometheus_client" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.riemann_legacy
+// +build !custom outputs outputs.riemann_legacy
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/riemann_legacy" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.sql
+// +build !custom outputs outputs.sql
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/sql" // register plugin
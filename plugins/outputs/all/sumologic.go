@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.sumologic
+// +build !custom outputs outputs.sumologic
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/sumologic" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.timestream
+// +build !custom outputs outputs.timestream
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/timestream" // register plugin
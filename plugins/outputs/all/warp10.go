@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.warp10
+// +build !custom outputs outputs.warp10
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/warp10" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.wavefront
+// +build !custom outputs outputs.wavefront
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/wavefront" // register plugin
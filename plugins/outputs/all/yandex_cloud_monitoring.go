@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || outputs || outputs.yandex_cloud_monitoring
+// +build !custom outputs outputs.yandex_cloud_monitoring
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/outputs/yandex_cloud_monitoring" // register plugin
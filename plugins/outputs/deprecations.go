@@ -0,0 +1,6 @@
+package outputs
+
+import "github.com/influxdata/telegraf"
+
+// Deprecations lists the deprecated outputs.
+var Deprecations = map[string]telegraf.DeprecationInfo{}
@@ -1,7 +1,6 @@
 package graphite
 
 import (
-	"crypto/tls"
 	"errors"
 	"io"
 	"math/rand"
@@ -9,6 +8,7 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/resolver"
 	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/serializers"
@@ -92,12 +92,12 @@ func (g *Graphite) Connect() error {
 	var conns []net.Conn
 	for _, server := range g.Servers {
 		// Dialer with timeout
-		d := net.Dialer{Timeout: time.Duration(g.Timeout) * time.Second}
+		d := resolver.NewDialer(net.Dialer{Timeout: time.Duration(g.Timeout) * time.Second})
 
 		// Get secure connection if tls config is set
 		var conn net.Conn
 		if tlsConfig != nil {
-			conn, err = tls.DialWithDialer(&d, "tcp", server, tlsConfig)
+			conn, err = d.DialTLS("tcp", server, tlsConfig)
 		} else {
 			conn, err = d.Dial("tcp", server)
 		}
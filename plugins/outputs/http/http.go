@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -63,8 +62,9 @@ var sampleConfig = `
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_OUTPUT.md
   # data_format = "influx"
 
-  ## HTTP Content-Encoding for write request body, can be set to "gzip" to
-  ## compress body or "identity" to apply no encoding.
+  ## HTTP Content-Encoding for write request body, can be set to "gzip",
+  ## "zlib", "snappy" or "zstd" to compress body or "identity" to apply no
+  ## encoding.
   # content_encoding = "identity"
 
   ## Additional HTTP headers
@@ -76,6 +76,11 @@ var sampleConfig = `
   ## Maximum amount of time before idle connection is closed.
   ## Zero means no limit.
   # idle_conn_timeout = 0
+
+  ## Log the method, URL, status, duration and byte counts of every request
+  ## for troubleshooting.  Credentials and query parameter values are
+  ## redacted, consecutive failed requests to a URL are counted as attempts.
+  # log_requests = false
 `
 
 const (
@@ -96,6 +101,7 @@ type HTTP struct {
 
 	client     *http.Client
 	serializer serializers.Serializer
+	encoder    internal.ContentEncoder
 }
 
 func (h *HTTP) SetSerializer(serializer serializers.Serializer) {
@@ -111,6 +117,12 @@ func (h *HTTP) Connect() error {
 		return fmt.Errorf("invalid method [%s] %s", h.URL, h.Method)
 	}
 
+	encoder, err := internal.NewContentEncoder(h.ContentEncoding)
+	if err != nil {
+		return err
+	}
+	h.encoder = encoder
+
 	ctx := context.Background()
 	client, err := h.HTTPClientConfig.CreateClient(ctx, h.Log)
 	if err != nil {
@@ -144,19 +156,12 @@ func (h *HTTP) Write(metrics []telegraf.Metric) error {
 }
 
 func (h *HTTP) write(reqBody []byte) error {
-	var reqBodyBuffer io.Reader = bytes.NewBuffer(reqBody)
-
-	var err error
-	if h.ContentEncoding == "gzip" {
-		rc, err := internal.CompressWithGzip(reqBodyBuffer)
-		if err != nil {
-			return err
-		}
-		defer rc.Close()
-		reqBodyBuffer = rc
+	reqBody, err := h.encoder.Encode(reqBody)
+	if err != nil {
+		return err
 	}
 
-	req, err := http.NewRequest(h.Method, h.URL, reqBodyBuffer)
+	req, err := http.NewRequest(h.Method, h.URL, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
@@ -167,8 +172,8 @@ func (h *HTTP) write(reqBody []byte) error {
 
 	req.Header.Set("User-Agent", internal.ProductToken())
 	req.Header.Set("Content-Type", defaultContentType)
-	if h.ContentEncoding == "gzip" {
-		req.Header.Set("Content-Encoding", "gzip")
+	if h.ContentEncoding != "" && h.ContentEncoding != "identity" {
+		req.Header.Set("Content-Encoding", h.ContentEncoding)
 	}
 	for k, v := range h.Headers {
 		if strings.ToLower(k) == "host" {
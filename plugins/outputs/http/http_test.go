@@ -1,7 +1,6 @@
 package http
 
 import (
-	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -230,7 +229,7 @@ func TestContentType(t *testing.T) {
 	}
 }
 
-func TestContentEncodingGzip(t *testing.T) {
+func TestContentEncoding(t *testing.T) {
 	ts := httptest.NewServer(http.NotFoundHandler())
 	defer ts.Close()
 
@@ -258,6 +257,30 @@ func TestContentEncodingGzip(t *testing.T) {
 			},
 			expected: "gzip",
 		},
+		{
+			name: "zlib content_encoding",
+			plugin: &HTTP{
+				URL:             u.String(),
+				ContentEncoding: "zlib",
+			},
+			expected: "zlib",
+		},
+		{
+			name: "snappy content_encoding",
+			plugin: &HTTP{
+				URL:             u.String(),
+				ContentEncoding: "snappy",
+			},
+			expected: "snappy",
+		},
+		{
+			name: "zstd content_encoding",
+			plugin: &HTTP{
+				URL:             u.String(),
+				ContentEncoding: "zstd",
+			},
+			expected: "zstd",
+		},
 	}
 
 	for _, tt := range tests {
@@ -265,14 +288,11 @@ func TestContentEncodingGzip(t *testing.T) {
 			ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, tt.expected, r.Header.Get("Content-Encoding"))
 
-				body := r.Body
-				var err error
-				if r.Header.Get("Content-Encoding") == "gzip" {
-					body, err = gzip.NewReader(r.Body)
-					require.NoError(t, err)
-				}
-
-				payload, err := ioutil.ReadAll(body)
+				decoder, err := internal.NewContentDecoder(r.Header.Get("Content-Encoding"))
+				require.NoError(t, err)
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				payload, err := decoder.Decode(body)
 				require.NoError(t, err)
 				require.Contains(t, string(payload), "cpu value=42")
 
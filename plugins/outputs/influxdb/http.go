@@ -13,11 +13,13 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/resolver"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
@@ -46,6 +48,9 @@ type APIError struct {
 	StatusCode  int
 	Title       string
 	Description string
+
+	// RetryAfter is the delay requested by the Retry-After header
+	RetryAfter time.Duration
 }
 
 func (e APIError) Error() string {
@@ -103,6 +108,7 @@ type HTTPConfig struct {
 	ExcludeRetentionPolicyTag bool
 	Consistency               string
 	SkipDatabaseCreation      bool
+	RetryStatusCodes          []int
 
 	InfluxUintSupport bool `toml:"influx_uint_support"`
 	Serializer        *influx.Serializer
@@ -158,12 +164,17 @@ func NewHTTPClient(config HTTPConfig) (*httpClient, error) {
 		config.Serializer = influx.NewSerializer()
 	}
 
+	if config.RetryStatusCodes == nil {
+		config.RetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+	}
+
 	var transport *http.Transport
 	switch config.URL.Scheme {
 	case "http", "https":
 		transport = &http.Transport{
 			Proxy:           proxy,
 			TLSClientConfig: config.TLSConfig,
+			DialContext:     resolver.NewDialer(net.Dialer{}).DialContext,
 		}
 	case "unix":
 		transport = &http.Transport{
@@ -278,8 +289,12 @@ func (c *httpClient) Write(ctx context.Context, metrics []telegraf.Metric) error
 		return c.writeBatch(ctx, c.config.Database, c.config.RetentionPolicy, metrics)
 	}
 
+	// Batches are written in the order the database and retention policy
+	// first occur in the metrics.
+	var order []dbrp
 	batches := make(map[dbrp][]telegraf.Metric)
-	for _, metric := range metrics {
+	indices := make(map[dbrp][]int)
+	for i, metric := range metrics {
 		db, ok := metric.GetTag(c.config.DatabaseTag)
 		if !ok {
 			db = c.config.Database
@@ -307,10 +322,16 @@ func (c *httpClient) Write(ctx context.Context, metrics []telegraf.Metric) error
 			}
 		}
 
+		if _, ok := batches[dbrp]; !ok {
+			order = append(order, dbrp)
+		}
 		batches[dbrp] = append(batches[dbrp], metric)
+		indices[dbrp] = append(indices[dbrp], i)
 	}
 
-	for dbrp, batch := range batches {
+	var accepted []int
+	for _, dbrp := range order {
+		batch := batches[dbrp]
 		if !c.config.SkipDatabaseCreation && !c.createDatabaseExecuted[dbrp.Database] {
 			err := c.CreateDatabase(ctx, dbrp.Database)
 			if err != nil {
@@ -321,8 +342,16 @@ func (c *httpClient) Write(ctx context.Context, metrics []telegraf.Metric) error
 
 		err := c.writeBatch(ctx, dbrp.Database, dbrp.RetentionPolicy, batch)
 		if err != nil {
+			// Report the batches already written so they are not sent again.
+			if len(accepted) > 0 {
+				return &telegraf.PartialWriteError{
+					Err:           err,
+					MetricsAccept: accepted,
+				}
+			}
 			return err
 		}
+		accepted = append(accepted, indices[dbrp]...)
 	}
 	return nil
 }
@@ -385,6 +414,18 @@ func (c *httpClient) writeBatch(ctx context.Context, db, rp string, metrics []te
 		}
 	}
 
+	// Overloaded servers and proxies ask for the request to be retried
+	for _, code := range c.config.RetryStatusCodes {
+		if resp.StatusCode == code {
+			return &APIError{
+				StatusCode:  resp.StatusCode,
+				Title:       resp.Status,
+				Description: desc,
+				RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
+			}
+		}
+	}
+
 	//checks for any 4xx code and drops metric and retrying will not make the request work
 	if len(resp.Status) > 0 && resp.Status[0] == '4' {
 		c.log.Errorf("E! [outputs.influxdb] Failed to write metric (will be dropped: %s): %s\n", resp.Status, desc)
@@ -436,6 +477,23 @@ func (c *httpClient) writeBatch(ctx context.Context, db, rp string, metrics []te
 	}
 }
 
+// parseRetryAfter returns the delay of a Retry-After header given in seconds
+// or as a date, or zero if it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
 func (c *httpClient) makeQueryRequest(query string) (*http.Request, error) {
 	queryURL, err := makeQueryURL(c.config.URL)
 	if err != nil {
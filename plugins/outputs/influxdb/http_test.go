@@ -468,6 +468,50 @@ func TestHTTP_Write(t *testing.T) {
 				require.Equal(t, expected, err)
 			},
 		},
+		{
+			name: "too many requests is retried after delay",
+			config: influxdb.HTTPConfig{
+				URL:      u,
+				Database: "telegraf",
+				Log:      testutil.Logger{},
+			},
+			queryHandlerFunc: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			errFunc: func(t *testing.T, err error) {
+				expected := &influxdb.APIError{
+					StatusCode: 429,
+					Title:      "429 Too Many Requests",
+					RetryAfter: 7 * time.Second,
+				}
+				require.Equal(t, expected, err)
+			},
+		},
+		{
+			name: "too many requests is dropped without retry status code",
+			config: influxdb.HTTPConfig{
+				URL:              u,
+				Database:         "telegraf",
+				RetryStatusCodes: []int{},
+				Log:              testutil.Logger{},
+			},
+			queryHandlerFunc: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+		},
+		{
+			name: "bad request is dropped",
+			config: influxdb.HTTPConfig{
+				URL:      u,
+				Database: "telegraf",
+				Log:      testutil.Logger{},
+			},
+			queryHandlerFunc: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "invalid field format"}`))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1192,3 +1236,42 @@ func TestDBNotFoundShouldDropMetricWhenSkipDatabaseCreateIsTrue(t *testing.T) {
 	require.Contains(t, logger.LastError, "database not found")
 	require.NoError(t, err)
 }
+
+func TestDBRPTagsPartialWrite(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	u, err := url.Parse(fmt.Sprintf("http://%s", ts.Listener.Addr().String()))
+	require.NoError(t, err)
+
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("db") == "bar" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	config := influxdb.HTTPConfig{
+		URL:                  u,
+		SkipDatabaseCreation: true,
+		Database:             "telegraf",
+		DatabaseTag:          "database",
+		Log:                  testutil.Logger{},
+	}
+	client, err := influxdb.NewHTTPClient(config)
+	require.NoError(t, err)
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"database": "foo"}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0)),
+		testutil.MustMetric("cpu", map[string]string{"database": "bar"}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0)),
+		testutil.MustMetric("cpu", map[string]string{"database": "foo"}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0)),
+	}
+
+	err = client.Write(context.Background(), metrics)
+	require.Error(t, err)
+	partial, ok := err.(*telegraf.PartialWriteError)
+	require.True(t, ok)
+	require.Equal(t, []int{0, 2}, partial.MetricsAccept)
+	require.Empty(t, partial.MetricsReject)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -52,9 +53,18 @@ type InfluxDB struct {
 	InfluxUintSupport         bool              `toml:"influx_uint_support"`
 	tls.ClientConfig
 
+	// Tried in order once all urls failed
+	SecondaryURLs []string `toml:"secondary_urls"`
+
+	// Retry responses with these status codes within a flush
+	RetryStatusCodes []int           `toml:"retry_status_codes"`
+	RetryBudget      config.Duration `toml:"retry_budget"`
+	RetryBackoff     config.Duration `toml:"retry_backoff"`
+
 	Precision string // precision deprecated in 1.0; value is ignored
 
-	clients []Client
+	clients   []Client
+	secondary []Client
 
 	CreateHTTPClientF func(config *HTTPConfig) (Client, error)
 	CreateUDPClientF  func(config *UDPConfig) (Client, error)
@@ -71,6 +81,10 @@ var sampleConfig = `
   # urls = ["udp://127.0.0.1:8089"]
   # urls = ["http://127.0.0.1:8086"]
 
+  ## URLs written to in the given order when writing to all of the urls
+  ## failed, e.g. a standby cluster or a proxy.
+  # secondary_urls = []
+
   ## The target database for metrics; will be created as needed.
   ## For UDP url endpoint database needs to be configured on server side.
   # database = "telegraf"
@@ -105,6 +119,16 @@ var sampleConfig = `
   ## Timeout for HTTP messages.
   # timeout = "5s"
 
+  ## HTTP responses with these status codes are retried, honoring the
+  ## Retry-After header.  Other 4xx responses drop the metrics.
+  # retry_status_codes = [429, 503]
+
+  ## Total time a flush may wait before retrying a URL, 0 moves on to the next
+  ## URL right away.  The wait starts at retry_backoff, doubling with every
+  ## retry, or the Retry-After of the response if longer.
+  # retry_budget = "0s"
+  # retry_backoff = "1s"
+
   ## HTTP Basic Auth
   # username = "telegraf"
   # password = "metricsmetricsmetricsmetrics"
@@ -143,6 +167,10 @@ var sampleConfig = `
 func (i *InfluxDB) Connect() error {
 	ctx := context.Background()
 
+	if i.RetryBudget > 0 && i.RetryBackoff <= 0 {
+		return errors.New("retry_backoff must be positive")
+	}
+
 	urls := make([]string, 0, len(i.URLs))
 	urls = append(urls, i.URLs...)
 	if i.URL != "" {
@@ -154,46 +182,55 @@ func (i *InfluxDB) Connect() error {
 	}
 
 	for _, u := range urls {
-		parts, err := url.Parse(u)
+		c, err := i.newClient(ctx, u)
 		if err != nil {
-			return fmt.Errorf("error parsing url [%q]: %v", u, err)
+			return err
 		}
+		i.clients = append(i.clients, c)
+	}
 
-		var proxy *url.URL
-		if len(i.HTTPProxy) > 0 {
-			proxy, err = url.Parse(i.HTTPProxy)
-			if err != nil {
-				return fmt.Errorf("error parsing proxy_url [%s]: %v", i.HTTPProxy, err)
-			}
+	for _, u := range i.SecondaryURLs {
+		c, err := i.newClient(ctx, u)
+		if err != nil {
+			return err
 		}
+		i.secondary = append(i.secondary, c)
+	}
 
-		switch parts.Scheme {
-		case "udp", "udp4", "udp6":
-			c, err := i.udpClient(parts)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			i.clients = append(i.clients, c)
-		case "http", "https", "unix":
-			c, err := i.httpClient(ctx, parts, proxy)
-			if err != nil {
-				return err
-			}
+func (i *InfluxDB) newClient(ctx context.Context, u string) (Client, error) {
+	parts, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url [%q]: %v", u, err)
+	}
 
-			i.clients = append(i.clients, c)
-		default:
-			return fmt.Errorf("unsupported scheme [%q]: %q", u, parts.Scheme)
+	var proxy *url.URL
+	if len(i.HTTPProxy) > 0 {
+		proxy, err = url.Parse(i.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing proxy_url [%s]: %v", i.HTTPProxy, err)
 		}
 	}
 
-	return nil
+	switch parts.Scheme {
+	case "udp", "udp4", "udp6":
+		return i.udpClient(parts)
+	case "http", "https", "unix":
+		return i.httpClient(ctx, parts, proxy)
+	default:
+		return nil, fmt.Errorf("unsupported scheme [%q]: %q", u, parts.Scheme)
+	}
 }
 
 func (i *InfluxDB) Close() error {
 	for _, client := range i.clients {
 		client.Close()
 	}
+	for _, client := range i.secondary {
+		client.Close()
+	}
 	return nil
 }
 
@@ -206,22 +243,35 @@ func (i *InfluxDB) SampleConfig() string {
 }
 
 // Write sends metrics to one of the configured servers, logging each
-// unsuccessful. If all servers fail, return an error.
+// unsuccessful.  The urls are tried in random order followed by the
+// secondary_urls in order.  If all servers fail, return an error.
 func (i *InfluxDB) Write(metrics []telegraf.Metric) error {
 	ctx := context.Background()
 
+	clients := make([]Client, 0, len(i.clients)+len(i.secondary))
+	for _, n := range rand.Perm(len(i.clients)) {
+		clients = append(clients, i.clients[n])
+	}
+	clients = append(clients, i.secondary...)
+
+	deadline := time.Now().Add(time.Duration(i.RetryBudget))
 	allErrorsAreDatabaseNotFoundErrors := true
 	var err error
-	p := rand.Perm(len(i.clients))
-	for _, n := range p {
-		client := i.clients[n]
-		err = client.Write(ctx, metrics)
+	for _, client := range clients {
+		err = i.write(ctx, client, metrics, deadline)
 		if err == nil {
 			return nil
 		}
 
 		i.Log.Errorf("When writing to [%s]: %v", client.URL(), err)
 
+		// Part of the metrics are already written, only the remaining ones
+		// may be sent to another server.
+		var partial *telegraf.PartialWriteError
+		if errors.As(err, &partial) {
+			return err
+		}
+
 		switch apiError := err.(type) {
 		case *DatabaseNotFoundError:
 			if !i.SkipDatabaseCreation {
@@ -235,6 +285,12 @@ func (i *InfluxDB) Write(metrics []telegraf.Metric) error {
 					continue
 				}
 			}
+		case *APIError:
+			// The metrics are kept for the next flush if the server asked
+			// for a retry
+			if i.retryStatus(apiError.StatusCode) {
+				allErrorsAreDatabaseNotFoundErrors = false
+			}
 		}
 	}
 
@@ -245,6 +301,45 @@ func (i *InfluxDB) Write(metrics []telegraf.Metric) error {
 	return errors.New("could not write any address")
 }
 
+// write sends the metrics to the client, retrying responses with one of the
+// retry_status_codes as long as the wait ends before the deadline.
+func (i *InfluxDB) write(ctx context.Context, client Client, metrics []telegraf.Metric, deadline time.Time) error {
+	backoff := time.Duration(i.RetryBackoff)
+	for {
+		err := client.Write(ctx, metrics)
+
+		// Retrying a partial write would send the accepted metrics again
+		var partial *telegraf.PartialWriteError
+		if err == nil || i.RetryBudget <= 0 || errors.As(err, &partial) {
+			return err
+		}
+		apiError, ok := err.(*APIError)
+		if !ok || !i.retryStatus(apiError.StatusCode) {
+			return err
+		}
+
+		wait := backoff
+		if apiError.RetryAfter > wait {
+			wait = apiError.RetryAfter
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		i.Log.Warnf("When writing to [%s]: %v; retrying in %s", client.URL(), err, wait)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+func (i *InfluxDB) retryStatus(code int) bool {
+	for _, c := range i.RetryStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *InfluxDB) udpClient(url *url.URL) (Client, error) {
 	config := &UDPConfig{
 		URL:            url,
@@ -285,6 +380,7 @@ func (i *InfluxDB) httpClient(ctx context.Context, url *url.URL, proxy *url.URL)
 		RetentionPolicyTag:        i.RetentionPolicyTag,
 		ExcludeRetentionPolicyTag: i.ExcludeRetentionPolicyTag,
 		Consistency:               i.WriteConsistency,
+		RetryStatusCodes:          i.RetryStatusCodes,
 		Serializer:                i.newSerializer(),
 		Log:                       i.Log,
 	}
@@ -324,7 +420,9 @@ func init() {
 			CreateUDPClientF: func(config *UDPConfig) (Client, error) {
 				return NewUDPClient(*config)
 			},
-			ContentEncoding: "gzip",
+			ContentEncoding:  "gzip",
+			RetryStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+			RetryBackoff:     config.Duration(time.Second),
 		}
 	})
 }
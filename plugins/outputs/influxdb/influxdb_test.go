@@ -214,3 +214,80 @@ func TestWriteRecreateDatabaseIfDatabaseNotFound(t *testing.T) {
 	// We only have one URL, so we expect an error
 	require.Error(t, err)
 }
+
+func TestWriteRetryStatusCodes(t *testing.T) {
+	var attempts int
+	var retryAfter time.Duration
+	output := influxdb.InfluxDB{
+		URLs:                 []string{"http://localhost:8086"},
+		SkipDatabaseCreation: true,
+		RetryStatusCodes:     []int{http.StatusTooManyRequests},
+		RetryBudget:          config.Duration(time.Second),
+		RetryBackoff:         config.Duration(10 * time.Millisecond),
+		CreateHTTPClientF: func(config *influxdb.HTTPConfig) (influxdb.Client, error) {
+			return &MockClient{
+				WriteF: func(ctx context.Context, metrics []telegraf.Metric) error {
+					attempts++
+					if attempts < 3 {
+						return &influxdb.APIError{
+							StatusCode: http.StatusTooManyRequests,
+							Title:      "429 Too Many Requests",
+							RetryAfter: retryAfter,
+						}
+					}
+					return nil
+				},
+				URLF: func() string {
+					return "http://localhost:8086"
+				},
+			}, nil
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, output.Connect())
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	require.NoError(t, output.Write([]telegraf.Metric{m}))
+	require.Equal(t, 3, attempts)
+
+	// A Retry-After beyond the budget gives up without waiting
+	attempts = 0
+	retryAfter = time.Minute
+	start := time.Now()
+	require.Error(t, output.Write([]telegraf.Metric{m}))
+	require.Equal(t, 1, attempts)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestWriteSecondaryURLs(t *testing.T) {
+	var written []string
+	output := influxdb.InfluxDB{
+		URLs:                 []string{"http://primary:8086"},
+		SecondaryURLs:        []string{"http://secondary-1:8086", "http://secondary-2:8086"},
+		SkipDatabaseCreation: true,
+		CreateHTTPClientF: func(config *influxdb.HTTPConfig) (influxdb.Client, error) {
+			u := config.URL.String()
+			return &MockClient{
+				WriteF: func(ctx context.Context, metrics []telegraf.Metric) error {
+					written = append(written, u)
+					if u == "http://secondary-2:8086" {
+						return nil
+					}
+					return &influxdb.APIError{
+						StatusCode: http.StatusBadGateway,
+						Title:      "502 Bad Gateway",
+					}
+				},
+				URLF: func() string {
+					return u
+				},
+			}, nil
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, output.Connect())
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	require.NoError(t, output.Write([]telegraf.Metric{m}))
+	require.Equal(t, []string{"http://primary:8086", "http://secondary-1:8086", "http://secondary-2:8086"}, written)
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/resolver"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
@@ -53,7 +54,7 @@ func NewUDPClient(config UDPConfig) (*udpClient, error) {
 
 	dialer := config.Dialer
 	if dialer == nil {
-		dialer = &netDialer{net.Dialer{}}
+		dialer = &netDialer{resolver.NewDialer(net.Dialer{})}
 	}
 
 	client := &udpClient{
@@ -120,7 +121,7 @@ func (c *udpClient) CreateDatabase(_ context.Context, _ string) error {
 }
 
 type netDialer struct {
-	net.Dialer
+	*resolver.Dialer
 }
 
 func (d *netDialer) DialContext(ctx context.Context, network, address string) (Conn, error) {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -38,6 +37,16 @@ func (e APIError) Error() string {
 const (
 	defaultRequestTimeout = time.Second * 5
 	defaultMaxWait        = 60 // seconds
+	// The server may ask for longer pauses than our own backoff, e.g. until
+	// the write quota of the token is reset
+	maxRetryAfter = 600 // seconds
+)
+
+// Headers reporting the remaining write quota of the token and the seconds
+// until it is reset.
+var (
+	quotaRemainingHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining"}
+	quotaResetHeaders     = []string{"X-RateLimit-Reset", "RateLimit-Reset"}
 )
 
 type HTTPConfig struct {
@@ -169,8 +178,8 @@ func (g genericRespError) Error() string {
 }
 
 func (c *httpClient) Write(ctx context.Context, metrics []telegraf.Metric) error {
-	if c.retryTime.After(time.Now()) {
-		return errors.New("retry time has not elapsed")
+	if wait := time.Until(c.retryTime); wait > 0 {
+		return fmt.Errorf("retry time has not elapsed, writes are paused for %s", wait.Round(time.Second))
 	}
 
 	batches := make(map[string][]telegraf.Metric)
@@ -234,6 +243,8 @@ func (c *httpClient) writeBatch(ctx context.Context, bucket string, metrics []te
 	}
 	defer resp.Body.Close()
 
+	c.trackQuota(resp.Header)
+
 	switch resp.StatusCode {
 	case
 		// this is the expected response:
@@ -278,7 +289,9 @@ func (c *httpClient) writeBatch(ctx context.Context, bucket string, metrics []te
 		// ^ these handle the cases where the server is likely overloaded, and may not be able to say so.
 		c.retryCount++
 		retryDuration := c.getRetryDuration(resp.Header)
-		c.retryTime = time.Now().Add(retryDuration)
+		if retryTime := time.Now().Add(retryDuration); retryTime.After(c.retryTime) {
+			c.retryTime = retryTime
+		}
 		log.Printf("W! [outputs.influxdb_v2] Failed to write; will retry in %s. (%s)\n", retryDuration, resp.Status)
 		return fmt.Errorf("waiting %s for server before sending metric again", retryDuration)
 	}
@@ -308,24 +321,69 @@ func (c *httpClient) getRetryDuration(headers http.Header) time.Duration {
 	// basic exponential backoff (x^2)/40 (denominator to widen the slope)
 	// at 40 denominator, it'll take 35 retries to hit the max defaultMaxWait of 30s
 	backoff := math.Pow(float64(c.retryCount), 2) / 40
+	backoff = math.Min(backoff, defaultMaxWait)
 
 	// get any value from the header, if available
 	retryAfterHeader := float64(0)
 	retryAfterHeaderString := headers.Get("Retry-After")
 	if len(retryAfterHeaderString) > 0 {
-		var err error
-		retryAfterHeader, err = strconv.ParseFloat(retryAfterHeaderString, 64)
-		if err != nil {
+		var ok bool
+		retryAfterHeader, ok = parseRetryAfter(retryAfterHeaderString, time.Now())
+		if !ok {
 			// there was a value but we couldn't parse it? guess minimum 10 sec
 			retryAfterHeader = 10
 		}
 	}
-	// take the highest value from both, but not over the max wait.
-	retry := math.Max(backoff, retryAfterHeader)
-	retry = math.Min(retry, defaultMaxWait)
+	// take the highest value from both, the server may ask for a longer
+	// wait than our own back-off up to maxRetryAfter.
+	retry := math.Max(backoff, math.Min(retryAfterHeader, maxRetryAfter))
 	return time.Duration(retry) * time.Second
 }
 
+// parseRetryAfter returns the seconds to wait given by a Retry-After header,
+// either as a number of seconds or as a date.
+func parseRetryAfter(value string, now time.Time) (float64, bool) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return math.Max(seconds, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return math.Max(date.Sub(now).Seconds(), 0), true
+	}
+	return 0, false
+}
+
+// trackQuota pauses the writes once the server reports the write quota of
+// the token as used up, until the quota is reset.
+func (c *httpClient) trackQuota(headers http.Header) {
+	remaining, ok := headerValue(headers, quotaRemainingHeaders)
+	if !ok || remaining > 0 {
+		return
+	}
+	reset, ok := headerValue(headers, quotaResetHeaders)
+	if !ok || reset <= 0 {
+		return
+	}
+	reset = math.Min(reset, maxRetryAfter)
+
+	retryTime := time.Now().Add(time.Duration(reset * float64(time.Second)))
+	if retryTime.After(c.retryTime) {
+		c.retryTime = retryTime
+		log.Printf("W! [outputs.influxdb_v2] Write quota of token used up; pausing writes for %s\n",
+			time.Duration(reset*float64(time.Second)).Round(time.Second))
+	}
+}
+
+// headerValue returns the number in the first of the headers present.
+func headerValue(headers http.Header, keys []string) (float64, bool) {
+	for _, key := range keys {
+		if value := headers.Get(key); value != "" {
+			v, err := strconv.ParseFloat(value, 64)
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
+
 func (c *httpClient) makeWriteRequest(url string, body io.Reader) (*http.Request, error) {
 	var err error
 
@@ -72,3 +72,26 @@ func TestExponentialBackoffCalculation(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryAfterHeader(t *testing.T) {
+	c := &httpClient{retryCount: 1}
+	tests := []struct {
+		name       string
+		retryAfter string
+		expected   time.Duration
+	}{
+		{name: "seconds", retryAfter: "30", expected: 30 * time.Second},
+		{name: "date", retryAfter: time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat), expected: 119 * time.Second},
+		{name: "past date", retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0},
+		{name: "beyond max", retryAfter: "3600", expected: 600 * time.Second},
+		{name: "invalid", retryAfter: "soon", expected: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			headers.Set("Retry-After", tt.retryAfter)
+			// The date is given in whole seconds
+			require.InDelta(t, float64(tt.expected), float64(c.getRetryDuration(headers)), float64(time.Second))
+		})
+	}
+}
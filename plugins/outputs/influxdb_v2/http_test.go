@@ -111,3 +111,66 @@ func TestWriteBucketTagWorksOnRetry(t *testing.T) {
 	err = client.Write(ctx, metrics)
 	require.NoError(t, err)
 }
+
+func TestWritePausedByRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+	}{
+		{
+			name:    "retry after",
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After": "60"},
+		},
+		{
+			name:    "quota used up",
+			status:  http.StatusNoContent,
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "60"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			client, err := influxdb.NewHTTPClient(&influxdb.HTTPConfig{
+				URL:    genURL(ts.URL),
+				Bucket: "telegraf",
+			})
+			require.NoError(t, err)
+
+			metrics := []telegraf.Metric{
+				testutil.MustMetric(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			}
+
+			ctx := context.Background()
+			err = client.Write(ctx, metrics)
+			if tt.status == http.StatusNoContent {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			// Writes are paused without contacting the server
+			err = client.Write(ctx, metrics)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "writes are paused")
+			require.Equal(t, 1, requests)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package instrumental
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"regexp"
 	"strings"
@@ -31,13 +33,27 @@ type Instrumental struct {
 	Timeout    config.Duration `toml:"timeout"`
 	Debug      bool            `toml:"debug"`
 
+	// The connection is kept open between writes, the points are queued while
+	// reconnecting with an exponential backoff
+	KeepAlivePeriod     config.Duration `toml:"keep_alive_period"`
+	ReconnectBackoffMin config.Duration `toml:"reconnect_backoff_min"`
+	ReconnectBackoffMax config.Duration `toml:"reconnect_backoff_max"`
+	MaxQueuedPoints     int             `toml:"max_queued_points"`
+
 	Log telegraf.Logger `toml:"-"`
 
-	conn net.Conn
+	conn       net.Conn
+	port       string
+	serializer serializers.Serializer
+
+	queue         []string
+	backoff       time.Duration
+	nextReconnect time.Time
 }
 
 const (
 	DefaultHost     = "collector.instrumentalapp.com"
+	DefaultPort     = "8000"
 	HelloMessage    = "hello version go/telegraf/1.1\n"
 	AuthFormat      = "authenticate %s\n"
 	HandshakeFormat = HelloMessage + AuthFormat
@@ -55,44 +71,84 @@ var sampleConfig = `
   timeout = "2s"
   ## Display Communication to Instrumental
   debug = false
+
+  ## The connection is kept open between writes and probed with TCP keep-alive
+  ## packets at this period.
+  # keep_alive_period = "30s"
+
+  ## Reconnects are delayed by a randomized, exponentially growing backoff
+  ## between these bounds.
+  # reconnect_backoff_min = "1s"
+  # reconnect_backoff_max = "1m"
+
+  ## Maximum number of points queued while the collector is unreachable.  Once
+  ## reached, writes fail and the metrics are kept in the metric buffer.
+  # max_queued_points = 10000
 `
 
-func (i *Instrumental) Connect() error {
-	connection, err := net.DialTimeout("tcp", i.Host+":8000", time.Duration(i.Timeout))
+func (i *Instrumental) Init() error {
+	if i.ReconnectBackoffMin <= 0 {
+		return errors.New("reconnect_backoff_min must be positive")
+	}
+	if i.ReconnectBackoffMax < i.ReconnectBackoffMin {
+		return errors.New("reconnect_backoff_max must not be less than reconnect_backoff_min")
+	}
+	if i.MaxQueuedPoints < 1 {
+		return errors.New("max_queued_points must be positive")
+	}
+	if i.port == "" {
+		i.port = DefaultPort
+	}
 
+	s, err := serializers.NewGraphiteSerializer(i.Prefix, i.Template, false, "strict", ".", i.Templates)
 	if err != nil {
-		i.conn = nil
 		return err
 	}
+	i.serializer = s
+	return nil
+}
 
-	err = i.authenticate(connection)
+func (i *Instrumental) Connect() error {
+	connection, err := net.DialTimeout("tcp", net.JoinHostPort(i.Host, i.port), time.Duration(i.Timeout))
 	if err != nil {
-		i.conn = nil
 		return err
 	}
 
-	return nil
-}
+	if tcp, ok := connection.(*net.TCPConn); ok && i.KeepAlivePeriod > 0 {
+		if err := tcp.SetKeepAlive(true); err != nil {
+			i.Log.Warnf("Enabling keep-alive failed: %v", err)
+		} else if err := tcp.SetKeepAlivePeriod(time.Duration(i.KeepAlivePeriod)); err != nil {
+			i.Log.Warnf("Setting keep-alive period failed: %v", err)
+		}
+	}
 
-func (i *Instrumental) Close() error {
-	i.conn.Close()
-	i.conn = nil
+	if err := i.authenticate(connection); err != nil {
+		connection.Close()
+		return err
+	}
+
+	i.conn = connection
 	return nil
 }
 
-func (i *Instrumental) Write(metrics []telegraf.Metric) error {
-	if i.conn == nil {
-		err := i.Connect()
-		if err != nil {
-			return fmt.Errorf("failed to (re)connect to Instrumental. Error: %s", err)
+func (i *Instrumental) Close() error {
+	if len(i.queue) > 0 && i.conn != nil {
+		if err := i.flush(); err != nil {
+			i.Log.Errorf("Dropping %d queued points: %v", len(i.queue), err)
 		}
 	}
+	i.disconnect()
+	return nil
+}
 
-	s, err := serializers.NewGraphiteSerializer(i.Prefix, i.Template, false, "strict", ".", i.Templates)
-	if err != nil {
-		return err
+func (i *Instrumental) disconnect() {
+	if i.conn != nil {
+		i.conn.Close()
+		i.conn = nil
 	}
+}
 
+func (i *Instrumental) Write(metrics []telegraf.Metric) error {
 	var points []string
 	var metricType string
 
@@ -111,7 +167,7 @@ func (i *Instrumental) Write(metrics []telegraf.Metric) error {
 		metricType = m.Tags()["metric_type"]
 		m.RemoveTag("metric_type")
 
-		buf, err := s.Serialize(m)
+		buf, err := i.serializer.Serialize(m)
 		if err != nil {
 			i.Log.Debugf("Could not serialize metric: %v", err)
 			continue
@@ -149,25 +205,95 @@ func (i *Instrumental) Write(metrics []telegraf.Metric) error {
 		}
 	}
 
-	allPoints := strings.Join(points, "")
-	_, err = fmt.Fprintf(i.conn, allPoints)
-
-	if err != nil {
-		if err == io.EOF {
-			i.Close()
+	// Refuse the metrics when the queue is full, so they are retried from the
+	// metric buffer instead of being dropped
+	if len(i.queue)+len(points) > i.MaxQueuedPoints {
+		if err := i.flush(); err != nil {
+			return fmt.Errorf("%d points queued: %v", len(i.queue), err)
 		}
+		if len(points) > i.MaxQueuedPoints {
+			return fmt.Errorf("batch of %d points exceeds max_queued_points", len(points))
+		}
+	}
+	i.queue = append(i.queue, points...)
 
-		return err
+	// Transient failures keep the points queued for the next write
+	if err := i.flush(); err != nil {
+		i.Log.Warnf("Queued %d points: %v", len(i.queue), err)
 	}
+	return nil
+}
 
-	// force the connection closed after sending data
-	// to deal with various disconnection scenarios and eschew holding
-	// open idle connections en masse
-	i.Close()
+// flush sends the queued points, reconnecting to the collector unless still
+// backing off from a failed attempt.
+func (i *Instrumental) flush() error {
+	if len(i.queue) == 0 {
+		return nil
+	}
 
+	if i.conn != nil && !i.alive() {
+		i.disconnect()
+	}
+	if i.conn == nil {
+		if time.Now().Before(i.nextReconnect) {
+			return fmt.Errorf("reconnecting to Instrumental in %s", time.Until(i.nextReconnect).Round(time.Millisecond))
+		}
+		if err := i.Connect(); err != nil {
+			i.scheduleReconnect()
+			return fmt.Errorf("failed to (re)connect to Instrumental: %v", err)
+		}
+	}
+
+	if _, err := i.conn.Write([]byte(strings.Join(i.queue, ""))); err != nil {
+		i.disconnect()
+		i.scheduleReconnect()
+		return fmt.Errorf("writing to Instrumental failed: %v", err)
+	}
+
+	i.queue = i.queue[:0]
+	i.backoff = 0
 	return nil
 }
 
+// scheduleReconnect delays the next reconnect by the doubled backoff, with a
+// random jitter so agents do not reconnect in lockstep after an outage.
+func (i *Instrumental) scheduleReconnect() {
+	if i.backoff == 0 {
+		i.backoff = time.Duration(i.ReconnectBackoffMin)
+	} else {
+		i.backoff *= 2
+	}
+	if max := time.Duration(i.ReconnectBackoffMax); i.backoff > max {
+		i.backoff = max
+	}
+
+	delay := i.backoff/2 + time.Duration(rand.Int63n(int64(i.backoff/2)+1))
+	i.nextReconnect = time.Now().Add(delay)
+}
+
+// alive checks if the collector closed the connection, as writes to a closed
+// connection succeed until the reset is received.  Any data sent by the
+// collector is an error message.
+func (i *Instrumental) alive() bool {
+	b := make([]byte, 512)
+	if err := i.conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		return false
+	}
+	n, err := i.conn.Read(b)
+	if n > 0 {
+		i.Log.Errorf("Instrumental responded: %s", strings.TrimSpace(string(b[:n])))
+	}
+	if e, ok := err.(net.Error); err == nil || ok && e.Timeout() {
+		return i.conn.SetReadDeadline(time.Time{}) == nil
+	}
+	if err == io.EOF {
+		i.Log.Debug("Connection closed by Instrumental")
+	} else if err != nil {
+		i.Log.Debugf("Connection to Instrumental failed: %v", err)
+	}
+	return false
+}
+
 func (i *Instrumental) Description() string {
 	return "Configuration for sending metrics to an Instrumental project"
 }
@@ -192,15 +318,18 @@ func (i *Instrumental) authenticate(conn net.Conn) error {
 		return fmt.Errorf("authentication failed: %s", responses)
 	}
 
-	i.conn = conn
 	return nil
 }
 
 func init() {
 	outputs.Add("instrumental", func() telegraf.Output {
 		return &Instrumental{
-			Host:     DefaultHost,
-			Template: graphite.DefaultTemplate,
+			Host:                DefaultHost,
+			Template:            graphite.DefaultTemplate,
+			KeepAlivePeriod:     config.Duration(30 * time.Second),
+			ReconnectBackoffMin: config.Duration(time.Second),
+			ReconnectBackoffMax: config.Duration(time.Minute),
+			MaxQueuedPoints:     10000,
 		}
 	})
 }
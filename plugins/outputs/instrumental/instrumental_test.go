@@ -4,25 +4,27 @@ import (
 	"bufio"
 	"net"
 	"net/textproto"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/serializers/graphite"
+	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrite(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
-	TCPServer(t, &wg)
+	port := TCPServer(t, &wg)
 
-	i := Instrumental{
-		Host:     "127.0.0.1",
-		APIToken: "abc123token",
-		Prefix:   "my.prefix",
-	}
+	i := newInstrumental(port)
+	require.NoError(t, i.Init())
 
 	// Default to gauge
 	m1 := metric.New(
@@ -39,7 +41,7 @@ func TestWrite(t *testing.T) {
 	)
 
 	metrics := []telegraf.Metric{m1, m2}
-	i.Write(metrics)
+	require.NoError(t, i.Write(metrics))
 
 	// Counter and Histogram are increments
 	m3 := metric.New(
@@ -70,13 +72,142 @@ func TestWrite(t *testing.T) {
 	)
 
 	metrics = []telegraf.Metric{m3, m4, m5, m6}
-	i.Write(metrics)
+	require.NoError(t, i.Write(metrics))
+	require.NoError(t, i.Close())
 
 	wg.Wait()
 }
 
-func TCPServer(t *testing.T, wg *sync.WaitGroup) {
-	tcpServer, _ := net.Listen("tcp", "127.0.0.1:8000")
+func TestReconnect(t *testing.T) {
+	tcpServer, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcpServer.Close()
+
+	i := newInstrumental(tcpServer.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, i.Init())
+
+	lines := make(chan string, 2)
+	go func() {
+		// The collector drops the first connection after a point
+		for n := 0; n < 2; n++ {
+			conn, err := tcpServer.Accept()
+			if err != nil {
+				return
+			}
+			tp := textproto.NewReader(bufio.NewReader(conn))
+			_, _ = tp.ReadLine()
+			_, _ = tp.ReadLine()
+			_, _ = conn.Write([]byte("ok\nok\n"))
+			line, _ := tp.ReadLine()
+			conn.Close()
+			lines <- line
+		}
+	}()
+
+	require.NoError(t, i.Write([]telegraf.Metric{testMetric("first")}))
+	require.Equal(t, "gauge my.prefix.first 1 1289430000", <-lines)
+
+	// The closed connection is noticed before writing
+	require.NoError(t, i.Write([]telegraf.Metric{testMetric("second")}))
+	require.Equal(t, "gauge my.prefix.second 1 1289430000", <-lines)
+	require.NoError(t, i.Close())
+}
+
+func TestQueue(t *testing.T) {
+	// Reserve a port nothing listens on
+	tcpServer, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := tcpServer.Addr().(*net.TCPAddr).Port
+	require.NoError(t, tcpServer.Close())
+
+	i := newInstrumental(port)
+	i.MaxQueuedPoints = 2
+	require.NoError(t, i.Init())
+
+	// Points are queued while the collector is unreachable, backing off
+	// between reconnects
+	require.NoError(t, i.Write([]telegraf.Metric{testMetric("first")}))
+	require.Len(t, i.queue, 1)
+	require.True(t, i.nextReconnect.After(time.Now()))
+	require.Equal(t, time.Second, i.backoff)
+
+	require.NoError(t, i.Write([]telegraf.Metric{testMetric("second")}))
+	require.Len(t, i.queue, 2)
+
+	// A full queue refuses the metrics, so they are retried by the agent
+	require.Error(t, i.Write([]telegraf.Metric{testMetric("third")}))
+	require.Len(t, i.queue, 2)
+
+	// The queue is sent once the collector is reachable
+	tcpServer, err = net.Listen("tcp", net.JoinHostPort("127.0.0.1", i.port))
+	require.NoError(t, err)
+	defer tcpServer.Close()
+
+	lines := make(chan string, 3)
+	go func() {
+		conn, err := tcpServer.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewReader(bufio.NewReader(conn))
+		_, _ = tp.ReadLine()
+		_, _ = tp.ReadLine()
+		_, _ = conn.Write([]byte("ok\nok\n"))
+		for n := 0; n < 3; n++ {
+			line, _ := tp.ReadLine()
+			lines <- line
+		}
+	}()
+
+	i.nextReconnect = time.Time{}
+	require.NoError(t, i.Write([]telegraf.Metric{testMetric("third")}))
+	require.Equal(t, "gauge my.prefix.first 1 1289430000", <-lines)
+	require.Equal(t, "gauge my.prefix.second 1 1289430000", <-lines)
+	require.Equal(t, "gauge my.prefix.third 1 1289430000", <-lines)
+	require.Empty(t, i.queue)
+	require.Zero(t, i.backoff)
+	require.NoError(t, i.Close())
+}
+
+func TestScheduleReconnect(t *testing.T) {
+	i := newInstrumental(0)
+	i.ReconnectBackoffMax = config.Duration(3 * time.Second)
+
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		before := time.Now()
+		i.scheduleReconnect()
+		require.Equal(t, expected, i.backoff)
+		require.False(t, i.nextReconnect.Before(before.Add(expected/2)))
+		require.False(t, i.nextReconnect.After(time.Now().Add(expected)))
+	}
+}
+
+func newInstrumental(port int) *Instrumental {
+	return &Instrumental{
+		Host:                "127.0.0.1",
+		APIToken:            "abc123token",
+		Prefix:              "my.prefix",
+		Template:            graphite.DefaultTemplate,
+		ReconnectBackoffMin: config.Duration(time.Second),
+		ReconnectBackoffMax: config.Duration(time.Minute),
+		MaxQueuedPoints:     10000,
+		Log:                 testutil.Logger{},
+		port:                strconv.Itoa(port),
+	}
+}
+
+func testMetric(name string) telegraf.Metric {
+	return metric.New(
+		name,
+		map[string]string{},
+		map[string]interface{}{"value": int64(1)},
+		time.Date(2010, time.November, 10, 23, 0, 0, 0, time.UTC),
+	)
+}
+
+func TCPServer(t *testing.T, wg *sync.WaitGroup) int {
+	tcpServer, _ := net.Listen("tcp", "127.0.0.1:0")
 	go func() {
 		defer wg.Done()
 		conn, _ := tcpServer.Accept()
@@ -95,16 +226,7 @@ func TCPServer(t *testing.T, wg *sync.WaitGroup) {
 		data2, _ := tp.ReadLine()
 		assert.Equal(t, "gauge my.prefix.192_168_0_1.mymeasurement 3.14 1289430000", data2)
 
-		conn, _ = tcpServer.Accept()
-		conn.SetDeadline(time.Now().Add(1 * time.Second))
-		reader = bufio.NewReader(conn)
-		tp = textproto.NewReader(reader)
-
-		hello, _ = tp.ReadLine()
-		assert.Equal(t, "hello version go/telegraf/1.1", hello)
-		auth, _ = tp.ReadLine()
-		assert.Equal(t, "authenticate abc123token", auth)
-		conn.Write([]byte("ok\nok\n"))
+		// The connection is kept open for the next write
 
 		data3, _ := tp.ReadLine()
 		assert.Equal(t, "increment my.prefix.192_168_0_1.my_histogram 3.14 1289430000", data3)
@@ -120,4 +242,5 @@ func TCPServer(t *testing.T, wg *sync.WaitGroup) {
 
 		conn.Close()
 	}()
+	return tcpServer.Addr().(*net.TCPAddr).Port
 }
@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/influxdata/telegraf"
+)
+
+const avroRecordName = "com.influxdata.telegraf.Metric"
+
+// avroSchema is the schema of the metrics serialized as Avro records, the
+// timestamp is in nanoseconds.
+const avroSchema = `{"type":"record","name":"Metric","namespace":"com.influxdata.telegraf","fields":[` +
+	`{"name":"name","type":"string"},` +
+	`{"name":"tags","type":{"type":"map","values":"string"}},` +
+	`{"name":"fields","type":{"type":"map","values":["boolean","long","double","string"]}},` +
+	`{"name":"timestamp","type":"long"}]}`
+
+// Indexes of the types of the field value union
+const (
+	avroBoolean = iota
+	avroLong
+	avroDouble
+	avroString
+)
+
+// encodeAvro encodes the metric in the Avro binary encoding of avroSchema.
+func encodeAvro(metric telegraf.Metric) []byte {
+	buf := appendAvroString(nil, metric.Name())
+
+	tags := metric.TagList()
+	if len(tags) > 0 {
+		buf = appendAvroLong(buf, int64(len(tags)))
+		for _, tag := range tags {
+			buf = appendAvroString(buf, tag.Key)
+			buf = appendAvroString(buf, tag.Value)
+		}
+	}
+	buf = appendAvroLong(buf, 0)
+
+	// The fields are sorted in a copy, the metric may be shared with other
+	// outputs
+	fields := append([]*telegraf.Field(nil), metric.FieldList()...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	if len(fields) > 0 {
+		buf = appendAvroLong(buf, int64(len(fields)))
+		for _, field := range fields {
+			buf = appendAvroString(buf, field.Key)
+			buf = appendAvroValue(buf, field.Value)
+		}
+	}
+	buf = appendAvroLong(buf, 0)
+
+	return appendAvroLong(buf, metric.Time().UnixNano())
+}
+
+// appendAvroValue appends the field value as a branch of the value union,
+// unsigned integers exceeding a long are written as doubles.
+func appendAvroValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case bool:
+		buf = appendAvroLong(buf, avroBoolean)
+		if v {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case int64:
+		return appendAvroLong(appendAvroLong(buf, avroLong), v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return appendAvroLong(appendAvroLong(buf, avroLong), int64(v))
+		}
+		return appendAvroDouble(appendAvroLong(buf, avroDouble), float64(v))
+	case float64:
+		return appendAvroDouble(appendAvroLong(buf, avroDouble), v)
+	case string:
+		return appendAvroString(appendAvroLong(buf, avroString), v)
+	default:
+		return appendAvroString(appendAvroLong(buf, avroString), "")
+	}
+}
+
+// appendAvroLong appends the zig-zag encoded variable-length long.
+func appendAvroLong(buf []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+func appendAvroDouble(buf []byte, v float64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	return append(buf, b[:]...)
+}
+
+func appendAvroString(buf []byte, s string) []byte {
+	return append(appendAvroLong(buf, int64(len(s))), s...)
+}
@@ -1,14 +1,17 @@
 package kafka
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/gofrs/uuid"
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/resolver"
 	"github.com/influxdata/telegraf/plugins/common/kafka"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/serializers"
@@ -41,6 +44,9 @@ type Kafka struct {
 
 	kafka.WriteConfig
 
+	// Serialize the metrics with a schema of a schema registry
+	SchemaRegistry *SchemaRegistryConfig `toml:"schema_registry"`
+
 	Log telegraf.Logger `toml:"-"`
 
 	saramaConfig *sarama.Config
@@ -217,6 +223,28 @@ var sampleConfig = `
   ## more about them here:
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_OUTPUT.md
   # data_format = "influx"
+
+  ## Serialize the metrics as Avro or Protobuf records with a schema
+  ## registered in a Confluent Schema Registry instead of the data_format.
+  # [outputs.kafka.schema_registry]
+  #   url = "http://localhost:8081"
+  #   ## Either "avro" or "protobuf"
+  #   format = "avro"
+  #   ## Subject of the schema, one of
+  #   ##   topic        - <topic>-value
+  #   ##   record       - the name of the record
+  #   ##   topic_record - <topic>-<name of the record>
+  #   subject_name_strategy = "topic"
+  #   ## Register the schema if missing, otherwise it is only looked up
+  #   auto_register = true
+  #   ## Basic authentication, timeout and TLS config of the registry
+  #   # username = ""
+  #   # password = ""
+  #   # timeout = "5s"
+  #   # tls_ca = "/etc/telegraf/ca.pem"
+  #   # tls_cert = "/etc/telegraf/cert.pem"
+  #   # tls_key = "/etc/telegraf/key.pem"
+  #   # insecure_skip_verify = false
 `
 
 func ValidateTopicSuffixMethod(method string) error {
@@ -279,8 +307,25 @@ func (k *Kafka) Init() error {
 		return err
 	}
 
+	// Connect to the brokers with the cached addresses, sarama only supports
+	// custom dialers as proxies
+	if resolver.Default().Enabled() && !config.Net.Proxy.Enable {
+		config.Net.Proxy.Enable = true
+		config.Net.Proxy.Dialer = resolver.NewDialer(net.Dialer{
+			Timeout:   config.Net.DialTimeout,
+			KeepAlive: config.Net.KeepAlive,
+			LocalAddr: config.Net.LocalAddr,
+		})
+	}
+
 	k.saramaConfig = config
 
+	if k.SchemaRegistry != nil {
+		if err := k.SchemaRegistry.init(); err != nil {
+			return err
+		}
+	}
+
 	// Legacy support ssl config
 	if k.Certificate != "" {
 		k.TLSCert = k.Certificate
@@ -332,19 +377,34 @@ func (k *Kafka) routingKey(metric telegraf.Metric) (string, error) {
 }
 
 func (k *Kafka) Write(metrics []telegraf.Metric) error {
+	var rejected []int
 	msgs := make([]*sarama.ProducerMessage, 0, len(metrics))
-	for _, metric := range metrics {
+	for i, metric := range metrics {
 		metric, topic := k.GetTopicName(metric)
 
-		buf, err := k.serializer.Serialize(metric)
-		if err != nil {
-			k.Log.Debugf("Could not serialize metric: %v", err)
-			continue
+		var buf []byte
+		var err error
+		if k.SchemaRegistry != nil {
+			// Failing to get the schema affects all metrics of the topic,
+			// so the batch is retried
+			buf, err = k.SchemaRegistry.serialize(topic, metric)
+			if err != nil {
+				return err
+			}
+		} else {
+			buf, err = k.serializer.Serialize(metric)
+			if err != nil {
+				k.Log.Debugf("Could not serialize metric: %v", err)
+				rejected = append(rejected, i)
+				continue
+			}
 		}
 
 		m := &sarama.ProducerMessage{
 			Topic: topic,
 			Value: sarama.ByteEncoder(buf),
+			// Index of the metric in the batch to report partial writes
+			Metadata: i,
 		}
 
 		// Negative timestamps are not allowed by the Kafka protocol.
@@ -365,26 +425,66 @@ func (k *Kafka) Write(metrics []telegraf.Metric) error {
 
 	err := k.producer.SendMessages(msgs)
 	if err != nil {
-		// We could have many errors, return only the first encountered.
-		if errs, ok := err.(sarama.ProducerErrors); ok {
-			for _, prodErr := range errs {
-				if prodErr.Err == sarama.ErrMessageSizeTooLarge {
-					k.Log.Error("Message too large, consider increasing `max_message_bytes`; dropping batch")
-					return nil
-				}
-				if prodErr.Err == sarama.ErrInvalidTimestamp {
-					k.Log.Error("The timestamp of the message is out of acceptable range, consider increasing broker `message.timestamp.difference.max.ms`; dropping batch")
-					return nil
-				}
-				return prodErr
-			}
+		errs, ok := err.(sarama.ProducerErrors)
+		if !ok {
+			return err
 		}
-		return err
+		return k.partialWriteError(metrics, rejected, errs)
 	}
 
 	return nil
 }
 
+// partialWriteError reports the messages not produced to Kafka.  Messages
+// which can never be accepted by the broker are dropped, all others are
+// retried.
+func (k *Kafka) partialWriteError(metrics []telegraf.Metric, rejected []int, errs sarama.ProducerErrors) error {
+	failed := make(map[int]bool, len(rejected)+len(errs))
+	for _, i := range rejected {
+		failed[i] = true
+	}
+
+	var retry error
+	for _, prodErr := range errs {
+		i, ok := prodErr.Msg.Metadata.(int)
+		if !ok {
+			// Without the index the batch can only be retried as a whole.
+			return prodErr
+		}
+		failed[i] = true
+
+		switch prodErr.Err {
+		case sarama.ErrMessageSizeTooLarge:
+			k.Log.Error("Message too large, consider increasing `max_message_bytes`; dropping metric")
+			rejected = append(rejected, i)
+		case sarama.ErrInvalidTimestamp:
+			k.Log.Error("The timestamp of the message is out of acceptable range, consider increasing broker `message.timestamp.difference.max.ms`; dropping metric")
+			rejected = append(rejected, i)
+		default:
+			// We could have many errors, return only the first encountered.
+			if retry == nil {
+				retry = prodErr
+			}
+		}
+	}
+
+	accepted := make([]int, 0, len(metrics)-len(failed))
+	for i := range metrics {
+		if !failed[i] {
+			accepted = append(accepted, i)
+		}
+	}
+
+	if retry == nil {
+		retry = errors.New("messages rejected by the broker")
+	}
+	return &telegraf.PartialWriteError{
+		Err:           retry,
+		MetricsAccept: accepted,
+		MetricsReject: rejected,
+	}
+}
+
 func init() {
 	sarama.Logger = &DebugLogger{}
 	outputs.Add("kafka", func() telegraf.Output {
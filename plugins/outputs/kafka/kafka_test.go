@@ -303,3 +303,56 @@ func TestTopicTag(t *testing.T) {
 		})
 	}
 }
+
+// FailingProducer fails to send the messages at the given positions.
+type FailingProducer struct {
+	MockProducer
+	errs map[int]error
+}
+
+func (p *FailingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	var errs sarama.ProducerErrors
+	for i, msg := range msgs {
+		if err, ok := p.errs[i]; ok {
+			errs = append(errs, &sarama.ProducerError{Msg: msg, Err: err})
+			continue
+		}
+		p.sent = append(p.sent, msg)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func TestWritePartial(t *testing.T) {
+	plugin := &Kafka{
+		Brokers:      []string{"127.0.0.1"},
+		Topic:        "telegraf",
+		producerFunc: NewMockProducer,
+		Log:          testutil.Logger{},
+	}
+	s, err := serializers.NewInfluxSerializer()
+	require.NoError(t, err)
+	plugin.SetSerializer(s)
+	require.NoError(t, plugin.Connect())
+
+	producer := &FailingProducer{
+		errs: map[int]error{
+			1: sarama.ErrMessageSizeTooLarge,
+			2: sarama.ErrOutOfBrokers,
+		},
+	}
+	plugin.producer = producer
+
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"time_idle": 42.0}, time.Unix(0, 0))
+	err = plugin.Write([]telegraf.Metric{m, m, m})
+	require.Error(t, err)
+
+	partial, ok := err.(*telegraf.PartialWriteError)
+	require.True(t, ok)
+	require.Equal(t, sarama.ErrOutOfBrokers, partial.Err.(*sarama.ProducerError).Err)
+	require.Equal(t, []int{0}, partial.MetricsAccept)
+	require.Equal(t, []int{1}, partial.MetricsReject)
+	require.Len(t, producer.sent, 1)
+}
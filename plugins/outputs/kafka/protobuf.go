@@ -0,0 +1,99 @@
+package kafka
+
+import (
+	"math"
+	"sort"
+
+	"github.com/influxdata/telegraf"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const protobufRecordName = "telegraf.Metric"
+
+// protobufSchema is the schema of the metrics serialized as Protobuf
+// messages, the timestamp is in nanoseconds.
+const protobufSchema = `syntax = "proto3";
+package telegraf;
+
+message Metric {
+  string name = 1;
+  map<string, string> tags = 2;
+  map<string, Value> fields = 3;
+  int64 timestamp = 4;
+}
+
+message Value {
+  oneof value {
+    double double_value = 1;
+    int64 int_value = 2;
+    uint64 uint_value = 3;
+    bool bool_value = 4;
+    string string_value = 5;
+  }
+}
+`
+
+// encodeProtobuf encodes the metric as the Metric message of protobufSchema.
+func encodeProtobuf(metric telegraf.Metric) []byte {
+	var buf []byte
+	if metric.Name() != "" {
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendString(buf, metric.Name())
+	}
+
+	for _, tag := range metric.TagList() {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, tag.Key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, tag.Value)
+
+		buf = protowire.AppendTag(buf, 2, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, entry)
+	}
+
+	// The fields are sorted in a copy, the metric may be shared with other
+	// outputs
+	fields := append([]*telegraf.Field(nil), metric.FieldList()...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	for _, field := range fields {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, field.Key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendBytes(entry, encodeProtobufValue(field.Value))
+
+		buf = protowire.AppendTag(buf, 3, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, entry)
+	}
+
+	if ts := metric.Time().UnixNano(); ts != 0 {
+		buf = protowire.AppendTag(buf, 4, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(ts))
+	}
+	return buf
+}
+
+// encodeProtobufValue encodes the field value as a Value message, members of
+// the oneof are written even with their default value.
+func encodeProtobufValue(value interface{}) []byte {
+	var buf []byte
+	switch v := value.(type) {
+	case float64:
+		buf = protowire.AppendTag(buf, 1, protowire.Fixed64Type)
+		buf = protowire.AppendFixed64(buf, math.Float64bits(v))
+	case int64:
+		buf = protowire.AppendTag(buf, 2, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(v))
+	case uint64:
+		buf = protowire.AppendTag(buf, 3, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, v)
+	case bool:
+		buf = protowire.AppendTag(buf, 4, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, protowire.EncodeBool(v))
+	case string:
+		buf = protowire.AppendTag(buf, 5, protowire.BytesType)
+		buf = protowire.AppendString(buf, v)
+	}
+	return buf
+}
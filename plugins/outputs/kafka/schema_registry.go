@@ -0,0 +1,184 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+)
+
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaRegistryConfig serializes the metrics as Avro or Protobuf records
+// with a schema registered in a Confluent Schema Registry, framed in the
+// Confluent wire format: a zero magic byte and the big-endian schema id
+// followed by the record.
+type SchemaRegistryConfig struct {
+	URL                 string          `toml:"url"`
+	Format              string          `toml:"format"`
+	SubjectNameStrategy string          `toml:"subject_name_strategy"`
+	AutoRegister        *bool           `toml:"auto_register"`
+	Username            string          `toml:"username"`
+	Password            string          `toml:"password"`
+	Timeout             config.Duration `toml:"timeout"`
+	tls.ClientConfig
+
+	client     *http.Client
+	schema     string
+	schemaType string
+	record     string
+	encode     func(telegraf.Metric) []byte
+	// prefix precedes the record after the schema id, Protobuf records are
+	// prefixed with the index of the message in the schema
+	prefix []byte
+	ids    map[string]int32
+}
+
+func (c *SchemaRegistryConfig) init() error {
+	if c.URL == "" {
+		return errors.New("schema_registry requires an url")
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return fmt.Errorf("invalid schema_registry url: %w", err)
+	}
+
+	switch c.Format {
+	case "", "avro":
+		c.schema = avroSchema
+		c.schemaType = "AVRO"
+		c.record = avroRecordName
+		c.encode = encodeAvro
+		c.prefix = nil
+	case "protobuf":
+		c.schema = protobufSchema
+		c.schemaType = "PROTOBUF"
+		c.record = protobufRecordName
+		c.encode = encodeProtobuf
+		// The index of the first message is encoded as a single zero
+		c.prefix = []byte{0}
+	default:
+		return fmt.Errorf("unknown schema_registry format %q", c.Format)
+	}
+
+	switch c.SubjectNameStrategy {
+	case "", "topic", "record", "topic_record":
+	default:
+		return fmt.Errorf("unknown subject_name_strategy %q", c.SubjectNameStrategy)
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = config.Duration(5 * time.Second)
+	}
+	tlsCfg, err := c.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+	c.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsCfg,
+		},
+		Timeout: time.Duration(c.Timeout),
+	}
+	c.ids = make(map[string]int32)
+	return nil
+}
+
+// subject returns the subject of the schema of the records written to the
+// topic, following the naming strategies of the Confluent serializers.
+func (c *SchemaRegistryConfig) subject(topic string) string {
+	switch c.SubjectNameStrategy {
+	case "record":
+		return c.record
+	case "topic_record":
+		return topic + "-" + c.record
+	default:
+		return topic + "-value"
+	}
+}
+
+// serialize frames the record of the metric with the id of the schema of the
+// topic.
+func (c *SchemaRegistryConfig) serialize(topic string, metric telegraf.Metric) ([]byte, error) {
+	id, err := c.schemaID(c.subject(topic))
+	if err != nil {
+		return nil, err
+	}
+
+	record := c.encode(metric)
+	buf := make([]byte, 5, 5+len(c.prefix)+len(record))
+	binary.BigEndian.PutUint32(buf[1:], uint32(id))
+	buf = append(buf, c.prefix...)
+	return append(buf, record...), nil
+}
+
+// schemaID returns the id of the schema of the subject, registering the
+// schema unless disabled.  The ids are cached as they never change.
+func (c *SchemaRegistryConfig) schemaID(subject string) (int32, error) {
+	if id, ok := c.ids[subject]; ok {
+		return id, nil
+	}
+
+	// Registering an existing schema returns its id, looking it up does not
+	// require write permissions on the registry
+	path := "/subjects/" + url.PathEscape(subject)
+	if c.AutoRegister == nil || *c.AutoRegister {
+		path += "/versions"
+	}
+
+	request := struct {
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType,omitempty"`
+	}{Schema: c.schema}
+	// The type is omitted for Avro to support registries predating the
+	// other formats
+	if c.schemaType != "AVRO" {
+		request.SchemaType = c.schemaType
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimSuffix(c.URL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", schemaRegistryContentType)
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("requesting schema of subject %q failed: %w", subject, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("requesting schema of subject %q failed with status %s: %s",
+			subject, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var schema struct {
+		ID int32 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+		return 0, fmt.Errorf("decoding schema of subject %q failed: %w", subject, err)
+	}
+	c.ids[subject] = schema.ID
+	return schema.ID, nil
+}
@@ -0,0 +1,184 @@
+package kafka
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func testMetric() telegraf.Metric {
+	return testutil.MustMetric(
+		"cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"value": 1.5},
+		time.Unix(0, 1),
+	)
+}
+
+func TestEncodeAvro(t *testing.T) {
+	expected := []byte{
+		0x06, 'c', 'p', 'u',
+		0x02, 0x08, 'h', 'o', 's', 't', 0x02, 'a', 0x00,
+		0x02, 0x0a, 'v', 'a', 'l', 'u', 'e', 0x04, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f, 0x00,
+		0x02,
+	}
+	require.Equal(t, expected, encodeAvro(testMetric()))
+
+	// The union holds the type of the field
+	m := testutil.MustMetric("m", map[string]string{}, map[string]interface{}{
+		"b": true,
+		"i": int64(-1),
+		"s": "x",
+		"u": uint64(1 << 63),
+	}, time.Unix(0, 0))
+	expected = []byte{
+		0x02, 'm',
+		0x00,
+		0x08,
+		0x02, 'b', 0x00, 0x01,
+		0x02, 'i', 0x02, 0x01,
+		0x02, 's', 0x06, 0x02, 'x',
+		0x02, 'u', 0x04, 0, 0, 0, 0, 0, 0, 0xe0, 0x43,
+		0x00,
+		0x00,
+	}
+	require.Equal(t, expected, encodeAvro(m))
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(avroSchema), &schema))
+}
+
+func TestEncodeProtobuf(t *testing.T) {
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"metric.proto": protobufSchema}),
+	}
+	files, err := parser.ParseFiles("metric.proto")
+	require.NoError(t, err)
+	md := files[0].FindMessage(protobufRecordName)
+	require.NotNil(t, md)
+
+	m := testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{
+		"value": 1.5,
+		"count": int64(2),
+		"ok":    false,
+	}, time.Unix(0, 1))
+
+	msg := dynamic.NewMessage(md)
+	require.NoError(t, msg.Unmarshal(encodeProtobuf(m)))
+	require.Equal(t, "cpu", msg.GetFieldByName("name"))
+	require.Equal(t, map[interface{}]interface{}{"host": "a"}, msg.GetFieldByName("tags"))
+	require.Equal(t, int64(1), msg.GetFieldByName("timestamp"))
+
+	fields := msg.GetFieldByName("fields").(map[interface{}]interface{})
+	require.Len(t, fields, 3)
+	require.Equal(t, 1.5, fields["value"].(*dynamic.Message).GetFieldByName("double_value"))
+	require.Equal(t, int64(2), fields["count"].(*dynamic.Message).GetFieldByName("int_value"))
+	ok := fields["ok"].(*dynamic.Message)
+	require.True(t, ok.HasFieldName("bool_value"))
+	require.Equal(t, false, ok.GetFieldByName("bool_value"))
+}
+
+func TestEncodeKeepsFieldOrder(t *testing.T) {
+	m := testutil.MustMetric("m", map[string]string{}, map[string]interface{}{}, time.Unix(0, 0))
+	m.AddField("z", 1.0)
+	m.AddField("a", 2.0)
+
+	encodeAvro(m)
+	encodeProtobuf(m)
+	require.Equal(t, "z", m.FieldList()[0].Key)
+	require.Equal(t, "a", m.FieldList()[1].Key)
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, schemaRegistryContentType, r.Header.Get("Content-Type"))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if r.URL.Path == "/subjects/missing-value" {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code":40401,"message":"Subject 'missing-value' not found."}`))
+			require.NoError(t, err)
+			return
+		}
+		if body["schemaType"] == "PROTOBUF" {
+			require.Equal(t, protobufSchema, body["schema"])
+			_, err := w.Write([]byte(`{"id":7}`))
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, avroSchema, body["schema"])
+		_, err := w.Write([]byte(`{"id":258}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	producer := &MockProducer{}
+	k := &Kafka{
+		Topic:          "telegraf",
+		SchemaRegistry: &SchemaRegistryConfig{URL: ts.URL},
+		producerFunc: func(_ []string, _ *sarama.Config) (sarama.SyncProducer, error) {
+			return producer, nil
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, k.Init())
+	require.NoError(t, k.Connect())
+
+	// The schema id is requested once per subject
+	require.NoError(t, k.Write([]telegraf.Metric{testMetric(), testMetric()}))
+	require.Equal(t, []string{"/subjects/telegraf-value/versions"}, requests)
+	require.Len(t, producer.sent, 2)
+	value, err := producer.sent[0].Value.Encode()
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0, 0, 0, 1, 2}, encodeAvro(testMetric())...), value)
+
+	// Protobuf records are prefixed with the index of the message
+	requests = nil
+	producer.sent = nil
+	k.SchemaRegistry = &SchemaRegistryConfig{
+		URL:                 ts.URL,
+		Format:              "protobuf",
+		SubjectNameStrategy: "topic_record",
+	}
+	require.NoError(t, k.Init())
+	require.NoError(t, k.Write([]telegraf.Metric{testMetric()}))
+	require.Equal(t, []string{"/subjects/telegraf-telegraf.Metric/versions"}, requests)
+	value, err = producer.sent[0].Value.Encode()
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0, 0, 0, 0, 7, 0}, encodeProtobuf(testMetric())...), value)
+
+	// Without registration the schema is only looked up, the batch is
+	// retried if it is missing
+	autoRegister := false
+	k.Topic = "missing"
+	k.SchemaRegistry = &SchemaRegistryConfig{URL: ts.URL, AutoRegister: &autoRegister}
+	require.NoError(t, k.Init())
+	err = k.Write([]telegraf.Metric{testMetric()})
+	require.EqualError(t, err, `requesting schema of subject "missing-value" failed with status 404 Not Found: `+
+		`{"error_code":40401,"message":"Subject 'missing-value' not found."}`)
+}
+
+func TestSchemaRegistryInvalid(t *testing.T) {
+	k := &Kafka{SchemaRegistry: &SchemaRegistryConfig{}}
+	require.EqualError(t, k.Init(), "schema_registry requires an url")
+
+	k.SchemaRegistry = &SchemaRegistryConfig{URL: "http://localhost:8081", Format: "json"}
+	require.EqualError(t, k.Init(), `unknown schema_registry format "json"`)
+
+	k.SchemaRegistry = &SchemaRegistryConfig{URL: "http://localhost:8081", SubjectNameStrategy: "measurement"}
+	require.EqualError(t, k.Init(), `unknown subject_name_strategy "measurement"`)
+}
@@ -25,6 +25,10 @@ type Librato struct {
 	Template  string          `toml:"template"`
 	Log       telegraf.Logger `toml:"-"`
 
+	// Send tagged measurements instead of gauges with a source
+	APIMode   string `toml:"api_mode"`
+	BatchSize int    `toml:"batch_size"`
+
 	APIUrl string
 	client *http.Client
 }
@@ -32,6 +36,20 @@ type Librato struct {
 // https://www.librato.com/docs/kb/faq/best_practices/naming_convention_metrics_sources.html#naming-limitations-for-sources-and-metrics
 var reUnacceptedChar = regexp.MustCompile("[^.a-zA-Z0-9_-]")
 
+// https://www.librato.com/docs/api/#create-a-measurement
+var (
+	reUnacceptedMeasurementChar = regexp.MustCompile("[^.:a-zA-Z0-9_-]")
+	reUnacceptedTagKeyChar      = regexp.MustCompile("[^.:a-zA-Z0-9_-]")
+	reUnacceptedTagValueChar    = regexp.MustCompile(`[^.:a-zA-Z0-9_?\\/ -]`)
+)
+
+// Length limits of the Measurements API
+const (
+	maxNameLength     = 255
+	maxTagKeyLength   = 64
+	maxTagValueLength = 255
+)
+
 var sampleConfig = `
   ## Librato API Docs
   ## http://dev.librato.com/v1/metrics-authentication
@@ -48,6 +66,14 @@ var sampleConfig = `
   ## This template is used in librato's source (not metric's name)
   template = "host"
 
+  ## API used to send the metrics, "source" sends gauges with the source built
+  ## from the template to the legacy metrics API, "tagged" sends measurements
+  ## with the metric tags to the Measurements API.  Tag keys and values are
+  ## sanitized to the characters and lengths accepted by the API.
+  # api_mode = "source"
+
+  ## Maximum number of gauges or measurements sent in a single request.
+  # batch_size = 300
 `
 
 // LMetrics is the default struct for Librato's API fromat
@@ -63,14 +89,50 @@ type Gauge struct {
 	MeasureTime int64   `json:"measure_time"`
 }
 
-const libratoAPI = "https://metrics-api.librato.com/v1/metrics"
+// LMeasurements is the format of the Measurements API
+type LMeasurements struct {
+	Measurements []*Measurement `json:"measurements"`
+}
+
+// Measurement is a tagged measurement of the Measurements API
+type Measurement struct {
+	Name  string            `json:"name"`
+	Value float64           `json:"value"`
+	Time  int64             `json:"time"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+const (
+	libratoAPI             = "https://metrics-api.librato.com/v1/metrics"
+	libratoMeasurementsAPI = "https://metrics-api.librato.com/v1/measurements"
+	defaultBatchSize       = 300
+)
 
 // NewLibrato is the main constructor for librato output plugins
 func NewLibrato(apiURL string) *Librato {
 	return &Librato{
-		APIUrl:   apiURL,
-		Template: "host",
+		APIUrl:    apiURL,
+		Template:  "host",
+		APIMode:   "source",
+		BatchSize: defaultBatchSize,
+	}
+}
+
+// Init validates the api mode and selects the endpoint of the tagged mode.
+func (l *Librato) Init() error {
+	switch l.APIMode {
+	case "", "source":
+	case "tagged":
+		if l.APIUrl == libratoAPI {
+			l.APIUrl = libratoMeasurementsAPI
+		}
+	default:
+		return fmt.Errorf("unknown api_mode %q", l.APIMode)
+	}
+	if l.BatchSize < 1 {
+		l.BatchSize = defaultBatchSize
 	}
+	return nil
 }
 
 // Connect is the default output plugin connection function who make sure it
@@ -93,6 +155,9 @@ func (l *Librato) Write(metrics []telegraf.Metric) error {
 	if len(metrics) == 0 {
 		return nil
 	}
+	if l.APIMode == "tagged" {
+		return l.writeMeasurements(metrics)
+	}
 	if l.Template == "" {
 		l.Template = "host"
 	}
@@ -114,57 +179,90 @@ func (l *Librato) Write(metrics []telegraf.Metric) error {
 		}
 	}
 
-	metricCounter := len(tempGauges)
-	// make sur we send a batch of maximum 300
-	sizeBatch := 300
-	for start := 0; start < metricCounter; start += sizeBatch {
-		lmetrics := LMetrics{}
+	// make sure we send batches of at most batch_size
+	sizeBatch := l.batchSize()
+	for start := 0; start < len(tempGauges); start += sizeBatch {
 		end := start + sizeBatch
-		if end > metricCounter {
-			end = metricCounter
-			sizeBatch = end - start
+		if end > len(tempGauges) {
+			end = len(tempGauges)
 		}
-		lmetrics.Gauges = make([]*Gauge, sizeBatch)
-		copy(lmetrics.Gauges, tempGauges[start:end])
-		metricsBytes, err := json.Marshal(lmetrics)
-		if err != nil {
-			return fmt.Errorf("unable to marshal Metrics, %s", err.Error())
+		lmetrics := LMetrics{Gauges: tempGauges[start:end]}
+		if err := l.post(lmetrics); err != nil {
+			return err
 		}
+	}
 
-		l.Log.Debugf("Librato request: %v", string(metricsBytes))
+	return nil
+}
 
-		req, err := http.NewRequest(
-			"POST",
-			l.APIUrl,
-			bytes.NewBuffer(metricsBytes))
-		if err != nil {
-			return fmt.Errorf("unable to create http.Request, %s", err.Error())
+// writeMeasurements sends the metrics as tagged measurements.
+func (l *Librato) writeMeasurements(metrics []telegraf.Metric) error {
+	var measurements []*Measurement
+	for _, m := range metrics {
+		measurements = append(measurements, l.buildMeasurements(m)...)
+	}
+
+	sizeBatch := l.batchSize()
+	for start := 0; start < len(measurements); start += sizeBatch {
+		end := start + sizeBatch
+		if end > len(measurements) {
+			end = len(measurements)
 		}
-		req.Header.Add("Content-Type", "application/json")
-		req.SetBasicAuth(l.APIUser, l.APIToken)
+		if err := l.post(LMeasurements{Measurements: measurements[start:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		resp, err := l.client.Do(req)
+func (l *Librato) batchSize() int {
+	if l.BatchSize < 1 {
+		return defaultBatchSize
+	}
+	return l.BatchSize
+}
+
+// post sends a batch of gauges or measurements to the API.
+func (l *Librato) post(batch interface{}) error {
+	metricsBytes, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("unable to marshal Metrics, %s", err.Error())
+	}
+
+	l.Log.Debugf("Librato request: %v", string(metricsBytes))
+
+	req, err := http.NewRequest(
+		"POST",
+		l.APIUrl,
+		bytes.NewBuffer(metricsBytes))
+	if err != nil {
+		return fmt.Errorf("unable to create http.Request, %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.SetBasicAuth(l.APIUser, l.APIToken)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		l.Log.Debugf("Error POSTing metrics: %v", err.Error())
+		return fmt.Errorf("error POSTing metrics, %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	// The Measurements API answers with 202 Accepted
+	ok := resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted
+	if !ok || l.Debug {
+		htmlData, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			l.Log.Debugf("Error POSTing metrics: %v", err.Error())
-			return fmt.Errorf("error POSTing metrics, %s", err.Error())
+			l.Log.Debugf("Couldn't get response! (%v)", err)
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 || l.Debug {
-			htmlData, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				l.Log.Debugf("Couldn't get response! (%v)", err)
-			}
-			if resp.StatusCode != 200 {
-				return fmt.Errorf(
-					"received bad status code, %d\n %s",
-					resp.StatusCode,
-					string(htmlData))
-			}
-			l.Log.Debugf("Librato response: %v", string(htmlData))
+		if !ok {
+			return fmt.Errorf(
+				"received bad status code, %d\n %s",
+				resp.StatusCode,
+				string(htmlData))
 		}
+		l.Log.Debugf("Librato response: %v", string(htmlData))
 	}
-
 	return nil
 }
 
@@ -215,6 +313,57 @@ func (l *Librato) buildGauges(m telegraf.Metric) ([]*Gauge, error) {
 	return gauges, nil
 }
 
+// buildMeasurements returns a measurement per numeric field of the metric,
+// tagged with the sanitized metric tags.
+func (l *Librato) buildMeasurements(m telegraf.Metric) []*Measurement {
+	if m.Time().Unix() == 0 {
+		l.Log.Debugf("Time was zero for %s, skipping", m.Name())
+		return nil
+	}
+
+	tags := make(map[string]string, len(m.TagList()))
+	for _, tag := range m.TagList() {
+		key := truncate(reUnacceptedTagKeyChar.ReplaceAllString(tag.Key, "_"), maxTagKeyLength)
+		value := truncate(reUnacceptedTagValueChar.ReplaceAllString(tag.Value, "_"), maxTagValueLength)
+		if value == "" {
+			continue
+		}
+		tags[key] = value
+	}
+
+	var measurements []*Measurement
+	for _, field := range m.FieldList() {
+		if !verifyValue(field.Value) {
+			continue
+		}
+		metricName := m.Name()
+		if field.Key != "value" {
+			metricName = fmt.Sprintf("%s.%s", m.Name(), field.Key)
+		}
+
+		// The value conversion is shared with the gauges
+		gauge := &Gauge{}
+		if err := gauge.setValue(field.Value); err != nil {
+			l.Log.Debugf("Unable to extract value of %s: %v", metricName, err)
+			continue
+		}
+		measurements = append(measurements, &Measurement{
+			Name:  truncate(reUnacceptedMeasurementChar.ReplaceAllString(metricName, "-"), maxNameLength),
+			Value: gauge.Value,
+			Time:  m.Time().Unix(),
+			Tags:  tags,
+		})
+	}
+	return measurements
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length]
+	}
+	return s
+}
+
 func verifyValue(v interface{}) bool {
 	switch v.(type) {
 	case string:
@@ -1,10 +1,12 @@
 package librato
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -278,3 +280,69 @@ func TestBuildGaugeWithSource(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteMeasurements(t *testing.T) {
+	var requests []LMeasurements
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body LMeasurements
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			requests = append(requests, body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+	defer ts.Close()
+
+	l := newTestLibrato(ts.URL)
+	l.APIUser = "telegraf@influxdb.com"
+	l.APIToken = "123456"
+	l.APIMode = "tagged"
+	l.BatchSize = 2
+	require.NoError(t, l.Init())
+	require.NoError(t, l.Connect())
+
+	mtime := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	m := metric.New(
+		"cpu load",
+		map[string]string{
+			"host":                  "host1",
+			"path":                  "/var/log (old)",
+			"empty":                 "",
+			"invalid key!":          "value",
+			strings.Repeat("k", 70): strings.Repeat("v", 300),
+		},
+		map[string]interface{}{"value": 1.5, "user": int64(2), "state": "ok", "up": true},
+		mtime,
+	)
+	require.NoError(t, l.Write([]telegraf.Metric{m}))
+
+	// The three numeric fields are sent in two batches
+	require.Len(t, requests, 2)
+	require.Len(t, requests[0].Measurements, 2)
+	require.Len(t, requests[1].Measurements, 1)
+
+	tags := map[string]string{
+		"host":                  "host1",
+		"path":                  "/var/log _old_",
+		"invalid_key_":          "value",
+		strings.Repeat("k", 64): strings.Repeat("v", 255),
+	}
+	var actual []*Measurement
+	for _, r := range requests {
+		actual = append(actual, r.Measurements...)
+	}
+	require.ElementsMatch(t, []*Measurement{
+		{Name: "cpu-load", Value: 1.5, Time: mtime.Unix(), Tags: tags},
+		{Name: "cpu-load.user", Value: 2, Time: mtime.Unix(), Tags: tags},
+		{Name: "cpu-load.up", Value: 1, Time: mtime.Unix(), Tags: tags},
+	}, actual)
+}
+
+func TestInitAPIMode(t *testing.T) {
+	l := NewLibrato(libratoAPI)
+	l.APIMode = "tagged"
+	require.NoError(t, l.Init())
+	require.Equal(t, libratoMeasurementsAPI, l.APIUrl)
+
+	l.APIMode = "sources"
+	require.EqualError(t, l.Init(), `unknown api_mode "sources"`)
+}
@@ -11,12 +11,16 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
-	"github.com/influxdata/telegraf/plugins/common/tls"
+	mqttconfig "github.com/influxdata/telegraf/plugins/common/mqtt"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/serializers"
 )
 
 var sampleConfig = `
+  ## Brokers given as host:port use TCP or, if configured, TLS.  Use the
+  ## ws:// or wss:// scheme to connect over websockets.
+  ##   example: servers = ["localhost:1883"]
+  ##            servers = ["wss://broker.example.com:443/mqtt"]
   servers = ["localhost:1883"] # required.
 
   ## MQTT outputs send metrics to this topic format
@@ -46,6 +50,12 @@ var sampleConfig = `
   # tls_key = "/etc/telegraf/key.pem"
   ## Use TLS but skip chain & host verification
   # insecure_skip_verify = false
+  ## Resume TLS sessions when reconnecting, sparing the full handshake.
+  # tls_session_resumption = false
+
+  ## HTTP headers sent when connecting over websockets.
+  # [outputs.mqtt.websocket_headers]
+  #   Authorization = "Bearer token"
 
   ## When true, metrics will be sent in one MQTT message per flush.  Otherwise,
   ## metrics are written one metric per MQTT message.
@@ -71,7 +81,7 @@ type MQTT struct {
 	TopicPrefix string
 	QoS         int    `toml:"qos"`
 	ClientID    string `toml:"client_id"`
-	tls.ClientConfig
+	mqttconfig.Config
 	BatchMessage bool `toml:"batch"`
 	Retain       bool `toml:"retain"`
 
@@ -203,17 +213,6 @@ func (m *MQTT) createOpts() (*paho.ClientOptions, error) {
 		opts.SetClientID("Telegraf-Output-" + internal.RandomString(5))
 	}
 
-	tlsCfg, err := m.ClientConfig.TLSConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	scheme := "tcp"
-	if tlsCfg != nil {
-		scheme = "ssl"
-		opts.SetTLSConfig(tlsCfg)
-	}
-
 	user := m.Username
 	if user != "" {
 		opts.SetUsername(user)
@@ -226,10 +225,8 @@ func (m *MQTT) createOpts() (*paho.ClientOptions, error) {
 	if len(m.Servers) == 0 {
 		return opts, fmt.Errorf("could not get host informations")
 	}
-	for _, host := range m.Servers {
-		server := fmt.Sprintf("%s://%s", scheme, host)
-
-		opts.AddBroker(server)
+	if err := m.Config.SetConfig(opts, m.Servers); err != nil {
+		return nil, err
 	}
 	opts.SetAutoReconnect(true)
 	return opts, nil
@@ -1,19 +1,42 @@
 package nsq
 
 import (
+	"errors"
 	"fmt"
-	"log"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/serializers"
 	"github.com/nsqio/go-nsq"
 )
 
+// publisher publishes messages to a single nsqd, implemented by nsq.Producer.
+type publisher interface {
+	Publish(topic string, body []byte) error
+	MultiPublish(topic string, body [][]byte) error
+	Stop()
+}
+
 type NSQ struct {
-	Server   string
-	Topic    string
-	producer *nsq.Producer
+	Server string
+	Topic  string
+
+	// Publish to several nsqd, failing over or in turns
+	Servers     []string `toml:"servers"`
+	PublishMode string   `toml:"publish_mode"`
+	BatchSize   int      `toml:"batch_size"`
+
+	AuthSecret string `toml:"auth_secret"`
+	EnableTLS  bool   `toml:"enable_tls"`
+	tls.ClientConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	producers []publisher
+	// current is the index of the producer published to next
+	current int
 
 	serializer serializers.Serializer
 }
@@ -24,6 +47,27 @@ var sampleConfig = `
   ## NSQ topic for producer messages
   topic = "telegraf"
 
+  ## Additional nsqd instances.  With the "failover" publish_mode messages are
+  ## published to the first available instance, with "round_robin" each batch
+  ## is published to the next instance, skipping unavailable ones.
+  # servers = ["nsqd-1:4150", "nsqd-2:4150"]
+  # publish_mode = "failover"
+
+  ## Number of messages published at once with MPUB, 1 publishes every
+  ## message on its own.
+  # batch_size = 1
+
+  ## Secret sent to nsqd instances requiring authorization.
+  # auth_secret = ""
+
+  ## Optional TLS Config
+  # enable_tls = false
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
+
   ## Data format to output.
   ## Each data format has its own unique set of configuration options, read
   ## more about them here:
@@ -35,20 +79,64 @@ func (n *NSQ) SetSerializer(serializer serializers.Serializer) {
 	n.serializer = serializer
 }
 
+func (n *NSQ) Init() error {
+	if n.PublishMode == "" {
+		n.PublishMode = "failover"
+	}
+	if !choice.Contains(n.PublishMode, []string{"failover", "round_robin"}) {
+		return fmt.Errorf("unknown publish_mode %q", n.PublishMode)
+	}
+	if n.BatchSize < 1 {
+		n.BatchSize = 1
+	}
+	if len(n.servers()) == 0 {
+		return errors.New("no nsqd server configured")
+	}
+	return nil
+}
+
+// servers returns the nsqd instances, the server option comes first.
+func (n *NSQ) servers() []string {
+	var servers []string
+	if n.Server != "" {
+		servers = append(servers, n.Server)
+	}
+	return append(servers, n.Servers...)
+}
+
 func (n *NSQ) Connect() error {
 	config := nsq.NewConfig()
-	producer, err := nsq.NewProducer(n.Server, config)
+	config.AuthSecret = n.AuthSecret
+	if n.EnableTLS {
+		tlsConfig, err := n.ClientConfig.TLSConfig()
+		if err != nil {
+			return err
+		}
+		config.TlsV1 = true
+		config.TlsConfig = tlsConfig
+	}
 
-	if err != nil {
-		return err
+	producers := make([]publisher, 0, len(n.servers()))
+	for _, server := range n.servers() {
+		producer, err := nsq.NewProducer(server, config)
+		if err != nil {
+			for _, p := range producers {
+				p.Stop()
+			}
+			return err
+		}
+		producers = append(producers, producer)
 	}
 
-	n.producer = producer
+	n.producers = producers
+	n.current = 0
 	return nil
 }
 
 func (n *NSQ) Close() error {
-	n.producer.Stop()
+	for _, producer := range n.producers {
+		producer.Stop()
+	}
 	return nil
 }
 
@@ -65,23 +153,65 @@ func (n *NSQ) Write(metrics []telegraf.Metric) error {
 		return nil
 	}
 
+	batchSize := n.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	batch := make([][]byte, 0, batchSize)
 	for _, metric := range metrics {
 		buf, err := n.serializer.Serialize(metric)
 		if err != nil {
-			log.Printf("D! [outputs.nsq] Could not serialize metric: %v", err)
+			n.Log.Debugf("Could not serialize metric: %v", err)
 			continue
 		}
 
-		err = n.producer.Publish(n.Topic, buf)
-		if err != nil {
-			return fmt.Errorf("FAILED to send NSQD message: %s", err)
+		batch = append(batch, buf)
+		if len(batch) == batchSize {
+			if err := n.publish(batch); err != nil {
+				return err
+			}
+			batch = make([][]byte, 0, batchSize)
 		}
 	}
+	if len(batch) > 0 {
+		return n.publish(batch)
+	}
 	return nil
 }
 
+// publish sends the batch to the current nsqd, trying the other instances in
+// order if it fails.  Round robin moves on to the next instance after every
+// batch, failover sticks to the instance which succeeded.
+func (n *NSQ) publish(batch [][]byte) error {
+	var err error
+	for i := 0; i < len(n.producers); i++ {
+		index := (n.current + i) % len(n.producers)
+		producer := n.producers[index]
+		if len(batch) == 1 {
+			err = producer.Publish(n.Topic, batch[0])
+		} else {
+			err = producer.MultiPublish(n.Topic, batch)
+		}
+		if err == nil {
+			n.current = index
+			if n.PublishMode == "round_robin" {
+				n.current = (index + 1) % len(n.producers)
+			}
+			return nil
+		}
+		if len(n.producers) > 1 {
+			n.Log.Warnf("Publishing to nsqd %d failed, trying the next one: %v", index+1, err)
+		}
+	}
+	return fmt.Errorf("FAILED to send NSQD message: %s", err)
+}
+
 func init() {
 	outputs.Add("nsq", func() telegraf.Output {
-		return &NSQ{}
+		return &NSQ{
+			PublishMode: "failover",
+			BatchSize:   1,
+		}
 	})
 }
@@ -1,7 +1,9 @@
 package nsq
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/influxdata/telegraf/plugins/serializers"
 	"github.com/influxdata/telegraf/testutil"
@@ -29,3 +31,122 @@ func TestConnectAndWriteIntegration(t *testing.T) {
 	err = n.Write(testutil.MockMetrics())
 	require.NoError(t, err)
 }
+
+type mockProducer struct {
+	fail     bool
+	messages [][]byte
+	batches  int
+}
+
+func (p *mockProducer) Publish(_ string, body []byte) error {
+	return p.MultiPublish("", [][]byte{body})
+}
+
+func (p *mockProducer) MultiPublish(_ string, body [][]byte) error {
+	if p.fail {
+		return errors.New("connection refused")
+	}
+	p.messages = append(p.messages, body...)
+	p.batches++
+	return nil
+}
+
+func (p *mockProducer) Stop() {}
+
+func TestPublishModes(t *testing.T) {
+	s, _ := serializers.NewInfluxSerializer()
+	first, second := &mockProducer{}, &mockProducer{}
+	n := &NSQ{
+		Servers:    []string{"nsqd-1:4150", "nsqd-2:4150"},
+		Topic:      "telegraf",
+		BatchSize:  2,
+		Log:        testutil.Logger{},
+		serializer: s,
+	}
+	require.NoError(t, n.Init())
+	n.producers = []publisher{first, second}
+
+	// Failover sticks to the first available nsqd
+	require.NoError(t, n.Write(testutil.MockMetrics()))
+	require.NoError(t, n.Write(testutil.MockMetrics()))
+	require.Len(t, first.messages, 2)
+	require.Empty(t, second.messages)
+
+	first.fail = true
+	require.NoError(t, n.Write(testutil.MockMetrics()))
+	first.fail = false
+	require.NoError(t, n.Write(testutil.MockMetrics()))
+	require.Len(t, first.messages, 2)
+	require.Len(t, second.messages, 2)
+
+	second.fail = true
+	first.fail = true
+	require.Error(t, n.Write(testutil.MockMetrics()))
+
+	// Round robin alternates between the nsqd
+	first, second = &mockProducer{}, &mockProducer{}
+	n.producers = []publisher{first, second}
+	n.current = 0
+	n.PublishMode = "round_robin"
+	require.NoError(t, n.Init())
+	for i := 0; i < 4; i++ {
+		require.NoError(t, n.Write(testutil.MockMetrics()))
+	}
+	require.Len(t, first.messages, 2)
+	require.Len(t, second.messages, 2)
+}
+
+func TestBatchSize(t *testing.T) {
+	s, _ := serializers.NewInfluxSerializer()
+	producer := &mockProducer{}
+	n := &NSQ{
+		Server:     "localhost:4150",
+		Topic:      "telegraf",
+		BatchSize:  2,
+		Log:        testutil.Logger{},
+		serializer: s,
+	}
+	require.NoError(t, n.Init())
+	n.producers = []publisher{producer}
+
+	metrics := append(testutil.MockMetrics(), testutil.MockMetrics()...)
+	metrics = append(metrics, testutil.MockMetrics()...)
+	require.NoError(t, n.Write(metrics))
+	require.Len(t, producer.messages, 3)
+	require.Equal(t, 2, producer.batches)
+}
+
+func TestInitInvalid(t *testing.T) {
+	n := &NSQ{Server: "localhost:4150", PublishMode: "random"}
+	require.EqualError(t, n.Init(), `unknown publish_mode "random"`)
+
+	n = &NSQ{}
+	require.EqualError(t, n.Init(), "no nsqd server configured")
+}
+
+func TestDataFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "influx", expected: "test1,tag1=value1 value=1 1257894000000000000\n"},
+		{format: "json", expected: `{"fields":{"value":1},"name":"test1","tags":{"tag1":"value1"},"timestamp":1257894000}` + "\n"},
+		{format: "graphite", expected: "value1.test1 1 1257894000\n"},
+		{format: "prometheus", expected: "# HELP test1_value Telegraf collected metric\n# TYPE test1_value untyped\ntest1_value{tag1=\"value1\"} 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			s, err := serializers.NewSerializer(&serializers.Config{DataFormat: tt.format, TimestampUnits: time.Second})
+			require.NoError(t, err)
+			producer := &mockProducer{}
+			n := &NSQ{Server: "localhost:4150", Topic: "telegraf", Log: testutil.Logger{}}
+			n.SetSerializer(s)
+			require.NoError(t, n.Init())
+			n.producers = []publisher{producer}
+
+			require.NoError(t, n.Write(testutil.MockMetrics()))
+			require.Len(t, producer.messages, 1)
+			require.Equal(t, tt.expected, string(producer.messages[0]))
+		})
+	}
+}
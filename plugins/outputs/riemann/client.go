@@ -0,0 +1,131 @@
+package riemann
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/amir/raidman"
+	"github.com/amir/raidman/proto"
+	pb "github.com/golang/protobuf/proto"
+)
+
+// client sends events to Riemann, implemented by raidman.Client for plain
+// TCP and UDP.
+type client interface {
+	SendMulti(events []*raidman.Event) error
+	Close() error
+}
+
+// tlsClient sends events over a TLS connection, which raidman does not
+// support, using the same framing as plain TCP.
+type tlsClient struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func dialTLS(addr string, cfg *tls.Config, timeout time.Duration) (*tlsClient, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &tlsClient{conn: conn, timeout: timeout}, nil
+}
+
+func (c *tlsClient) SendMulti(events []*raidman.Event) error {
+	message := &proto.Msg{}
+	for _, event := range events {
+		e, err := toProtoEvent(event)
+		if err != nil {
+			return err
+		}
+		message.Events = append(message.Events, e)
+	}
+
+	data, err := pb.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if c.timeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return err
+		}
+	}
+
+	// Messages are prefixed with their big-endian length
+	frame := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	if _, err := c.conn.Write(append(frame, data...)); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(c.conn, frame[:4]); err != nil {
+		return err
+	}
+	response := make([]byte, binary.BigEndian.Uint32(frame[:4]))
+	if _, err := io.ReadFull(c.conn, response); err != nil {
+		return err
+	}
+	msg := &proto.Msg{}
+	if err := pb.Unmarshal(response, msg); err != nil {
+		return err
+	}
+	if !msg.GetOk() {
+		return errors.New(msg.GetError())
+	}
+	return nil
+}
+
+func (c *tlsClient) Close() error {
+	return c.conn.Close()
+}
+
+// toProtoEvent converts the event like raidman does for plain connections.
+func toProtoEvent(event *raidman.Event) (*proto.Event, error) {
+	e := &proto.Event{
+		Tags: event.Tags,
+	}
+	if event.Host != "" {
+		e.Host = pb.String(event.Host)
+	}
+	if event.Service != "" {
+		e.Service = pb.String(event.Service)
+	}
+	if event.State != "" {
+		e.State = pb.String(event.State)
+	}
+	if event.Description != "" {
+		e.Description = pb.String(event.Description)
+	}
+	if event.Ttl != 0 {
+		e.Ttl = pb.Float32(event.Ttl)
+	}
+	if event.Time != 0 {
+		e.Time = pb.Int64(event.Time)
+	}
+	for k, v := range event.Attributes {
+		e.Attributes = append(e.Attributes, &proto.Attribute{Key: pb.String(k), Value: pb.String(v)})
+	}
+
+	switch v := event.Metric.(type) {
+	case nil:
+	case int:
+		e.MetricSint64 = pb.Int64(int64(v))
+	case int64:
+		e.MetricSint64 = pb.Int64(v)
+	case uint64:
+		e.MetricSint64 = pb.Int64(int64(v))
+	case float32:
+		e.MetricF = pb.Float32(v)
+	case float64:
+		e.MetricD = pb.Float64(v)
+	default:
+		return nil, fmt.Errorf("metric of invalid type %T", v)
+	}
+	return e, nil
+}
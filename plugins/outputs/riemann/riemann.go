@@ -1,16 +1,20 @@
 package riemann
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/amir/raidman"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
 
@@ -26,11 +30,22 @@ type Riemann struct {
 	Timeout                config.Duration `toml:"timeout"`
 	Log                    telegraf.Logger `toml:"-"`
 
-	client *raidman.Client
+	// Send the events in messages of at most this size, 0 sends all events
+	// of a write in a single message
+	BatchSize int `toml:"batch_size"`
+
+	// Take the TTL of the events from a tag and add custom attributes
+	TTLTag        string            `toml:"ttl_tag"`
+	Attributes    map[string]string `toml:"attributes"`
+	TagAttributes map[string]string `toml:"tag_attributes"`
+
+	tlsint.ClientConfig
+
+	client client
 }
 
 var sampleConfig = `
-  ## The full TCP or UDP URL of the Riemann server
+  ## The full TCP, TLS or UDP URL of the Riemann server
   url = "tcp://localhost:5555"
 
   ## Riemann event TTL, floating-point time in seconds.
@@ -60,21 +75,63 @@ var sampleConfig = `
 
   ## Riemann client write timeout, defaults to "5s" if not set.
   # timeout = "5s"
+
+  ## Maximum number of events sent in a single message, 0 sends all events of
+  ## a write at once.  Keep messages over UDP small to avoid truncation.
+  # batch_size = 0
+
+  ## Tag holding the TTL of the events, overriding the ttl setting.  The value
+  ## is a duration, e.g. "90s", or a number of seconds.  The tag is not sent.
+  # ttl_tag = ""
+
+  ## Additional attributes added to all events.
+  # attributes = {environment = "production"}
+
+  ## Rename the attributes of tags, e.g. to follow the conventions of the
+  ## Riemann streams.
+  # tag_attributes = {dc = "datacenter"}
+
+  ## Optional TLS Config, used with a "tls://" url
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
 `
 
+func (r *Riemann) Init() error {
+	if r.BatchSize < 0 {
+		return errors.New("batch_size must not be negative")
+	}
+	return nil
+}
+
 func (r *Riemann) Connect() error {
 	parsedURL, err := url.Parse(r.URL)
 	if err != nil {
 		return err
 	}
 
-	client, err := raidman.DialWithTimeout(parsedURL.Scheme, parsedURL.Host, time.Duration(r.Timeout))
+	var c client
+	if parsedURL.Scheme == "tls" {
+		var tlsCfg *tls.Config
+		tlsCfg, err = r.ClientConfig.TLSConfig()
+		if err != nil {
+			return err
+		}
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{}
+		}
+		c, err = dialTLS(parsedURL.Host, tlsCfg, time.Duration(r.Timeout))
+	} else {
+		c, err = raidman.DialWithTimeout(parsedURL.Scheme, parsedURL.Host, time.Duration(r.Timeout))
+	}
 	if err != nil {
 		r.client = nil
 		return err
 	}
 
-	r.client = client
+	r.client = c
 	return nil
 }
 
@@ -112,14 +169,22 @@ func (r *Riemann) Write(metrics []telegraf.Metric) error {
 		events = append(events, evs...)
 	}
 
-	if err := r.client.SendMulti(events); err != nil {
-		r.Close()
-		return fmt.Errorf("failed to send riemann message: %s", err)
+	for len(events) > 0 {
+		batch := events
+		if r.BatchSize > 0 && len(batch) > r.BatchSize {
+			batch = batch[:r.BatchSize]
+		}
+		if err := r.client.SendMulti(batch); err != nil {
+			r.Close()
+			return fmt.Errorf("failed to send riemann message: %s", err)
+		}
+		events = events[len(batch):]
 	}
 	return nil
 }
 
 func (r *Riemann) buildRiemannEvents(m telegraf.Metric) []*raidman.Event {
+	ttl := r.ttl(m)
 	events := []*raidman.Event{}
 	for fieldName, value := range m.Fields() {
 		// get host for Riemann event
@@ -132,15 +197,20 @@ func (r *Riemann) buildRiemannEvents(m telegraf.Metric) []*raidman.Event {
 			}
 		}
 
+		tags := m.Tags()
+		if r.TTLTag != "" {
+			delete(tags, r.TTLTag)
+		}
+
 		event := &raidman.Event{
 			Host:        host,
-			Ttl:         r.TTL,
+			Ttl:         ttl,
 			Description: r.DescriptionText,
 			Time:        m.Time().Unix(),
 
-			Attributes: r.attributes(m.Name(), m.Tags()),
+			Attributes: r.attributes(m.Name(), tags),
 			Service:    r.service(m.Name(), fieldName),
-			Tags:       r.tags(m.Tags()),
+			Tags:       r.tags(tags),
 		}
 
 		switch value := value.(type) {
@@ -163,13 +233,44 @@ func (r *Riemann) buildRiemannEvents(m telegraf.Metric) []*raidman.Event {
 	return events
 }
 
+// ttl returns the TTL of the events of the metric, taken from the ttl_tag if
+// present and valid.
+func (r *Riemann) ttl(m telegraf.Metric) float32 {
+	if r.TTLTag == "" {
+		return r.TTL
+	}
+	value, ok := m.GetTag(r.TTLTag)
+	if !ok {
+		return r.TTL
+	}
+	if seconds, err := strconv.ParseFloat(value, 32); err == nil {
+		return float32(seconds)
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return float32(d.Seconds())
+	}
+	r.Log.Debugf("Invalid TTL %q in tag %q, using the default", value, r.TTLTag)
+	return r.TTL
+}
+
 func (r *Riemann) attributes(name string, tags map[string]string) map[string]string {
+	attributes := make(map[string]string, len(tags)+len(r.Attributes)+1)
+	for k, v := range r.Attributes {
+		attributes[k] = v
+	}
+	for k, v := range tags {
+		if k == "host" { // exclude 'host' tag
+			continue
+		}
+		if renamed, ok := r.TagAttributes[k]; ok {
+			k = renamed
+		}
+		attributes[k] = v
+	}
 	if r.MeasurementAsAttribute {
-		tags["measurement"] = name
+		attributes["measurement"] = name
 	}
-
-	delete(tags, "host") // exclude 'host' tag
-	return tags
+	return attributes
 }
 
 func (r *Riemann) service(name string, field string) string {
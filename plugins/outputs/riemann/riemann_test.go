@@ -1,12 +1,19 @@
 package riemann
 
 import (
+	"crypto/tls"
+	"encoding/binary"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/testutil"
 
 	"github.com/amir/raidman"
+	"github.com/amir/raidman/proto"
+	pb "github.com/golang/protobuf/proto"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +33,15 @@ func TestAttributes(t *testing.T) {
 	require.Equal(t,
 		map[string]string{"tag1": "value1", "tag2": "value2", "measurement": "test"},
 		r.attributes("test", tags))
+
+	// custom attributes are added and tag attributes renamed
+	r.MeasurementAsAttribute = false
+	r.Attributes = map[string]string{"environment": "production"}
+	r.TagAttributes = map[string]string{"tag1": "renamed"}
+	require.Equal(t,
+		map[string]string{"renamed": "value1", "tag2": "value2", "environment": "production"},
+		r.attributes("test", tags))
+	require.Equal(t, map[string]string{"tag1": "value1", "tag2": "value2"}, tags)
 }
 
 func TestService(t *testing.T) {
@@ -167,3 +183,139 @@ func TestStateEvents(t *testing.T) {
 	}
 	require.Equal(t, expectedEvent, events[0])
 }
+
+func TestTTLTag(t *testing.T) {
+	r := &Riemann{
+		TTL:    30.0,
+		TTLTag: "ttl",
+		Log:    testutil.Logger{},
+	}
+
+	for value, expected := range map[string]float32{"90s": 90.0, "2.5": 2.5, "invalid": 30.0} {
+		m := metric.New(
+			"test",
+			map[string]string{"host": "host", "ttl": value, "tag1": "value1"},
+			map[string]interface{}{"value": 1.0},
+			time.Unix(0, 0),
+		)
+		events := r.buildRiemannEvents(m)
+		require.Len(t, events, 1)
+		require.Equal(t, expected, events[0].Ttl, value)
+		// the ttl tag is not sent
+		require.Equal(t, map[string]string{"tag1": "value1"}, events[0].Attributes)
+		require.Equal(t, []string{"value1"}, events[0].Tags)
+	}
+}
+
+type mockClient struct {
+	batches [][]*raidman.Event
+}
+
+func (c *mockClient) SendMulti(events []*raidman.Event) error {
+	c.batches = append(c.batches, events)
+	return nil
+}
+
+func (c *mockClient) Close() error {
+	return nil
+}
+
+func TestBatchSize(t *testing.T) {
+	client := &mockClient{}
+	r := &Riemann{
+		BatchSize: 2,
+		Log:       testutil.Logger{},
+		client:    client,
+	}
+	require.NoError(t, r.Init())
+
+	var metrics []telegraf.Metric
+	for i := 0; i < 5; i++ {
+		metrics = append(metrics, metric.New(
+			"test",
+			map[string]string{"host": "host"},
+			map[string]interface{}{"value": i},
+			time.Unix(0, 0),
+		))
+	}
+	require.NoError(t, r.Write(metrics))
+	require.Len(t, client.batches, 3)
+	require.Len(t, client.batches[0], 2)
+	require.Len(t, client.batches[1], 2)
+	require.Len(t, client.batches[2], 1)
+
+	r.BatchSize = -1
+	require.Error(t, r.Init())
+}
+
+func TestTLS(t *testing.T) {
+	pki := testutil.NewPKI("../../../testutil/pki")
+	// The cipher suite of the test pki is not offered by current clients
+	tlsServerConfig := pki.TLSServerConfig()
+	tlsServerConfig.TLSCipherSuites = nil
+	serverConfig, err := tlsServerConfig.TLSConfig()
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan *proto.Msg, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var header [4]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+		msg := &proto.Msg{}
+		if err := pb.Unmarshal(data, msg); err != nil {
+			return
+		}
+		received <- msg
+
+		response, _ := pb.Marshal(&proto.Msg{Ok: pb.Bool(true)})
+		binary.BigEndian.PutUint32(header[:], uint32(len(response)))
+		_, _ = conn.Write(append(header[:], response...))
+	}()
+
+	r := &Riemann{
+		URL:          "tls://" + listener.Addr().String(),
+		TTL:          2.5,
+		Separator:    "/",
+		Timeout:      config.Duration(5 * time.Second),
+		ClientConfig: *pki.TLSClientConfig(),
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, r.Init())
+	require.NoError(t, r.Connect())
+	defer r.Close()
+
+	m := metric.New(
+		"test",
+		map[string]string{"host": "abc123", "tag1": "value1"},
+		map[string]interface{}{"value": 5.6},
+		time.Unix(1257894000, 0),
+	)
+	require.NoError(t, r.Write([]telegraf.Metric{m}))
+
+	msg := <-received
+	require.Len(t, msg.Events, 1)
+	event := msg.Events[0]
+	require.Equal(t, "abc123", event.GetHost())
+	require.Equal(t, "test/value", event.GetService())
+	require.Equal(t, int64(1257894000), event.GetTime())
+	require.Equal(t, float32(2.5), event.GetTtl())
+	require.Equal(t, 5.6, event.GetMetricD())
+	require.Equal(t, []string{"value1"}, event.GetTags())
+	require.Len(t, event.GetAttributes(), 1)
+	require.Equal(t, "tag1", event.GetAttributes()[0].GetKey())
+	require.Equal(t, "value1", event.GetAttributes()[0].GetValue())
+}
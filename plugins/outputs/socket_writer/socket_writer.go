@@ -60,8 +60,10 @@ func (sw *SocketWriter) SampleConfig() string {
   ## Defaults to the OS configuration.
   # keep_alive_period = "5m"
 
-  ## Content encoding for packet-based connections (i.e. UDP, unixgram).
-  ## Can be set to "gzip" or to "identity" to apply no encoding.
+  ## Content encoding for message payloads, can be set to "gzip", "zlib",
+  ## "snappy" or "zstd" to compress the payloads or to "identity" to apply no encoding.
+  ## Stream sockets use the snappy framing format, packet sockets the snappy
+  ## block format.
   ##
   # content_encoding = "identity"
 
@@ -102,7 +104,12 @@ func (sw *SocketWriter) Connect() error {
 		log.Printf("unable to configure keep alive (%s): %s", sw.Address, err)
 	}
 	//set encoder
-	sw.encoder, err = internal.NewContentEncoder(sw.ContentEncoding)
+	switch spl[0] {
+	case "tcp", "tcp4", "tcp6", "unix":
+		sw.encoder, err = internal.NewStreamContentEncoder(sw.ContentEncoding)
+	default:
+		sw.encoder, err = internal.NewContentEncoder(sw.ContentEncoding)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,204 @@
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// magicByte starts every message in the Confluent wire format, followed by
+// the schema id as 4 byte big-endian integer.
+const magicByte = 0
+
+type Parser struct {
+	MetricName       string
+	SchemaRegistry   string
+	Schema           string
+	MeasurementField string
+	Tags             []string
+	Fields           []string
+	Timestamp        string
+	TimestampFormat  string
+	FieldSeparator   string
+	DefaultTags      map[string]string
+	Log              telegraf.Logger
+
+	registry *schemaRegistry
+	schema   *schema
+}
+
+func (p *Parser) Init() error {
+	switch {
+	case p.SchemaRegistry != "":
+		registry, err := newSchemaRegistry(p.SchemaRegistry)
+		if err != nil {
+			return err
+		}
+		p.registry = registry
+	case p.Schema != "":
+		s, err := parseSchema(p.Schema)
+		if err != nil {
+			return err
+		}
+		p.schema = s
+	default:
+		return fmt.Errorf("either avro_schema_registry or avro_schema must be set")
+	}
+
+	if p.Timestamp != "" && p.TimestampFormat == "" {
+		return fmt.Errorf("avro_timestamp_format must be set if avro_timestamp is used")
+	}
+	if p.FieldSeparator == "" {
+		p.FieldSeparator = "_"
+	}
+	return nil
+}
+
+// Parse decodes a message.  With a schema registry the message has to be in
+// the Confluent wire format and contains a single record.  With a configured
+// schema the message may contain several records in sequence.
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	s := p.schema
+	if p.registry != nil {
+		if len(buf) < 5 || buf[0] != magicByte {
+			return nil, fmt.Errorf("message is not in the schema registry wire format")
+		}
+		id := int(binary.BigEndian.Uint32(buf[1:5]))
+		var err error
+		if s, err = p.registry.get(id); err != nil {
+			return nil, err
+		}
+		buf = buf[5:]
+	}
+
+	now := time.Now()
+	d := &decoder{buf: buf}
+	metrics := make([]telegraf.Metric, 0)
+	for d.remaining() > 0 {
+		start := d.pos
+		value, err := d.decode(s)
+		if err != nil {
+			return nil, err
+		}
+		if d.pos == start {
+			return nil, fmt.Errorf("schema does not consume any data")
+		}
+
+		m, err := p.createMetric(value, now)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: avro ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) createMetric(value interface{}, now time.Time) (telegraf.Metric, error) {
+	flat := make(map[string]interface{})
+	p.flatten("", value, flat)
+
+	name := p.MetricName
+	if p.MeasurementField != "" {
+		if v, ok := flat[p.MeasurementField]; ok && v != nil {
+			name = fmt.Sprint(v)
+		}
+	}
+
+	timestamp := now
+	if p.Timestamp != "" {
+		v, ok := flat[p.Timestamp]
+		if !ok || v == nil {
+			return nil, fmt.Errorf("timestamp field %q not found", p.Timestamp)
+		}
+		var err error
+		if timestamp, err = internal.ParseTimestamp(p.TimestampFormat, v, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := make(map[string]string)
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	for _, key := range p.Tags {
+		if v, ok := flat[key]; ok && v != nil {
+			tags[key] = fmt.Sprint(v)
+		}
+	}
+
+	fields := make(map[string]interface{})
+	if len(p.Fields) > 0 {
+		for _, key := range p.Fields {
+			if v, ok := flat[key]; ok && v != nil {
+				fields[key] = v
+			}
+		}
+	} else {
+		for key, v := range flat {
+			if v == nil || key == p.Timestamp || key == p.MeasurementField || p.isTag(key) {
+				continue
+			}
+			fields[key] = v
+		}
+	}
+
+	return metric.New(name, tags, fields, timestamp), nil
+}
+
+func (p *Parser) isTag(key string) bool {
+	for _, tag := range p.Tags {
+		if tag == key {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten adds the leaves of nested records, maps and arrays to the result,
+// joining the names of the levels with the field separator.
+func (p *Parser) flatten(prefix string, value interface{}, result map[string]interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + p.FieldSeparator + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			p.flatten(join(key), item, result)
+		}
+	case []interface{}:
+		for i, item := range v {
+			p.flatten(join(strconv.Itoa(i)), item, result)
+		}
+	default:
+		if prefix == "" {
+			prefix = "value"
+		}
+		result[prefix] = v
+	}
+}
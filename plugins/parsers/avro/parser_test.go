@@ -0,0 +1,295 @@
+package avro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const cpuSchema = `
+{
+  "type": "record",
+  "name": "cpu",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "host", "type": "string"},
+    {"name": "state", "type": {"type": "enum", "name": "State", "symbols": ["up", "down"]}},
+    {"name": "time", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "usage", "type": {"type": "record", "name": "Usage", "fields": [
+      {"name": "user", "type": "double"},
+      {"name": "system", "type": "float"}
+    ]}},
+    {"name": "comment", "type": ["null", "string"], "default": null},
+    {"name": "cores", "type": {"type": "array", "items": "int"}},
+    {"name": "labels", "type": {"type": "map", "values": "string"}},
+    {"name": "previous", "type": ["null", "Usage"], "default": null},
+    {"name": "healthy", "type": "boolean"}
+  ]
+}`
+
+// encoder produces Avro binary encoded test data.
+type encoder []byte
+
+func (e *encoder) long(v int64) *encoder {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64((v<<1)^(v>>63)))
+	*e = append(*e, buf[:n]...)
+	return e
+}
+
+func (e *encoder) str(v string) *encoder {
+	e.long(int64(len(v)))
+	*e = append(*e, v...)
+	return e
+}
+
+func (e *encoder) double(v float64) *encoder {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+	*e = append(*e, buf...)
+	return e
+}
+
+func (e *encoder) float(v float32) *encoder {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+	*e = append(*e, buf...)
+	return e
+}
+
+func (e *encoder) boolean(v bool) *encoder {
+	if v {
+		*e = append(*e, 1)
+	} else {
+		*e = append(*e, 0)
+	}
+	return e
+}
+
+func cpuRecord(host string, ts int64) []byte {
+	e := &encoder{}
+	e.str(host)
+	e.long(1) // down
+	e.long(ts)
+	e.double(42.5).float(1.5)
+	e.long(1).str("busy")
+	e.long(2).long(0).long(3).long(0)
+	e.long(-1).long(8).str("rack").str("a1").long(0)
+	e.long(0)
+	e.boolean(true)
+	return *e
+}
+
+func TestParseSchema(t *testing.T) {
+	p := &Parser{
+		MetricName:      "avro",
+		Schema:          cpuSchema,
+		Tags:            []string{"host", "labels_rack"},
+		Timestamp:       "time",
+		TimestampFormat: "unix_ms",
+	}
+	require.NoError(t, p.Init())
+
+	buf := append(cpuRecord("server01", 1600000000000), cpuRecord("server02", 1600000001000)...)
+	metrics, err := p.Parse(buf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"avro",
+			map[string]string{
+				"host":        "server01",
+				"labels_rack": "a1",
+			},
+			map[string]interface{}{
+				"state":        "down",
+				"usage_user":   42.5,
+				"usage_system": 1.5,
+				"comment":      "busy",
+				"cores_0":      int64(0),
+				"cores_1":      int64(3),
+				"healthy":      true,
+			},
+			time.Unix(1600000000, 0),
+		),
+		testutil.MustMetric(
+			"avro",
+			map[string]string{
+				"host":        "server02",
+				"labels_rack": "a1",
+			},
+			map[string]interface{}{
+				"state":        "down",
+				"usage_user":   42.5,
+				"usage_system": 1.5,
+				"comment":      "busy",
+				"cores_0":      int64(0),
+				"cores_1":      int64(3),
+				"healthy":      true,
+			},
+			time.Unix(1600000001, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseSelectedFields(t *testing.T) {
+	p := &Parser{
+		MetricName:       "avro",
+		Schema:           cpuSchema,
+		MeasurementField: "host",
+		Fields:           []string{"usage_user", "previous_user"},
+	}
+	require.NoError(t, p.Init())
+
+	metrics, err := p.Parse(cpuRecord("server01", 0))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, "server01", metrics[0].Name())
+	require.Equal(t, map[string]interface{}{"usage_user": 42.5}, metrics[0].Fields())
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/schemas/ids/7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"schema": cpuSchema})
+	}))
+	defer ts.Close()
+
+	p := &Parser{
+		MetricName:     "avro",
+		SchemaRegistry: "http://user:secret@" + ts.Listener.Addr().String(),
+		Tags:           []string{"host"},
+	}
+	require.NoError(t, p.Init())
+
+	message := append([]byte{magicByte, 0, 0, 0, 7}, cpuRecord("server01", 0)...)
+	for i := 0; i < 2; i++ {
+		metrics, err := p.Parse(message)
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		require.Equal(t, "server01", metrics[0].Tags()["host"])
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// unknown schema id
+	_, err := p.Parse(append([]byte{magicByte, 0, 0, 0, 8}, cpuRecord("server01", 0)...))
+	require.Error(t, err)
+
+	// missing wire format header
+	_, err = p.Parse(cpuRecord("server01", 0))
+	require.Error(t, err)
+}
+
+func TestParseTruncated(t *testing.T) {
+	p := &Parser{
+		MetricName: "avro",
+		Schema:     cpuSchema,
+	}
+	require.NoError(t, p.Init())
+
+	buf := cpuRecord("server01", 0)
+	_, err := p.Parse(buf[:len(buf)-3])
+	require.Error(t, err)
+}
+
+func TestParseZeroSizeItemsCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		items string
+	}{
+		{name: "null", items: `"null"`},
+		{name: "empty record", items: `{"type": "record", "name": "Empty", "fields": []}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				MetricName: "avro",
+				Schema: `{"type": "record", "name": "test", "fields": [
+				  {"name": "items", "type": {"type": "array", "items": ` + tt.items + `}},
+				  {"name": "value", "type": "long"}
+				]}`,
+			}
+			require.NoError(t, p.Init())
+
+			e := &encoder{}
+			e.long(2).long(0).long(42)
+			metrics, err := p.Parse(*e)
+			require.NoError(t, err)
+			require.Len(t, metrics, 1)
+
+			e = &encoder{}
+			e.long(math.MaxInt64).long(0).long(42)
+			_, err = p.Parse(*e)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "exceeds the remaining")
+		})
+	}
+}
+
+func TestPrimitiveSchema(t *testing.T) {
+	p := &Parser{
+		MetricName: "avro",
+		Schema:     `"long"`,
+	}
+	require.NoError(t, p.Init())
+
+	e := &encoder{}
+	e.long(-5)
+	metrics, err := p.Parse(*e)
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, map[string]interface{}{"value": int64(-5)}, metrics[0].Fields())
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser *Parser
+	}{
+		{
+			name:   "no schema",
+			parser: &Parser{},
+		},
+		{
+			name:   "invalid schema",
+			parser: &Parser{Schema: `{"type": "record", "fields": []}`},
+		},
+		{
+			name:   "unknown type",
+			parser: &Parser{Schema: `{"type": "record", "name": "r", "fields": [{"name": "a", "type": "Unknown"}]}`},
+		},
+		{
+			name:   "timestamp without format",
+			parser: &Parser{Schema: `"long"`, Timestamp: "value"},
+		},
+		{
+			name:   "invalid registry",
+			parser: &Parser{SchemaRegistry: "ftp://localhost"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.parser.Init())
+		})
+	}
+}
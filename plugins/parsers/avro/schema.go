@@ -0,0 +1,324 @@
+package avro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// schema is a node of a parsed Avro schema.
+type schema struct {
+	kind    string
+	name    string
+	fields  []recordField
+	symbols []string
+	items   *schema
+	values  *schema
+	types   []*schema
+	size    int
+}
+
+type recordField struct {
+	name   string
+	schema *schema
+}
+
+var primitives = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+// parseSchema parses the JSON representation of an Avro schema.
+func parseSchema(definition string) (*schema, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(definition), &raw); err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+
+	p := &schemaParser{named: make(map[string]*schema)}
+	return p.parse(raw, "")
+}
+
+type schemaParser struct {
+	named map[string]*schema
+}
+
+func (p *schemaParser) parse(raw interface{}, namespace string) (*schema, error) {
+	switch v := raw.(type) {
+	case string:
+		return p.lookup(v, namespace)
+	case []interface{}:
+		union := &schema{kind: "union"}
+		for _, branch := range v {
+			s, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			union.types = append(union.types, s)
+		}
+		return union, nil
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	default:
+		return nil, fmt.Errorf("invalid schema element %v", raw)
+	}
+}
+
+func (p *schemaParser) lookup(name, namespace string) (*schema, error) {
+	if primitives[name] {
+		return &schema{kind: name}, nil
+	}
+	if s, ok := p.named[fullName(name, namespace)]; ok {
+		return s, nil
+	}
+	if s, ok := p.named[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+func (p *schemaParser) parseComplex(v map[string]interface{}, namespace string) (*schema, error) {
+	kind, ok := v["type"].(string)
+	if !ok {
+		// the type itself is a schema, e.g. {"type": {"type": "array", ...}}
+		return p.parse(v["type"], namespace)
+	}
+
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		name, _ := v["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("%s without name", kind)
+		}
+		if ns, ok := v["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		s := &schema{kind: kind, name: fullName(name, namespace)}
+		if kind == "error" {
+			s.kind = "record"
+		}
+		// register before parsing the fields to allow recursive types
+		p.named[s.name] = s
+		if i := strings.LastIndex(s.name, "."); i >= 0 {
+			namespace = s.name[:i]
+		}
+
+		switch s.kind {
+		case "record":
+			fields, _ := v["fields"].([]interface{})
+			for _, f := range fields {
+				field, ok := f.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid field in record %q", s.name)
+				}
+				fieldName, _ := field["name"].(string)
+				fieldSchema, err := p.parse(field["type"], namespace)
+				if err != nil {
+					return nil, fmt.Errorf("field %q of record %q: %v", fieldName, s.name, err)
+				}
+				s.fields = append(s.fields, recordField{name: fieldName, schema: fieldSchema})
+			}
+		case "enum":
+			symbols, _ := v["symbols"].([]interface{})
+			for _, symbol := range symbols {
+				s.symbols = append(s.symbols, fmt.Sprint(symbol))
+			}
+		case "fixed":
+			size, ok := v["size"].(float64)
+			if !ok {
+				return nil, fmt.Errorf("fixed %q without size", s.name)
+			}
+			s.size = int(size)
+		}
+		return s, nil
+	case "array":
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{kind: kind, items: items}, nil
+	case "map":
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{kind: kind, values: values}, nil
+	default:
+		// primitive, possibly annotated with a logical type
+		return p.lookup(kind, namespace)
+	}
+}
+
+func fullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+var errShortBuffer = errors.New("unexpected end of data")
+
+// decoder reads Avro binary encoded data.
+type decoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.buf) - d.pos
+}
+
+func (d *decoder) readLong() (int64, error) {
+	value, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errShortBuffer
+	}
+	d.pos += n
+	// zig-zag decoding
+	return int64(value>>1) ^ -int64(value&1), nil
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || d.remaining() < n {
+		return nil, errShortBuffer
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// decode returns the value of the given schema.  Records and maps are
+// returned as map[string]interface{}, arrays as []interface{}, integers as
+// int64, floating point numbers as float64 and bytes as string.
+func (d *decoder) decode(s *schema) (interface{}, error) {
+	switch s.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.readBytes(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return d.readLong()
+	case "float":
+		b, err := d.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "string":
+		n, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.readBytes(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "fixed":
+		b, err := d.readBytes(s.size)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "enum":
+		index, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(s.symbols) {
+			return nil, fmt.Errorf("invalid symbol index %d for enum %q", index, s.name)
+		}
+		return s.symbols[index], nil
+	case "union":
+		index, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(s.types) {
+			return nil, fmt.Errorf("invalid union branch %d", index)
+		}
+		return d.decode(s.types[index])
+	case "record":
+		record := make(map[string]interface{}, len(s.fields))
+		for _, field := range s.fields {
+			value, err := d.decode(field.schema)
+			if err != nil {
+				return nil, fmt.Errorf("decoding field %q of %q failed: %v", field.name, s.name, err)
+			}
+			record[field.name] = value
+		}
+		return record, nil
+	case "array":
+		var items []interface{}
+		err := d.readBlocks(func() error {
+			item, err := d.decode(s.items)
+			items = append(items, item)
+			return err
+		})
+		return items, err
+	case "map":
+		values := make(map[string]interface{})
+		err := d.readBlocks(func() error {
+			key, err := d.decode(&schema{kind: "string"})
+			if err != nil {
+				return err
+			}
+			values[key.(string)], err = d.decode(s.values)
+			return err
+		})
+		return values, err
+	default:
+		return nil, fmt.Errorf("unsupported type %q", s.kind)
+	}
+}
+
+// readBlocks calls fn for every item of the blocks of an array or map.
+func (d *decoder) readBlocks(fn func() error) error {
+	for {
+		count, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			// a negative count is followed by the size of the block in bytes
+			count = -count
+			if _, err := d.readLong(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			pos := d.pos
+			if err := fn(); err != nil {
+				return err
+			}
+			// Items taking no bytes, e.g. nulls or empty records, never run
+			// out of data, so bound their number to not loop on a malformed
+			// count
+			if i == 0 && d.pos == pos && count > int64(d.remaining()) {
+				return fmt.Errorf("block of %d items exceeds the remaining %d bytes", count, d.remaining())
+			}
+		}
+	}
+}
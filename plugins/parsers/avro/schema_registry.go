@@ -0,0 +1,95 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// schemaRegistry fetches writer schemas by id from a Confluent compatible
+// schema registry.  Schemas are immutable, so they are cached forever.
+type schemaRegistry struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[int]*schema
+}
+
+func newSchemaRegistry(address string) (*schemaRegistry, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema registry URL failed: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q for schema registry", u.Scheme)
+	}
+
+	registry := &schemaRegistry{
+		client: &http.Client{Timeout: 10 * time.Second},
+		cache:  make(map[int]*schema),
+	}
+	// credentials are sent explicitly so they do not show up in errors
+	if u.User != nil {
+		registry.username = u.User.Username()
+		registry.password, _ = u.User.Password()
+		u.User = nil
+	}
+	registry.url = u.String()
+	return registry, nil
+}
+
+func (r *schemaRegistry) get(id int) (*schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.cache[id]; ok {
+		return s, nil
+	}
+
+	definition, err := r.fetch(id)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseSchema(definition)
+	if err != nil {
+		return nil, fmt.Errorf("schema %d: %v", id, err)
+	}
+	r.cache[id] = s
+	return s, nil
+}
+
+func (r *schemaRegistry) fetch(id int) (string, error) {
+	addr := r.url + "/schemas/ids/" + strconv.Itoa(id)
+	req, err := http.NewRequest("GET", addr, nil)
+	if err != nil {
+		return "", err
+	}
+	if r.username != "" || r.password != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching schema %d failed: %v", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching schema %d: %s returned HTTP status %s", id, addr, resp.Status)
+	}
+
+	var body struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding schema %d failed: %v", id, err)
+	}
+	return body.Schema, nil
+}
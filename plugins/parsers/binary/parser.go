@@ -0,0 +1,245 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Parser creates metrics from fixed-layout binary frames.  Every
+// configuration produces one metric per frame.
+type Parser struct {
+	MetricName  string
+	Endianness  string
+	Configs     []Config
+	DefaultTags map[string]string
+
+	byteOrder binary.ByteOrder
+}
+
+// Config describes the metric extracted from a frame.
+type Config struct {
+	MetricName string  `toml:"metric_name"`
+	Entries    []Entry `toml:"entries"`
+}
+
+// Entry describes a single value of the frame.
+type Entry struct {
+	Name       string `toml:"name"`
+	Type       string `toml:"type"`
+	Offset     int    `toml:"offset"`
+	Length     int    `toml:"length"`
+	Endianness string `toml:"endianness"`
+	Assignment string `toml:"assignment"`
+	Format     string `toml:"format"`
+
+	byteOrder binary.ByteOrder
+}
+
+var typeSizes = map[string]int{
+	"bool":    1,
+	"int8":    1,
+	"uint8":   1,
+	"int16":   2,
+	"uint16":  2,
+	"int32":   4,
+	"uint32":  4,
+	"float32": 4,
+	"int64":   8,
+	"uint64":  8,
+	"float64": 8,
+}
+
+func byteOrder(endianness string) (binary.ByteOrder, error) {
+	switch endianness {
+	case "", "be":
+		return binary.BigEndian, nil
+	case "le":
+		return binary.LittleEndian, nil
+	default:
+		return nil, fmt.Errorf("invalid endianness %q", endianness)
+	}
+}
+
+func (p *Parser) Init() error {
+	var err error
+	if p.byteOrder, err = byteOrder(p.Endianness); err != nil {
+		return err
+	}
+
+	if len(p.Configs) == 0 {
+		return fmt.Errorf("no binary configuration given")
+	}
+
+	for i := range p.Configs {
+		cfg := &p.Configs[i]
+		if cfg.MetricName == "" {
+			cfg.MetricName = p.MetricName
+		}
+		if len(cfg.Entries) == 0 {
+			return fmt.Errorf("binary configuration %q has no entries", cfg.MetricName)
+		}
+
+		for j := range cfg.Entries {
+			if err := p.initEntry(&cfg.Entries[j]); err != nil {
+				return fmt.Errorf("entry %d of %q: %v", j+1, cfg.MetricName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Parser) initEntry(e *Entry) error {
+	if e.Offset < 0 {
+		return fmt.Errorf("negative offset %d", e.Offset)
+	}
+
+	if e.Type == "string" {
+		if e.Length <= 0 {
+			return fmt.Errorf("length required for string entries")
+		}
+	} else {
+		size, ok := typeSizes[e.Type]
+		if !ok {
+			return fmt.Errorf("unknown type %q", e.Type)
+		}
+		e.Length = size
+	}
+
+	e.byteOrder = p.byteOrder
+	if e.Endianness != "" {
+		var err error
+		if e.byteOrder, err = byteOrder(e.Endianness); err != nil {
+			return err
+		}
+	}
+
+	switch e.Assignment {
+	case "":
+		e.Assignment = "field"
+		fallthrough
+	case "field", "tag":
+		if e.Name == "" {
+			return fmt.Errorf("name required for %s entries", e.Assignment)
+		}
+	case "measurement":
+	case "time":
+		if e.Format == "" {
+			return fmt.Errorf("format required for time entries")
+		}
+		if e.Type == "bool" || strings.HasPrefix(e.Type, "float") {
+			return fmt.Errorf("type %q can not be used as time", e.Type)
+		}
+	default:
+		return fmt.Errorf("invalid assignment %q", e.Assignment)
+	}
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	now := time.Now()
+
+	metrics := make([]telegraf.Metric, 0, len(p.Configs))
+	for _, cfg := range p.Configs {
+		m, err := p.parseFrame(buf, cfg, now)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: binary ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) parseFrame(buf []byte, cfg Config, now time.Time) (telegraf.Metric, error) {
+	name := cfg.MetricName
+	timestamp := now
+	tags := make(map[string]string)
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	fields := make(map[string]interface{})
+
+	for _, e := range cfg.Entries {
+		if e.Offset+e.Length > len(buf) {
+			return nil, fmt.Errorf("frame of %d bytes too short for entry %q at offset %d", len(buf), e.Name, e.Offset)
+		}
+		value := e.decode(buf[e.Offset : e.Offset+e.Length])
+
+		switch e.Assignment {
+		case "field":
+			fields[e.Name] = value
+		case "tag":
+			tags[e.Name] = fmt.Sprint(value)
+		case "measurement":
+			name = fmt.Sprint(value)
+		case "time":
+			if u, ok := value.(uint64); ok {
+				if u > math.MaxInt64 {
+					return nil, fmt.Errorf("timestamp %d out of range", u)
+				}
+				value = int64(u)
+			}
+			var err error
+			if timestamp, err = internal.ParseTimestamp(e.Format, value, "UTC"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return metric.New(name, tags, fields, timestamp), nil
+}
+
+// decode returns the value of the entry.  Integers are returned as int64 or
+// uint64, floating point numbers as float64.
+func (e *Entry) decode(b []byte) interface{} {
+	switch e.Type {
+	case "bool":
+		return b[0] != 0
+	case "int8":
+		return int64(int8(b[0]))
+	case "uint8":
+		return uint64(b[0])
+	case "int16":
+		return int64(int16(e.byteOrder.Uint16(b)))
+	case "uint16":
+		return uint64(e.byteOrder.Uint16(b))
+	case "int32":
+		return int64(int32(e.byteOrder.Uint32(b)))
+	case "uint32":
+		return uint64(e.byteOrder.Uint32(b))
+	case "int64":
+		return int64(e.byteOrder.Uint64(b))
+	case "uint64":
+		return e.byteOrder.Uint64(b)
+	case "float32":
+		return float64(math.Float32frombits(e.byteOrder.Uint32(b)))
+	case "float64":
+		return math.Float64frombits(e.byteOrder.Uint64(b))
+	default:
+		// strings are padded with NUL bytes
+		return strings.TrimRight(string(b), "\x00")
+	}
+}
@@ -0,0 +1,174 @@
+package binary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// frame of a fictional sensor containing the sensor id (uint16, big-endian),
+// the temperature (int16, big-endian), the humidity (float32, little-endian),
+// the timestamp (uint32, big-endian), the location (string of 8 bytes padded
+// with NUL) and a low battery flag (bool)
+var frame = []byte{
+	0x01, 0x02,
+	0xff, 0x38,
+	0x00, 0x00, 0x28, 0x42,
+	0x5f, 0x5e, 0x10, 0x00,
+	'r', 'o', 'o', 'f', 0x00, 0x00, 0x00, 0x00,
+	0x01,
+}
+
+func sensorEntries() []Entry {
+	return []Entry{
+		{Name: "sensor", Type: "uint16", Offset: 0, Assignment: "tag"},
+		{Name: "temperature", Type: "int16", Offset: 2},
+		{Name: "humidity", Type: "float32", Offset: 4, Endianness: "le"},
+		{Name: "time", Type: "uint32", Offset: 8, Assignment: "time", Format: "unix"},
+		{Name: "location", Type: "string", Offset: 12, Length: 8, Assignment: "tag"},
+		{Name: "battery_low", Type: "bool", Offset: 20},
+	}
+}
+
+func TestParse(t *testing.T) {
+	p := &Parser{
+		MetricName: "binary",
+		Configs:    []Config{{Entries: sensorEntries()}},
+	}
+	require.NoError(t, p.Init())
+
+	metrics, err := p.Parse(frame)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"binary",
+			map[string]string{
+				"sensor":   "258",
+				"location": "roof",
+			},
+			map[string]interface{}{
+				"temperature": int64(-200),
+				"humidity":    float64(42),
+				"battery_low": true,
+			},
+			time.Unix(1600000000, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseLittleEndianAndMeasurement(t *testing.T) {
+	p := &Parser{
+		MetricName: "binary",
+		Endianness: "le",
+		Configs: []Config{
+			{
+				Entries: []Entry{
+					{Type: "string", Offset: 12, Length: 8, Assignment: "measurement"},
+					{Name: "sensor", Type: "uint16", Offset: 0},
+					{Name: "humidity", Type: "float32", Offset: 4},
+				},
+			},
+			{
+				MetricName: "raw",
+				Entries: []Entry{
+					{Name: "first", Type: "uint8", Offset: 0},
+					{Name: "sensor", Type: "uint16", Offset: 0, Endianness: "be"},
+				},
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	metrics, err := p.Parse(frame)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"roof",
+			map[string]string{},
+			map[string]interface{}{
+				"sensor":   uint64(0x0201),
+				"humidity": float64(42),
+			},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"raw",
+			map[string]string{},
+			map[string]interface{}{
+				"first":  uint64(1),
+				"sensor": uint64(0x0102),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
+
+func TestParseShortFrame(t *testing.T) {
+	p := &Parser{
+		MetricName: "binary",
+		Configs:    []Config{{Entries: sensorEntries()}},
+	}
+	require.NoError(t, p.Init())
+
+	_, err := p.Parse(frame[:10])
+	require.Error(t, err)
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		endianness string
+		entry      Entry
+	}{
+		{
+			name:       "invalid endianness",
+			endianness: "middle",
+			entry:      Entry{Name: "a", Type: "int8"},
+		},
+		{
+			name:  "unknown type",
+			entry: Entry{Name: "a", Type: "int24"},
+		},
+		{
+			name:  "string without length",
+			entry: Entry{Name: "a", Type: "string"},
+		},
+		{
+			name:  "field without name",
+			entry: Entry{Type: "int8"},
+		},
+		{
+			name:  "time without format",
+			entry: Entry{Type: "int64", Assignment: "time"},
+		},
+		{
+			name:  "float time",
+			entry: Entry{Type: "float64", Assignment: "time", Format: "unix"},
+		},
+		{
+			name:  "invalid assignment",
+			entry: Entry{Name: "a", Type: "int8", Assignment: "label"},
+		},
+		{
+			name:  "negative offset",
+			entry: Entry{Name: "a", Type: "int8", Offset: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				MetricName: "binary",
+				Endianness: tt.endianness,
+				Configs:    []Config{{Entries: []Entry{tt.entry}}},
+			}
+			require.Error(t, p.Init())
+		})
+	}
+}
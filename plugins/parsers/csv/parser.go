@@ -1,10 +1,12 @@
 package csv
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -33,8 +35,20 @@ type Config struct {
 	TrimSpace         bool     `toml:"csv_trim_space"`
 	SkipValues        []string `toml:"csv_skip_values"`
 
+	MetadataRows       int      `toml:"csv_metadata_rows"`
+	MetadataSeparators []string `toml:"csv_metadata_separators"`
+	MetadataTrimSet    string   `toml:"csv_metadata_trim_set"`
+	ResetMode          string   `toml:"csv_reset_mode"`
+
 	gotColumnNames bool
 
+	remainingSkipRows     int
+	remainingMetadataRows int
+	remainingHeaderRows   int
+	headerNames           []string
+	metadataTags          map[string]string
+	metadataSeparators    []string
+
 	TimeFunc    func() time.Time
 	DefaultTags map[string]string
 }
@@ -67,13 +81,43 @@ func NewParser(c *Config) (*Parser, error) {
 		return nil, fmt.Errorf("csv_column_names field count doesn't match with csv_column_types")
 	}
 
+	switch c.ResetMode {
+	case "":
+		c.ResetMode = "always"
+	case "always", "none":
+	default:
+		return nil, fmt.Errorf("unknown csv_reset_mode %q", c.ResetMode)
+	}
+
+	if c.MetadataRows > 0 && len(c.MetadataSeparators) == 0 {
+		return nil, fmt.Errorf("csv_metadata_separators must be specified if csv_metadata_rows is set")
+	}
+
+	// Try the longest separators first so "==" is preferred over "=".
+	c.metadataSeparators = append([]string(nil), c.MetadataSeparators...)
+	sort.SliceStable(c.metadataSeparators, func(i, j int) bool {
+		return len(c.metadataSeparators[i]) > len(c.metadataSeparators[j])
+	})
+
 	c.gotColumnNames = len(c.ColumnNames) > 0
 
 	if c.TimeFunc == nil {
 		c.TimeFunc = time.Now
 	}
 
-	return &Parser{Config: c}, nil
+	p := &Parser{Config: c}
+	p.Reset()
+	return p, nil
+}
+
+// Reset makes the parser expect the rows to skip, the metadata rows and the
+// header rows again, as at the beginning of a new document.
+func (p *Parser) Reset() {
+	p.remainingSkipRows = p.SkipRows
+	p.remainingMetadataRows = p.MetadataRows
+	p.remainingHeaderRows = p.HeaderRowCount
+	p.headerNames = nil
+	p.metadataTags = make(map[string]string)
 }
 
 func (p *Parser) SetTimeFunc(fn TimeFunc) {
@@ -94,50 +138,53 @@ func (p *Parser) compile(r io.Reader) *csv.Reader {
 	return csvReader
 }
 
+// Parse parses a document.  With the "always" reset mode, the default, every
+// document starts with the rows to skip, the metadata and the header.  With the
+// "none" reset mode these are only expected once and later calls continue
+// where the previous one stopped, as needed by inputs streaming the content.
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
-	r := bytes.NewReader(buf)
-	csvReader := p.compile(r)
+	if p.ResetMode == "always" {
+		p.Reset()
+	}
+
+	metrics := make([]telegraf.Metric, 0)
+	bufReader := bufio.NewReader(bytes.NewReader(buf))
+
 	// skip first rows
-	for i := 0; i < p.SkipRows; i++ {
-		_, err := csvReader.Read()
+	for p.remainingSkipRows > 0 {
+		line, err := readLine(bufReader)
 		if err != nil {
 			return nil, err
 		}
+		if line == nil {
+			return metrics, nil
+		}
+		p.remainingSkipRows--
 	}
+
+	for p.remainingMetadataRows > 0 {
+		line, err := readLine(bufReader)
+		if err != nil {
+			return nil, err
+		}
+		if line == nil {
+			return metrics, nil
+		}
+		p.parseMetadataRow(*line)
+	}
+
+	csvReader := p.compile(bufReader)
 	// if there is a header and we did not get DataColumns
 	// set DataColumns to names extracted from the header
-	// we always reread the header to avoid side effects
-	// in cases where multiple files with different
-	// headers are read
-	if !p.gotColumnNames {
-		headerNames := make([]string, 0)
-		for i := 0; i < p.HeaderRowCount; i++ {
-			header, err := csvReader.Read()
-			if err != nil {
-				return nil, err
-			}
-			//concatenate header names
-			for i := range header {
-				name := header[i]
-				if p.TrimSpace {
-					name = strings.Trim(name, " ")
-				}
-				if len(headerNames) <= i {
-					headerNames = append(headerNames, name)
-				} else {
-					headerNames[i] = headerNames[i] + name
-				}
-			}
+	for p.remainingHeaderRows > 0 {
+		header, err := csvReader.Read()
+		if err == io.EOF {
+			return metrics, nil
 		}
-		p.ColumnNames = headerNames[p.SkipColumns:]
-	} else {
-		// if columns are named, just skip header rows
-		for i := 0; i < p.HeaderRowCount; i++ {
-			_, err := csvReader.Read()
-			if err != nil {
-				return nil, err
-			}
+		if err != nil {
+			return nil, err
 		}
+		p.parseHeaderRow(header)
 	}
 
 	table, err := csvReader.ReadAll()
@@ -145,7 +192,6 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 		return nil, err
 	}
 
-	metrics := make([]telegraf.Metric, 0)
 	for _, record := range table {
 		m, err := p.parseRecord(record)
 		if err != nil {
@@ -156,11 +202,31 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	return metrics, nil
 }
 
-// ParseLine does not use any information in header and assumes DataColumns is set
-// it will also not skip any rows
+// ParseLine parses a single line.  Rows to skip, metadata rows and header rows
+// still expected by the parser are consumed and do not produce a metric.
 func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	if p.remainingSkipRows > 0 {
+		p.remainingSkipRows--
+		return nil, nil
+	}
+
+	if p.remainingMetadataRows > 0 {
+		p.parseMetadataRow(line)
+		return nil, nil
+	}
+
 	r := bytes.NewReader([]byte(line))
 	csvReader := p.compile(r)
+
+	if p.remainingHeaderRows > 0 {
+		header, err := csvReader.Read()
+		if err != nil {
+			return nil, err
+		}
+		p.parseHeaderRow(header)
+		return nil, nil
+	}
+
 	// if there is nothing in DataColumns, ParseLine will fail
 	if len(p.ColumnNames) == 0 {
 		return nil, fmt.Errorf("[parsers.csv] data columns must be specified")
@@ -177,9 +243,70 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	return m, nil
 }
 
+// readLine returns the next line of the reader or nil at the end of the input.
+func readLine(r *bufio.Reader) (*string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+	return &line, nil
+}
+
+// parseMetadataRow adds the key and value of a metadata row, split at the
+// first matching separator, to the metadata tags.
+func (p *Parser) parseMetadataRow(line string) {
+	p.remainingMetadataRows--
+
+	line = strings.TrimRight(line, "\r\n")
+	for _, separator := range p.metadataSeparators {
+		parts := strings.SplitN(line, separator, 2)
+		if len(parts) < 2 {
+			continue
+		}
+
+		key := strings.Trim(parts[0], p.MetadataTrimSet)
+		if key == "" {
+			continue
+		}
+		p.metadataTags[key] = strings.Trim(parts[1], p.MetadataTrimSet)
+		return
+	}
+}
+
+// parseHeaderRow concatenates the header names with the previous header rows,
+// unless the column names are configured.
+func (p *Parser) parseHeaderRow(header []string) {
+	p.remainingHeaderRows--
+	if p.gotColumnNames {
+		return
+	}
+
+	for i := range header {
+		name := header[i]
+		if p.TrimSpace {
+			name = strings.Trim(name, " ")
+		}
+		if len(p.headerNames) <= i {
+			p.headerNames = append(p.headerNames, name)
+		} else {
+			p.headerNames[i] = p.headerNames[i] + name
+		}
+	}
+
+	if p.remainingHeaderRows == 0 {
+		p.ColumnNames = p.headerNames[p.SkipColumns:]
+	}
+}
+
 func (p *Parser) parseRecord(record []string) (telegraf.Metric, error) {
 	recordFields := make(map[string]interface{})
 	tags := make(map[string]string)
+	for k, v := range p.metadataTags {
+		tags[k] = v
+	}
 
 	// skip columns in record
 	record = record[p.SkipColumns:]
@@ -664,3 +664,149 @@ func TestSkipSpecifiedStringValue(t *testing.T) {
 	}
 	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
 }
+
+func TestMetadataRows(t *testing.T) {
+	p, err := NewParser(
+		&Config{
+			MetricName:         "csv",
+			SkipRows:           1,
+			MetadataRows:       3,
+			MetadataSeparators: []string{":", "="},
+			MetadataTrimSet:    " #",
+			HeaderRowCount:     1,
+			TimeFunc:           DefaultTime,
+		},
+	)
+	require.NoError(t, err)
+	testCSV := `garbage nonsense
+# Version=1.1
+# File Created: 2021-11-17T07:02:45+10:00
+# no separator here
+a,b
+1,2`
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("csv",
+			map[string]string{
+				"Version":      "1.1",
+				"File Created": "2021-11-17T07:02:45+10:00",
+			},
+			map[string]interface{}{
+				"a": int64(1),
+				"b": int64(2),
+			},
+			DefaultTime(),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestMetadataRowsRequireSeparators(t *testing.T) {
+	_, err := NewParser(
+		&Config{
+			HeaderRowCount: 1,
+			MetadataRows:   1,
+		},
+	)
+	require.Error(t, err)
+}
+
+func TestResetModeNone(t *testing.T) {
+	p, err := NewParser(
+		&Config{
+			MetricName:     "csv",
+			HeaderRowCount: 2,
+			ResetMode:      "none",
+			TimeFunc:       DefaultTime,
+		},
+	)
+	require.NoError(t, err)
+
+	// The header is split over several chunks of the stream
+	metrics, err := p.Parse([]byte("a,b\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 0)
+
+	metrics, err = p.Parse([]byte("1,2\n3,4\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+
+	metrics, err = p.Parse([]byte("5,6\n"))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("csv",
+			map[string]string{},
+			map[string]interface{}{
+				"a1": int64(5),
+				"b2": int64(6),
+			},
+			DefaultTime(),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestResetModeAlways(t *testing.T) {
+	p, err := NewParser(
+		&Config{
+			MetricName:     "csv",
+			HeaderRowCount: 1,
+			TimeFunc:       DefaultTime,
+		},
+	)
+	require.NoError(t, err)
+
+	// Every document comes with its own header
+	for _, testCSV := range []string{"a,b\n1,2\n", "c,d\n1,2\n"} {
+		metrics, err := p.Parse([]byte(testCSV))
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		require.Len(t, metrics[0].FieldList(), 2)
+	}
+	require.Equal(t, []string{"c", "d"}, p.ColumnNames)
+}
+
+func TestInvalidResetMode(t *testing.T) {
+	_, err := NewParser(
+		&Config{
+			HeaderRowCount: 1,
+			ResetMode:      "sometimes",
+		},
+	)
+	require.Error(t, err)
+}
+
+func TestParseLineHeaderRows(t *testing.T) {
+	p, err := NewParser(
+		&Config{
+			MetricName:         "csv",
+			SkipRows:           1,
+			MetadataRows:       1,
+			MetadataSeparators: []string{"="},
+			HeaderRowCount:     2,
+			TimeFunc:           DefaultTime,
+		},
+	)
+	require.NoError(t, err)
+
+	for _, line := range []string{"garbage", "site=berlin", "a,b", "_x,_y"} {
+		m, err := p.ParseLine(line)
+		require.NoError(t, err)
+		require.Nil(t, m)
+	}
+
+	m, err := p.ParseLine("1,2")
+	require.NoError(t, err)
+	testutil.RequireMetricEqual(t,
+		testutil.MustMetric("csv",
+			map[string]string{"site": "berlin"},
+			map[string]interface{}{
+				"a_x": int64(1),
+				"b_y": int64(2),
+			},
+			DefaultTime(),
+		), m)
+}
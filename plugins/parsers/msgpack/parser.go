@@ -0,0 +1,72 @@
+package msgpack
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/serializers/msgpack"
+)
+
+// Parser decodes metrics in the MessagePack format written by the msgpack
+// serializer.
+type Parser struct {
+	DefaultTags map[string]string
+}
+
+// Parse decodes all metrics contained in the buffer.
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	metrics := make([]telegraf.Metric, 0)
+	for len(buf) > 0 {
+		var m msgpack.Metric
+		rest, err := m.UnmarshalMsg(buf)
+		if err != nil {
+			return nil, fmt.Errorf("decoding metric failed: %v", err)
+		}
+		buf = rest
+
+		metrics = append(metrics, p.createMetric(&m))
+	}
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: msgpack ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) createMetric(m *msgpack.Metric) telegraf.Metric {
+	tags := make(map[string]string, len(p.DefaultTags)+len(m.Tags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	for k, v := range m.Tags {
+		tags[k] = v
+	}
+
+	fields := make(map[string]interface{}, len(m.Fields))
+	for k, v := range m.Fields {
+		switch value := v.(type) {
+		case float32:
+			fields[k] = float64(value)
+		case []byte:
+			fields[k] = string(value)
+		default:
+			fields[k] = v
+		}
+	}
+
+	return metric.New(m.Name, tags, fields, m.Time.Time())
+}
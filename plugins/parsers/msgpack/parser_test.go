@@ -0,0 +1,67 @@
+package msgpack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/serializers/msgpack"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	metrics := []telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{
+				"usage_idle": 91.5,
+				"count":      int64(-3),
+				"total":      uint64(1 << 63),
+				"healthy":    true,
+				"state":      "ok",
+			},
+			time.Unix(1600000000, 123456789),
+		),
+		testutil.MustMetric(
+			"mem",
+			map[string]string{},
+			map[string]interface{}{
+				"free": int64(1024),
+			},
+			time.Unix(1600000000, 0),
+		),
+	}
+
+	buf, err := msgpack.NewSerializer().SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	p := &Parser{}
+	actual, err := p.Parse(buf)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, metrics, actual)
+}
+
+func TestParseDefaultTags(t *testing.T) {
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{"host": "server01"},
+		map[string]interface{}{"value": int64(1)},
+		time.Unix(0, 0),
+	)
+	buf, err := msgpack.NewSerializer().Serialize(m)
+	require.NoError(t, err)
+
+	p := &Parser{}
+	p.SetDefaultTags(map[string]string{"host": "default", "region": "eu"})
+	actual, err := p.ParseLine(string(buf))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"host": "server01", "region": "eu"}, actual.Tags())
+}
+
+func TestParseInvalid(t *testing.T) {
+	p := &Parser{}
+	_, err := p.Parse([]byte("cpu value=1"))
+	require.Error(t, err)
+}
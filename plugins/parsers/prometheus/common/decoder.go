@@ -0,0 +1,181 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// IsProtobuf returns true if the Content-Type header announces the delimited
+// protocol buffer format.
+func IsProtobuf(header http.Header) bool {
+	mediatype, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return mediatype == "application/vnd.google.protobuf" &&
+		params["encoding"] == "delimited" &&
+		params["proto"] == "io.prometheus.client.MetricFamily"
+}
+
+// FamilyDecoder decodes an exposition in the protocol buffer, text or
+// OpenMetrics format one metric family at a time, so the exposition is never
+// held in memory as a whole.
+//
+// The text formats are split before the HELP, TYPE or UNIT line of each
+// family, and before each sample of a new name outside of families announced
+// by these lines.
+type FamilyDecoder struct {
+	reader      *bufio.Reader
+	protobuf    bool
+	openMetrics bool
+
+	// family and name of the last sample of the current part
+	family string
+	sample string
+
+	// first line of the next part
+	pending    []byte
+	done       bool
+	terminated bool
+}
+
+// NewFamilyDecoder returns a decoder reading the exposition from r in the
+// format announced by the Content-Type header.
+func NewFamilyDecoder(r io.Reader, header http.Header) *FamilyDecoder {
+	return &FamilyDecoder{
+		reader:      bufio.NewReader(r),
+		protobuf:    IsProtobuf(header),
+		openMetrics: IsOpenMetrics(header),
+	}
+}
+
+// Decode returns the metric families of the next part of the exposition,
+// usually a single family.  It returns io.EOF once the exposition is
+// exhausted.
+func (d *FamilyDecoder) Decode() (map[string]*dto.MetricFamily, error) {
+	if d.protobuf {
+		mf := &dto.MetricFamily{}
+		if _, err := pbutil.ReadDelimited(d.reader, mf); err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("reading metric family protocol buffer failed: %s", err)
+		}
+		return map[string]*dto.MetricFamily{mf.GetName(): mf}, nil
+	}
+
+	part, err := d.nextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.openMetrics {
+		families, err := ParseOpenMetrics(append(part, "# EOF\n"...))
+		if err != nil {
+			return nil, fmt.Errorf("reading openmetrics format failed: %s", err)
+		}
+		return families, nil
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(part))
+	if err != nil {
+		return nil, fmt.Errorf("reading text format failed: %s", err)
+	}
+	return families, nil
+}
+
+// nextPart returns the lines of the next part of a text exposition.
+func (d *FamilyDecoder) nextPart() ([]byte, error) {
+	var part []byte
+	d.family, d.sample = "", ""
+	if d.pending != nil {
+		d.startsFamily(bytes.TrimSpace(d.pending))
+		part = append(part, d.pending...)
+		d.pending = nil
+	}
+
+	for !d.done {
+		line, err := d.reader.ReadBytes('\n')
+		if err == io.EOF {
+			d.done = true
+		} else if err != nil {
+			return nil, err
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		if d.openMetrics && string(trimmed) == "# EOF" {
+			d.done = true
+			d.terminated = true
+			break
+		}
+		if line[len(line)-1] != '\n' {
+			line = append(line, '\n')
+		}
+		if d.startsFamily(trimmed) && len(part) > 0 {
+			d.pending = line
+			return part, nil
+		}
+		part = append(part, line...)
+	}
+
+	if len(part) > 0 {
+		return part, nil
+	}
+	if d.openMetrics && !d.terminated {
+		return nil, errors.New("reading openmetrics format failed: data does not end with # EOF")
+	}
+	return nil, io.EOF
+}
+
+// startsFamily records the family of the line and returns true if the line
+// begins another family than the previous lines.
+func (d *FamilyDecoder) startsFamily(line []byte) bool {
+	if line[0] == '#' {
+		fields := bytes.Fields(line)
+		if len(fields) < 3 || !isMetadata(fields[1]) {
+			return false
+		}
+		name := string(fields[2])
+		if name == d.family {
+			return false
+		}
+		d.family, d.sample = name, ""
+		return true
+	}
+
+	// Samples belong to the family of the preceding metadata
+	if d.family != "" {
+		return false
+	}
+	name := string(line)
+	if i := bytes.IndexAny(line, "{ \t"); i >= 0 {
+		name = string(line[:i])
+	}
+	if name == d.sample {
+		return false
+	}
+	d.sample = name
+	return true
+}
+
+func isMetadata(keyword []byte) bool {
+	switch string(keyword) {
+	case "HELP", "TYPE", "UNIT":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeAll returns the names of the families of each decoded part.
+func decodeAll(t *testing.T, d *FamilyDecoder) [][]string {
+	var parts [][]string
+	for {
+		families, err := d.Decode()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+
+		var names []string
+		for name := range families {
+			names = append(names, name)
+		}
+		parts = append(parts, names)
+	}
+}
+
+func TestFamilyDecoderText(t *testing.T) {
+	exposition := `
+# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 15
+# HELP http_request_duration_seconds Duration of requests.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.5"} 10
+http_request_duration_seconds_bucket{le="+Inf"} 12
+http_request_duration_seconds_sum 4.2
+http_request_duration_seconds_count 12
+untyped_a{label="1"} 1
+untyped_a{label="2"} 2
+untyped_b 3
+`
+	d := NewFamilyDecoder(strings.NewReader(exposition), http.Header{})
+	require.Equal(t, [][]string{
+		{"go_goroutines"},
+		{"http_request_duration_seconds", "untyped_a", "untyped_b"},
+	}, sortParts(decodeAll(t, d)))
+}
+
+func TestFamilyDecoderUntyped(t *testing.T) {
+	exposition := "a{label=\"1\"} 1\na{label=\"2\"} 2\nb 3\n# TYPE c counter\nc 4"
+	d := NewFamilyDecoder(strings.NewReader(exposition), http.Header{})
+	require.Equal(t, [][]string{{"a"}, {"b"}, {"c"}}, decodeAll(t, d))
+}
+
+func TestFamilyDecoderOpenMetrics(t *testing.T) {
+	header := http.Header{"Content-Type": []string{"application/openmetrics-text; version=1.0.0"}}
+	exposition := `# TYPE requests counter
+# HELP requests Number of requests.
+requests_total 5
+requests_created 1.6e9
+# TYPE temperature_celsius gauge
+# UNIT temperature_celsius celsius
+temperature_celsius 21.5
+# EOF
+`
+	d := NewFamilyDecoder(strings.NewReader(exposition), header)
+	require.Equal(t, [][]string{
+		{"requests_created", "requests_total"},
+		{"temperature_celsius"},
+	}, sortParts(decodeAll(t, d)))
+
+	// The exposition must be terminated
+	d = NewFamilyDecoder(strings.NewReader("# TYPE up gauge\nup 1\n"), header)
+	families, err := d.Decode()
+	require.NoError(t, err)
+	require.Contains(t, families, "up")
+	_, err = d.Decode()
+	require.EqualError(t, err, "reading openmetrics format failed: data does not end with # EOF")
+}
+
+func TestFamilyDecoderProtobuf(t *testing.T) {
+	var buf bytes.Buffer
+	for _, name := range []string{"a", "b"} {
+		mf := &dto.MetricFamily{
+			Name: proto.String(name),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{
+				{Gauge: &dto.Gauge{Value: proto.Float64(1)}},
+			},
+		}
+		_, err := pbutil.WriteDelimited(&buf, mf)
+		require.NoError(t, err)
+	}
+
+	header := http.Header{"Content-Type": []string{"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited"}}
+	d := NewFamilyDecoder(&buf, header)
+	require.Equal(t, [][]string{{"a"}, {"b"}}, decodeAll(t, d))
+}
+
+func sortParts(parts [][]string) [][]string {
+	for _, names := range parts {
+		sort.Strings(names)
+	}
+	return parts
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/influxdata/telegraf"
 	dto "github.com/prometheus/client_model/go"
 )
@@ -34,3 +36,20 @@ func MakeLabels(m *dto.Metric, defaultTags map[string]string) map[string]string
 
 	return result
 }
+
+// Add value, timestamp and labels of the exemplar as fields named after the
+// field holding the sample
+func AddExemplarFields(fields map[string]interface{}, name string, e *dto.Exemplar) {
+	if e == nil {
+		return
+	}
+
+	fields[name+"_exemplar"] = e.GetValue()
+	if ts := e.GetTimestamp(); ts != nil {
+		t := time.Unix(ts.GetSeconds(), int64(ts.GetNanos()))
+		fields[name+"_exemplar_timestamp"] = float64(t.UnixNano()) / float64(time.Second)
+	}
+	for _, lp := range e.Label {
+		fields[name+"_exemplar_"+lp.GetName()] = lp.GetValue()
+	}
+}
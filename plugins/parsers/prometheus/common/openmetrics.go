@@ -0,0 +1,213 @@
+package common
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// IsOpenMetrics returns true if the Content-Type header announces the
+// OpenMetrics text format.
+func IsOpenMetrics(header http.Header) bool {
+	mediatype, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return mediatype == "application/openmetrics-text"
+}
+
+// ParseOpenMetrics reads an OpenMetrics exposition into metric families.
+//
+// Families are named like in the Prometheus text format, so counters keep
+// their "_total" suffix and "_created" samples become gauges of their own.
+// Exemplars are attached to the counters and histogram buckets they belong
+// to.  The exposition must be terminated by "# EOF".
+func ParseOpenMetrics(buf []byte) (map[string]*dto.MetricFamily, error) {
+	b := &openMetricsBuilder{
+		families: make(map[string]*dto.MetricFamily),
+		metrics:  make(map[string]*dto.Metric),
+	}
+
+	parser := textparse.NewOpenMetricsParser(buf)
+	for {
+		entry, err := parser.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			name, mtype := parser.Type()
+			b.family = string(name)
+			b.mtype = mtype
+		case textparse.EntrySeries:
+			_, ts, value := parser.Series()
+
+			var lset labels.Labels
+			parser.Metric(&lset)
+
+			var e exemplar.Exemplar
+			var ex *dto.Exemplar
+			if parser.Exemplar(&e) {
+				ex = makeExemplar(&e)
+			}
+
+			if err := b.add(lset, ts, value, ex); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b.families, nil
+}
+
+// familySuffixes are the suffixes samples may add to the name of their family
+var familySuffixes = map[string]bool{
+	"":         true,
+	"_total":   true,
+	"_created": true,
+	"_info":    true,
+	"_bucket":  true,
+	"_count":   true,
+	"_sum":     true,
+	"_gcount":  true,
+	"_gsum":    true,
+}
+
+type openMetricsBuilder struct {
+	families map[string]*dto.MetricFamily
+	metrics  map[string]*dto.Metric
+
+	// family and type of the last TYPE line
+	family string
+	mtype  textparse.MetricType
+}
+
+func (b *openMetricsBuilder) add(lset labels.Labels, ts *int64, value float64, ex *dto.Exemplar) error {
+	name := lset.Get(labels.MetricName)
+
+	mtype := textparse.MetricType(textparse.MetricTypeUnknown)
+	family := name
+	suffix := ""
+	if b.family != "" && strings.HasPrefix(name, b.family) && familySuffixes[name[len(b.family):]] {
+		mtype = b.mtype
+		family = b.family
+		suffix = name[len(b.family):]
+	}
+
+	if suffix == "_created" {
+		m := b.metric(name, dto.MetricType_GAUGE, lset.WithoutLabels(), ts)
+		m.Gauge = &dto.Gauge{Value: proto.Float64(value)}
+		return nil
+	}
+
+	switch mtype {
+	case textparse.MetricTypeCounter:
+		m := b.metric(name, dto.MetricType_COUNTER, lset.WithoutLabels(), ts)
+		m.Counter = &dto.Counter{Value: proto.Float64(value), Exemplar: ex}
+	case textparse.MetricTypeGauge, textparse.MetricTypeInfo, textparse.MetricTypeStateset:
+		m := b.metric(name, dto.MetricType_GAUGE, lset.WithoutLabels(), ts)
+		m.Gauge = &dto.Gauge{Value: proto.Float64(value)}
+	case textparse.MetricTypeHistogram, textparse.MetricTypeGaugeHistogram:
+		m := b.metric(family, dto.MetricType_HISTOGRAM, lset.WithoutLabels(labels.BucketLabel), ts)
+		if m.Histogram == nil {
+			m.Histogram = &dto.Histogram{}
+		}
+		switch suffix {
+		case "_bucket":
+			bound, err := strconv.ParseFloat(lset.Get(labels.BucketLabel), 64)
+			if err != nil {
+				return err
+			}
+			m.Histogram.Bucket = append(m.Histogram.Bucket, &dto.Bucket{
+				UpperBound:      proto.Float64(bound),
+				CumulativeCount: proto.Uint64(uint64(value)),
+				Exemplar:        ex,
+			})
+		case "_count", "_gcount":
+			m.Histogram.SampleCount = proto.Uint64(uint64(value))
+		case "_sum", "_gsum":
+			m.Histogram.SampleSum = proto.Float64(value)
+		}
+	case textparse.MetricTypeSummary:
+		m := b.metric(family, dto.MetricType_SUMMARY, lset.WithoutLabels("quantile"), ts)
+		if m.Summary == nil {
+			m.Summary = &dto.Summary{}
+		}
+		switch suffix {
+		case "":
+			quantile, err := strconv.ParseFloat(lset.Get("quantile"), 64)
+			if err != nil {
+				return err
+			}
+			m.Summary.Quantile = append(m.Summary.Quantile, &dto.Quantile{
+				Quantile: proto.Float64(quantile),
+				Value:    proto.Float64(value),
+			})
+		case "_count":
+			m.Summary.SampleCount = proto.Uint64(uint64(value))
+		case "_sum":
+			m.Summary.SampleSum = proto.Float64(value)
+		}
+	default:
+		m := b.metric(name, dto.MetricType_UNTYPED, lset.WithoutLabels(), ts)
+		m.Untyped = &dto.Untyped{Value: proto.Float64(value)}
+	}
+	return nil
+}
+
+// metric returns the metric with the given labels in the named family,
+// creating both if necessary.
+func (b *openMetricsBuilder) metric(name string, mtype dto.MetricType, lset labels.Labels, ts *int64) *dto.Metric {
+	mf, ok := b.families[name]
+	if !ok {
+		mf = &dto.MetricFamily{Name: proto.String(name), Type: mtype.Enum()}
+		b.families[name] = mf
+	}
+
+	key := name + lset.String()
+	if m, ok := b.metrics[key]; ok {
+		return m
+	}
+
+	m := &dto.Metric{TimestampMs: ts}
+	for _, l := range lset {
+		m.Label = append(m.Label, &dto.LabelPair{
+			Name:  proto.String(l.Name),
+			Value: proto.String(l.Value),
+		})
+	}
+	mf.Metric = append(mf.Metric, m)
+	b.metrics[key] = m
+	return m
+}
+
+func makeExemplar(e *exemplar.Exemplar) *dto.Exemplar {
+	ex := &dto.Exemplar{Value: proto.Float64(e.Value)}
+	for _, l := range e.Labels {
+		ex.Label = append(ex.Label, &dto.LabelPair{
+			Name:  proto.String(l.Name),
+			Value: proto.String(l.Value),
+		})
+	}
+	if e.HasTs {
+		ex.Timestamp = &timestamp.Timestamp{
+			Seconds: e.Ts / 1000,
+			Nanos:   int32(e.Ts%1000) * 1000000,
+		}
+	}
+	return ex
+}
@@ -1,23 +1,18 @@
 package prometheus
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"math"
-	"mime"
 	"net/http"
 	"time"
 
-	"github.com/matttproud/golang_protobuf_extensions/pbutil"
-
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers/prometheus/common"
 
 	dto "github.com/prometheus/client_model/go"
-	"github.com/prometheus/common/expfmt"
 )
 
 type Parser struct {
@@ -26,41 +21,25 @@ type Parser struct {
 }
 
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
-	var parser expfmt.TextParser
 	var metrics []telegraf.Metric
-	var err error
-	// parse even if the buffer begins with a newline
-	buf = bytes.TrimPrefix(buf, []byte("\n"))
-	// Read raw data
-	buffer := bytes.NewBuffer(buf)
-	reader := bufio.NewReader(buffer)
-
-	// Prepare output
-	metricFamilies := make(map[string]*dto.MetricFamily)
-	mediatype, params, err := mime.ParseMediaType(p.Header.Get("Content-Type"))
-	if err == nil && mediatype == "application/vnd.google.protobuf" &&
-		params["encoding"] == "delimited" &&
-		params["proto"] == "io.prometheus.client.MetricFamily" {
-		for {
-			mf := &dto.MetricFamily{}
-			if _, ierr := pbutil.ReadDelimited(reader, mf); ierr != nil {
-				if ierr == io.EOF {
-					break
-				}
-				return nil, fmt.Errorf("reading metric family protocol buffer failed: %s", ierr)
-			}
-			metricFamilies[mf.GetName()] = mf
+	now := time.Now()
+	decoder := common.NewFamilyDecoder(bytes.NewReader(buf), p.Header)
+	for {
+		metricFamilies, err := decoder.Decode()
+		if err == io.EOF {
+			return metrics, nil
 		}
-	} else {
-		metricFamilies, err = parser.TextToMetricFamilies(reader)
 		if err != nil {
-			return nil, fmt.Errorf("reading text format failed: %s", err)
+			return nil, err
 		}
+		metrics = append(metrics, p.ParseFamilies(metricFamilies, now)...)
 	}
+}
 
-	now := time.Now()
-
-	// read metrics
+// ParseFamilies converts the metric families into metrics, using now as the
+// time of samples without a timestamp.
+func (p *Parser) ParseFamilies(metricFamilies map[string]*dto.MetricFamily, now time.Time) []telegraf.Metric {
+	var metrics []telegraf.Metric
 	for metricName, mf := range metricFamilies {
 		for _, m := range mf.Metric {
 			// reading tags
@@ -87,8 +66,7 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 			}
 		}
 	}
-
-	return metrics, err
+	return metrics
 }
 
 func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
@@ -153,6 +131,7 @@ func makeBuckets(m *dto.Metric, tags map[string]string, metricName string, metri
 		fields = make(map[string]interface{})
 		newTags["le"] = fmt.Sprint(b.GetUpperBound())
 		fields[metricName+"_bucket"] = float64(b.GetCumulativeCount())
+		common.AddExemplarFields(fields, metricName+"_bucket", b.GetExemplar())
 
 		histogramMetric := metric.New("prometheus", newTags, fields, t, common.ValueType(metricType))
 		metrics = append(metrics, histogramMetric)
@@ -170,6 +149,7 @@ func getNameAndValue(m *dto.Metric, metricName string) map[string]interface{} {
 	} else if m.Counter != nil {
 		if !math.IsNaN(m.GetCounter().GetValue()) {
 			fields[metricName] = float64(m.GetCounter().GetValue())
+			common.AddExemplarFields(fields, metricName, m.GetCounter().GetExemplar())
 		}
 	} else if m.Untyped != nil {
 		if !math.IsNaN(m.GetUntyped().GetValue()) {
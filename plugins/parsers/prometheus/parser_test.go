@@ -446,3 +446,97 @@ func TestParserProtobufHeader(t *testing.T) {
 	}
 	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime(), testutil.SortMetrics())
 }
+
+const validOpenMetrics = `# TYPE http_requests counter
+# HELP http_requests Number of requests.
+http_requests_total{code="200"} 1027 # {trace_id="abc123"} 1 1600000000.5
+http_requests_created{code="200"} 1600000000
+# TYPE rpc_duration_seconds histogram
+rpc_duration_seconds_bucket{le="0.5"} 3 # {trace_id="def456"} 0.2
+rpc_duration_seconds_bucket{le="+Inf"} 5
+rpc_duration_seconds_count 5
+rpc_duration_seconds_sum 4.5
+# TYPE build info
+build_info{version="1.2.3"} 1
+# EOF
+`
+
+func TestParserOpenMetrics(t *testing.T) {
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"code": "200"},
+			map[string]interface{}{
+				"http_requests_total":                    float64(1027),
+				"http_requests_total_exemplar":           float64(1),
+				"http_requests_total_exemplar_timestamp": float64(1600000000.5),
+				"http_requests_total_exemplar_trace_id":  "abc123",
+			},
+			time.Unix(0, 0),
+			telegraf.Counter,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"code": "200"},
+			map[string]interface{}{
+				"http_requests_created": float64(1600000000),
+			},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{
+				"rpc_duration_seconds_count": float64(5),
+				"rpc_duration_seconds_sum":   float64(4.5),
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"le": "0.5"},
+			map[string]interface{}{
+				"rpc_duration_seconds_bucket":                   float64(3),
+				"rpc_duration_seconds_bucket_exemplar":          float64(0.2),
+				"rpc_duration_seconds_bucket_exemplar_trace_id": "def456",
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"le": "+Inf"},
+			map[string]interface{}{
+				"rpc_duration_seconds_bucket": float64(5),
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"version": "1.2.3"},
+			map[string]interface{}{
+				"build_info": float64(1),
+			},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	parser := Parser{Header: header}
+	metrics, err := parser.Parse([]byte(validOpenMetrics))
+	assert.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestParserOpenMetricsMissingEOF(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/openmetrics-text; version=1.0.0")
+	parser := Parser{Header: header}
+	_, err := parser.Parse([]byte("# TYPE up gauge\nup 1\n"))
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/parsers/avro"
+	"github.com/influxdata/telegraf/plugins/parsers/binary"
 	"github.com/influxdata/telegraf/plugins/parsers/collectd"
 	"github.com/influxdata/telegraf/plugins/parsers/csv"
 	"github.com/influxdata/telegraf/plugins/parsers/dropwizard"
@@ -14,6 +16,7 @@ import (
 	"github.com/influxdata/telegraf/plugins/parsers/json"
 	"github.com/influxdata/telegraf/plugins/parsers/json_v2"
 	"github.com/influxdata/telegraf/plugins/parsers/logfmt"
+	"github.com/influxdata/telegraf/plugins/parsers/msgpack"
 	"github.com/influxdata/telegraf/plugins/parsers/nagios"
 	"github.com/influxdata/telegraf/plugins/parsers/prometheus"
 	"github.com/influxdata/telegraf/plugins/parsers/prometheusremotewrite"
@@ -138,20 +141,24 @@ type Config struct {
 	GrokUniqueTimestamp    string   `toml:"grok_unique_timestamp"`
 
 	//csv configuration
-	CSVColumnNames       []string `toml:"csv_column_names"`
-	CSVColumnTypes       []string `toml:"csv_column_types"`
-	CSVComment           string   `toml:"csv_comment"`
-	CSVDelimiter         string   `toml:"csv_delimiter"`
-	CSVHeaderRowCount    int      `toml:"csv_header_row_count"`
-	CSVMeasurementColumn string   `toml:"csv_measurement_column"`
-	CSVSkipColumns       int      `toml:"csv_skip_columns"`
-	CSVSkipRows          int      `toml:"csv_skip_rows"`
-	CSVTagColumns        []string `toml:"csv_tag_columns"`
-	CSVTimestampColumn   string   `toml:"csv_timestamp_column"`
-	CSVTimestampFormat   string   `toml:"csv_timestamp_format"`
-	CSVTimezone          string   `toml:"csv_timezone"`
-	CSVTrimSpace         bool     `toml:"csv_trim_space"`
-	CSVSkipValues        []string `toml:"csv_skip_values"`
+	CSVColumnNames        []string `toml:"csv_column_names"`
+	CSVColumnTypes        []string `toml:"csv_column_types"`
+	CSVComment            string   `toml:"csv_comment"`
+	CSVDelimiter          string   `toml:"csv_delimiter"`
+	CSVHeaderRowCount     int      `toml:"csv_header_row_count"`
+	CSVMeasurementColumn  string   `toml:"csv_measurement_column"`
+	CSVSkipColumns        int      `toml:"csv_skip_columns"`
+	CSVSkipRows           int      `toml:"csv_skip_rows"`
+	CSVTagColumns         []string `toml:"csv_tag_columns"`
+	CSVTimestampColumn    string   `toml:"csv_timestamp_column"`
+	CSVTimestampFormat    string   `toml:"csv_timestamp_format"`
+	CSVTimezone           string   `toml:"csv_timezone"`
+	CSVTrimSpace          bool     `toml:"csv_trim_space"`
+	CSVSkipValues         []string `toml:"csv_skip_values"`
+	CSVMetadataRows       int      `toml:"csv_metadata_rows"`
+	CSVMetadataSeparators []string `toml:"csv_metadata_separators"`
+	CSVMetadataTrimSet    string   `toml:"csv_metadata_trim_set"`
+	CSVResetMode          string   `toml:"csv_reset_mode"`
 
 	// FormData configuration
 	FormUrlencodedTagKeys []string `toml:"form_urlencoded_tag_keys"`
@@ -167,10 +174,26 @@ type Config struct {
 
 	// JSONPath configuration
 	JSONV2Config []JSONV2Config `toml:"json_v2"`
+
+	// Avro configuration
+	AvroSchemaRegistry   string   `toml:"avro_schema_registry"`
+	AvroSchema           string   `toml:"avro_schema"`
+	AvroMeasurementField string   `toml:"avro_measurement_field"`
+	AvroTags             []string `toml:"avro_tags"`
+	AvroFields           []string `toml:"avro_fields"`
+	AvroTimestamp        string   `toml:"avro_timestamp"`
+	AvroTimestampFormat  string   `toml:"avro_timestamp_format"`
+	AvroFieldSeparator   string   `toml:"avro_field_separator"`
+
+	// Binary configuration
+	BinaryEndianness string `toml:"binary_endianness"`
+	BinaryConfig     []BinaryConfig
 }
 
 type XPathConfig xpath.Config
 
+type BinaryConfig binary.Config
+
 type JSONV2Config struct {
 	json_v2.Config
 }
@@ -231,22 +254,26 @@ func NewParser(config *Config) (Parser, error) {
 			config.GrokUniqueTimestamp)
 	case "csv":
 		config := &csv.Config{
-			MetricName:        config.MetricName,
-			HeaderRowCount:    config.CSVHeaderRowCount,
-			SkipRows:          config.CSVSkipRows,
-			SkipColumns:       config.CSVSkipColumns,
-			Delimiter:         config.CSVDelimiter,
-			Comment:           config.CSVComment,
-			TrimSpace:         config.CSVTrimSpace,
-			ColumnNames:       config.CSVColumnNames,
-			ColumnTypes:       config.CSVColumnTypes,
-			TagColumns:        config.CSVTagColumns,
-			MeasurementColumn: config.CSVMeasurementColumn,
-			TimestampColumn:   config.CSVTimestampColumn,
-			TimestampFormat:   config.CSVTimestampFormat,
-			Timezone:          config.CSVTimezone,
-			DefaultTags:       config.DefaultTags,
-			SkipValues:        config.CSVSkipValues,
+			MetricName:         config.MetricName,
+			HeaderRowCount:     config.CSVHeaderRowCount,
+			SkipRows:           config.CSVSkipRows,
+			SkipColumns:        config.CSVSkipColumns,
+			Delimiter:          config.CSVDelimiter,
+			Comment:            config.CSVComment,
+			TrimSpace:          config.CSVTrimSpace,
+			ColumnNames:        config.CSVColumnNames,
+			ColumnTypes:        config.CSVColumnTypes,
+			TagColumns:         config.CSVTagColumns,
+			MeasurementColumn:  config.CSVMeasurementColumn,
+			TimestampColumn:    config.CSVTimestampColumn,
+			TimestampFormat:    config.CSVTimestampFormat,
+			Timezone:           config.CSVTimezone,
+			DefaultTags:        config.DefaultTags,
+			SkipValues:         config.CSVSkipValues,
+			MetadataRows:       config.CSVMetadataRows,
+			MetadataSeparators: config.CSVMetadataSeparators,
+			MetadataTrimSet:    config.CSVMetadataTrimSet,
+			ResetMode:          config.CSVResetMode,
 		}
 
 		return csv.NewParser(config)
@@ -258,6 +285,8 @@ func NewParser(config *Config) (Parser, error) {
 			config.DefaultTags,
 			config.FormUrlencodedTagKeys,
 		)
+	case "msgpack":
+		parser, err = NewMessagePackParser(config.DefaultTags)
 	case "prometheus":
 		parser, err = NewPrometheusParser(config.DefaultTags)
 	case "prometheusremotewrite":
@@ -273,6 +302,30 @@ func NewParser(config *Config) (Parser, error) {
 		}
 	case "json_v2":
 		parser, err = NewJSONPathParser(config.JSONV2Config)
+	case "binary":
+		configs := make([]binary.Config, 0, len(config.BinaryConfig))
+		for _, cfg := range config.BinaryConfig {
+			configs = append(configs, binary.Config(cfg))
+		}
+		parser = &binary.Parser{
+			MetricName:  config.MetricName,
+			Endianness:  config.BinaryEndianness,
+			Configs:     configs,
+			DefaultTags: config.DefaultTags,
+		}
+	case "avro":
+		parser = &avro.Parser{
+			MetricName:       config.MetricName,
+			SchemaRegistry:   config.AvroSchemaRegistry,
+			Schema:           config.AvroSchema,
+			MeasurementField: config.AvroMeasurementField,
+			Tags:             config.AvroTags,
+			Fields:           config.AvroFields,
+			Timestamp:        config.AvroTimestamp,
+			TimestampFormat:  config.AvroTimestampFormat,
+			FieldSeparator:   config.AvroFieldSeparator,
+			DefaultTags:      config.DefaultTags,
+		}
 	default:
 		err = fmt.Errorf("Invalid data format: %s", config.DataFormat)
 	}
@@ -378,6 +431,12 @@ func NewFormUrlencodedParser(
 	}, nil
 }
 
+func NewMessagePackParser(defaultTags map[string]string) (Parser, error) {
+	return &msgpack.Parser{
+		DefaultTags: defaultTags,
+	}, nil
+}
+
 func NewPrometheusParser(defaultTags map[string]string) (Parser, error) {
 	return &prometheus.Parser{
 		DefaultTags: defaultTags,
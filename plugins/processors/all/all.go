@@ -1,30 +1,12 @@
+// Package all registers the processor plugins.  Each plugin is imported by
+// its own generated file, so a binary with a subset of plugins can be built
+// using the "custom" build tag together with "processors" to include all processors
+// or "processors.<name>" to include single ones, e.g.
+//
+//	go build -tags custom,processors.rename ./cmd/telegraf
+//
+// The name of a plugin is its directory below plugins/processors, with slashes
+// replaced by underscores.  Run "go generate" after adding a plugin.
 package all
 
-import (
-	//Blank imports for plugins to register themselves
-	_ "github.com/influxdata/telegraf/plugins/processors/aws/ec2"
-	_ "github.com/influxdata/telegraf/plugins/processors/clone"
-	_ "github.com/influxdata/telegraf/plugins/processors/converter"
-	_ "github.com/influxdata/telegraf/plugins/processors/date"
-	_ "github.com/influxdata/telegraf/plugins/processors/dedup"
-	_ "github.com/influxdata/telegraf/plugins/processors/defaults"
-	_ "github.com/influxdata/telegraf/plugins/processors/enum"
-	_ "github.com/influxdata/telegraf/plugins/processors/execd"
-	_ "github.com/influxdata/telegraf/plugins/processors/filepath"
-	_ "github.com/influxdata/telegraf/plugins/processors/ifname"
-	_ "github.com/influxdata/telegraf/plugins/processors/override"
-	_ "github.com/influxdata/telegraf/plugins/processors/parser"
-	_ "github.com/influxdata/telegraf/plugins/processors/pivot"
-	_ "github.com/influxdata/telegraf/plugins/processors/port_name"
-	_ "github.com/influxdata/telegraf/plugins/processors/printer"
-	_ "github.com/influxdata/telegraf/plugins/processors/regex"
-	_ "github.com/influxdata/telegraf/plugins/processors/rename"
-	_ "github.com/influxdata/telegraf/plugins/processors/reverse_dns"
-	_ "github.com/influxdata/telegraf/plugins/processors/s2geo"
-	_ "github.com/influxdata/telegraf/plugins/processors/starlark"
-	_ "github.com/influxdata/telegraf/plugins/processors/strings"
-	_ "github.com/influxdata/telegraf/plugins/processors/tag_limit"
-	_ "github.com/influxdata/telegraf/plugins/processors/template"
-	_ "github.com/influxdata/telegraf/plugins/processors/topk"
-	_ "github.com/influxdata/telegraf/plugins/processors/unpivot"
-)
+//go:generate go run ../../../scripts/generate_plugins.go processors
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.aws_ec2
+// +build !custom processors processors.aws_ec2
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/aws/ec2" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.clone
+// +build !custom processors processors.clone
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/clone" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.dedup
+// +build !custom processors processors.dedup
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/dedup" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.filepath
+// +build !custom processors processors.filepath
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/filepath" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.override
+// +build !custom processors processors.override
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/override" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.port_name
+// +build !custom processors processors.port_name
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/port_name" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.printer
+// +build !custom processors processors.printer
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/printer" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.rename
+// +build !custom processors processors.rename
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/rename" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.reverse_dns
+// +build !custom processors processors.reverse_dns
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/reverse_dns" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.s2geo
+// +build !custom processors processors.s2geo
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/s2geo" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.starlark
+// +build !custom processors processors.starlark
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/starlark" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.strings
+// +build !custom processors processors.strings
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/strings" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.tag_limit
+// +build !custom processors processors.tag_limit
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/tag_limit" // register plugin
@@ -0,0 +1,8 @@
+// Code generated by scripts/generate_plugins.go; DO NOT EDIT.
+
+//go:build !custom || processors || processors.unpivot
+// +build !custom processors processors.unpivot
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/unpivot" // register plugin
@@ -175,6 +175,12 @@ func (r *AwsEc2Processor) Init() error {
 	return nil
 }
 
+// PreserveOrder makes the processor emit the metrics in the order they were
+// added, as if ordered was set.
+func (r *AwsEc2Processor) PreserveOrder() {
+	r.Ordered = true
+}
+
 func (r *AwsEc2Processor) Start(acc telegraf.Accumulator) error {
 	if r.Ordered {
 		r.parallel = parallel.NewOrdered(acc, r.asyncAdd, DefaultMaxOrderedQueueSize, r.MaxParallelCalls)
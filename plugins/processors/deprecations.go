@@ -0,0 +1,6 @@
+package processors
+
+import "github.com/influxdata/telegraf"
+
+// Deprecations lists the deprecated processors.
+var Deprecations = map[string]telegraf.DeprecationInfo{}
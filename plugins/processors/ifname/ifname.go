@@ -191,6 +191,12 @@ func (d *IfName) invalidate(agent string) {
 	d.lock.Unlock()
 }
 
+// PreserveOrder makes the processor emit the metrics in the order they were
+// added, as if ordered was set.
+func (d *IfName) PreserveOrder() {
+	d.Ordered = true
+}
+
 func (d *IfName) Start(acc telegraf.Accumulator) error {
 	d.acc = acc
 
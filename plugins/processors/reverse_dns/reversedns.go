@@ -83,6 +83,12 @@ func (r *ReverseDNS) Description() string {
 	return "ReverseDNS does a reverse lookup on IP addresses to retrieve the DNS name"
 }
 
+// PreserveOrder makes the processor emit the metrics in the order they were
+// added, as if ordered was set.
+func (r *ReverseDNS) PreserveOrder() {
+	r.Ordered = true
+}
+
 func (r *ReverseDNS) Start(acc telegraf.Accumulator) error {
 	r.acc = acc
 	r.reverseDNSCache = NewReverseDNSCache(
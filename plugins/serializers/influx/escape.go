@@ -5,55 +5,49 @@ import "strings"
 const (
 	escapes            = "\t\n\f\r ,="
 	nameEscapes        = "\t\n\f\r ,"
-	stringFieldEscapes = "\t\n\f\r\\\""
+	stringFieldEscapes = "\\\""
 )
 
-var (
-	escaper = strings.NewReplacer(
-		"\t", `\t`,
-		"\n", `\n`,
-		"\f", `\f`,
-		"\r", `\r`,
-		`,`, `\,`,
-		` `, `\ `,
-		`=`, `\=`,
-	)
-
-	nameEscaper = strings.NewReplacer(
-		"\t", `\t`,
-		"\n", `\n`,
-		"\f", `\f`,
-		"\r", `\r`,
-		`,`, `\,`,
-		` `, `\ `,
-	)
+// appendEscape appends a tagkey, tagvalue, or fieldkey
+func appendEscape(buf []byte, s string) []byte {
+	return appendEscaped(buf, s, escapes)
+}
 
-	stringFieldEscaper = strings.NewReplacer(
-		`"`, `\"`,
-		`\`, `\\`,
-	)
-)
+// appendNameEscape appends a measurement name
+func appendNameEscape(buf []byte, s string) []byte {
+	return appendEscaped(buf, s, nameEscapes)
+}
 
-// Escape a tagkey, tagvalue, or fieldkey
-func escape(s string) string {
-	if strings.ContainsAny(s, escapes) {
-		return escaper.Replace(s)
-	}
-	return s
+// appendStringFieldEscape appends a string field
+func appendStringFieldEscape(buf []byte, s string) []byte {
+	return appendEscaped(buf, s, stringFieldEscapes)
 }
 
-// Escape a measurement name
-func nameEscape(s string) string {
-	if strings.ContainsAny(s, nameEscapes) {
-		return nameEscaper.Replace(s)
+// appendEscaped appends the string to the buffer, prefixing the characters
+// in chars with a backslash.  Whitespace is written as escape sequence.
+func appendEscaped(buf []byte, s string, chars string) []byte {
+	if !strings.ContainsAny(s, chars) {
+		return append(buf, s...)
 	}
-	return s
-}
 
-// Escape a string field
-func stringFieldEscape(s string) string {
-	if strings.ContainsAny(s, stringFieldEscapes) {
-		return stringFieldEscaper.Replace(s)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if strings.IndexByte(chars, c) < 0 {
+			buf = append(buf, c)
+			continue
+		}
+		switch c {
+		case '\t':
+			buf = append(buf, `\t`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\f':
+			buf = append(buf, `\f`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		default:
+			buf = append(buf, '\\', c)
+		}
 	}
-	return s
+	return buf
 }
@@ -28,6 +28,10 @@ const (
 	UintSupport FieldTypeSupport = 1 << iota
 )
 
+// headerCacheSize is the maximum number of series whose header is kept for
+// reuse.
+const headerCacheSize = 1000
+
 var (
 	NeedMoreSpace = "need more space"
 	InvalidName   = "invalid name"
@@ -67,6 +71,27 @@ type Serializer struct {
 	header []byte
 	footer []byte
 	pair   []byte
+
+	headerCache map[uint64]*cachedHeader
+}
+
+// cachedHeader is the serialized name and tags of a series.
+type cachedHeader struct {
+	name   string
+	tags   []telegraf.Tag
+	header []byte
+}
+
+func (c *cachedHeader) matches(m telegraf.Metric) bool {
+	if c.name != m.Name() || len(c.tags) != len(m.TagList()) {
+		return false
+	}
+	for i, tag := range m.TagList() {
+		if c.tags[i] != *tag {
+			return false
+		}
+	}
+	return true
 }
 
 func NewSerializer() *Serializer {
@@ -76,6 +101,8 @@ func NewSerializer() *Serializer {
 		header: make([]byte, 0, 50),
 		footer: make([]byte, 0, 21),
 		pair:   make([]byte, 0, 50),
+
+		headerCache: make(map[uint64]*cachedHeader),
 	}
 	return serializer
 }
@@ -142,27 +169,25 @@ func (s *Serializer) write(w io.Writer, b []byte) error {
 }
 
 func (s *Serializer) buildHeader(m telegraf.Metric) error {
+	id := m.HashID()
+	if c, ok := s.headerCache[id]; ok && c.matches(m) {
+		s.header = append(s.header[:0], c.header...)
+		return nil
+	}
+
 	s.header = s.header[:0]
 
-	name := nameEscape(m.Name())
-	if name == "" {
+	if m.Name() == "" {
 		return s.newMetricError(InvalidName)
 	}
 
-	s.header = append(s.header, name...)
+	s.header = appendNameEscape(s.header, m.Name())
 
 	for _, tag := range m.TagList() {
-		key := escape(tag.Key)
-		value := escape(tag.Value)
-
 		// Tag keys and values that end with a backslash cannot be encoded by
 		// line protocol.
-		if strings.HasSuffix(key, `\`) {
-			key = strings.TrimRight(key, `\`)
-		}
-		if strings.HasSuffix(value, `\`) {
-			value = strings.TrimRight(value, `\`)
-		}
+		key := strings.TrimRight(tag.Key, `\`)
+		value := strings.TrimRight(tag.Value, `\`)
 
 		// Tag keys and values must not be the empty string.
 		if key == "" || value == "" {
@@ -170,15 +195,35 @@ func (s *Serializer) buildHeader(m telegraf.Metric) error {
 		}
 
 		s.header = append(s.header, ',')
-		s.header = append(s.header, key...)
+		s.header = appendEscape(s.header, key)
 		s.header = append(s.header, '=')
-		s.header = append(s.header, value...)
+		s.header = appendEscape(s.header, value)
 	}
 
 	s.header = append(s.header, ' ')
+
+	s.cacheHeader(id, m)
 	return nil
 }
 
+// cacheHeader keeps the current header for reuse by metrics of the same
+// series.  The cache is cleared when full to bound its memory usage.
+func (s *Serializer) cacheHeader(id uint64, m telegraf.Metric) {
+	if s.headerCache == nil || len(s.headerCache) >= headerCacheSize {
+		s.headerCache = make(map[uint64]*cachedHeader)
+	}
+
+	c := &cachedHeader{
+		name:   m.Name(),
+		tags:   make([]telegraf.Tag, 0, len(m.TagList())),
+		header: append([]byte(nil), s.header...),
+	}
+	for _, tag := range m.TagList() {
+		c.tags = append(c.tags, *tag)
+	}
+	s.headerCache[id] = c
+}
+
 func (s *Serializer) buildFooter(m telegraf.Metric) {
 	s.footer = s.footer[:0]
 	s.footer = append(s.footer, ' ')
@@ -188,7 +233,6 @@ func (s *Serializer) buildFooter(m telegraf.Metric) {
 
 func (s *Serializer) buildFieldPair(key string, value interface{}) error {
 	s.pair = s.pair[:0]
-	key = escape(key)
 
 	// Some keys are not encodeable as line protocol, such as those with a
 	// trailing '\' or empty strings.
@@ -196,7 +240,7 @@ func (s *Serializer) buildFieldPair(key string, value interface{}) error {
 		return &FieldError{"invalid field key"}
 	}
 
-	s.pair = append(s.pair, key...)
+	s.pair = appendEscape(s.pair, key)
 	s.pair = append(s.pair, '=')
 	pair, err := s.appendFieldValue(s.pair, value)
 	if err != nil {
@@ -217,9 +261,7 @@ func (s *Serializer) writeMetric(w io.Writer, m telegraf.Metric) error {
 	s.buildFooter(m)
 
 	if s.fieldSortOrder == SortFields {
-		sort.Slice(m.FieldList(), func(i, j int) bool {
-			return m.FieldList()[i].Key < m.FieldList()[j].Key
-		})
+		sort.Sort(fieldsByKey(m.FieldList()))
 	}
 
 	pairsLen := 0
@@ -289,6 +331,12 @@ func (s *Serializer) writeMetric(w io.Writer, m telegraf.Metric) error {
 	return s.write(w, s.footer)
 }
 
+type fieldsByKey []*telegraf.Field
+
+func (f fieldsByKey) Len() int           { return len(f) }
+func (f fieldsByKey) Less(i, j int) bool { return f[i].Key < f[j].Key }
+func (f fieldsByKey) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+
 func (s *Serializer) newMetricError(reason string) *MetricError {
 	if len(s.header) != 0 {
 		series := bytes.TrimRight(s.header, " ")
@@ -346,7 +394,7 @@ func appendBoolField(buf []byte, value bool) []byte {
 
 func appendStringField(buf []byte, value string) []byte {
 	buf = append(buf, '"')
-	buf = append(buf, stringFieldEscape(value)...)
+	buf = appendStringFieldEscape(buf, value)
 	buf = append(buf, '"')
 	return buf
 }
@@ -2,6 +2,7 @@ package influx
 
 import (
 	"math"
+	"strconv"
 	"testing"
 	"time"
 
@@ -515,3 +516,86 @@ func TestSerialize_SerializeBatch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte("cpu value=42 0\ncpu value=42 0\n"), output)
 }
+
+func TestSerialize_HeaderCache(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{
+			"host": "localhost",
+		},
+		map[string]interface{}{
+			"value": 42.0,
+		},
+		time.Unix(0, 0),
+	)
+
+	serializer := NewSerializer()
+	output, err := serializer.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, "cpu,host=localhost value=42 0\n", string(output))
+
+	// serializing the same series again uses the cached header
+	output, err = serializer.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, "cpu,host=localhost value=42 0\n", string(output))
+
+	// modifying the metric must not use the stale header
+	m.AddTag("host", "remote host")
+	output, err = serializer.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, "cpu,host=remote\\ host value=42 0\n", string(output))
+
+	m.SetName("mem")
+	output, err = serializer.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, "mem,host=remote\\ host value=42 0\n", string(output))
+}
+
+func TestSerialize_HeaderCacheLimit(t *testing.T) {
+	serializer := NewSerializer()
+	for i := 0; i < headerCacheSize+10; i++ {
+		m := metric.New(
+			"cpu",
+			map[string]string{
+				"id": strconv.Itoa(i),
+			},
+			map[string]interface{}{
+				"value": 42.0,
+			},
+			time.Unix(0, 0),
+		)
+		output, err := serializer.Serialize(m)
+		require.NoError(t, err)
+		require.Equal(t, "cpu,id="+strconv.Itoa(i)+" value=42 0\n", string(output))
+	}
+	require.LessOrEqual(t, len(serializer.headerCache), headerCacheSize)
+}
+
+func BenchmarkSerializeBatch(b *testing.B) {
+	metrics := make([]telegraf.Metric, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		metrics = append(metrics, metric.New(
+			"cpu",
+			map[string]string{
+				"host": "localhost",
+				"cpu":  "cpu" + strconv.Itoa(i%8),
+				"dc":   "us-east-1",
+			},
+			map[string]interface{}{
+				"usage_idle":   91.5,
+				"usage_user":   5.5,
+				"usage_system": 3.0,
+			},
+			time.Unix(0, int64(i)),
+		))
+	}
+
+	serializer := NewSerializer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_, err := serializer.SerializeBatch(metrics)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
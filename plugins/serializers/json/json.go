@@ -2,23 +2,105 @@ package json
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/influxdata/telegraf"
 )
 
-type serializer struct {
+// FormatConfig contains the options of the serializer.  Empty values select
+// the defaults.
+type FormatConfig struct {
+	// TimestampUnits is the resolution of numeric timestamps.
 	TimestampUnits time.Duration
+
+	// TimestampFormat is a Go time layout; if set timestamps are written as
+	// strings in UTC instead of numbers.
+	TimestampFormat string
+
+	// BatchFormat is either "object", wrapping the metrics of a batch into
+	// an object with the key "metrics", or "array" for a plain array.
+	BatchFormat string
+
+	// Dot separated paths of the name, tags, fields and timestamp inside
+	// each metric object.  A path of "." places the tags or fields directly
+	// into the metric object.
+	NamePath      string
+	TagsPath      string
+	FieldsPath    string
+	TimestampPath string
+}
+
+type serializer struct {
+	TimestampUnits  time.Duration
+	TimestampFormat string
+	BatchFormat     string
+
+	namePath      []string
+	tagsPath      []string
+	fieldsPath    []string
+	timestampPath []string
 }
 
 func NewSerializer(timestampUnits time.Duration) (*serializer, error) {
+	return NewSerializerConfig(FormatConfig{TimestampUnits: timestampUnits})
+}
+
+func NewSerializerConfig(config FormatConfig) (*serializer, error) {
 	s := &serializer{
-		TimestampUnits: truncateDuration(timestampUnits),
+		TimestampUnits:  truncateDuration(config.TimestampUnits),
+		TimestampFormat: config.TimestampFormat,
+		BatchFormat:     config.BatchFormat,
+	}
+
+	switch s.BatchFormat {
+	case "":
+		s.BatchFormat = "object"
+	case "object", "array":
+	default:
+		return nil, fmt.Errorf("invalid batch format %q", s.BatchFormat)
+	}
+
+	var err error
+	if s.namePath, err = splitPath(config.NamePath, "name", false); err != nil {
+		return nil, err
+	}
+	if s.tagsPath, err = splitPath(config.TagsPath, "tags", true); err != nil {
+		return nil, err
+	}
+	if s.fieldsPath, err = splitPath(config.FieldsPath, "fields", true); err != nil {
+		return nil, err
+	}
+	if s.timestampPath, err = splitPath(config.TimestampPath, "timestamp", false); err != nil {
+		return nil, err
 	}
 	return s, nil
 }
 
+// splitPath returns the keys of a dot separated path.  The root path "."
+// results in no keys and is only allowed for groups of values.
+func splitPath(path, defaultPath string, allowRoot bool) ([]string, error) {
+	if path == "" {
+		path = defaultPath
+	}
+	if path == "." {
+		if !allowRoot {
+			return nil, fmt.Errorf("%s can not be placed at the root", defaultPath)
+		}
+		return nil, nil
+	}
+
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid %s path %q", defaultPath, path)
+		}
+	}
+	return keys, nil
+}
+
 func (s *serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
 	m := s.createObject(metric)
 	serialized, err := json.Marshal(m)
@@ -37,8 +119,11 @@ func (s *serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 		objects = append(objects, m)
 	}
 
-	obj := map[string]interface{}{
-		"metrics": objects,
+	var obj interface{} = objects
+	if s.BatchFormat == "object" {
+		obj = map[string]interface{}{
+			"metrics": objects,
+		}
 	}
 
 	serialized, err := json.Marshal(obj)
@@ -51,11 +136,11 @@ func (s *serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 func (s *serializer) createObject(metric telegraf.Metric) map[string]interface{} {
 	m := make(map[string]interface{}, 4)
 
-	tags := make(map[string]string, len(metric.TagList()))
+	tags := make(map[string]interface{}, len(metric.TagList()))
 	for _, tag := range metric.TagList() {
 		tags[tag.Key] = tag.Value
 	}
-	m["tags"] = tags
+	insertGroup(m, s.tagsPath, tags)
 
 	fields := make(map[string]interface{}, len(metric.FieldList()))
 	for _, field := range metric.FieldList() {
@@ -68,10 +153,54 @@ func (s *serializer) createObject(metric telegraf.Metric) map[string]interface{}
 		}
 		fields[field.Key] = field.Value
 	}
-	m["fields"] = fields
+	insertGroup(m, s.fieldsPath, fields)
+
+	insertValue(m, s.namePath, metric.Name())
+	if s.TimestampFormat != "" {
+		insertValue(m, s.timestampPath, metric.Time().UTC().Format(s.TimestampFormat))
+	} else {
+		insertValue(m, s.timestampPath, metric.Time().UnixNano()/int64(s.TimestampUnits))
+	}
+	return m
+}
+
+// insertGroup adds the values at the given path, merging them with any
+// values already present there.
+func insertGroup(m map[string]interface{}, path []string, values map[string]interface{}) {
+	if len(path) == 0 {
+		for k, v := range values {
+			m[k] = v
+		}
+		return
+	}
+
+	parent := subObject(m, path[:len(path)-1])
+	key := path[len(path)-1]
+	if existing, ok := parent[key].(map[string]interface{}); ok {
+		for k, v := range values {
+			existing[k] = v
+		}
+		return
+	}
+	parent[key] = values
+}
+
+// insertValue sets the value at the given path, replacing existing values.
+func insertValue(m map[string]interface{}, path []string, value interface{}) {
+	subObject(m, path[:len(path)-1])[path[len(path)-1]] = value
+}
 
-	m["name"] = metric.Name()
-	m["timestamp"] = metric.Time().UnixNano() / int64(s.TimestampUnits)
+// subObject returns the object at the given path, creating missing levels.
+// Values that are in the way of the path are replaced.
+func subObject(m map[string]interface{}, path []string) map[string]interface{} {
+	for _, key := range path {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
 	return m
 }
 
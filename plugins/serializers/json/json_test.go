@@ -224,3 +224,120 @@ func TestSerializeBatchSkipInfAllFields(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte(`{"metrics":[{"fields":{},"name":"cpu","tags":{},"timestamp":0}]}`), buf)
 }
+
+func TestSerializeTimestampFormat(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{
+			"value": 42.0,
+		},
+		time.Unix(1525478795, 123456789),
+	)
+
+	s, err := NewSerializerConfig(FormatConfig{TimestampFormat: time.RFC3339Nano})
+	require.NoError(t, err)
+	buf, err := s.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"fields":{"value":42},"name":"cpu","tags":{},"timestamp":"2018-05-05T00:06:35.123456789Z"}`+"\n", string(buf))
+}
+
+func TestSerializeBatchArray(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{
+			"value": 42.0,
+		},
+		time.Unix(0, 0),
+	)
+
+	s, err := NewSerializerConfig(FormatConfig{BatchFormat: "array"})
+	require.NoError(t, err)
+	buf, err := s.SerializeBatch([]telegraf.Metric{m, m})
+	require.NoError(t, err)
+	require.Equal(t, `[{"fields":{"value":42},"name":"cpu","tags":{},"timestamp":0},{"fields":{"value":42},"name":"cpu","tags":{},"timestamp":0}]`, string(buf))
+}
+
+func TestSerializePaths(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{
+			"host": "server01",
+		},
+		map[string]interface{}{
+			"usage_idle": 91.5,
+		},
+		time.Unix(1525478795, 0),
+	)
+
+	tests := []struct {
+		name     string
+		config   FormatConfig
+		expected string
+	}{
+		{
+			name: "nested",
+			config: FormatConfig{
+				NamePath:      "event.type",
+				TagsPath:      "event.meta",
+				FieldsPath:    "data",
+				TimestampPath: "event.time",
+			},
+			expected: `{"data":{"usage_idle":91.5},"event":{"meta":{"host":"server01"},"time":1525478795,"type":"cpu"}}`,
+		},
+		{
+			name: "flattened",
+			config: FormatConfig{
+				NamePath:      "metric",
+				TagsPath:      ".",
+				FieldsPath:    ".",
+				TimestampPath: "time",
+			},
+			expected: `{"host":"server01","metric":"cpu","time":1525478795,"usage_idle":91.5}`,
+		},
+		{
+			name: "shared group",
+			config: FormatConfig{
+				TagsPath:   "values",
+				FieldsPath: "values",
+			},
+			expected: `{"name":"cpu","timestamp":1525478795,"values":{"host":"server01","usage_idle":91.5}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSerializerConfig(tt.config)
+			require.NoError(t, err)
+			buf, err := s.Serialize(m)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected+"\n", string(buf))
+		})
+	}
+}
+
+func TestSerializerConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config FormatConfig
+	}{
+		{
+			name:   "invalid batch format",
+			config: FormatConfig{BatchFormat: "lines"},
+		},
+		{
+			name:   "name at root",
+			config: FormatConfig{NamePath: "."},
+		},
+		{
+			name:   "empty path element",
+			config: FormatConfig{TagsPath: "meta..tags"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSerializerConfig(tt.config)
+			require.Error(t, err)
+		})
+	}
+}
@@ -27,6 +27,11 @@ type MessagePackTime struct {
 	time time.Time
 }
 
+// Time returns the timestamp carried by the extension
+func (t *MessagePackTime) Time() time.Time {
+	return t.time
+}
+
 func init() {
 	msgp.RegisterExtension(-1, func() msgp.Extension { return new(MessagePackTime) })
 }
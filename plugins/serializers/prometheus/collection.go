@@ -9,6 +9,7 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/influxdata/telegraf"
+	internalMetric "github.com/influxdata/telegraf/metric"
 	dto "github.com/prometheus/client_model/go"
 )
 
@@ -181,6 +182,15 @@ func (c *Collection) createLabels(metric telegraf.Metric) []LabelPair {
 
 func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 	labels := c.createLabels(metric)
+	if value, ok := internalMetric.GetHistogram(metric); ok {
+		c.addHistogram(metric, labels, value, now)
+		return
+	}
+	if value, ok := internalMetric.GetSummary(metric); ok {
+		c.addSummary(metric, labels, value, now)
+		return
+	}
+
 	for _, field := range metric.FieldList() {
 		metricName := MetricName(metric.Name(), field.Key, metric.Type())
 		metricName, ok := SanitizeMetricName(metricName)
@@ -331,6 +341,70 @@ func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 	}
 }
 
+// addHistogram adds a histogram with a field per bucket, e.g. created by
+// AddHistogram, as the metric family named after the measurement.
+func (c *Collection) addHistogram(metric telegraf.Metric, labels []LabelPair, value internalMetric.HistogramValue, now time.Time) {
+	histogram := &Histogram{
+		Buckets: make([]Bucket, 0, len(value.Buckets)),
+		Count:   value.Count,
+		Sum:     value.Sum,
+	}
+	for _, b := range value.Buckets {
+		histogram.Buckets = append(histogram.Buckets, Bucket{Bound: b.UpperBound, Count: b.Count})
+	}
+	c.addMetric(metric, &Metric{
+		Labels:    labels,
+		Time:      metric.Time(),
+		AddTime:   now,
+		Histogram: histogram,
+	})
+}
+
+// addSummary adds a summary with a field per quantile, e.g. created by
+// AddSummary, as the metric family named after the measurement.
+func (c *Collection) addSummary(metric telegraf.Metric, labels []LabelPair, value internalMetric.SummaryValue, now time.Time) {
+	summary := &Summary{
+		Quantiles: make([]Quantile, 0, len(value.Quantiles)),
+		Count:     value.Count,
+		Sum:       value.Sum,
+	}
+	for _, q := range value.Quantiles {
+		summary.Quantiles = append(summary.Quantiles, Quantile{Quantile: q.Quantile, Value: q.Value})
+	}
+	c.addMetric(metric, &Metric{
+		Labels:  labels,
+		Time:    metric.Time(),
+		AddTime: now,
+		Summary: summary,
+	})
+}
+
+func (c *Collection) addMetric(metric telegraf.Metric, m *Metric) {
+	metricName, ok := SanitizeMetricName(metric.Name())
+	if !ok {
+		return
+	}
+
+	family := MetricFamily{
+		Name: metricName,
+		Type: metric.Type(),
+	}
+	entry, ok := c.Entries[family]
+	if !ok {
+		entry = Entry{
+			Family:  family,
+			Metrics: make(map[MetricKey]*Metric),
+		}
+		c.Entries[family] = entry
+	}
+
+	metricKey := MakeMetricKey(m.Labels)
+	if existing, ok := entry.Metrics[metricKey]; ok && m.Time.Before(existing.Time) {
+		return
+	}
+	entry.Metrics[metricKey] = m
+}
+
 func (c *Collection) Expire(now time.Time, age time.Duration) {
 	expireTime := now.Add(-age)
 	for _, entry := range c.Entries {
@@ -136,7 +136,9 @@ func MetricName(measurement, fieldKey string, valueType telegraf.ValueType) stri
 		}
 	}
 
-	if measurement == "prometheus" {
+	// Metrics named after their family keep the name of the Prometheus metric
+	// in the field
+	if measurement == "prometheus" || fieldKey == measurement {
 		return fieldKey
 	}
 	return measurement + "_" + fieldKey
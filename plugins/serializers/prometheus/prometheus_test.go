@@ -135,6 +135,94 @@ http_request_duration_seconds_bucket{le="0.5"} 129389
 http_request_duration_seconds_bucket{le="+Inf"} 0
 http_request_duration_seconds_sum 0
 http_request_duration_seconds_count 0
+`),
+		},
+		{
+			name: "prometheus input counter named by family",
+			metric: testutil.MustMetric(
+				"http_requests_total",
+				map[string]string{
+					"code": "400",
+				},
+				map[string]interface{}{
+					"http_requests_total": 3.0,
+				},
+				time.Unix(0, 0),
+				telegraf.Counter,
+			),
+			expected: []byte(`
+# HELP http_requests_total Telegraf collected metric
+# TYPE http_requests_total counter
+http_requests_total{code="400"} 3
+`),
+		},
+		{
+			name: "prometheus input histogram named by family",
+			metric: testutil.MustMetric(
+				"http_request_duration_seconds",
+				map[string]string{},
+				map[string]interface{}{
+					"http_request_duration_seconds_sum":   53423,
+					"http_request_duration_seconds_count": 144320,
+				},
+				time.Unix(0, 0),
+				telegraf.Histogram,
+			),
+			expected: []byte(`
+# HELP http_request_duration_seconds Telegraf collected metric
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="+Inf"} 144320
+http_request_duration_seconds_sum 53423
+http_request_duration_seconds_count 144320
+`),
+		},
+		{
+			name: "histogram with bucket fields",
+			metric: testutil.MustMetric(
+				"http_request_duration_seconds",
+				map[string]string{
+					"method": "get",
+				},
+				map[string]interface{}{
+					"0.1":   10.0,
+					"0.5":   25.0,
+					"count": 30.0,
+					"sum":   7.5,
+				},
+				time.Unix(0, 0),
+				telegraf.Histogram,
+			),
+			expected: []byte(`
+# HELP http_request_duration_seconds Telegraf collected metric
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="get",le="0.1"} 10
+http_request_duration_seconds_bucket{method="get",le="0.5"} 25
+http_request_duration_seconds_bucket{method="get",le="+Inf"} 30
+http_request_duration_seconds_sum{method="get"} 7.5
+http_request_duration_seconds_count{method="get"} 30
+`),
+		},
+		{
+			name: "summary with quantile fields",
+			metric: testutil.MustMetric(
+				"rpc_duration_seconds",
+				map[string]string{},
+				map[string]interface{}{
+					"0.5":   0.2,
+					"0.9":   0.8,
+					"count": 12.0,
+					"sum":   4.0,
+				},
+				time.Unix(0, 0),
+				telegraf.Summary,
+			),
+			expected: []byte(`
+# HELP rpc_duration_seconds Telegraf collected metric
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.2
+rpc_duration_seconds{quantile="0.9"} 0.8
+rpc_duration_seconds_sum 4
+rpc_duration_seconds_count 12
 `),
 		},
 		{
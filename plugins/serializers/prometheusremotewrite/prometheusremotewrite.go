@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/influxdata/telegraf/plugins/serializers/prometheus"
 
 	"github.com/influxdata/telegraf"
+	internalMetric "github.com/influxdata/telegraf/metric"
 	"github.com/prometheus/prometheus/prompb"
 )
 
@@ -58,6 +60,15 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 	var entries = make(map[MetricKey]prompb.TimeSeries)
 	for _, metric := range metrics {
 		commonLabels := s.createLabels(metric)
+		if value, ok := internalMetric.GetHistogram(metric); ok {
+			addSeries(entries, histogramSeries(metric, commonLabels, value))
+			continue
+		}
+		if value, ok := internalMetric.GetSummary(metric); ok {
+			addSeries(entries, summarySeries(metric, commonLabels, value))
+			continue
+		}
+
 		var metrickey MetricKey
 		var promts prompb.TimeSeries
 		for _, field := range metric.FieldList() {
@@ -342,3 +353,68 @@ func getPromTS(name string, labels []prompb.Label, value float64, ts time.Time)
 	})
 	return MakeMetricKey(labels), prompb.TimeSeries{Labels: labels, Samples: sample}
 }
+
+// histogramSeries returns the series of a histogram with a field per bucket,
+// e.g. created by AddHistogram, named after the measurement.
+func histogramSeries(metric telegraf.Metric, labels []prompb.Label, value internalMetric.HistogramValue) map[MetricKey]prompb.TimeSeries {
+	name, ok := prometheus.SanitizeMetricName(metric.Name())
+	if !ok {
+		return nil
+	}
+
+	series := make(map[MetricKey]prompb.TimeSeries, len(value.Buckets)+3)
+	hasInf := false
+	for _, b := range value.Buckets {
+		if math.IsInf(b.UpperBound, 1) {
+			hasInf = true
+		}
+		key, ts := getPromTS(name+"_bucket", withLabel(labels, "le", fmt.Sprint(b.UpperBound)), float64(b.Count), metric.Time())
+		series[key] = ts
+	}
+	if !hasInf {
+		key, ts := getPromTS(name+"_bucket", withLabel(labels, "le", "+Inf"), float64(value.Count), metric.Time())
+		series[key] = ts
+	}
+	key, ts := getPromTS(name+"_sum", labels, value.Sum, metric.Time())
+	series[key] = ts
+	key, ts = getPromTS(name+"_count", labels, float64(value.Count), metric.Time())
+	series[key] = ts
+	return series
+}
+
+// summarySeries returns the series of a summary with a field per quantile,
+// e.g. created by AddSummary, named after the measurement.
+func summarySeries(metric telegraf.Metric, labels []prompb.Label, value internalMetric.SummaryValue) map[MetricKey]prompb.TimeSeries {
+	name, ok := prometheus.SanitizeMetricName(metric.Name())
+	if !ok {
+		return nil
+	}
+
+	series := make(map[MetricKey]prompb.TimeSeries, len(value.Quantiles)+2)
+	for _, q := range value.Quantiles {
+		key, ts := getPromTS(name, withLabel(labels, "quantile", fmt.Sprint(q.Quantile)), q.Value, metric.Time())
+		series[key] = ts
+	}
+	key, ts := getPromTS(name+"_sum", labels, value.Sum, metric.Time())
+	series[key] = ts
+	key, ts = getPromTS(name+"_count", labels, float64(value.Count), metric.Time())
+	series[key] = ts
+	return series
+}
+
+// addSeries adds the series to the entries unless the entries contain a newer
+// sample of the series.
+func addSeries(entries map[MetricKey]prompb.TimeSeries, series map[MetricKey]prompb.TimeSeries) {
+	for key, ts := range series {
+		if existing, ok := entries[key]; ok && ts.Samples[0].Timestamp < existing.Samples[0].Timestamp {
+			continue
+		}
+		entries[key] = ts
+	}
+}
+
+func withLabel(labels []prompb.Label, name, value string) []prompb.Label {
+	result := make([]prompb.Label, len(labels), len(labels)+1)
+	copy(result, labels)
+	return append(result, prompb.Label{Name: name, Value: value})
+}
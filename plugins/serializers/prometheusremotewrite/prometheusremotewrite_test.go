@@ -130,6 +130,51 @@ http_request_duration_seconds_count 0
 http_request_duration_seconds_sum 0
 http_request_duration_seconds_bucket{le="+Inf"} 0
 http_request_duration_seconds_bucket{le="0.5"} 129389
+`),
+		},
+		{
+			name: "histogram with bucket fields",
+			metric: testutil.MustMetric(
+				"http_request_duration_seconds",
+				map[string]string{
+					"method": "get",
+				},
+				map[string]interface{}{
+					"0.1":   10.0,
+					"0.5":   25.0,
+					"count": 30.0,
+					"sum":   7.5,
+				},
+				time.Unix(0, 0),
+				telegraf.Histogram,
+			),
+			expected: []byte(`
+http_request_duration_seconds_count{method="get"} 30
+http_request_duration_seconds_sum{method="get"} 7.5
+http_request_duration_seconds_bucket{le="+Inf", method="get"} 30
+http_request_duration_seconds_bucket{le="0.1", method="get"} 10
+http_request_duration_seconds_bucket{le="0.5", method="get"} 25
+`),
+		},
+		{
+			name: "summary with quantile fields",
+			metric: testutil.MustMetric(
+				"rpc_duration_seconds",
+				map[string]string{},
+				map[string]interface{}{
+					"0.5":   0.2,
+					"0.9":   0.8,
+					"count": 12.0,
+					"sum":   4.0,
+				},
+				time.Unix(0, 0),
+				telegraf.Summary,
+			),
+			expected: []byte(`
+rpc_duration_seconds_count 12
+rpc_duration_seconds_sum 4
+rpc_duration_seconds{quantile="0.5"} 0.2
+rpc_duration_seconds{quantile="0.9"} 0.8
 `),
 		},
 	}
@@ -88,6 +88,18 @@ type Config struct {
 	// Timestamp units to use for JSON formatted output
 	TimestampUnits time.Duration `toml:"timestamp_units"`
 
+	// Go time layout of string timestamps in JSON formatted output
+	JSONTimestampFormat string `toml:"json_timestamp_format"`
+
+	// Layout of JSON formatted batches, "object" or "array"
+	JSONBatchFormat string `toml:"json_batch_format"`
+
+	// Paths of the parts of a metric in JSON formatted output
+	JSONNamePath      string `toml:"json_name_path"`
+	JSONTagsPath      string `toml:"json_tags_path"`
+	JSONFieldsPath    string `toml:"json_fields_path"`
+	JSONTimestampPath string `toml:"json_timestamp_path"`
+
 	// Include HEC routing fields for splunkmetric output
 	HecRouting bool `toml:"hec_routing"`
 
@@ -123,7 +135,7 @@ func NewSerializer(config *Config) (Serializer, error) {
 	case "graphite":
 		serializer, err = NewGraphiteSerializer(config.Prefix, config.Template, config.GraphiteTagSupport, config.GraphiteTagSanitizeMode, config.GraphiteSeparator, config.Templates)
 	case "json":
-		serializer, err = NewJSONSerializer(config.TimestampUnits)
+		serializer, err = NewJSONSerializerConfig(config)
 	case "splunkmetric":
 		serializer, err = NewSplunkmetricSerializer(config.HecRouting, config.SplunkmetricMultiMetric)
 	case "nowmetric":
@@ -146,7 +158,7 @@ func NewSerializer(config *Config) (Serializer, error) {
 
 func NewPrometheusRemoteWriteSerializer(config *Config) (Serializer, error) {
 	sortMetrics := prometheusremotewrite.NoSortMetrics
-	if config.PrometheusExportTimestamp {
+	if config.PrometheusSortMetrics {
 		sortMetrics = prometheusremotewrite.SortMetrics
 	}
 
@@ -168,7 +180,7 @@ func NewPrometheusSerializer(config *Config) (Serializer, error) {
 	}
 
 	sortMetrics := prometheus.NoSortMetrics
-	if config.PrometheusExportTimestamp {
+	if config.PrometheusSortMetrics {
 		sortMetrics = prometheus.SortMetrics
 	}
 
@@ -192,6 +204,18 @@ func NewJSONSerializer(timestampUnits time.Duration) (Serializer, error) {
 	return json.NewSerializer(timestampUnits)
 }
 
+func NewJSONSerializerConfig(config *Config) (Serializer, error) {
+	return json.NewSerializerConfig(json.FormatConfig{
+		TimestampUnits:  config.TimestampUnits,
+		TimestampFormat: config.JSONTimestampFormat,
+		BatchFormat:     config.JSONBatchFormat,
+		NamePath:        config.JSONNamePath,
+		TagsPath:        config.JSONTagsPath,
+		FieldsPath:      config.JSONFieldsPath,
+		TimestampPath:   config.JSONTimestampPath,
+	})
+}
+
 func NewCarbon2Serializer(carbon2format string, carbon2SanitizeReplaceChar string) (Serializer, error) {
 	return carbon2.NewSerializer(carbon2format, carbon2SanitizeReplaceChar)
 }
@@ -237,8 +261,12 @@ func NewGraphiteSerializer(prefix, template string, tagSupport bool, tagSanitize
 		template = defaultTemplate
 	}
 
-	if tagSanitizeMode == "" {
+	switch tagSanitizeMode {
+	case "":
 		tagSanitizeMode = "strict"
+	case "strict", "compatible":
+	default:
+		return nil, fmt.Errorf("invalid graphite_tag_sanitize_mode %q", tagSanitizeMode)
 	}
 
 	if separator == "" {
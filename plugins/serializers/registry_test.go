@@ -0,0 +1,142 @@
+package serializers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// unsortedMetrics returns enough metrics that an unsorted output is
+// practically never in order by chance.
+func unsortedMetrics() []telegraf.Metric {
+	var metrics []telegraf.Metric
+	for i := 20; i > 0; i-- {
+		metrics = append(metrics, testutil.MustMetric(
+			fmt.Sprintf("metric%02d", i),
+			map[string]string{},
+			map[string]interface{}{"value": float64(i)},
+			time.Unix(1600000000, 0),
+		))
+	}
+	return metrics
+}
+
+func TestPrometheusSerializerOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		sortMetrics     bool
+		exportTimestamp bool
+	}{
+		{name: "sort only", sortMetrics: true},
+		{name: "timestamp only", exportTimestamp: true},
+		{name: "sort and timestamp", sortMetrics: true, exportTimestamp: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSerializer(&Config{
+				DataFormat:                "prometheus",
+				PrometheusSortMetrics:     tt.sortMetrics,
+				PrometheusExportTimestamp: tt.exportTimestamp,
+			})
+			require.NoError(t, err)
+
+			output, err := s.SerializeBatch(unsortedMetrics())
+			require.NoError(t, err)
+
+			var samples []string
+			for _, line := range strings.Split(string(output), "\n") {
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				fields := strings.Fields(line)
+				samples = append(samples, fields[0])
+				if tt.exportTimestamp {
+					require.Len(t, fields, 3)
+					require.Equal(t, "1600000000000", fields[2])
+				} else {
+					require.Len(t, fields, 2)
+				}
+			}
+			require.Len(t, samples, 20)
+			if tt.sortMetrics {
+				require.True(t, sort.StringsAreSorted(samples), "unsorted output: %v", samples)
+			}
+		})
+	}
+}
+
+func TestPrometheusRemoteWriteSerializerSortMetrics(t *testing.T) {
+	s, err := NewSerializer(&Config{
+		DataFormat:            "prometheusremotewrite",
+		PrometheusSortMetrics: true,
+	})
+	require.NoError(t, err)
+
+	output, err := s.SerializeBatch(unsortedMetrics())
+	require.NoError(t, err)
+
+	data, err := snappy.Decode(nil, output)
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, proto.Unmarshal(data, &req))
+
+	var names []string
+	for _, ts := range req.Timeseries {
+		for _, l := range ts.Labels {
+			if l.Name == "__name__" {
+				names = append(names, l.Value)
+			}
+		}
+	}
+	require.Len(t, names, 20)
+	require.True(t, sort.StringsAreSorted(names), "unsorted output: %v", names)
+}
+
+func TestGraphiteTagSanitizeMode(t *testing.T) {
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{"path": "/var/log"},
+		map[string]interface{}{"value": 42},
+		time.Unix(1600000000, 0),
+	)
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected string
+		err      string
+	}{
+		{name: "default", mode: "", expected: "cpu;path=-var-log 42 1600000000\n"},
+		{name: "strict", mode: "strict", expected: "cpu;path=-var-log 42 1600000000\n"},
+		{name: "compatible", mode: "compatible", expected: "cpu;path=/var/log 42 1600000000\n"},
+		{name: "lenient", mode: "lenient", err: `invalid graphite_tag_sanitize_mode "lenient"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSerializer(&Config{
+				DataFormat:              "graphite",
+				GraphiteTagSupport:      true,
+				GraphiteTagSanitizeMode: tt.mode,
+			})
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			output, err := s.Serialize(m)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(output))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+//go:build ignore
+// +build ignore
+
+// generate_plugins writes one file per plugin into the "all" package of a
+// plugin category, importing the plugin behind build tags.  Run it through
+// go generate from the "all" package:
+//
+//	go run ../../../scripts/generate_plugins.go inputs
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const header = "// Code generated by scripts/generate_plugins.go; DO NOT EDIT."
+
+const template = header + `
+
+//go:build !custom || %[1]s || %[1]s.%[2]s
+// +build !custom %[1]s %[1]s.%[2]s
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/%[1]s/%[3]s" // register plugin
+`
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %s <inputs|outputs|processors|aggregators>", os.Args[0])
+	}
+	category := os.Args[1]
+
+	plugins, err := findPlugins("..", category)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(plugins) == 0 {
+		log.Fatalf("no %s found", category)
+	}
+
+	if err := removeGenerated("."); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, plugin := range plugins {
+		// nested plugins like "aws/ec2" use "aws_ec2" as name
+		name := strings.ReplaceAll(plugin, "/", "_")
+		content := fmt.Sprintf(template, category, name, plugin)
+		if err := ioutil.WriteFile(name+".go", []byte(content), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// findPlugins returns the package directories below root, relative to root,
+// that register a plugin of the category.
+func findPlugins(root, category string) ([]string, error) {
+	register := regexp.MustCompile(`\b` + category + `\.(Add|AddStreaming)\(`)
+
+	found := make(map[string]bool)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "all" || info.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if register.Match(content) {
+			dir, err := filepath.Rel(root, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			found[filepath.ToSlash(dir)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make([]string, 0, len(found))
+	for plugin := range found {
+		plugins = append(plugins, plugin)
+	}
+	sort.Strings(plugins)
+	return plugins, nil
+}
+
+// removeGenerated deletes the files written by a previous run.
+func removeGenerated(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(content, []byte(header)) {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}